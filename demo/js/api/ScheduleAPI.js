@@ -16,7 +16,7 @@ export default class ScheduleAPI {
   }
 
   getSchedule(projectID) {
-    return this.baseAPI.get(`/projects/${projectID}/schedule`);
+    return this.baseAPI.get(`/projects/${projectID}/schedule?geometry=true`);
   }
 
   getScheduleIcal(projectID) {
@@ -35,6 +35,22 @@ export default class ScheduleAPI {
     return this.baseAPI.getIcal(`/vehicles/${vehicleID}/schedule`);
   }
 
+  // stream the schedule events of the project, closing the stream of the previously viewed project
+  streamScheduleEvents(projectID, onScheduleCreate, onScheduleDelete) {
+    if (ScheduleAPI.eventSource) {
+      ScheduleAPI.eventSource.close();
+    }
+    const eventSource = new EventSource(
+      `${this.baseAPI.baseURL}/projects/${projectID}/events?events=schedule.created,schedule.deleted`
+    );
+    eventSource.addEventListener("schedule.created", () => {
+      this.getSchedule(projectID).then((data) => onScheduleCreate(data));
+    });
+    eventSource.addEventListener("schedule.deleted", () => onScheduleDelete());
+    ScheduleAPI.eventSource = eventSource;
+    return eventSource;
+  }
+
   deleteSchedule(projectID) {
     return this.baseAPI.delete(`/projects/${projectID}/schedule`);
   }
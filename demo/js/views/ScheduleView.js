@@ -22,6 +22,14 @@ export default class ScheduleView extends AbstractView {
       params.projectID,
       this.handlers()
     );
+
+    // refresh the schedule when it is changed by another client
+    const { onScheduleCreate, onScheduleDelete } = this.handlers();
+    this.scheduleAPI.streamScheduleEvents(
+      this.projectID,
+      onScheduleCreate,
+      onScheduleDelete
+    );
   }
 
   // render the schedules for this project
@@ -136,6 +144,11 @@ export default class ScheduleView extends AbstractView {
 
   // get the geometry for the schedule
   getRoute(schedule) {
+    // use the geometry returned by the server, if present
+    if (schedule.geometry) {
+      return Promise.resolve({ geometry: schedule.geometry });
+    }
+
     // get the coordinates
     let coordinates = [];
     schedule.route.forEach((route) => {
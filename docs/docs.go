@@ -290,7 +290,7 @@ var doc = `{
                 }
             },
             "patch": {
-                "description": "Update a job (partial update) with its job_id",
+                "description": "Update a job (partial update) with its job_id\n\nThe \"skills\" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.",
                 "consumes": [
                     "application/json"
                 ],
@@ -355,13 +355,16 @@ var doc = `{
         },
         "/jobs/{job_id}/schedule": {
             "get": {
-                "description": "Get the schedule for a job using job_id",
+                "description": "Get the schedule for a job using job_id\n\n**For GeoJSON content type**: A FeatureCollection is returned, with a Point feature for each step of the job, or with \"unassigned\" = true if the job is unassigned.\n\n**For CSV and XLSX content types**: A row is returned for the step of the job, with the flattened task_data keys, in the Schedule section (CSV) or sheet (XLSX), or in the Unassigned one if the job is unassigned.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "text/calendar",
-                    "application/json"
+                    "application/json",
+                    "application/geo+json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
                     "Job"
@@ -457,7 +460,7 @@ var doc = `{
                 }
             },
             "post": {
-                "description": "Create a new project with the input payload\nThe \"duration_calc\" parameter must be either \"euclidean\", \"valhalla\" or \"osrm\"",
+                "description": "Create a new project with the input payload\nThe \"duration_calc\" parameter must be one of the registered matrix providers: \"euclidean\", \"valhalla\" or \"osrm\", and \"graphhopper\", \"openrouteservice\", \"pgrouting\" or \"static\" when configured\n\nThe solver options are passed to vrp_vroom when the project is scheduled:\n- \"objective\": minimise the total \"duration\" of the routes (default), the number of \"vehicles\", or the total \"cost\".\n- \"vehicle_fixed_cost\" and \"vehicle_cost_per_hour\": cost of using a vehicle, and cost of an hour of its route, used with the \"cost\" objective. Default values are 0 and 3600.\nThe objective is given to vrp_vroom as the costs of the travels between the locations: the duration of a travel times the cost per hour, plus the fixed cost for the travels leaving the start of a vehicle.\nThe tasks and the vehicles are given to the solver ordered by their id, so that the schedule of the same project is reproducible.\n\nThe planning horizon \"horizon_start\" and \"horizon_end\" (dates in the YYYY-MM-DD format, at most 366 days apart) is used to create the occurrences of the recurring jobs and the shifts of the vehicles when the project is scheduled.\n\nThe \"timezone\" is the IANA time zone of the project, such as \"Europe/Berlin\". The timestamps of the project are then local times of the time zone: the timestamps with an offset (RFC 3339) are converted to the local time, the timestamps without an offset are local times, and the timestamps are returned with the offset of the time zone. When the time zone is changed, the local times of the timestamps are kept. Without a time zone, the timestamps are returned without an offset, and the timestamps with an offset are converted to UTC.",
                 "consumes": [
                     "application/json"
                 ],
@@ -507,6 +510,58 @@ var doc = `{
                 }
             }
         },
+        "/projects/import": {
+            "post": {
+                "description": "Create a new project from a snapshot exported with the GET /projects/{project_id}/export endpoint, in a single transaction.\n\nThe project, jobs, shipments, vehicle types, vehicles and breaks are created with new IDs, and the references between them (the vehicle of a break, the vehicle type of a vehicle, and the vehicles and tasks of the schedule) are updated to the new IDs. The response of the export endpoint can be given as it is, or only its \"data\" field.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Project"
+                ],
+                "summary": "Import a project",
+                "parameters": [
+                    {
+                        "description": "Project snapshot",
+                        "name": "Snapshot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.ProjectSnapshot"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.Project"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/projects/{project_id}": {
             "get": {
                 "description": "Fetch a project with its project_id",
@@ -605,7 +660,7 @@ var doc = `{
                 }
             },
             "patch": {
-                "description": "Update a project with its project_id\nThe \"duration_calc\" parameter must be either \"euclidean\", \"valhalla\" or \"osrm\"",
+                "description": "Update a project with its project_id\nThe \"duration_calc\" parameter must be one of the registered matrix providers: \"euclidean\", \"valhalla\" or \"osrm\", and \"graphhopper\", \"openrouteservice\", \"pgrouting\" or \"static\" when configured\n\nThe solver options are passed to vrp_vroom when the project is scheduled:\n- \"objective\": minimise the total \"duration\" of the routes (default), the number of \"vehicles\", or the total \"cost\".\n- \"vehicle_fixed_cost\" and \"vehicle_cost_per_hour\": cost of using a vehicle, and cost of an hour of its route, used with the \"cost\" objective. Default values are 0 and 3600.\nThe objective is given to vrp_vroom as the costs of the travels between the locations: the duration of a travel times the cost per hour, plus the fixed cost for the travels leaving the start of a vehicle.\nThe tasks and the vehicles are given to the solver ordered by their id, so that the schedule of the same project is reproducible.\n\nThe planning horizon \"horizon_start\" and \"horizon_end\" (dates in the YYYY-MM-DD format, at most 366 days apart) is used to create the occurrences of the recurring jobs and the shifts of the vehicles when the project is scheduled.\n\nThe \"timezone\" is the IANA time zone of the project, such as \"Europe/Berlin\". The timestamps of the project are then local times of the time zone: the timestamps with an offset (RFC 3339) are converted to the local time, the timestamps without an offset are local times, and the timestamps are returned with the offset of the time zone. When the time zone is changed, the local times of the timestamps are kept. Without a time zone, the timestamps are returned without an offset, and the timestamps with an offset are converted to UTC.",
                 "consumes": [
                     "application/json"
                 ],
@@ -668,9 +723,9 @@ var doc = `{
                 }
             }
         },
-        "/projects/{project_id}/jobs": {
-            "get": {
-                "description": "Get a list of jobs for a project with project_id",
+        "/projects/{project_id}/clone": {
+            "post": {
+                "description": "Copy a project along with its jobs, shipments, vehicles and breaks into a new project, in a single transaction. The deleted tasks and vehicles are not copied.\n\nWhen schedule = true, the schedule of the project is also copied, referring to the new tasks and vehicles. Default value is false.\n\nThe response contains the new project, and the mapping of the old IDs to the new IDs of the project, jobs, shipments, vehicles and breaks.",
                 "consumes": [
                     "application/json"
                 ],
@@ -678,9 +733,9 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Job"
+                    "Project"
                 ],
-                "summary": "List jobs for a project",
+                "summary": "Clone a project",
                 "parameters": [
                     {
                         "type": "integer",
@@ -688,11 +743,17 @@ var doc = `{
                         "name": "project_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Copy the schedule",
+                        "name": "schedule",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "allOf": [
                                 {
@@ -702,10 +763,7 @@ var doc = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/database.Job"
-                                            }
+                                            "$ref": "#/definitions/database.ClonedProject"
                                         }
                                     }
                                 }
@@ -717,21 +775,26 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/util.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new job with the input payload",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/projects/{project_id}/events": {
+            "get": {
+                "description": "Stream the events of a project as Server-Sent Events (Content-Type = text/event-stream), so that the clients do not have to poll the schedule.\n\nEach event has the \"id\" and the \"event\" fields of the event, and its \"data\" is the JSON payload of the event, which is the same as the body of the webhook deliveries. The events are \"schedule.created\" (when the project is scheduled, its schedule is edited or a version is restored), \"schedule.deleted\", \"job.status_changed\" and \"shipment.status_changed\" (when the status of a task is different after a schedule is created or deleted), \"task.created\", \"task.updated\" (when the fields of a task are changed) and \"task.deleted\".\n\nThe events are sent with PostgreSQL NOTIFY by the triggers of the tables, so that the changes made through the other servers sharing the database are streamed as well. The events sent while a client is disconnected are not replayed. A keep-alive comment is sent every 15 seconds.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "Job"
+                    "Event"
                 ],
-                "summary": "Create a new job",
+                "summary": "Stream the events of a project",
                 "parameters": [
                     {
                         "type": "integer",
@@ -741,32 +804,17 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "description": "Job object",
-                        "name": "Job",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/database.CreateJobParams"
-                        }
+                        "type": "string",
+                        "description": "Comma separated list of the events to stream, all the events by default",
+                        "name": "events",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Stream of the events",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/util.SuccessResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/database.Job"
-                                        }
-                                    }
-                                }
-                            ]
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -774,24 +822,29 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/util.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
                     }
                 }
             }
         },
-        "/projects/{project_id}/schedule": {
+        "/projects/{project_id}/export": {
             "get": {
-                "description": "Get the schedule for a project.\n\n**For JSON content type**: When overview = true, only the metadata is returned. Default value is false, which also returns the summary object.",
+                "description": "Export a project along with its jobs, shipments, vehicles and breaks as a single JSON document, which can be imported again with the POST /projects/import endpoint.\n\nWhen schedule = true, the schedule of the project is also exported. Default value is false.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/calendar",
                     "application/json"
                 ],
                 "tags": [
-                    "Schedule"
+                    "Project"
                 ],
-                "summary": "Get the schedule",
+                "summary": "Export a project",
                 "parameters": [
                     {
                         "type": "integer",
@@ -802,8 +855,8 @@ var doc = `{
                     },
                     {
                         "type": "boolean",
-                        "description": "Overview",
-                        "name": "overview",
+                        "description": "Export the schedule",
+                        "name": "schedule",
                         "in": "query"
                     }
                 ],
@@ -819,7 +872,7 @@ var doc = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/util.ScheduleData"
+                                            "$ref": "#/definitions/database.ProjectSnapshot"
                                         }
                                     }
                                 }
@@ -839,19 +892,23 @@ var doc = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/projects/{project_id}/import": {
             "post": {
-                "description": "Schedule the tasks present in a project, deleting any previous schedule and return the new schedule.\n\nWhen fresh = true, the old schedule is ignored and a fresh schedule is created. Otherwise, the old schedule of each task is altered such that it remains in the \"max_shift\" interval. Default value is false.\n**For JSON content type**: When overview = true, only the metadata is returned. Default value is false, which also returns the summary object.",
+                "description": "Import the jobs, shipments, vehicles and breaks of a project in a single transaction.\n\nThe rows are given as a JSON array of objects (Content-Type = application/json), or as a CSV file with a header row (Content-Type = text/csv, or multipart/form-data with the file in the \"file\" field). Each row has a \"type\" field (job, shipment, vehicle or break), along with the fields of the corresponding create endpoint. In a CSV file, the nested fields are given with a dot in the column name (e.g. \"location.latitude\"), and the array and object fields are given in JSON format (e.g. \"[10,20]\").\n\nA break refers to an existing vehicle of the project with the \"vehicle_id\" field, or to an imported vehicle with the \"vehicle_ref\" field, matching the \"ref\" field of the vehicle. A vehicle with a \"vehicle_type_id\" gets the fields of the vehicle type which are not given, and the breaks of the type.\n\nAll the rows are validated before any insertion, and the errors of all the rows are returned together. When dry_run = true, the rows are only validated. Default value is false.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Schedule"
+                    "Project"
                 ],
-                "summary": "Schedule the tasks",
+                "summary": "Import jobs, shipments, vehicles and breaks",
                 "parameters": [
                     {
                         "type": "integer",
@@ -862,20 +919,26 @@ var doc = `{
                     },
                     {
                         "type": "boolean",
-                        "description": "Fresh",
-                        "name": "fresh",
+                        "description": "Dry run",
+                        "name": "dry_run",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Overview",
-                        "name": "overview",
-                        "in": "query"
+                        "description": "Rows to import",
+                        "name": "Rows",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "object"
+                            }
+                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -885,47 +948,29 @@ var doc = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/util.ScheduleData"
+                                            "$ref": "#/definitions/database.ImportResult"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/util.ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "Delete the schedule for a project",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Schedule"
-                ],
-                "summary": "Delete the schedule",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Project ID",
-                        "name": "project_id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/util.Success"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.ImportResult"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -943,9 +988,9 @@ var doc = `{
                 }
             }
         },
-        "/projects/{project_id}/shipments": {
+        "/projects/{project_id}/jobs": {
             "get": {
-                "description": "Get a list of shipments for a project with project_id",
+                "description": "Get a list of jobs for a project with project_id",
                 "consumes": [
                     "application/json"
                 ],
@@ -953,9 +998,9 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Shipment"
+                    "Job"
                 ],
-                "summary": "List shipments for a project",
+                "summary": "List jobs for a project",
                 "parameters": [
                     {
                         "type": "integer",
@@ -979,7 +1024,7 @@ var doc = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/database.Shipment"
+                                                "$ref": "#/definitions/database.Job"
                                             }
                                         }
                                     }
@@ -996,7 +1041,7 @@ var doc = `{
                 }
             },
             "post": {
-                "description": "Create a new shipment with the input payload",
+                "description": "Create a new job with the input payload\n\nWhen \"recurrence\" is given as a recurrence rule (FREQ=DAILY, WEEKLY or MONTHLY, with INTERVAL, BYDAY, BYMONTHDAY, COUNT and UNTIL), the job is a recurring job which is not scheduled itself.\nInstead, a job is created for each occurrence within the planning horizon of the project when it is scheduled, with the time windows of the recurring job moved to the date of the occurrence, and the \"recurring_job_id\" and \"occurrence\" fields set.\nThe occurrences are kept when the project is scheduled again, and updated with the changes of their recurring job. An occurrence modified like any other job is marked as \"overridden\", and keeps its changes instead.\n\nThe \"skills\" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1004,9 +1049,9 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Shipment"
+                    "Job"
                 ],
-                "summary": "Create a new shipment",
+                "summary": "Create a new job",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1016,12 +1061,12 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "description": "Create shipment",
-                        "name": "Shipment",
+                        "description": "Job object",
+                        "name": "Job",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/database.CreateShipmentParams"
+                            "$ref": "#/definitions/database.CreateJobParams"
                         }
                     }
                 ],
@@ -1037,7 +1082,7 @@ var doc = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/database.Shipment"
+                                            "$ref": "#/definitions/database.Job"
                                         }
                                     }
                                 }
@@ -1053,9 +1098,9 @@ var doc = `{
                 }
             }
         },
-        "/projects/{project_id}/vehicles": {
-            "get": {
-                "description": "Get a list of vehicles for a project with project_id",
+        "/projects/{project_id}/matrix": {
+            "delete": {
+                "description": "Clear the cached durations between the locations of a project, so that they are computed again by the routing engine in the next schedule request.\n\nThe cached durations are also cleared automatically when the \"duration_calc\" field of the project is changed. The durations between the locations which are still used by another project with the same \"duration_calc\" are kept.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1063,9 +1108,9 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Vehicle"
+                    "Matrix"
                 ],
-                "summary": "List vehicles for a project",
+                "summary": "Clear the matrix cache",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1079,22 +1124,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/util.SuccessResponse"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/database.Vehicle"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/util.Success"
                         }
                     },
                     "400": {
@@ -1102,21 +1132,33 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/util.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new vehicle with the input payload",
+            }
+        },
+        "/projects/{project_id}/schedule": {
+            "get": {
+                "description": "Get the schedule for a project.\n\n**For JSON content type**: When overview = true, only the metadata is returned. Default value is false, which also returns the summary object.\n\nWhen group_by = day, the steps of the vehicle routes are grouped by the day of their arrival, which is useful for the schedules over a planning horizon of several days.\n\nWhen geometry = true, the route geometry of each vehicle is returned, as a GeoJSON LineString (geometry_format = geojson, default) or an encoded polyline with precision 5 (geometry_format = polyline). Straight segments between the locations are returned when the duration calculation method can not compute the road path.\n\n**For GeoJSON content type**: A FeatureCollection is returned, with a Point feature for each step of the routes, a LineString feature for each vehicle route (following the road path when geometry = true), and a Point feature with \"unassigned\" = true for each unassigned task.\n\n**For CSV and XLSX content types**: A row is returned for each step of the routes, except the off-shift breaks, with the flattened task_data keys, followed by the unassigned tasks and the summary of each vehicle, in separate sections (CSV) or sheets (XLSX). The task_data values starting with \"=\", \"+\", \"-\" or \"@\" are prefixed with a quote in the CSV, so that they are not evaluated as formulas.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/calendar",
+                    "application/json",
+                    "application/geo+json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "Vehicle"
+                    "Schedule"
                 ],
-                "summary": "Create a new vehicle",
+                "summary": "Get the schedule",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1126,13 +1168,28 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "description": "Create vehicle",
-                        "name": "Vehicle",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/database.CreateVehicleParams"
-                        }
+                        "type": "boolean",
+                        "description": "Overview",
+                        "name": "overview",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Geometry",
+                        "name": "geometry",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Geometry format (geojson or polyline)",
+                        "name": "geometry_format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group the routes by (day)",
+                        "name": "group_by",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1147,7 +1204,7 @@ var doc = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/database.Vehicle"
+                                            "$ref": "#/definitions/util.ScheduleData"
                                         }
                                     }
                                 }
@@ -1159,13 +1216,17 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/util.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
                     }
                 }
-            }
-        },
-        "/shipments/{shipment_id}": {
-            "get": {
-                "description": "Fetch a shipment with its shipment_id",
+            },
+            "post": {
+                "description": "Schedule the tasks present in a project, deleting any previous schedule and return the new schedule.\n\nWhen fresh = true, the old schedule is ignored and a fresh schedule is created. Otherwise, the old schedule of each task is altered such that it remains in the \"max_shift\" interval. Default value is false.\n**For JSON content type**: When overview = true, only the metadata is returned. Default value is false, which also returns the summary object.\nWhen async = true, the schedule run is queued and 202 Accepted is returned immediately with the run. The status of the run can be polled using the \"/projects/{project_id}/schedule/runs/{run_id}\" endpoint, and the schedule can be fetched once the run has succeeded. Default value is false.\nThe schedule fails when the tasks, the vehicles or the solver settings of the project are changed while it is solved, and the previous schedule is kept.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1173,21 +1234,39 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Shipment"
+                    "Schedule"
                 ],
-                "summary": "Fetch a shipment",
+                "summary": "Schedule the tasks",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Shipment ID",
-                        "name": "shipment_id",
+                        "description": "Project ID",
+                        "name": "project_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fresh",
+                        "name": "fresh",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Overview",
+                        "name": "overview",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Async",
+                        "name": "async",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "allOf": [
                                 {
@@ -1197,7 +1276,25 @@ var doc = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/database.Shipment"
+                                            "$ref": "#/definitions/util.ScheduleData"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.ScheduleRun"
                                         }
                                     }
                                 }
@@ -1210,16 +1307,16 @@ var doc = `{
                             "$ref": "#/definitions/util.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
-                            "$ref": "#/definitions/util.NotFound"
+                            "$ref": "#/definitions/util.ErrorResponse"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Delete a shipment with its shipment_id",
+                "description": "Delete the schedule for a project",
                 "consumes": [
                     "application/json"
                 ],
@@ -1227,14 +1324,14 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Shipment"
+                    "Schedule"
                 ],
-                "summary": "Delete a shipment",
+                "summary": "Delete the schedule",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Shipment ID",
-                        "name": "shipment_id",
+                        "description": "Project ID",
+                        "name": "project_id",
                         "in": "path",
                         "required": true
                     }
@@ -1261,7 +1358,7 @@ var doc = `{
                 }
             },
             "patch": {
-                "description": "Update a shipment with its shipment_id",
+                "description": "Edit the routes of the schedule of a project manually, applying the operations in the given order, and return the new schedule.\n\nThe operations are:\n- \"move\": move an assigned stop (type and task_id) to the route of the vehicle with vehicle_id, at the given position of the route (starting from 0). The stop is added at the end of the route when the position is not given.\n- \"insert\": insert an unassigned stop in the route of a vehicle, in the same way as \"move\".\n- \"remove\": remove a stop from its route, making it unassigned. Removing the pickup or the delivery of a shipment removes both.\n- \"swap\": swap a stop (type and task_id) with another stop (other_type and other_task_id), in the same route or in the routes of two vehicles.\n- \"reorder\": change the order of the stops of the route of the vehicle with vehicle_id, given in the \"stops\" field.\n\nThe type of a stop is \"job\", \"pickup\", \"delivery\" or \"break\". The pickup and the delivery of a shipment must be in the same route, with the pickup before the delivery, and a break can only be in the route of its vehicle.\n\nThe arrival, departure, waiting time and load of the edited routes are computed again using the cached matrix. When the edited routes do not satisfy the time windows, capacity, skills, max tasks, pinned vehicles or locked tasks, the edit is rejected, unless force = true, in which case the violations are returned in the \"warnings\" field. The edited schedule is saved as a new version.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1269,24 +1366,24 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Shipment"
+                    "Schedule"
                 ],
-                "summary": "Update a shipment",
+                "summary": "Edit the schedule",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Shipment ID",
-                        "name": "shipment_id",
+                        "description": "Project ID",
+                        "name": "project_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update shipment",
-                        "name": "Shipment",
+                        "description": "Schedule edit operations",
+                        "name": "ScheduleEdit",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/database.UpdateShipmentParams"
+                            "$ref": "#/definitions/util.ScheduleEditParams"
                         }
                     }
                 ],
@@ -1302,7 +1399,7 @@ var doc = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/database.Shipment"
+                                            "$ref": "#/definitions/util.ScheduleEditData"
                                         }
                                     }
                                 }
@@ -1324,25 +1421,31 @@ var doc = `{
                 }
             }
         },
-        "/shipments/{shipment_id}/schedule": {
+        "/projects/{project_id}/schedule/compare/{other_project_id}": {
             "get": {
-                "description": "Get the schedule for a shipment using shipment_id",
+                "description": "Compare the schedule of a project with the schedule of another project, such as a clone of the project.\n\nThe response contains the overall and per-vehicle travel, setup, service, waiting time and distance of both schedules, along with the delta (other - base). The vehicles and tasks of a project are matched with the ones of its clones.\n\nFor each task, the \"changes\" field lists whether the task \"moved\" to another vehicle, was \"resequenced\" in the same vehicle, became \"assigned\" or \"unassigned\", or was \"added\" or \"removed\". The \"arrival_shift\" field is the shift of the arrival time of the tasks assigned in both schedules.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/calendar",
                     "application/json"
                 ],
                 "tags": [
-                    "Shipment"
+                    "Schedule"
                 ],
-                "summary": "Get the schedule for a shipment",
+                "summary": "Compare two schedules",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Shipment ID",
-                        "name": "shipment_id",
+                        "description": "Project ID",
+                        "name": "project_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Other Project ID",
+                        "name": "other_project_id",
                         "in": "path",
                         "required": true
                     }
@@ -1359,10 +1462,7 @@ var doc = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/util.ScheduleDataTask"
-                                            }
+                                            "$ref": "#/definitions/util.ScheduleComparison"
                                         }
                                     }
                                 }
@@ -1384,9 +1484,9 @@ var doc = `{
                 }
             }
         },
-        "/vehicles/{vehicle_id}": {
+        "/projects/{project_id}/schedule/runs": {
             "get": {
-                "description": "Fetch a vehicle with its vehicle_id",
+                "description": "Get a list of the schedule runs for a project, latest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -1394,14 +1494,14 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Vehicle"
+                    "Schedule"
                 ],
-                "summary": "Fetch a vehicle",
+                "summary": "List the schedule runs",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Vehicle ID",
-                        "name": "vehicle_id",
+                        "description": "Project ID",
+                        "name": "project_id",
                         "in": "path",
                         "required": true
                     }
@@ -1418,7 +1518,10 @@ var doc = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/database.Vehicle"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.ScheduleRun"
+                                            }
                                         }
                                     }
                                 }
@@ -1438,9 +1541,11 @@ var doc = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a vehicle with its vehicle_id",
+            }
+        },
+        "/projects/{project_id}/schedule/runs/{run_id}": {
+            "get": {
+                "description": "Fetch the status of a schedule run created with async = true.\n\nThe status is one of \"queued\", \"running\", \"succeeded\" or \"failed\". The error field contains the error message of a failed run.\n\nThe runs of a project are executed one at a time. When the server is stopped, the runs being executed are finished, and the queued runs are executed by the other servers or when it starts again. The runs left running by a server which stopped abruptly are marked as failed a minute after its last heartbeat.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1448,14 +1553,21 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Vehicle"
+                    "Schedule"
                 ],
-                "summary": "Delete a vehicle",
+                "summary": "Fetch a schedule run",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Vehicle ID",
-                        "name": "vehicle_id",
+                        "description": "Project ID",
+                        "name": "project_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Run ID",
+                        "name": "run_id",
                         "in": "path",
                         "required": true
                     }
@@ -1464,7 +1576,19 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/util.Success"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.ScheduleRun"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -1480,9 +1604,11 @@ var doc = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "description": "Update a vehicle with its vehicle_id",
+            }
+        },
+        "/projects/{project_id}/schedule/validate": {
+            "post": {
+                "description": "Simulate the proposed routes of the vehicles of a project, given as the ordered stops of each vehicle, against the jobs, shipments, vehicles and breaks of the project, and return the computed timings along with the violations of the constraints. The schedule of the project is not modified.\n\nThe type of a stop is \"job\", \"pickup\", \"delivery\" or \"break\", and a break can only be in the route of its vehicle. The jobs and shipments which are not in any route are unassigned.\n\nThe arrival, departure, waiting time and load of the routes are computed using the cached matrix, starting each route at the start of the time window of the vehicle. The \"violations\" field lists the constraints which are not satisfied, with the type \"time_window\", \"capacity\" (for each dimension of the load), \"skills\", \"max_tasks\", \"max_travel_time\", \"max_distance\", \"vehicle_time_window\", \"pinned_vehicle\" or \"pickup_delivery\", and \"feasible\" is true when there are no violations.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1490,24 +1616,24 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Vehicle"
+                    "Schedule"
                 ],
-                "summary": "Update a vehicle",
+                "summary": "Validate a proposed schedule",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Vehicle ID",
-                        "name": "vehicle_id",
+                        "description": "Project ID",
+                        "name": "project_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update vehicle",
-                        "name": "Vehicle",
+                        "description": "Proposed routes",
+                        "name": "ScheduleValidate",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/database.UpdateVehicleParams"
+                            "$ref": "#/definitions/util.ScheduleValidateParams"
                         }
                     }
                 ],
@@ -1523,7 +1649,7 @@ var doc = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/database.Vehicle"
+                                            "$ref": "#/definitions/util.ScheduleValidation"
                                         }
                                     }
                                 }
@@ -1545,9 +1671,9 @@ var doc = `{
                 }
             }
         },
-        "/vehicles/{vehicle_id}/breaks": {
+        "/projects/{project_id}/schedule/versions": {
             "get": {
-                "description": "Get a list of breaks",
+                "description": "Get a list of the saved versions of the schedule for a project, latest first.\n\nEvery schedule created for the project is saved as a numbered version, along with the hash of the inputs (jobs, shipments, vehicles and breaks), the solver settings and the metadata of the schedule. The active version is the current schedule of the project.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1555,14 +1681,14 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Break"
+                    "Schedule"
                 ],
-                "summary": "List breaks",
+                "summary": "List the schedule versions",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Vehicle ID",
-                        "name": "vehicle_id",
+                        "description": "Project ID",
+                        "name": "project_id",
                         "in": "path",
                         "required": true
                     }
@@ -1581,7 +1707,7 @@ var doc = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/database.Break"
+                                                "$ref": "#/definitions/database.ScheduleVersion"
                                             }
                                         }
                                     }
@@ -1594,11 +1720,19 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/util.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new break with the input payload",
+            }
+        },
+        "/projects/{project_id}/schedule/versions/{version}": {
+            "get": {
+                "description": "Fetch the schedule saved in a version, in the same format as the schedule of the project",
                 "consumes": [
                     "application/json"
                 ],
@@ -1606,25 +1740,23 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Break"
+                    "Schedule"
                 ],
-                "summary": "Create a new break",
+                "summary": "Fetch a schedule version",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Vehicle ID",
-                        "name": "vehicle_id",
+                        "description": "Project ID",
+                        "name": "project_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Create break",
-                        "name": "Break",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/database.CreateBreakParams"
-                        }
+                        "type": "integer",
+                        "description": "Version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1639,7 +1771,7 @@ var doc = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/database.Break"
+                                            "$ref": "#/definitions/util.ScheduleData"
                                         }
                                     }
                                 }
@@ -1651,37 +1783,43 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/util.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
                     }
                 }
             }
         },
-        "/vehicles/{vehicle_id}/schedule": {
-            "get": {
-                "description": "Get the schedule for a vehicle using vehicle_id\n\n**For JSON content type**: When overview = true, only the metadata is returned. Default value is false, which also returns the summary object.",
+        "/projects/{project_id}/schedule/versions/{version}/restore": {
+            "post": {
+                "description": "Replace the schedule of the project with the schedule saved in a version, and make it the active version.\n\nThe status of the jobs and shipments is reset according to the restored schedule.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/calendar",
                     "application/json"
                 ],
                 "tags": [
-                    "Vehicle"
+                    "Schedule"
                 ],
-                "summary": "Get the schedule for a vehicle",
+                "summary": "Restore a schedule version",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Vehicle ID",
-                        "name": "vehicle_id",
+                        "description": "Project ID",
+                        "name": "project_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "Overview",
-                        "name": "overview",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -1696,10 +1834,7 @@ var doc = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/util.ScheduleDB"
-                                            }
+                                            "$ref": "#/definitions/util.ScheduleData"
                                         }
                                     }
                                 }
@@ -1720,17 +1855,2081 @@ var doc = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "database.Break": {
-            "type": "object",
-            "properties": {
-                "created_at": {
+        },
+        "/projects/{project_id}/shipments": {
+            "get": {
+                "description": "Get a list of shipments for a project with project_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipment"
+                ],
+                "summary": "List shipments for a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "project_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.Shipment"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new shipment with the input payload\n\nThe \"skills\" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipment"
+                ],
+                "summary": "Create a new shipment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "project_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create shipment",
+                        "name": "Shipment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.CreateShipmentParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.Shipment"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{project_id}/vehicle_types": {
+            "get": {
+                "description": "Get a list of vehicle types for a project with project_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle Type"
+                ],
+                "summary": "List vehicle types for a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "project_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.VehicleType"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new vehicle type with the input payload\n\nA vehicle type is a template of the vehicles of a project. A vehicle created with a \"vehicle_type_id\" gets the fields of the type which are not given in its payload, and a break is created for the vehicle with each of the \"breaks\" of the type.\nChanging a vehicle type does not change the vehicles already created with the type.\n\nThe \"skills\" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle Type"
+                ],
+                "summary": "Create a new vehicle type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "project_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create vehicle type",
+                        "name": "VehicleType",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.CreateVehicleTypeParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.VehicleType"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{project_id}/vehicles": {
+            "get": {
+                "description": "Get a list of vehicles for a project with project_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle"
+                ],
+                "summary": "List vehicles for a project",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "project_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.Vehicle"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new vehicle with the input payload\n\nThe costs of the vehicle are reported in the schedule summary. The solver is only given the \"vehicle_fixed_cost\" and \"vehicle_cost_per_hour\" of the project, as vrp_vroom does not take the costs of each vehicle:\n- \"fixed_cost\": cost of using the vehicle. Defaults to the \"vehicle_fixed_cost\" of the project.\n- \"cost_per_hour\": cost of an hour of the route of the vehicle. Defaults to the \"vehicle_cost_per_hour\" of the project.\n- \"cost_per_km\": cost of a kilometer of the route of the vehicle. Default value is 0.\n\nThe limits of the route of the vehicle are enforced after scheduling, by unassigning the last tasks of a route exceeding them:\n- \"max_travel_time\": max travel time of the route, in the HH:MM:SS format.\n- \"max_distance\": max distance of the route, in meters.\nThe limits are not set by default, and a zero value removes a limit.\n\nWhen \"vehicle_type_id\" is given, the fields of the vehicle type are used for the fields which are not given, and the breaks of the vehicle type are created for the vehicle.\n\nWhen \"shift_recurrence\" is given as a recurrence rule, the time window of the vehicle is its first shift, and a shift at the same time of the day is created on each date of the recurrence within the planning horizon of the project when it is scheduled. The shifts and the days off of the vehicle are edited with the /vehicles/{vehicle_id}/shifts endpoints.\nThe vehicle can then serve tasks from the start of its first shift to the end of its last shift, and an off-shift break (with \"off_shift\" = true) is created between two consecutive shifts.\n\nThe \"skills\" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle"
+                ],
+                "summary": "Create a new vehicle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "project_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create vehicle",
+                        "name": "Vehicle",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.CreateVehicleParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.Vehicle"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/projects/{project_id}/webhooks": {
+            "get": {
+                "description": "Get the webhooks of a project. The secrets of the webhooks are not returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "List webhooks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "project_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.Webhook"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe to the events of a project with the input payload. The events are delivered with a POST request to the URL of the webhook, with a JSON body containing the \"id\", \"event\", \"project_id\", \"created_at\" and \"data\" of the event.\n\nThe events are \"schedule.created\" (when the project is scheduled, its schedule is edited or a version is restored), \"schedule.deleted\", \"job.status_changed\" and \"shipment.status_changed\" (when the status of a task is different after a schedule is created or deleted), \"task.created\", \"task.updated\" (when the fields of a task are changed) and \"task.deleted\". A webhook without events is subscribed to all the events.\n\nThe request contains the \"X-Scheduleserv-Event\" and \"X-Scheduleserv-Delivery\" headers, along with the \"X-Scheduleserv-Signature\" header which is \"sha256=\" followed by the hex encoded HMAC-SHA256 of the body with the secret of the webhook. A delivery succeeds when the response has a 2xx status code, and is otherwise retried with an exponential backoff.\n\nThe URL of the webhook is an http or https URL. The URLs of the local host, and the hosts resolving to a loopback, link-local or private (RFC 1918) address, are rejected unless WEBHOOK_ALLOW_PRIVATE is set to true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Create a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Project ID",
+                        "name": "project_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create webhook",
+                        "name": "Webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.CreateWebhookParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.Webhook"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts/{shift_id}": {
+            "get": {
+                "description": "Fetch a vehicle shift with its shift_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle Shift"
+                ],
+                "summary": "Fetch a vehicle shift",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shift ID",
+                        "name": "shift_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.VehicleShift"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a vehicle shift with its shift_id. The recurring shifts can not be deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle Shift"
+                ],
+                "summary": "Delete a vehicle shift",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shift ID",
+                        "name": "shift_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.Success"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a vehicle shift (partial update) with its shift_id. The recurring shifts can not be updated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle Shift"
+                ],
+                "summary": "Update a vehicle shift",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shift ID",
+                        "name": "shift_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update vehicle shift",
+                        "name": "VehicleShift",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.UpdateVehicleShiftParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.VehicleShift"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            }
+        },
+        "/shipments/{shipment_id}": {
+            "get": {
+                "description": "Fetch a shipment with its shipment_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipment"
+                ],
+                "summary": "Fetch a shipment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipment ID",
+                        "name": "shipment_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.Shipment"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a shipment with its shipment_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipment"
+                ],
+                "summary": "Delete a shipment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipment ID",
+                        "name": "shipment_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.Success"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a shipment with its shipment_id\n\nThe \"skills\" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shipment"
+                ],
+                "summary": "Update a shipment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipment ID",
+                        "name": "shipment_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update shipment",
+                        "name": "Shipment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.UpdateShipmentParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.Shipment"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            }
+        },
+        "/shipments/{shipment_id}/schedule": {
+            "get": {
+                "description": "Get the schedule for a shipment using shipment_id\n\n**For GeoJSON content type**: A FeatureCollection is returned, with a Point feature for each step of the shipment, or with \"unassigned\" = true if the shipment is unassigned.\n\n**For CSV and XLSX content types**: A row is returned for the pickup and the delivery of the shipment, with the flattened task_data keys, in the Schedule section (CSV) or sheet (XLSX), or in the Unassigned one if the shipment is unassigned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/calendar",
+                    "application/json",
+                    "application/geo+json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Shipment"
+                ],
+                "summary": "Get the schedule for a shipment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipment ID",
+                        "name": "shipment_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/util.ScheduleDataTask"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            }
+        },
+        "/vehicle_types/{vehicle_type_id}": {
+            "get": {
+                "description": "Fetch a vehicle type with its vehicle_type_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle Type"
+                ],
+                "summary": "Fetch a vehicle type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle Type ID",
+                        "name": "vehicle_type_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.VehicleType"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a vehicle type with its vehicle_type_id. The vehicles created with the type are not deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle Type"
+                ],
+                "summary": "Delete a vehicle type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle Type ID",
+                        "name": "vehicle_type_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.Success"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a vehicle type with its vehicle_type_id. The \"breaks\" are replaced when they are given.\n\nThe \"skills\" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle Type"
+                ],
+                "summary": "Update a vehicle type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle Type ID",
+                        "name": "vehicle_type_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update vehicle type",
+                        "name": "VehicleType",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.UpdateVehicleTypeParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.VehicleType"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            }
+        },
+        "/vehicle_types/{vehicle_type_id}/vehicles": {
+            "post": {
+                "description": "Create \"count\" vehicles of a vehicle type at the same depot, in a single transaction.\n\nThe vehicles get the fields of the vehicle type, and a break is created for each vehicle with each of the \"breaks\" of the type. The \"end_location\" defaults to the \"start_location\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle Type"
+                ],
+                "summary": "Create vehicles of a vehicle type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle Type ID",
+                        "name": "vehicle_type_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create vehicles of the type",
+                        "name": "Vehicles",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.CreateVehiclesOfTypeParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.Vehicle"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            }
+        },
+        "/vehicles/{vehicle_id}": {
+            "get": {
+                "description": "Fetch a vehicle with its vehicle_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle"
+                ],
+                "summary": "Fetch a vehicle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle ID",
+                        "name": "vehicle_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.Vehicle"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a vehicle with its vehicle_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle"
+                ],
+                "summary": "Delete a vehicle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle ID",
+                        "name": "vehicle_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.Success"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a vehicle with its vehicle_id\n\nThe \"skills\" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle"
+                ],
+                "summary": "Update a vehicle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle ID",
+                        "name": "vehicle_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update vehicle",
+                        "name": "Vehicle",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.UpdateVehicleParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.Vehicle"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            }
+        },
+        "/vehicles/{vehicle_id}/breaks": {
+            "get": {
+                "description": "Get a list of breaks, without the off-shift breaks generated from the shifts of the vehicle unless off_shift is true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Break"
+                ],
+                "summary": "List breaks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle ID",
+                        "name": "vehicle_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the off-shift breaks",
+                        "name": "off_shift",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.Break"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new break with the input payload",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Break"
+                ],
+                "summary": "Create a new break",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle ID",
+                        "name": "vehicle_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create break",
+                        "name": "Break",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.CreateBreakParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.Break"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vehicles/{vehicle_id}/schedule": {
+            "get": {
+                "description": "Get the schedule for a vehicle using vehicle_id\n\n**For JSON content type**: When overview = true, only the metadata is returned. Default value is false, which also returns the summary object.\n\nWhen geometry = true, the route geometry of each vehicle is returned, as a GeoJSON LineString (geometry_format = geojson, default) or an encoded polyline with precision 5 (geometry_format = polyline). Straight segments between the locations are returned when the duration calculation method can not compute the road path.\n\n**For GeoJSON content type**: A FeatureCollection is returned, with a Point feature for each step of the routes, a LineString feature for each vehicle route (following the road path when geometry = true), and a Point feature with \"unassigned\" = true for each unassigned task.\n\n**For CSV and XLSX content types**: A row is returned for each step of the route of the vehicle, except the off-shift breaks, with the flattened task_data keys, followed by the summary of the vehicle in a separate section (CSV) or sheet (XLSX).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/calendar",
+                    "application/json",
+                    "application/geo+json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Vehicle"
+                ],
+                "summary": "Get the schedule for a vehicle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle ID",
+                        "name": "vehicle_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Overview",
+                        "name": "overview",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Geometry",
+                        "name": "geometry",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Geometry format (geojson or polyline)",
+                        "name": "geometry_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/util.ScheduleDB"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            }
+        },
+        "/vehicles/{vehicle_id}/shifts": {
+            "get": {
+                "description": "Get the shifts and the days off of a vehicle, ordered by their start.\n\n**For iCalendar content type**: An event is returned for each shift and day off, with the \"SHIFT\" or \"DAY OFF\" category, along with the \"RECURRING\" category for the shifts created from the shift recurrence of the vehicle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Vehicle Shift"
+                ],
+                "summary": "List vehicle shifts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle ID",
+                        "name": "vehicle_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.VehicleShift"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a shift or a day off in the availability calendar of a vehicle with the input payload (Content-Type = application/json).\n\nWhen the vehicle has shifts, it is available from the start of its first shift to the end of its last shift when the project is scheduled, and an off-shift break is created between two consecutive shifts. A day off (with \"day_off\" = true) removes the shifts which it overlaps, such as the recurring shifts of the vehicle on a holiday.\n\nThe shifts are imported from an iCalendar file with Content-Type = text/calendar, returning all the shifts of the vehicle. The all-day events and the events with the \"DAY OFF\" category are days off, and the other events are shifts. The recurring events are expanded within the planning horizon of the project, or during a year without one. When replace = true, the shifts of the vehicle which are not recurring are replaced.",
+                "consumes": [
+                    "application/json",
+                    "text/calendar"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vehicle Shift"
+                ],
+                "summary": "Create vehicle shifts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vehicle ID",
+                        "name": "vehicle_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Replace the shifts (iCalendar import)",
+                        "name": "replace",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Create vehicle shift",
+                        "name": "VehicleShift",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.CreateVehicleShiftParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.VehicleShift"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhook_id}": {
+            "get": {
+                "description": "Fetch a webhook with its webhook_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Fetch a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.Webhook"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a webhook with its webhook_id, along with its deliveries",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/util.Success"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Update a webhook (partial update) with its webhook_id. The pending deliveries of an inactive webhook are not sent until it is active again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Update a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update webhook",
+                        "name": "Webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/database.UpdateWebhookParams"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/database.Webhook"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhook_id}/deliveries": {
+            "get": {
+                "description": "Get the delivery log of a webhook, latest first.\n\nThe status of a delivery is one of \"pending\", \"succeeded\" or \"failed\". A pending delivery is attempted again at \"next_attempt_at\", and a delivery is marked as failed after 5 attempts. The response code and the error of the last attempt are returned. The succeeded and failed deliveries are deleted 7 days after their last attempt.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "List the deliveries of a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "succeeded",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Status of the deliveries",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/util.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/database.WebhookDelivery"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/util.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/util.NotFound"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "database.Break": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "key1": "value1",
+                        "key2": "value2"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "off_shift": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "service": {
+                    "type": "string",
+                    "example": "00:02:00"
+                },
+                "time_windows": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "updated_at": {
                     "type": "string",
                     "example": "2021-12-01T13:00:00"
                 },
-                "data": {
+                "vehicle_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                }
+            }
+        },
+        "database.ClonedProject": {
+            "type": "object",
+            "properties": {
+                "id_mapping": {
+                    "$ref": "#/definitions/database.IDMapping"
+                },
+                "project": {
+                    "$ref": "#/definitions/database.Project"
+                }
+            }
+        },
+        "database.CreateBreakParams": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "key1": "value1",
+                        "key2": "value2"
+                    }
+                },
+                "service": {
+                    "type": "string",
+                    "example": "00:02:00"
+                },
+                "time_windows": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "database.CreateJobParams": {
+            "type": "object",
+            "required": [
+                "location"
+            ],
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "key1": "value1",
+                        "key2": "value2"
+                    }
+                },
+                "delivery": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        10,
+                        20
+                    ]
+                },
+                "location": {
+                    "$ref": "#/definitions/util.LocationParams"
+                },
+                "locked": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "pickup": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        5,
+                        15
+                    ]
+                },
+                "pinned_vehicle_id": {
+                    "type": "string",
+                    "minimum": 0,
+                    "example": "1234567812345678"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "recurrence": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO,WE"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "00:02:00"
+                },
+                "setup": {
+                    "type": "string",
+                    "example": "00:00:00"
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        5
+                    ]
+                },
+                "time_windows": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "database.CreateProjectParams": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "key1": "value1",
+                        "key2": "value2"
+                    }
+                },
+                "duration_calc": {
+                    "type": "string",
+                    "example": "euclidean"
+                },
+                "exploration_level": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "horizon_end": {
+                    "type": "string",
+                    "example": "2021-12-07"
+                },
+                "horizon_start": {
+                    "type": "string",
+                    "example": "2021-12-01"
+                },
+                "max_shift": {
+                    "type": "string",
+                    "example": "00:30:00"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Sample Project"
+                },
+                "objective": {
+                    "type": "string",
+                    "enum": [
+                        "duration",
+                        "vehicles",
+                        "cost"
+                    ],
+                    "example": "duration"
+                },
+                "timeout": {
+                    "type": "string",
+                    "example": "00:10:00"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "vehicle_cost_per_hour": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3600
+                },
+                "vehicle_fixed_cost": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                }
+            }
+        },
+        "database.CreateShipmentParams": {
+            "type": "object",
+            "required": [
+                "d_location",
+                "p_location"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        5,
+                        15
+                    ]
+                },
+                "d_data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "key1": "value1",
+                        "key2": "value2"
+                    }
+                },
+                "d_location": {
+                    "$ref": "#/definitions/util.LocationParams"
+                },
+                "d_service": {
+                    "type": "string",
+                    "example": "00:02:00"
+                },
+                "d_setup": {
+                    "type": "string",
+                    "example": "00:00:00"
+                },
+                "d_time_windows": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "locked": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "p_data": {
                     "type": "object",
                     "additionalProperties": {
                         "type": "string"
@@ -1740,15 +3939,18 @@ var doc = `{
                         "key2": "value2"
                     }
                 },
-                "id": {
-                    "type": "string",
-                    "example": "1234567812345678"
+                "p_location": {
+                    "$ref": "#/definitions/util.LocationParams"
                 },
-                "service": {
+                "p_service": {
                     "type": "string",
                     "example": "00:02:00"
                 },
-                "time_windows": {
+                "p_setup": {
+                    "type": "string",
+                    "example": "00:00:00"
+                },
+                "p_time_windows": {
                     "type": "array",
                     "items": {
                         "type": "array",
@@ -1757,50 +3959,356 @@ var doc = `{
                         }
                     }
                 },
-                "updated_at": {
+                "pinned_vehicle_id": {
                     "type": "string",
-                    "example": "2021-12-01T13:00:00"
+                    "minimum": 0,
+                    "example": "1234567812345678"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        5
+                    ]
+                }
+            }
+        },
+        "database.CreateVehicleParams": {
+            "type": "object",
+            "required": [
+                "end_location",
+                "start_location"
+            ],
+            "properties": {
+                "capacity": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        50,
+                        25
+                    ]
+                },
+                "cost_per_hour": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3600
+                },
+                "cost_per_km": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "key1": "value1",
+                        "key2": "value2"
+                    }
+                },
+                "end_location": {
+                    "$ref": "#/definitions/util.LocationParams"
+                },
+                "fixed_cost": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5000
+                },
+                "max_distance": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 200000
+                },
+                "max_tasks": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "max_travel_time": {
+                    "type": "string",
+                    "example": "08:00:00"
+                },
+                "shift_recurrence": {
+                    "type": "string",
+                    "example": "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR"
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        5
+                    ]
+                },
+                "speed_factor": {
+                    "type": "number",
+                    "example": 1
+                },
+                "start_location": {
+                    "$ref": "#/definitions/util.LocationParams"
+                },
+                "tw_close": {
+                    "type": "string",
+                    "example": "2021-12-31T23:59:00"
+                },
+                "tw_open": {
+                    "type": "string",
+                    "example": "2021-12-31T23:00:00"
+                },
+                "vehicle_type_id": {
+                    "type": "string",
+                    "minimum": 0,
+                    "example": "1234567812345678"
+                }
+            }
+        },
+        "database.CreateVehicleShiftParams": {
+            "type": "object",
+            "required": [
+                "tw_close",
+                "tw_open"
+            ],
+            "properties": {
+                "day_off": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "tw_close": {
+                    "type": "string",
+                    "example": "2021-12-01T17:00:00"
+                },
+                "tw_open": {
+                    "type": "string",
+                    "example": "2021-12-01T08:00:00"
+                }
+            }
+        },
+        "database.CreateVehicleTypeParams": {
+            "type": "object",
+            "properties": {
+                "breaks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.VehicleTypeBreakParams"
+                    }
+                },
+                "capacity": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        50,
+                        25
+                    ]
+                },
+                "cost_per_hour": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3600
+                },
+                "cost_per_km": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "key1": "value1",
+                        "key2": "value2"
+                    }
+                },
+                "fixed_cost": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5000
+                },
+                "max_distance": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 200000
+                },
+                "max_tasks": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "max_travel_time": {
+                    "type": "string",
+                    "example": "08:00:00"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Van"
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        5
+                    ]
+                },
+                "speed_factor": {
+                    "type": "number",
+                    "example": 1
+                },
+                "tw_close": {
+                    "type": "string",
+                    "example": "2021-12-31T23:59:00"
+                },
+                "tw_open": {
+                    "type": "string",
+                    "example": "2021-12-31T23:00:00"
+                }
+            }
+        },
+        "database.CreateVehiclesOfTypeParams": {
+            "type": "object",
+            "required": [
+                "count",
+                "start_location"
+            ],
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 10
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "key1": "value1",
+                        "key2": "value2"
+                    }
+                },
+                "end_location": {
+                    "$ref": "#/definitions/util.LocationParams"
+                },
+                "start_location": {
+                    "$ref": "#/definitions/util.LocationParams"
+                }
+            }
+        },
+        "database.CreateWebhookParams": {
+            "type": "object",
+            "required": [
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "schedule.created",
+                        "job.status_changed"
+                    ]
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "3f1b6f9e0c2d4a8b"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/webhook"
+                }
+            }
+        },
+        "database.IDMapping": {
+            "type": "object",
+            "properties": {
+                "breaks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "jobs": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "projects": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "shipments": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "vehicle_id": {
-                    "type": "string",
-                    "example": "1234567812345678"
+                "vehicles": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "database.CreateBreakParams": {
+        "database.ImportResult": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    },
-                    "example": {
-                        "key1": "value1",
-                        "key2": "value2"
-                    }
+                "breaks": {
+                    "type": "integer",
+                    "example": 20
                 },
-                "service": {
-                    "type": "string",
-                    "example": "00:02:00"
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "time_windows": {
-                    "type": "array",
-                    "items": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
-                    }
+                "jobs": {
+                    "type": "integer",
+                    "example": 2000
+                },
+                "shipments": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "vehicles": {
+                    "type": "integer",
+                    "example": 20
                 }
             }
         },
-        "database.CreateJobParams": {
+        "database.Job": {
             "type": "object",
-            "required": [
-                "location"
-            ],
             "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
                 "data": {
                     "type": "object",
                     "additionalProperties": {
@@ -1821,9 +4329,25 @@ var doc = `{
                         20
                     ]
                 },
+                "id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
                 "location": {
                     "$ref": "#/definitions/util.LocationParams"
                 },
+                "locked": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "occurrence": {
+                    "type": "string",
+                    "example": "2021-12-01"
+                },
+                "overridden": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "pickup": {
                     "type": "array",
                     "items": {
@@ -1834,10 +4358,26 @@ var doc = `{
                         15
                     ]
                 },
+                "pinned_vehicle_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
                 "priority": {
                     "type": "integer",
                     "example": 10
                 },
+                "project_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "recurrence": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO,WE"
+                },
+                "recurring_job_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
                 "service": {
                     "type": "string",
                     "example": "00:02:00"
@@ -1864,15 +4404,20 @@ var doc = `{
                             "type": "string"
                         }
                     }
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
                 }
             }
         },
-        "database.CreateProjectParams": {
+        "database.Project": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
                 "data": {
                     "type": "object",
                     "additionalProperties": {
@@ -1891,6 +4436,18 @@ var doc = `{
                     "type": "integer",
                     "example": 5
                 },
+                "horizon_end": {
+                    "type": "string",
+                    "example": "2021-12-07"
+                },
+                "horizon_start": {
+                    "type": "string",
+                    "example": "2021-12-01"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
                 "max_shift": {
                     "type": "string",
                     "example": "00:30:00"
@@ -1899,244 +4456,120 @@ var doc = `{
                     "type": "string",
                     "example": "Sample Project"
                 },
+                "objective": {
+                    "type": "string",
+                    "example": "duration"
+                },
                 "timeout": {
                     "type": "string",
                     "example": "00:10:00"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
+                "vehicle_cost_per_hour": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "vehicle_fixed_cost": {
+                    "type": "integer",
+                    "example": 0
                 }
             }
         },
-        "database.CreateShipmentParams": {
+        "database.ProjectSnapshot": {
             "type": "object",
-            "required": [
-                "d_location",
-                "p_location"
-            ],
             "properties": {
-                "amount": {
+                "breaks": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        5,
-                        15
-                    ]
-                },
-                "d_data": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    },
-                    "example": {
-                        "key1": "value1",
-                        "key2": "value2"
+                        "$ref": "#/definitions/database.Break"
                     }
                 },
-                "d_location": {
-                    "$ref": "#/definitions/util.LocationParams"
-                },
-                "d_service": {
-                    "type": "string",
-                    "example": "00:02:00"
-                },
-                "d_setup": {
-                    "type": "string",
-                    "example": "00:00:00"
-                },
-                "d_time_windows": {
+                "jobs": {
                     "type": "array",
                     "items": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
-                    }
-                },
-                "p_data": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    },
-                    "example": {
-                        "key1": "value1",
-                        "key2": "value2"
+                        "$ref": "#/definitions/database.Job"
                     }
                 },
-                "p_location": {
-                    "$ref": "#/definitions/util.LocationParams"
-                },
-                "p_service": {
-                    "type": "string",
-                    "example": "00:02:00"
-                },
-                "p_setup": {
-                    "type": "string",
-                    "example": "00:00:00"
+                "project": {
+                    "$ref": "#/definitions/database.Project"
                 },
-                "p_time_windows": {
+                "schedule": {
                     "type": "array",
                     "items": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
+                        "$ref": "#/definitions/util.ScheduleDB"
                     }
                 },
-                "priority": {
-                    "type": "integer",
-                    "example": 10
-                },
-                "skills": {
+                "shifts": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        1,
-                        5
-                    ]
-                }
-            }
-        },
-        "database.CreateVehicleParams": {
-            "type": "object",
-            "required": [
-                "end_location",
-                "start_location"
-            ],
-            "properties": {
-                "capacity": {
+                        "$ref": "#/definitions/database.VehicleShift"
+                    }
+                },
+                "shipments": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        50,
-                        25
-                    ]
-                },
-                "data": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    },
-                    "example": {
-                        "key1": "value1",
-                        "key2": "value2"
+                        "$ref": "#/definitions/database.Shipment"
                     }
                 },
-                "end_location": {
-                    "$ref": "#/definitions/util.LocationParams"
-                },
-                "max_tasks": {
-                    "type": "integer",
-                    "example": 20
+                "vehicle_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.VehicleType"
+                    }
                 },
-                "skills": {
+                "vehicles": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        1,
-                        5
-                    ]
+                        "$ref": "#/definitions/database.Vehicle"
+                    }
                 },
-                "speed_factor": {
-                    "type": "number",
+                "version": {
+                    "type": "integer",
                     "example": 1
-                },
-                "start_location": {
-                    "$ref": "#/definitions/util.LocationParams"
-                },
-                "tw_close": {
-                    "type": "string",
-                    "example": "2021-12-31T23:59:00"
-                },
-                "tw_open": {
-                    "type": "string",
-                    "example": "2021-12-31T23:00:00"
                 }
             }
         },
-        "database.Job": {
+        "database.ScheduleRun": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string",
                     "example": "2021-12-01T13:00:00"
                 },
-                "data": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    },
-                    "example": {
-                        "key1": "value1",
-                        "key2": "value2"
-                    }
+                "error": {
+                    "type": "string",
+                    "example": "No locations present in the project"
                 },
-                "delivery": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        10,
-                        20
-                    ]
+                "finished_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
+                "fresh": {
+                    "type": "boolean",
+                    "example": false
                 },
                 "id": {
                     "type": "string",
                     "example": "1234567812345678"
                 },
-                "location": {
-                    "$ref": "#/definitions/util.LocationParams"
-                },
-                "pickup": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        5,
-                        15
-                    ]
-                },
-                "priority": {
-                    "type": "integer",
-                    "example": 10
-                },
                 "project_id": {
                     "type": "string",
                     "example": "1234567812345678"
                 },
-                "service": {
+                "started_at": {
                     "type": "string",
-                    "example": "00:02:00"
+                    "example": "2021-12-01T13:00:00"
                 },
-                "setup": {
+                "status": {
                     "type": "string",
-                    "example": "00:00:00"
-                },
-                "skills": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    },
-                    "example": [
-                        1,
-                        5
-                    ]
-                },
-                "time_windows": {
-                    "type": "array",
-                    "items": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
-                    }
+                    "example": "succeeded"
                 },
                 "updated_at": {
                     "type": "string",
@@ -2144,23 +4577,9 @@ var doc = `{
                 }
             }
         },
-        "database.Project": {
+        "database.ScheduleSettings": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string",
-                    "example": "2021-12-01T13:00:00"
-                },
-                "data": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    },
-                    "example": {
-                        "key1": "value1",
-                        "key2": "value2"
-                    }
-                },
                 "duration_calc": {
                     "type": "string",
                     "example": "euclidean"
@@ -2169,25 +4588,64 @@ var doc = `{
                     "type": "integer",
                     "example": 5
                 },
-                "id": {
-                    "type": "string",
-                    "example": "1234567812345678"
-                },
                 "max_shift": {
                     "type": "string",
                     "example": "00:30:00"
                 },
-                "name": {
+                "objective": {
                     "type": "string",
-                    "example": "Sample Project"
+                    "example": "duration"
                 },
                 "timeout": {
                     "type": "string",
                     "example": "00:10:00"
                 },
+                "vehicle_cost_per_hour": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "vehicle_fixed_cost": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "database.ScheduleVersion": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
+                "fresh": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "inputs_hash": {
+                    "type": "string",
+                    "example": "9e107d9d372bb6826bd81d3542a419d6"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/util.MetadataResponse"
+                },
+                "project_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "settings": {
+                    "$ref": "#/definitions/database.ScheduleSettings"
+                },
                 "updated_at": {
                     "type": "string",
                     "example": "2021-12-01T13:00:00"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
@@ -2242,6 +4700,10 @@ var doc = `{
                     "type": "string",
                     "example": "1234567812345678"
                 },
+                "locked": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "p_data": {
                     "type": "object",
                     "additionalProperties": {
@@ -2272,6 +4734,10 @@ var doc = `{
                         }
                     }
                 },
+                "pinned_vehicle_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
                 "priority": {
                     "type": "integer",
                     "example": 10
@@ -2322,6 +4788,10 @@ var doc = `{
                 "location": {
                     "$ref": "#/definitions/util.LocationParams"
                 },
+                "locked": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "pickup": {
                     "type": "array",
                     "items": {
@@ -2332,10 +4802,19 @@ var doc = `{
                         15
                     ]
                 },
+                "pinned_vehicle_id": {
+                    "type": "string",
+                    "minimum": 0,
+                    "example": "1234567812345678"
+                },
                 "priority": {
                     "type": "integer",
                     "example": 10
                 },
+                "recurrence": {
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO,WE"
+                },
                 "service": {
                     "type": "string",
                     "example": "00:02:00"
@@ -2408,6 +4887,10 @@ var doc = `{
                         }
                     }
                 },
+                "locked": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "p_data": {
                     "type": "object",
                     "additionalProperties": {
@@ -2438,23 +4921,248 @@ var doc = `{
                         }
                     }
                 },
-                "priority": {
-                    "type": "integer",
-                    "example": 10
+                "pinned_vehicle_id": {
+                    "type": "string",
+                    "minimum": 0,
+                    "example": "1234567812345678"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        5
+                    ]
+                }
+            }
+        },
+        "database.UpdateVehicleParams": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        50,
+                        25
+                    ]
+                },
+                "cost_per_hour": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3600
+                },
+                "cost_per_km": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "key1": "value1",
+                        "key2": "value2"
+                    }
+                },
+                "end_location": {
+                    "$ref": "#/definitions/util.LocationParams"
+                },
+                "fixed_cost": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5000
+                },
+                "max_distance": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 200000
+                },
+                "max_tasks": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "max_travel_time": {
+                    "type": "string",
+                    "example": "08:00:00"
+                },
+                "shift_recurrence": {
+                    "type": "string",
+                    "example": "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR"
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        5
+                    ]
+                },
+                "speed_factor": {
+                    "type": "number",
+                    "example": 1
+                },
+                "start_location": {
+                    "$ref": "#/definitions/util.LocationParams"
+                },
+                "tw_close": {
+                    "type": "string",
+                    "example": "2021-12-31T23:59:00"
+                },
+                "tw_open": {
+                    "type": "string",
+                    "example": "2021-12-31T23:00:00"
+                },
+                "vehicle_type_id": {
+                    "type": "string",
+                    "minimum": 0,
+                    "example": "1234567812345678"
+                }
+            }
+        },
+        "database.UpdateVehicleShiftParams": {
+            "type": "object",
+            "properties": {
+                "day_off": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "tw_close": {
+                    "type": "string",
+                    "example": "2021-12-01T17:00:00"
+                },
+                "tw_open": {
+                    "type": "string",
+                    "example": "2021-12-01T08:00:00"
+                }
+            }
+        },
+        "database.UpdateVehicleTypeParams": {
+            "type": "object",
+            "properties": {
+                "breaks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.VehicleTypeBreakParams"
+                    }
+                },
+                "capacity": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        50,
+                        25
+                    ]
+                },
+                "cost_per_hour": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3600
+                },
+                "cost_per_km": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 100
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "key1": "value1",
+                        "key2": "value2"
+                    }
+                },
+                "fixed_cost": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5000
+                },
+                "max_distance": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 200000
+                },
+                "max_tasks": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "max_travel_time": {
+                    "type": "string",
+                    "example": "08:00:00"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Van"
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        5
+                    ]
+                },
+                "speed_factor": {
+                    "type": "number",
+                    "example": 1
+                },
+                "tw_close": {
+                    "type": "string",
+                    "example": "2021-12-31T23:59:00"
                 },
-                "skills": {
+                "tw_open": {
+                    "type": "string",
+                    "example": "2021-12-31T23:00:00"
+                }
+            }
+        },
+        "database.UpdateWebhookParams": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "events": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
                     },
                     "example": [
-                        1,
-                        5
+                        "schedule.created",
+                        "job.status_changed"
                     ]
+                },
+                "secret": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "3f1b6f9e0c2d4a8b"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/webhook"
                 }
             }
         },
-        "database.UpdateVehicleParams": {
+        "database.Vehicle": {
             "type": "object",
             "properties": {
                 "capacity": {
@@ -2467,6 +5175,18 @@ var doc = `{
                         25
                     ]
                 },
+                "cost_per_hour": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "cost_per_km": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
                 "data": {
                     "type": "object",
                     "additionalProperties": {
@@ -2480,10 +5200,34 @@ var doc = `{
                 "end_location": {
                     "$ref": "#/definitions/util.LocationParams"
                 },
+                "fixed_cost": {
+                    "type": "integer",
+                    "example": 5000
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "max_distance": {
+                    "type": "integer",
+                    "example": 200000
+                },
                 "max_tasks": {
                     "type": "integer",
                     "example": 20
                 },
+                "max_travel_time": {
+                    "type": "string",
+                    "example": "08:00:00"
+                },
+                "project_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "shift_recurrence": {
+                    "type": "string",
+                    "example": "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR"
+                },
                 "skills": {
                     "type": "array",
                     "items": {
@@ -2508,12 +5252,63 @@ var doc = `{
                 "tw_open": {
                     "type": "string",
                     "example": "2021-12-31T23:00:00"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
+                "vehicle_type_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
                 }
             }
         },
-        "database.Vehicle": {
+        "database.VehicleShift": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
+                "day_off": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "recurring": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "tw_close": {
+                    "type": "string",
+                    "example": "2021-12-01T17:00:00"
+                },
+                "tw_open": {
+                    "type": "string",
+                    "example": "2021-12-01T08:00:00"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
+                "vehicle_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                }
+            }
+        },
+        "database.VehicleType": {
             "type": "object",
             "properties": {
+                "breaks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/database.VehicleTypeBreak"
+                    }
+                },
                 "capacity": {
                     "type": "array",
                     "items": {
@@ -2524,6 +5319,14 @@ var doc = `{
                         25
                     ]
                 },
+                "cost_per_hour": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "cost_per_km": {
+                    "type": "integer",
+                    "example": 100
+                },
                 "created_at": {
                     "type": "string",
                     "example": "2021-12-01T13:00:00"
@@ -2538,17 +5341,30 @@ var doc = `{
                         "key2": "value2"
                     }
                 },
-                "end_location": {
-                    "$ref": "#/definitions/util.LocationParams"
+                "fixed_cost": {
+                    "type": "integer",
+                    "example": 5000
                 },
                 "id": {
                     "type": "string",
                     "example": "1234567812345678"
                 },
+                "max_distance": {
+                    "type": "integer",
+                    "example": 200000
+                },
                 "max_tasks": {
                     "type": "integer",
                     "example": 20
                 },
+                "max_travel_time": {
+                    "type": "string",
+                    "example": "08:00:00"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Van"
+                },
                 "project_id": {
                     "type": "string",
                     "example": "1234567812345678"
@@ -2567,9 +5383,6 @@ var doc = `{
                     "type": "number",
                     "example": 1
                 },
-                "start_location": {
-                    "$ref": "#/definitions/util.LocationParams"
-                },
                 "tw_close": {
                     "type": "string",
                     "example": "2021-12-31T23:59:00"
@@ -2584,6 +5397,156 @@ var doc = `{
                 }
             }
         },
+        "database.VehicleTypeBreak": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "key1": "value1",
+                        "key2": "value2"
+                    }
+                },
+                "service": {
+                    "type": "string",
+                    "example": "00:30:00"
+                },
+                "time_windows": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "database.VehicleTypeBreakParams": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "key1": "value1",
+                        "key2": "value2"
+                    }
+                },
+                "service": {
+                    "type": "string",
+                    "example": "00:30:00"
+                },
+                "time_windows": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "database.Webhook": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "schedule.created",
+                        "job.status_changed"
+                    ]
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "project_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/webhook"
+                }
+            }
+        },
+        "database.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Unexpected response status code 500"
+                },
+                "event": {
+                    "type": "string",
+                    "example": "job.status_changed"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "next_attempt_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:30"
+                },
+                "payload": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "event": "job.status_changed",
+                        "project_id": "1234567812345678"
+                    }
+                },
+                "response_code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "status": {
+                    "type": "string",
+                    "example": "succeeded"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2021-12-01T13:00:00"
+                },
+                "webhook_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                }
+            }
+        },
         "util.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -2633,6 +5596,14 @@ var doc = `{
                         "$ref": "#/definitions/util.ScheduleSummary"
                     }
                 },
+                "total_cost": {
+                    "type": "integer",
+                    "example": 12600
+                },
+                "total_distance": {
+                    "type": "integer",
+                    "example": 32400
+                },
                 "total_service": {
                     "type": "string",
                     "example": "00:10:00"
@@ -2664,9 +5635,37 @@ var doc = `{
                     "type": "string",
                     "example": "404"
                 },
-                "error": {
-                    "type": "string",
-                    "example": "Not Found"
+                "error": {
+                    "type": "string",
+                    "example": "Not Found"
+                }
+            }
+        },
+        "util.ScheduleComparison": {
+            "type": "object",
+            "properties": {
+                "other_project_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "project_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/util.TaskComparison"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/util.ScheduleDelta"
+                },
+                "vehicles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/util.VehicleComparison"
+                    }
                 }
             }
         },
@@ -2685,6 +5684,10 @@ var doc = `{
                     "type": "string",
                     "example": "2021-12-01T13:00:00"
                 },
+                "distance": {
+                    "type": "integer",
+                    "example": 9000
+                },
                 "load": {
                     "type": "array",
                     "items": {
@@ -2789,9 +5792,134 @@ var doc = `{
                 }
             }
         },
+        "util.ScheduleDelta": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "$ref": "#/definitions/util.ScheduleTotals"
+                },
+                "delta": {
+                    "$ref": "#/definitions/util.ScheduleTotals"
+                },
+                "other": {
+                    "$ref": "#/definitions/util.ScheduleTotals"
+                }
+            }
+        },
+        "util.ScheduleEditData": {
+            "type": "object",
+            "properties": {
+                "metadata": {
+                    "$ref": "#/definitions/util.MetadataResponse"
+                },
+                "project_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "schedule": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/util.ScheduleResponse"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Job 1234567812345678 cannot be served within its time windows"
+                    ]
+                }
+            }
+        },
+        "util.ScheduleEditParams": {
+            "type": "object",
+            "required": [
+                "operations"
+            ],
+            "properties": {
+                "force": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "operations": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/util.ScheduleOperation"
+                    }
+                }
+            }
+        },
+        "util.ScheduleOperation": {
+            "type": "object",
+            "required": [
+                "op"
+            ],
+            "properties": {
+                "op": {
+                    "type": "string",
+                    "enum": [
+                        "move",
+                        "insert",
+                        "remove",
+                        "swap",
+                        "reorder"
+                    ],
+                    "example": "move"
+                },
+                "other_task_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "other_type": {
+                    "type": "string",
+                    "enum": [
+                        "job",
+                        "pickup",
+                        "delivery",
+                        "break"
+                    ],
+                    "example": "job"
+                },
+                "position": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 0
+                },
+                "stops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/util.ScheduleStop"
+                    }
+                },
+                "task_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "job",
+                        "pickup",
+                        "delivery",
+                        "break"
+                    ],
+                    "example": "job"
+                },
+                "vehicle_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                }
+            }
+        },
         "util.ScheduleResponse": {
             "type": "object",
             "properties": {
+                "geometry": {
+                    "type": "object"
+                },
                 "route": {
                     "type": "array",
                     "items": {
@@ -2825,10 +5953,18 @@ var doc = `{
                     "type": "string",
                     "example": "2021-12-01T13:00:00"
                 },
+                "cumulative_distance": {
+                    "type": "integer",
+                    "example": 18000
+                },
                 "departure": {
                     "type": "string",
                     "example": "2021-12-01T13:00:00"
                 },
+                "distance": {
+                    "type": "integer",
+                    "example": 9000
+                },
                 "load": {
                     "type": "array",
                     "items": {
@@ -2882,9 +6018,54 @@ var doc = `{
                 }
             }
         },
+        "util.ScheduleRouteParams": {
+            "type": "object",
+            "required": [
+                "vehicle_id"
+            ],
+            "properties": {
+                "stops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/util.ScheduleStop"
+                    }
+                },
+                "vehicle_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                }
+            }
+        },
+        "util.ScheduleStop": {
+            "type": "object",
+            "required": [
+                "task_id",
+                "type"
+            ],
+            "properties": {
+                "task_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "job",
+                        "pickup",
+                        "delivery",
+                        "break"
+                    ],
+                    "example": "job"
+                }
+            }
+        },
         "util.ScheduleSummary": {
             "type": "object",
             "properties": {
+                "cost": {
+                    "type": "integer",
+                    "example": 6600
+                },
                 "service_time": {
                     "type": "string",
                     "example": "00:02:00"
@@ -2893,6 +6074,10 @@ var doc = `{
                     "type": "string",
                     "example": "00:00:00"
                 },
+                "total_distance": {
+                    "type": "integer",
+                    "example": 18000
+                },
                 "travel_time": {
                     "type": "string",
                     "example": "00:16:40"
@@ -2917,12 +6102,50 @@ var doc = `{
                 }
             }
         },
+        "util.ScheduleTotals": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "integer",
+                    "example": 12600
+                },
+                "distance": {
+                    "type": "integer",
+                    "example": 32400
+                },
+                "service_time": {
+                    "type": "string",
+                    "example": "00:10:00"
+                },
+                "setup_time": {
+                    "type": "string",
+                    "example": "00:05:00"
+                },
+                "travel_time": {
+                    "type": "string",
+                    "example": "01:00:00"
+                },
+                "waiting_time": {
+                    "type": "string",
+                    "example": "00:30:00"
+                }
+            }
+        },
         "util.ScheduleUnassigned": {
             "type": "object",
             "properties": {
                 "location": {
                     "$ref": "#/definitions/util.LocationParams"
                 },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "No vehicle has the skills [3]"
+                    ]
+                },
                 "task_data": {
                     "type": "object",
                     "additionalProperties": {
@@ -2943,6 +6166,77 @@ var doc = `{
                 }
             }
         },
+        "util.ScheduleValidateParams": {
+            "type": "object",
+            "required": [
+                "routes"
+            ],
+            "properties": {
+                "routes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/util.ScheduleRouteParams"
+                    }
+                }
+            }
+        },
+        "util.ScheduleValidation": {
+            "type": "object",
+            "properties": {
+                "feasible": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "metadata": {
+                    "$ref": "#/definitions/util.MetadataResponse"
+                },
+                "project_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "schedule": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/util.ScheduleResponse"
+                    }
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/util.ScheduleViolation"
+                    }
+                }
+            }
+        },
+        "util.ScheduleViolation": {
+            "type": "object",
+            "properties": {
+                "dimension": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Job 1234567812345678 cannot be served within its time windows by vehicle 1234567812345678"
+                },
+                "task_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "task_type": {
+                    "type": "string",
+                    "example": "job"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "time_window"
+                },
+                "vehicle_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                }
+            }
+        },
         "util.Success": {
             "type": "object",
             "properties": {
@@ -2969,6 +6263,74 @@ var doc = `{
                     "example": "OK"
                 }
             }
+        },
+        "util.TaskComparison": {
+            "type": "object",
+            "properties": {
+                "arrival_shift": {
+                    "type": "string",
+                    "example": "-00:10:00"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "moved"
+                    ]
+                },
+                "other_sequence": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "other_task_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "other_vehicle_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "sequence": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "task_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "job"
+                },
+                "vehicle_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                }
+            }
+        },
+        "util.VehicleComparison": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "$ref": "#/definitions/util.ScheduleTotals"
+                },
+                "delta": {
+                    "$ref": "#/definitions/util.ScheduleTotals"
+                },
+                "other": {
+                    "$ref": "#/definitions/util.ScheduleTotals"
+                },
+                "other_vehicle_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                },
+                "vehicle_id": {
+                    "type": "string",
+                    "example": "1234567812345678"
+                }
+            }
         }
     }
 }`
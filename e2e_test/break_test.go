@@ -63,6 +63,7 @@ func TestCreateBreak(t *testing.T) {
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
 					"service":      "00:00:00",
+					"off_shift":    false,
 					"vehicle_id":   "2550908592071787332",
 					"data":         map[string]interface{}{},
 					"time_windows": []interface{}{},
@@ -81,6 +82,7 @@ func TestCreateBreak(t *testing.T) {
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
 					"service":      "00:01:40",
+					"off_shift":    false,
 					"vehicle_id":   "2550908592071787332",
 					"data":         map[string]interface{}{},
 					"time_windows": []interface{}{},
@@ -112,6 +114,7 @@ func TestCreateBreak(t *testing.T) {
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
 					"service":      "00:00:00",
+					"off_shift":    false,
 					"vehicle_id":   "2550908592071787332",
 					"data":         map[string]interface{}{"key": "value"},
 					"time_windows": []interface{}{},
@@ -144,6 +147,7 @@ func TestCreateBreak(t *testing.T) {
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
 					"service":      "00:03:35",
+					"off_shift":    false,
 					"vehicle_id":   "2550908592071787332",
 					"data":         map[string]interface{}{"key": "value"},
 					"time_windows": []interface{}{},
@@ -224,6 +228,7 @@ func TestListBreaks(t *testing.T) {
 					map[string]interface{}{
 						"id":           "4668767710686035977",
 						"service":      "00:00:01",
+						"off_shift":    false,
 						"vehicle_id":   "2550908592071787332",
 						"data":         map[string]interface{}{"key": "value"},
 						"created_at":   "2021-10-26T21:24:38",
@@ -233,6 +238,7 @@ func TestListBreaks(t *testing.T) {
 					map[string]interface{}{
 						"id":         "3990300682121424906",
 						"service":    "00:05:24",
+						"off_shift":  false,
 						"vehicle_id": "2550908592071787332",
 						"data":       map[string]interface{}{"s": float64(1)},
 						"created_at": "2021-10-26T21:24:52",
@@ -320,6 +326,7 @@ func TestGetBreak(t *testing.T) {
 				"data": map[string]interface{}{
 					"id":           "4668767710686035977",
 					"service":      "00:00:01",
+					"off_shift":    false,
 					"vehicle_id":   "2550908592071787332",
 					"data":         map[string]interface{}{"key": "value"},
 					"created_at":   "2021-10-26T21:24:38",
@@ -381,6 +388,7 @@ func TestUpdateBreak(t *testing.T) {
 				"data": map[string]interface{}{
 					"id":           "4668767710686035977",
 					"service":      "00:00:01",
+					"off_shift":    false,
 					"vehicle_id":   "2550908592071787332",
 					"data":         map[string]interface{}{"key": "value"},
 					"created_at":   "2021-10-26T21:24:38",
@@ -411,6 +419,7 @@ func TestUpdateBreak(t *testing.T) {
 				"data": map[string]interface{}{
 					"id":           "4668767710686035977",
 					"service":      "00:01:40",
+					"off_shift":    false,
 					"vehicle_id":   "2550908592071787332",
 					"data":         map[string]interface{}{"key": "value"},
 					"created_at":   "2021-10-26T21:24:38",
@@ -444,6 +453,7 @@ func TestUpdateBreak(t *testing.T) {
 				"data": map[string]interface{}{
 					"id":           "4668767710686035977",
 					"service":      "00:01:40",
+					"off_shift":    false,
 					"vehicle_id":   "2550908592071787332",
 					"data":         map[string]interface{}{},
 					"created_at":   "2021-10-26T21:24:38",
@@ -459,6 +469,7 @@ func TestUpdateBreak(t *testing.T) {
 			breakID:    4668767710686035977,
 			body: map[string]interface{}{
 				"service":    "00:01:41",
+				"off_shift":  false,
 				"vehicle_id": "2550908592071787332",
 				"data":       map[string]interface{}{"s": 1},
 			},
@@ -466,6 +477,7 @@ func TestUpdateBreak(t *testing.T) {
 				"data": map[string]interface{}{
 					"id":           "4668767710686035977",
 					"service":      "00:01:41",
+					"off_shift":    false,
 					"vehicle_id":   "2550908592071787332",
 					"data":         map[string]interface{}{"s": float64(1)},
 					"created_at":   "2021-10-26T21:24:38",
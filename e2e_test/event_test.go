@@ -0,0 +1,239 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: event_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package e2etest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamEvent is an event read from a Server-Sent Events stream
+type streamEvent struct {
+	ID    string
+	Event string
+	Data  map[string]interface{}
+}
+
+// openEventStream opens the event stream at the URL, returning a channel with the events read from the stream,
+// along with the status code of the response. The stream is closed when the test ends.
+func openEventStream(t *testing.T, url string) (chan streamEvent, int) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	require.NoError(t, err)
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	events := make(chan streamEvent, 100)
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return events, response.StatusCode
+	}
+
+	go func() {
+		defer response.Body.Close()
+		scanner := bufio.NewScanner(response.Body)
+		event := streamEvent{}
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				event.ID = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				event.Event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event.Data)
+			case line == "" && event.Event != "":
+				events <- event
+				event = streamEvent{}
+			}
+		}
+	}()
+	return events, response.StatusCode
+}
+
+// waitEvent returns the first event of the stream with the given name, skipping the other events, or the next event
+// when the name is empty
+func waitEvent(t *testing.T, events chan streamEvent, name string) streamEvent {
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case event := <-events:
+			if name == "" || event.Event == name {
+				return event
+			}
+		case <-timeout:
+			require.FailNow(t, "Event not received", name)
+		}
+	}
+}
+
+func TestProjectEvents(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+	httpServer := httptest.NewServer(mux)
+	defer httpServer.Close()
+
+	send := func(t *testing.T, method string, url string, body interface{}) map[string]interface{} {
+		statusCode, m := sendJSON(t, mux, method, url, body)
+		require.Less(t, statusCode, 300, m)
+		return m
+	}
+	location := map[string]interface{}{"latitude": 2.0, "longitude": 3.0}
+
+	testCases := []struct {
+		name       string
+		url        func(projectID string) string
+		statusCode int
+		check      func(t *testing.T, projectID string, events chan streamEvent)
+	}{
+		{
+			name:       "Stream of a missing project",
+			url:        func(projectID string) string { return "/projects/123/events" },
+			statusCode: 404,
+		},
+		{
+			name:       "Invalid events",
+			url:        func(projectID string) string { return fmt.Sprintf("/projects/%s/events?events=job.created", projectID) },
+			statusCode: 400,
+		},
+		{
+			name:       "Created tasks",
+			url:        func(projectID string) string { return fmt.Sprintf("/projects/%s/events", projectID) },
+			statusCode: 200,
+			check: func(t *testing.T, projectID string, events chan streamEvent) {
+				m := send(t, "POST", fmt.Sprintf("/projects/%s/jobs", projectID), map[string]interface{}{"location": location})
+				event := waitEvent(t, events, "task.created")
+				assert.NotEmpty(t, event.ID)
+				assert.Equal(t, event.ID, event.Data["id"])
+				assert.Equal(t, projectID, event.Data["project_id"])
+				assert.Equal(t, map[string]interface{}{
+					"task_id": m["data"].(map[string]interface{})["id"],
+					"type":    "job",
+				}, event.Data["data"])
+			},
+		},
+		{
+			name:       "Changes made by the other servers sharing the database",
+			url:        func(projectID string) string { return fmt.Sprintf("/projects/%s/events", projectID) },
+			statusCode: 200,
+			check: func(t *testing.T, projectID string, events chan streamEvent) {
+				_, err := conn.Exec(context.Background(), "UPDATE jobs SET priority = 5 WHERE project_id = $1", projectID)
+				require.NoError(t, err)
+				event := waitEvent(t, events, "task.updated")
+				assert.Equal(t, "job", event.Data["data"].(map[string]interface{})["type"])
+			},
+		},
+		{
+			name:       "Schedule events",
+			url:        func(projectID string) string { return fmt.Sprintf("/projects/%s/events", projectID) },
+			statusCode: 200,
+			check: func(t *testing.T, projectID string, events chan streamEvent) {
+				send(t, "POST", fmt.Sprintf("/projects/%s/schedule", projectID), nil)
+				waitEvent(t, events, "job.status_changed")
+				waitEvent(t, events, "schedule.created")
+
+				// Scheduling again only sends the status changes of the tasks with a different status at the end
+				send(t, "POST", fmt.Sprintf("/projects/%s/schedule", projectID), nil)
+				statusChanges := map[interface{}]int{}
+				for event := waitEvent(t, events, ""); event.Event != "schedule.created"; event = waitEvent(t, events, "") {
+					assert.NotEqual(t, "task.updated", event.Event)
+					data := event.Data["data"].(map[string]interface{})
+					assert.NotEqual(t, data["previous_status"], data["status"])
+					statusChanges[data["task_id"]]++
+				}
+				for taskID, count := range statusChanges {
+					assert.Equal(t, 1, count, taskID)
+				}
+
+				send(t, "DELETE", fmt.Sprintf("/projects/%s/schedule", projectID), nil)
+				waitEvent(t, events, "schedule.deleted")
+			},
+		},
+		{
+			name: "Filter of the events",
+			url: func(projectID string) string {
+				return fmt.Sprintf("/projects/%s/events?events=schedule.created,schedule.deleted", projectID)
+			},
+			statusCode: 200,
+			check: func(t *testing.T, projectID string, events chan streamEvent) {
+				send(t, "POST", fmt.Sprintf("/projects/%s/schedule", projectID), nil)
+				send(t, "POST", fmt.Sprintf("/projects/%s/schedule", projectID), nil)
+				send(t, "DELETE", fmt.Sprintf("/projects/%s/schedule", projectID), nil)
+
+				// Only the schedule events are streamed with the events filter
+				assert.Equal(t, "schedule.created", waitEvent(t, events, "").Event)
+				assert.Equal(t, "schedule.created", waitEvent(t, events, "").Event)
+				assert.Equal(t, "schedule.deleted", waitEvent(t, events, "").Event)
+				assert.Equal(t, 0, len(events))
+			},
+		},
+		{
+			name:       "Events of the other projects",
+			url:        func(projectID string) string { return fmt.Sprintf("/projects/%s/events", projectID) },
+			statusCode: 200,
+			check: func(t *testing.T, projectID string, events chan streamEvent) {
+				_, err := conn.Exec(context.Background(), "SELECT create_event(123, 'schedule.deleted', '{}')")
+				require.NoError(t, err)
+				_, err = conn.Exec(context.Background(), "SELECT create_event($1, 'schedule.deleted', '{}')", projectID)
+				require.NoError(t, err)
+				event := waitEvent(t, events, "schedule.deleted")
+				assert.Equal(t, projectID, event.Data["project_id"])
+				assert.Equal(t, 0, len(events))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Each case streams the events of its own project with a vehicle and a job
+			project := createRow(t, mux, "/projects", map[string]interface{}{"name": tc.name, "duration_calc": "euclidean"})
+			projectID := project["id"].(string)
+			send(t, "POST", fmt.Sprintf("/projects/%s/vehicles", projectID), map[string]interface{}{"start_location": location, "end_location": location})
+			send(t, "POST", fmt.Sprintf("/projects/%s/jobs", projectID), map[string]interface{}{"location": location})
+
+			events, statusCode := openEventStream(t, httpServer.URL+tc.url(projectID))
+			assert.Equal(t, tc.statusCode, statusCode)
+			if tc.check != nil {
+				tc.check(t, projectID, events)
+			}
+		})
+	}
+}
@@ -0,0 +1,326 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: import_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package e2etest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countProjectRows returns the number of jobs, shipments, vehicles, breaks and time windows of a project
+func countProjectRows(t *testing.T, conn *pgxpool.Pool, projectID int64) map[string]int {
+	sql := `
+	SELECT
+		(SELECT count(*) FROM jobs WHERE project_id = $1),
+		(SELECT count(*) FROM shipments WHERE project_id = $1),
+		(SELECT count(*) FROM vehicles WHERE project_id = $1),
+		(SELECT count(*) FROM breaks JOIN vehicles ON breaks.vehicle_id = vehicles.id WHERE project_id = $1),
+		(SELECT count(*) FROM jobs_time_windows JOIN jobs USING(id) WHERE project_id = $1) +
+		(SELECT count(*) FROM shipments_time_windows JOIN shipments USING(id) WHERE project_id = $1) +
+		(SELECT count(*) FROM breaks_time_windows JOIN breaks USING(id) JOIN vehicles ON breaks.vehicle_id = vehicles.id WHERE project_id = $1)`
+
+	var jobs, shipments, vehicles, breaks, timeWindows int
+	err := conn.QueryRow(context.Background(), sql, projectID).Scan(&jobs, &shipments, &vehicles, &breaks, &timeWindows)
+	require.NoError(t, err)
+	return map[string]int{
+		"jobs":         jobs,
+		"shipments":    shipments,
+		"vehicles":     vehicles,
+		"breaks":       breaks,
+		"time_windows": timeWindows,
+	}
+}
+
+func TestImportProject(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	validRows := []interface{}{
+		map[string]interface{}{
+			"type":         "job",
+			"location":     map[string]interface{}{"latitude": 48.6113, "longitude": 2.0365},
+			"service":      "00:02:00",
+			"delivery":     []interface{}{10, 20},
+			"time_windows": []interface{}{[]interface{}{"2021-12-31T23:00:00", "2021-12-31T23:59:00"}},
+		},
+		map[string]interface{}{
+			"type":           "shipment",
+			"p_location":     map[string]interface{}{"latitude": 48.6113, "longitude": 2.0365},
+			"d_location":     map[string]interface{}{"latitude": 48.7113, "longitude": 2.1365},
+			"amount":         []interface{}{5},
+			"d_time_windows": []interface{}{[]interface{}{"2021-12-31T23:00:00", "2021-12-31T23:59:00"}},
+		},
+		map[string]interface{}{
+			"type":         "break",
+			"vehicle_ref":  "v1",
+			"service":      "00:10:00",
+			"time_windows": []interface{}{[]interface{}{"2021-12-31T23:00:00", "2021-12-31T23:59:00"}},
+		},
+		map[string]interface{}{
+			"type":           "vehicle",
+			"ref":            "v1",
+			"start_location": map[string]interface{}{"latitude": 48.6113, "longitude": 2.0365},
+			"end_location":   map[string]interface{}{"latitude": 48.6113, "longitude": 2.0365},
+			"capacity":       []interface{}{50},
+		},
+	}
+
+	validCSV := "type,ref,vehicle_ref,location.latitude,location.longitude,start_location.latitude,start_location.longitude,end_location.latitude,end_location.longitude,service,delivery,time_windows\n" +
+		"vehicle,v1,,,,48.6113,2.0365,48.6113,2.0365,,,\n" +
+		"job,,,48.6113,2.0365,,,,,00:02:00,\"[10]\",\"[[\"\"2021-12-31T23:00:00\"\",\"\"2021-12-31T23:59:00\"\"]]\"\n" +
+		"job,,,48.7113,2.1365,,,,,00:03:00,\"[20]\",\n" +
+		"break,,v1,,,,,,,00:10:00,,\n"
+
+	testCases := []struct {
+		name        string
+		statusCode  int
+		projectID   string
+		dryRun      bool
+		contentType string
+		body        interface{}
+		resBody     map[string]interface{}
+		counts      map[string]int
+	}{
+		{
+			name:       "Invalid ID",
+			statusCode: 404,
+			projectID:  "100",
+			body:       validRows,
+			resBody: map[string]interface{}{
+				"error": "Not Found",
+				"code":  "404",
+			},
+		},
+		{
+			name:       "No rows",
+			statusCode: 400,
+			projectID:  "8943284028902589305",
+			body:       []interface{}{},
+			resBody: map[string]interface{}{
+				"code":    "400",
+				"message": "Bad Request",
+				"errors":  []interface{}{"No rows to import"},
+			},
+		},
+		{
+			name:       "Not an array",
+			statusCode: 400,
+			projectID:  "8943284028902589305",
+			body:       map[string]interface{}{"type": "job"},
+			resBody: map[string]interface{}{
+				"code":    "400",
+				"message": "Bad Request",
+				"errors":  []interface{}{"Request body must be a JSON array of objects"},
+			},
+		},
+		{
+			name:       "Invalid rows",
+			statusCode: 400,
+			projectID:  "8943284028902589305",
+			body: []interface{}{
+				map[string]interface{}{"type": "job"},
+				map[string]interface{}{"type": "unknown"},
+				map[string]interface{}{"type": "break", "vehicle_ref": "v2"},
+				map[string]interface{}{"type": "break", "vehicle_id": "7300272137290532980"},
+				map[string]interface{}{"type": "vehicle", "capacity": "50"},
+			},
+			resBody: map[string]interface{}{
+				"code":    "400",
+				"message": "Bad Request",
+				"errors": []interface{}{
+					"Row 1: Field 'location' of type 'util.LocationParams' is required",
+					"Row 2: Field 'type' must be one out of job, shipment, vehicle, break",
+					"Row 3: Field 'vehicle_ref' must be the 'ref' of an imported vehicle",
+					"Row 4: Vehicle with the given 'vehicle_id' does not exist in the project",
+					"Row 5: Field 'capacity' must be of '[]int64' type.",
+				},
+			},
+			counts: map[string]int{"jobs": 0, "shipments": 0, "vehicles": 0, "breaks": 0, "time_windows": 0},
+		},
+		{
+			name:       "Invalid time window, rolled back",
+			statusCode: 400,
+			projectID:  "8943284028902589305",
+			body: []interface{}{
+				validRows[0],
+				map[string]interface{}{
+					"type":         "job",
+					"location":     map[string]interface{}{"latitude": 48.6113, "longitude": 2.0365},
+					"time_windows": []interface{}{[]interface{}{"2021-12-31T23:59:00", "2021-12-31T23:00:00"}},
+				},
+			},
+			resBody: map[string]interface{}{
+				"code":    "400",
+				"message": "Bad Request",
+				"errors":  []interface{}{"Row 2: Field 'tw_open' must be less than or equal to field 'tw_close'"},
+			},
+			counts: map[string]int{"jobs": 0, "shipments": 0, "vehicles": 0, "breaks": 0, "time_windows": 0},
+		},
+		{
+			name:       "Dry run",
+			statusCode: 200,
+			projectID:  "8943284028902589305",
+			dryRun:     true,
+			body:       validRows,
+			resBody: map[string]interface{}{
+				"code":    "200",
+				"message": "OK",
+				"data": map[string]interface{}{
+					"jobs":      float64(1),
+					"shipments": float64(1),
+					"vehicles":  float64(1),
+					"breaks":    float64(1),
+					"dry_run":   true,
+				},
+			},
+			counts: map[string]int{"jobs": 0, "shipments": 0, "vehicles": 0, "breaks": 0, "time_windows": 0},
+		},
+		{
+			name:       "JSON rows",
+			statusCode: 201,
+			projectID:  "8943284028902589305",
+			body:       validRows,
+			resBody: map[string]interface{}{
+				"code":    "201",
+				"message": "Created",
+				"data": map[string]interface{}{
+					"jobs":      float64(1),
+					"shipments": float64(1),
+					"vehicles":  float64(1),
+					"breaks":    float64(1),
+					"dry_run":   false,
+				},
+			},
+			counts: map[string]int{"jobs": 1, "shipments": 1, "vehicles": 1, "breaks": 1, "time_windows": 3},
+		},
+		{
+			name:        "CSV file",
+			statusCode:  201,
+			projectID:   "2593982828701335033",
+			contentType: "text/csv",
+			body:        validCSV,
+			resBody: map[string]interface{}{
+				"code":    "201",
+				"message": "Created",
+				"data": map[string]interface{}{
+					"jobs":      float64(2),
+					"shipments": float64(0),
+					"vehicles":  float64(1),
+					"breaks":    float64(1),
+					"dry_run":   false,
+				},
+			},
+		},
+		{
+			name:        "CSV upload",
+			statusCode:  201,
+			projectID:   "3909655254191459783",
+			contentType: "multipart/form-data",
+			body:        validCSV,
+			resBody: map[string]interface{}{
+				"code":    "201",
+				"message": "Created",
+				"data": map[string]interface{}{
+					"jobs":      float64(2),
+					"shipments": float64(0),
+					"vehicles":  float64(1),
+					"breaks":    float64(1),
+					"dry_run":   false,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body io.Reader
+			contentType := tc.contentType
+			switch tc.contentType {
+			case "text/csv":
+				body = strings.NewReader(tc.body.(string))
+			case "multipart/form-data":
+				b := &bytes.Buffer{}
+				writer := multipart.NewWriter(b)
+				part, err := writer.CreateFormFile("file", "import.csv")
+				require.NoError(t, err)
+				_, err = part.Write([]byte(tc.body.(string)))
+				require.NoError(t, err)
+				require.NoError(t, writer.Close())
+				body = b
+				contentType = writer.FormDataContentType()
+			default:
+				b, err := json.Marshal(tc.body)
+				require.NoError(t, err)
+				body = bytes.NewBuffer(b)
+				contentType = "application/json"
+			}
+
+			url := "/projects/" + tc.projectID + "/import"
+			if tc.dryRun {
+				url += "?dry_run=true"
+			}
+			request, err := http.NewRequest("POST", url, body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", contentType)
+
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+
+			resp := recorder.Result()
+			respBody, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Error(err)
+			}
+
+			assert.Equal(t, tc.statusCode, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+			m := map[string]interface{}{}
+			if err = json.Unmarshal(respBody, &m); err != nil {
+				t.Error(err)
+			}
+			assert.Equal(t, tc.resBody, m)
+			if tc.counts != nil {
+				assert.Equal(t, tc.counts, countProjectRows(t, conn, 8943284028902589305))
+			}
+		})
+	}
+}
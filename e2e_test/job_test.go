@@ -154,15 +154,21 @@ func TestCreateJob(t *testing.T) {
 						"latitude":  12.3457,
 						"longitude": 56.78,
 					},
-					"setup":        "00:00:00",
-					"service":      "00:00:00",
-					"delivery":     []interface{}{},
-					"pickup":       []interface{}{},
-					"skills":       []interface{}{},
-					"priority":     float64(0),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{},
-					"time_windows": []interface{}{},
+					"setup":             "00:00:00",
+					"service":           "00:00:00",
+					"delivery":          []interface{}{},
+					"pickup":            []interface{}{},
+					"skills":            []interface{}{},
+					"priority":          float64(0),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "3909655254191459782",
+					"data":              map[string]interface{}{},
+					"time_windows":      []interface{}{},
 				},
 				"code":    "201",
 				"message": "Created",
@@ -316,15 +322,21 @@ func TestCreateJob(t *testing.T) {
 						"latitude":  12.3457,
 						"longitude": 56.78,
 					},
-					"setup":        "00:00:10",
-					"service":      "00:03:35",
-					"delivery":     []interface{}{float64(10), float64(20)},
-					"pickup":       []interface{}{float64(15), float64(16)},
-					"skills":       []interface{}{float64(5), float64(50), float64(100)},
-					"priority":     float64(10),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{"key": "value"},
-					"time_windows": []interface{}{},
+					"setup":             "00:00:10",
+					"service":           "00:03:35",
+					"delivery":          []interface{}{float64(10), float64(20)},
+					"pickup":            []interface{}{float64(15), float64(16)},
+					"skills":            []interface{}{float64(5), float64(50), float64(100)},
+					"priority":          float64(10),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "3909655254191459782",
+					"data":              map[string]interface{}{"key": "value"},
+					"time_windows":      []interface{}{},
 				},
 				"code":    "201",
 				"message": "Created",
@@ -405,16 +417,22 @@ func TestListJobs(t *testing.T) {
 							"latitude":  32.234,
 							"longitude": -23.2342,
 						},
-						"setup":      "00:00:00",
-						"service":    "00:02:25",
-						"delivery":   []interface{}{float64(10), float64(20)},
-						"pickup":     []interface{}{float64(20), float64(30)},
-						"skills":     []interface{}{float64(5), float64(50), float64(100)},
-						"priority":   float64(11),
-						"project_id": "2593982828701335033",
-						"data":       map[string]interface{}{"key": "value"},
-						"created_at": "2021-10-24T20:31:25",
-						"updated_at": "2021-10-24T20:31:25",
+						"setup":             "00:00:00",
+						"service":           "00:02:25",
+						"delivery":          []interface{}{float64(10), float64(20)},
+						"pickup":            []interface{}{float64(20), float64(30)},
+						"skills":            []interface{}{float64(5), float64(50), float64(100)},
+						"priority":          float64(11),
+						"pinned_vehicle_id": nil,
+						"locked":            false,
+						"recurrence":        "",
+						"recurring_job_id":  nil,
+						"occurrence":        nil,
+						"overridden":        false,
+						"project_id":        "2593982828701335033",
+						"data":              map[string]interface{}{"key": "value"},
+						"created_at":        "2021-10-24T20:31:25",
+						"updated_at":        "2021-10-24T20:31:25",
 						"time_windows": []interface{}{
 							[]interface{}{
 								"2020-10-10T00:00:00",
@@ -432,16 +450,22 @@ func TestListJobs(t *testing.T) {
 							"latitude":  -81.23,
 							"longitude": float64(12),
 						},
-						"setup":      "00:00:00",
-						"service":    "00:01:01",
-						"delivery":   []interface{}{float64(5), float64(6)},
-						"pickup":     []interface{}{float64(7), float64(8)},
-						"skills":     []interface{}{},
-						"priority":   float64(0),
-						"project_id": "2593982828701335033",
-						"data":       map[string]interface{}{"data": []interface{}{"value1", float64(2)}},
-						"created_at": "2021-10-24T21:12:24",
-						"updated_at": "2021-10-24T21:12:24",
+						"setup":             "00:00:00",
+						"service":           "00:01:01",
+						"delivery":          []interface{}{float64(5), float64(6)},
+						"pickup":            []interface{}{float64(7), float64(8)},
+						"skills":            []interface{}{},
+						"priority":          float64(0),
+						"pinned_vehicle_id": nil,
+						"locked":            false,
+						"recurrence":        "",
+						"recurring_job_id":  nil,
+						"occurrence":        nil,
+						"overridden":        false,
+						"project_id":        "2593982828701335033",
+						"data":              map[string]interface{}{"data": []interface{}{"value1", float64(2)}},
+						"created_at":        "2021-10-24T21:12:24",
+						"updated_at":        "2021-10-24T21:12:24",
 						"time_windows": []interface{}{
 							[]interface{}{
 								"2020-10-10T00:10:00",
@@ -514,16 +538,22 @@ func TestGetJob(t *testing.T) {
 						"latitude":  32.234,
 						"longitude": -23.2342,
 					},
-					"setup":      "00:00:00",
-					"service":    "00:02:25",
-					"delivery":   []interface{}{float64(10), float64(20)},
-					"pickup":     []interface{}{float64(20), float64(30)},
-					"skills":     []interface{}{float64(5), float64(50), float64(100)},
-					"priority":   float64(11),
-					"project_id": "2593982828701335033",
-					"data":       map[string]interface{}{"key": "value"},
-					"created_at": "2021-10-24T20:31:25",
-					"updated_at": "2021-10-24T20:31:25",
+					"setup":             "00:00:00",
+					"service":           "00:02:25",
+					"delivery":          []interface{}{float64(10), float64(20)},
+					"pickup":            []interface{}{float64(20), float64(30)},
+					"skills":            []interface{}{float64(5), float64(50), float64(100)},
+					"priority":          float64(11),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "2593982828701335033",
+					"data":              map[string]interface{}{"key": "value"},
+					"created_at":        "2021-10-24T20:31:25",
+					"updated_at":        "2021-10-24T20:31:25",
 					"time_windows": []interface{}{
 						[]interface{}{
 							"2020-10-10T00:00:00",
@@ -593,16 +623,22 @@ func TestUpdateJob(t *testing.T) {
 						"latitude":  32.234,
 						"longitude": -23.2342,
 					},
-					"setup":        "00:00:00",
-					"service":      "00:02:25",
-					"delivery":     []interface{}{float64(10), float64(20)},
-					"pickup":       []interface{}{float64(20), float64(30)},
-					"skills":       []interface{}{float64(5), float64(50), float64(100)},
-					"priority":     float64(11),
-					"project_id":   "2593982828701335033",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-24T20:31:25",
-					"time_windows": []interface{}{},
+					"setup":             "00:00:00",
+					"service":           "00:02:25",
+					"delivery":          []interface{}{float64(10), float64(20)},
+					"pickup":            []interface{}{float64(20), float64(30)},
+					"skills":            []interface{}{float64(5), float64(50), float64(100)},
+					"priority":          float64(11),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "2593982828701335033",
+					"data":              map[string]interface{}{"key": "value"},
+					"created_at":        "2021-10-24T20:31:25",
+					"time_windows":      []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -792,16 +828,22 @@ func TestUpdateJob(t *testing.T) {
 						"latitude":  23.4567,
 						"longitude": -78.90,
 					},
-					"setup":        "00:00:00",
-					"service":      "00:02:25",
-					"delivery":     []interface{}{float64(10), float64(20)},
-					"pickup":       []interface{}{float64(20), float64(30)},
-					"skills":       []interface{}{float64(5), float64(50), float64(100)},
-					"priority":     float64(11),
-					"project_id":   "2593982828701335033",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-24T20:31:25",
-					"time_windows": []interface{}{},
+					"setup":             "00:00:00",
+					"service":           "00:02:25",
+					"delivery":          []interface{}{float64(10), float64(20)},
+					"pickup":            []interface{}{float64(20), float64(30)},
+					"skills":            []interface{}{float64(5), float64(50), float64(100)},
+					"priority":          float64(11),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "2593982828701335033",
+					"data":              map[string]interface{}{"key": "value"},
+					"created_at":        "2021-10-24T20:31:25",
+					"time_windows":      []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -821,16 +863,22 @@ func TestUpdateJob(t *testing.T) {
 						"latitude":  23.4567,
 						"longitude": -78.90,
 					},
-					"setup":        "00:01:40",
-					"service":      "00:02:25",
-					"delivery":     []interface{}{float64(10), float64(20)},
-					"pickup":       []interface{}{float64(20), float64(30)},
-					"skills":       []interface{}{float64(5), float64(50), float64(100)},
-					"priority":     float64(11),
-					"project_id":   "2593982828701335033",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-24T20:31:25",
-					"time_windows": []interface{}{},
+					"setup":             "00:01:40",
+					"service":           "00:02:25",
+					"delivery":          []interface{}{float64(10), float64(20)},
+					"pickup":            []interface{}{float64(20), float64(30)},
+					"skills":            []interface{}{float64(5), float64(50), float64(100)},
+					"priority":          float64(11),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "2593982828701335033",
+					"data":              map[string]interface{}{"key": "value"},
+					"created_at":        "2021-10-24T20:31:25",
+					"time_windows":      []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -850,16 +898,22 @@ func TestUpdateJob(t *testing.T) {
 						"latitude":  23.4567,
 						"longitude": -78.90,
 					},
-					"setup":        "00:01:40",
-					"service":      "00:16:45",
-					"delivery":     []interface{}{float64(10), float64(20)},
-					"pickup":       []interface{}{float64(20), float64(30)},
-					"skills":       []interface{}{float64(5), float64(50), float64(100)},
-					"priority":     float64(11),
-					"project_id":   "2593982828701335033",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-24T20:31:25",
-					"time_windows": []interface{}{},
+					"setup":             "00:01:40",
+					"service":           "00:16:45",
+					"delivery":          []interface{}{float64(10), float64(20)},
+					"pickup":            []interface{}{float64(20), float64(30)},
+					"skills":            []interface{}{float64(5), float64(50), float64(100)},
+					"priority":          float64(11),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "2593982828701335033",
+					"data":              map[string]interface{}{"key": "value"},
+					"created_at":        "2021-10-24T20:31:25",
+					"time_windows":      []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -879,16 +933,22 @@ func TestUpdateJob(t *testing.T) {
 						"latitude":  23.4567,
 						"longitude": -78.90,
 					},
-					"setup":        "00:01:40",
-					"service":      "00:16:45",
-					"delivery":     []interface{}{float64(20), float64(30)},
-					"pickup":       []interface{}{float64(20), float64(30)},
-					"skills":       []interface{}{float64(5), float64(50), float64(100)},
-					"priority":     float64(11),
-					"project_id":   "2593982828701335033",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-24T20:31:25",
-					"time_windows": []interface{}{},
+					"setup":             "00:01:40",
+					"service":           "00:16:45",
+					"delivery":          []interface{}{float64(20), float64(30)},
+					"pickup":            []interface{}{float64(20), float64(30)},
+					"skills":            []interface{}{float64(5), float64(50), float64(100)},
+					"priority":          float64(11),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "2593982828701335033",
+					"data":              map[string]interface{}{"key": "value"},
+					"created_at":        "2021-10-24T20:31:25",
+					"time_windows":      []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -908,16 +968,22 @@ func TestUpdateJob(t *testing.T) {
 						"latitude":  23.4567,
 						"longitude": -78.90,
 					},
-					"setup":        "00:01:40",
-					"service":      "00:16:45",
-					"delivery":     []interface{}{float64(20), float64(30)},
-					"pickup":       []interface{}{float64(10), float64(20)},
-					"skills":       []interface{}{float64(5), float64(50), float64(100)},
-					"priority":     float64(11),
-					"project_id":   "2593982828701335033",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-24T20:31:25",
-					"time_windows": []interface{}{},
+					"setup":             "00:01:40",
+					"service":           "00:16:45",
+					"delivery":          []interface{}{float64(20), float64(30)},
+					"pickup":            []interface{}{float64(10), float64(20)},
+					"skills":            []interface{}{float64(5), float64(50), float64(100)},
+					"priority":          float64(11),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "2593982828701335033",
+					"data":              map[string]interface{}{"key": "value"},
+					"created_at":        "2021-10-24T20:31:25",
+					"time_windows":      []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -937,16 +1003,22 @@ func TestUpdateJob(t *testing.T) {
 						"latitude":  23.4567,
 						"longitude": -78.90,
 					},
-					"setup":        "00:01:40",
-					"service":      "00:16:45",
-					"delivery":     []interface{}{float64(20), float64(30)},
-					"pickup":       []interface{}{float64(10), float64(20)},
-					"skills":       []interface{}{float64(5)},
-					"priority":     float64(11),
-					"project_id":   "2593982828701335033",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-24T20:31:25",
-					"time_windows": []interface{}{},
+					"setup":             "00:01:40",
+					"service":           "00:16:45",
+					"delivery":          []interface{}{float64(20), float64(30)},
+					"pickup":            []interface{}{float64(10), float64(20)},
+					"skills":            []interface{}{float64(5)},
+					"priority":          float64(11),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "2593982828701335033",
+					"data":              map[string]interface{}{"key": "value"},
+					"created_at":        "2021-10-24T20:31:25",
+					"time_windows":      []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -966,16 +1038,22 @@ func TestUpdateJob(t *testing.T) {
 						"latitude":  23.4567,
 						"longitude": -78.90,
 					},
-					"setup":        "00:01:40",
-					"service":      "00:16:45",
-					"delivery":     []interface{}{float64(20), float64(30)},
-					"pickup":       []interface{}{float64(10), float64(20)},
-					"skills":       []interface{}{float64(5)},
-					"priority":     float64(100),
-					"project_id":   "2593982828701335033",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-24T20:31:25",
-					"time_windows": []interface{}{},
+					"setup":             "00:01:40",
+					"service":           "00:16:45",
+					"delivery":          []interface{}{float64(20), float64(30)},
+					"pickup":            []interface{}{float64(10), float64(20)},
+					"skills":            []interface{}{float64(5)},
+					"priority":          float64(100),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "2593982828701335033",
+					"data":              map[string]interface{}{"key": "value"},
+					"created_at":        "2021-10-24T20:31:25",
+					"time_windows":      []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -995,16 +1073,22 @@ func TestUpdateJob(t *testing.T) {
 						"latitude":  23.4567,
 						"longitude": -78.90,
 					},
-					"setup":        "00:01:40",
-					"service":      "00:16:45",
-					"delivery":     []interface{}{float64(20), float64(30)},
-					"pickup":       []interface{}{float64(10), float64(20)},
-					"skills":       []interface{}{float64(5)},
-					"priority":     float64(100),
-					"project_id":   "2593982828701335033",
-					"data":         map[string]interface{}{},
-					"created_at":   "2021-10-24T20:31:25",
-					"time_windows": []interface{}{},
+					"setup":             "00:01:40",
+					"service":           "00:16:45",
+					"delivery":          []interface{}{float64(20), float64(30)},
+					"pickup":            []interface{}{float64(10), float64(20)},
+					"skills":            []interface{}{float64(5)},
+					"priority":          float64(100),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "2593982828701335033",
+					"data":              map[string]interface{}{},
+					"created_at":        "2021-10-24T20:31:25",
+					"time_windows":      []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -1048,16 +1132,22 @@ func TestUpdateJob(t *testing.T) {
 						"latitude":  23.4567,
 						"longitude": -78.90,
 					},
-					"setup":        "00:01:40",
-					"service":      "00:16:45",
-					"delivery":     []interface{}{float64(20), float64(30)},
-					"pickup":       []interface{}{float64(10), float64(20)},
-					"skills":       []interface{}{float64(5)},
-					"priority":     float64(100),
-					"project_id":   "8943284028902589305",
-					"data":         map[string]interface{}{},
-					"created_at":   "2021-10-24T20:31:25",
-					"time_windows": []interface{}{},
+					"setup":             "00:01:40",
+					"service":           "00:16:45",
+					"delivery":          []interface{}{float64(20), float64(30)},
+					"pickup":            []interface{}{float64(10), float64(20)},
+					"skills":            []interface{}{float64(5)},
+					"priority":          float64(100),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "8943284028902589305",
+					"data":              map[string]interface{}{},
+					"created_at":        "2021-10-24T20:31:25",
+					"time_windows":      []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -1088,16 +1178,22 @@ func TestUpdateJob(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"setup":        "00:00:10",
-					"service":      "00:01:45",
-					"delivery":     []interface{}{float64(20)},
-					"pickup":       []interface{}{float64(4)},
-					"skills":       []interface{}{},
-					"priority":     float64(0),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{"key": 123.23},
-					"created_at":   "2021-10-24T20:31:25",
-					"time_windows": []interface{}{},
+					"setup":             "00:00:10",
+					"service":           "00:01:45",
+					"delivery":          []interface{}{float64(20)},
+					"pickup":            []interface{}{float64(4)},
+					"skills":            []interface{}{},
+					"priority":          float64(0),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"recurrence":        "",
+					"recurring_job_id":  nil,
+					"occurrence":        nil,
+					"overridden":        false,
+					"project_id":        "3909655254191459782",
+					"data":              map[string]interface{}{"key": 123.23},
+					"created_at":        "2021-10-24T20:31:25",
+					"time_windows":      []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -1255,12 +1351,14 @@ func TestGetJobScheduleJson(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-01-03T16:42:27",
-									"departure":    "2020-01-03T16:47:27",
-									"travel_time":  "54:32:27",
-									"setup_time":   "00:00:00",
-									"service_time": "00:05:00",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-03T16:42:27",
+									"departure":           "2020-01-03T16:47:27",
+									"travel_time":         "54:32:27",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:05:00",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										0.0,
 										0.0,
@@ -0,0 +1,302 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: matrix_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package e2etest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/database"
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countProjectMatrix returns the number of cached durations between the locations of a project
+func countProjectMatrix(t *testing.T, conn *pgxpool.Pool, projectID int) int {
+	sql := `
+	WITH project_locations AS (
+		SELECT location_id FROM jobs WHERE project_id = $1 UNION
+		SELECT unnest(ARRAY[p_location_id, d_location_id]) FROM shipments WHERE project_id = $1 UNION
+		SELECT unnest(ARRAY[start_id, end_id]) FROM vehicles WHERE project_id = $1
+	)
+	SELECT count(*) FROM matrix
+	WHERE start_id IN (SELECT location_id FROM project_locations)
+	AND end_id IN (SELECT location_id FROM project_locations)`
+
+	var count int
+	err := conn.QueryRow(context.Background(), sql, projectID).Scan(&count)
+	require.NoError(t, err)
+	return count
+}
+
+func TestClearMatrix(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	testCases := []struct {
+		name       string
+		statusCode int
+		projectID  int
+		sql        string
+		schedule   bool
+		keepShared bool
+		resBody    map[string]interface{}
+	}{
+		{
+			name:       "Invalid ID",
+			statusCode: 404,
+			projectID:  100,
+			resBody: map[string]interface{}{
+				"error": "Not Found",
+				"code":  "404",
+			},
+		},
+		{
+			name:       "Correct ID, keeping the durations shared with another project",
+			statusCode: 200,
+			projectID:  3909655254191459782,
+			schedule:   true,
+			keepShared: true,
+			resBody: map[string]interface{}{
+				"code":    "200",
+				"message": "OK",
+			},
+		},
+		{
+			name:       "Correct ID",
+			statusCode: 200,
+			projectID:  3909655254191459782,
+			sql:        "UPDATE projects SET duration_calc = 'euclidean' WHERE id = 2593982828701335033",
+			schedule:   true,
+			resBody: map[string]interface{}{
+				"code":    "200",
+				"message": "OK",
+			},
+		},
+		{
+			name:       "Correct ID, but no cached durations",
+			statusCode: 200,
+			projectID:  2593982828701335033,
+			resBody: map[string]interface{}{
+				"code":    "200",
+				"message": "OK",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.sql != "" {
+				_, err := conn.Exec(context.Background(), tc.sql)
+				require.NoError(t, err)
+			}
+			if tc.schedule {
+				url := fmt.Sprintf("/projects/%d/schedule", tc.projectID)
+				request, err := http.NewRequest("POST", url, nil)
+				require.NoError(t, err)
+				recorder := httptest.NewRecorder()
+				mux.ServeHTTP(recorder, request)
+				require.Equal(t, 201, recorder.Result().StatusCode)
+				assert.NotEqual(t, 0, countProjectMatrix(t, conn, tc.projectID))
+			}
+
+			count := countProjectMatrix(t, conn, tc.projectID)
+			url := fmt.Sprintf("/projects/%d/matrix", tc.projectID)
+			request, err := http.NewRequest("DELETE", url, nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+
+			resp := recorder.Result()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Error(err)
+			}
+
+			assert.Equal(t, tc.statusCode, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+			m := map[string]interface{}{}
+			if err = json.Unmarshal(body, &m); err != nil {
+				t.Error(err)
+			}
+			assert.Equal(t, tc.resBody, m)
+			if tc.keepShared {
+				assert.Equal(t, count, countProjectMatrix(t, conn, tc.projectID))
+			} else {
+				assert.Equal(t, 0, countProjectMatrix(t, conn, tc.projectID))
+			}
+		})
+	}
+}
+
+func TestMatrixCacheInvalidation(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	projectID := 3909655254191459782
+
+	// Schedule the project, caching the durations
+	url := fmt.Sprintf("/projects/%d/schedule", projectID)
+	request, err := http.NewRequest("POST", url, nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+	require.Equal(t, 201, recorder.Result().StatusCode)
+	count := countProjectMatrix(t, conn, projectID)
+	assert.NotEqual(t, 0, count)
+
+	// Scheduling again must reuse the cached durations
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+	require.Equal(t, 201, recorder.Result().StatusCode)
+	assert.Equal(t, count, countProjectMatrix(t, conn, projectID))
+
+	// Updating the project without changing the duration_calc keeps the cache
+	url = fmt.Sprintf("/projects/%d", projectID)
+	request, err = http.NewRequest("PATCH", url, strings.NewReader(`{"name": "Updated Project"}`))
+	require.NoError(t, err)
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+	require.Equal(t, 200, recorder.Result().StatusCode)
+	assert.Equal(t, count, countProjectMatrix(t, conn, projectID))
+
+	// Changing the duration_calc keeps the durations used by another project with the old duration_calc
+	request, err = http.NewRequest("PATCH", url, strings.NewReader(`{"duration_calc": "euclidean"}`))
+	require.NoError(t, err)
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+	require.Equal(t, 200, recorder.Result().StatusCode)
+	assert.Equal(t, count, countProjectMatrix(t, conn, projectID))
+
+	// Changing the duration_calc clears the cache, once no other project uses the old duration_calc
+	request, err = http.NewRequest("PATCH", url, strings.NewReader(`{"duration_calc": "osrm"}`))
+	require.NoError(t, err)
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+	require.Equal(t, 200, recorder.Result().StatusCode)
+	_, err = conn.Exec(context.Background(), "UPDATE projects SET duration_calc = 'euclidean' WHERE id != $1", projectID)
+	require.NoError(t, err)
+	assert.Equal(t, count, countProjectMatrix(t, conn, projectID))
+	request, err = http.NewRequest("PATCH", url, strings.NewReader(`{"duration_calc": "euclidean"}`))
+	require.NoError(t, err)
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+	require.Equal(t, 200, recorder.Result().StatusCode)
+	assert.Equal(t, 0, countProjectMatrix(t, conn, projectID))
+}
+
+func TestPgRoutingMatrix(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "")
+	defer conn.Close()
+	mux := server.Router
+
+	// A road network with a one-way shortcut from the depot to the last job
+	_, err := conn.Exec(context.Background(), `
+	CREATE TABLE road_edges (id BIGINT, source BIGINT, target BIGINT, cost FLOAT, reverse_cost FLOAT, length_m FLOAT);
+	CREATE TABLE road_edges_vertices_pgr (id BIGINT, the_geom geometry(Point, 4326));
+	INSERT INTO road_edges_vertices_pgr VALUES
+		(1, ST_SetSRID(ST_Point(1.0, 1.0), 4326)),
+		(2, ST_SetSRID(ST_Point(1.01, 1.0), 4326)),
+		(3, ST_SetSRID(ST_Point(1.02, 1.0), 4326));
+	INSERT INTO road_edges VALUES (1, 1, 2, 100, 100, 1000), (2, 2, 3, 200, 200, 2000), (3, 1, 3, 250, -1, 5000);`)
+	require.NoError(t, err)
+
+	project := createRow(t, mux, "/projects", map[string]interface{}{"name": "pgRouting", "duration_calc": "pgrouting"})
+	projectID := project["id"].(string)
+	depot := map[string]interface{}{"latitude": 1.0, "longitude": 1.0}
+	createRow(t, mux, fmt.Sprintf("/projects/%s/vehicles", projectID), map[string]interface{}{"start_location": depot, "end_location": depot})
+	createRow(t, mux, fmt.Sprintf("/projects/%s/jobs", projectID), map[string]interface{}{"location": map[string]interface{}{"latitude": 1.0, "longitude": 1.01}})
+	createRow(t, mux, fmt.Sprintf("/projects/%s/jobs", projectID), map[string]interface{}{"location": map[string]interface{}{"latitude": 1.0, "longitude": 1.02}})
+	createRow(t, mux, fmt.Sprintf("/projects/%s/schedule", projectID), nil)
+
+	// The durations use the cost of the edges, and the distances their length
+	testCases := []struct {
+		name     string
+		start    [2]float64
+		end      [2]float64
+		duration int64
+		distance int64
+	}{
+		{
+			name:     "Shortcut from the depot",
+			start:    [2]float64{1.0, 1.0},
+			end:      [2]float64{1.0, 1.02},
+			duration: 250,
+			distance: 3000,
+		},
+		{
+			name:     "No shortcut back to the depot",
+			start:    [2]float64{1.0, 1.02},
+			end:      [2]float64{1.0, 1.0},
+			duration: 300,
+			distance: 3000,
+		},
+		{
+			name:     "Same location",
+			start:    [2]float64{1.0, 1.01},
+			end:      [2]float64{1.0, 1.01},
+			duration: 0,
+			distance: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var duration, distance int64
+			err := conn.QueryRow(context.Background(), `
+			SELECT duration, distance FROM matrix WHERE duration_calc = 'pgrouting' AND start_id = $1 AND end_id = $2`,
+				util.GetLocationId(tc.start[0], tc.start[1]), util.GetLocationId(tc.end[0], tc.end[1]),
+			).Scan(&duration, &distance)
+			require.NoError(t, err)
+			assert.Equal(t, tc.duration, duration)
+			assert.Equal(t, tc.distance, distance)
+		})
+	}
+
+	// The route geometry is the path through the vertices of the road network
+	route, err := database.NewPgRoutingMatrixProvider(conn, "road_edges", "").GetRoute(context.Background(), []int64{
+		util.GetLocationId(1.0, 1.0), util.GetLocationId(1.0, 1.02), util.GetLocationId(1.0, 1.0),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{1.0, 1.0}, {1.02, 1.0}, {1.01, 1.0}, {1.0, 1.0}}, route)
+}
@@ -0,0 +1,158 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: pinned_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package e2etest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinnedTasks(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	getScheduledStep := func(t *testing.T, taskID string) (string, string) {
+		var vehicleID, serviceStart string
+		err := conn.QueryRow(context.Background(), `
+		SELECT vehicle_id::TEXT, (arrival + waiting_time)::TEXT FROM schedules WHERE task_id = $1 AND type = 'job'`, taskID).Scan(&vehicleID, &serviceStart)
+		require.NoError(t, err)
+		return vehicleID, serviceStart
+	}
+	otherProject := map[string]interface{}{
+		"errors":  []interface{}{"Field 'pinned_vehicle_id' must be the ID of a vehicle of the same project"},
+		"message": "Bad Request",
+		"code":    "400",
+	}
+
+	testCases := []struct {
+		name       string
+		url        string
+		schedule   bool
+		body       func(vehicleIDs []string) map[string]interface{}
+		statusCode int
+		resBody    map[string]interface{}
+		check      func(t *testing.T, projectID string, taskID string, vehicleIDs []string, data map[string]interface{})
+	}{
+		{
+			name: "Job pinned to a vehicle of another project",
+			url:  "/jobs/%s",
+			body: func(vehicleIDs []string) map[string]interface{} {
+				return map[string]interface{}{"pinned_vehicle_id": "150202809001685363"}
+			},
+			statusCode: 400,
+			resBody:    otherProject,
+		},
+		{
+			name: "Shipment pinned to a vehicle of another project",
+			url:  "/shipments/%s",
+			body: func(vehicleIDs []string) map[string]interface{} {
+				return map[string]interface{}{"pinned_vehicle_id": "150202809001685363"}
+			},
+			statusCode: 400,
+			resBody:    otherProject,
+		},
+		{
+			name: "Pin a job to a vehicle",
+			url:  "/jobs/%s",
+			body: func(vehicleIDs []string) map[string]interface{} {
+				return map[string]interface{}{"pinned_vehicle_id": vehicleIDs[1]}
+			},
+			statusCode: 200,
+			check: func(t *testing.T, projectID string, taskID string, vehicleIDs []string, data map[string]interface{}) {
+				assert.Equal(t, vehicleIDs[1], data["pinned_vehicle_id"])
+
+				createRow(t, mux, fmt.Sprintf("/projects/%s/schedule?fresh=true", projectID), nil)
+				vehicleID, _ := getScheduledStep(t, taskID)
+				assert.NotEqual(t, vehicleIDs[0], vehicleID)
+			},
+		},
+		{
+			name:     "Lock a job",
+			url:      "/jobs/%s",
+			schedule: true,
+			body: func(vehicleIDs []string) map[string]interface{} {
+				return map[string]interface{}{"pinned_vehicle_id": "0", "locked": true}
+			},
+			statusCode: 200,
+			check: func(t *testing.T, projectID string, taskID string, vehicleIDs []string, data map[string]interface{}) {
+				assert.Equal(t, nil, data["pinned_vehicle_id"])
+				assert.Equal(t, true, data["locked"])
+
+				vehicleID, serviceStart := getScheduledStep(t, taskID)
+				for _, url := range []string{"/projects/%s/schedule", "/projects/%s/schedule?fresh=true"} {
+					createRow(t, mux, fmt.Sprintf(url, projectID), nil)
+					newVehicleID, newServiceStart := getScheduledStep(t, taskID)
+					assert.Equal(t, vehicleID, newVehicleID)
+					if vehicleID != "-1" {
+						assert.Equal(t, serviceStart, newServiceStart)
+					}
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Each case has its own project with two vehicles, a job and a shipment
+			project := createRow(t, mux, "/projects", map[string]interface{}{"name": tc.name, "duration_calc": "euclidean"})
+			projectID := project["id"].(string)
+			depot := map[string]interface{}{"latitude": 1.0, "longitude": 1.0}
+			location := map[string]interface{}{"latitude": 1.0, "longitude": 1.01}
+			vehicleIDs := []string{}
+			for i := 0; i < 2; i++ {
+				vehicle := createRow(t, mux, fmt.Sprintf("/projects/%s/vehicles", projectID), map[string]interface{}{"start_location": depot, "end_location": depot})
+				vehicleIDs = append(vehicleIDs, vehicle["id"].(string))
+			}
+			job := createRow(t, mux, fmt.Sprintf("/projects/%s/jobs", projectID), map[string]interface{}{"location": location})
+			shipment := createRow(t, mux, fmt.Sprintf("/projects/%s/shipments", projectID), map[string]interface{}{"p_location": depot, "d_location": location})
+			taskID := job["id"].(string)
+			if tc.url == "/shipments/%s" {
+				taskID = shipment["id"].(string)
+			}
+			if tc.schedule {
+				createRow(t, mux, fmt.Sprintf("/projects/%s/schedule?fresh=true", projectID), nil)
+			}
+
+			statusCode, m := sendJSON(t, mux, "PATCH", fmt.Sprintf(tc.url, taskID), tc.body(vehicleIDs))
+			assert.Equal(t, tc.statusCode, statusCode)
+			if tc.resBody != nil {
+				assert.Equal(t, tc.resBody, m)
+			}
+			if tc.check != nil {
+				tc.check(t, projectID, taskID, vehicleIDs, m["data"].(map[string]interface{}))
+			}
+		})
+	}
+}
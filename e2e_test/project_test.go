@@ -71,12 +71,18 @@ func TestCreateProject(t *testing.T) {
 			},
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
-					"data":              map[string]interface{}{},
-					"name":              "Sample Project",
-					"duration_calc":     "euclidean",
-					"exploration_level": 5.0,
-					"timeout":           "00:10:00",
-					"max_shift":         "00:30:00",
+					"data":                  map[string]interface{}{},
+					"name":                  "Sample Project",
+					"duration_calc":         "euclidean",
+					"exploration_level":     5.0,
+					"timeout":               "00:10:00",
+					"max_shift":             "00:30:00",
+					"objective":             "duration",
+					"vehicle_fixed_cost":    0.0,
+					"vehicle_cost_per_hour": 3600.0,
+					"horizon_start":         nil,
+					"horizon_end":           nil,
+					"timezone":              nil,
 				},
 				"code":    "201",
 				"message": "Created",
@@ -106,6 +112,64 @@ func TestCreateProject(t *testing.T) {
 				"errors":  []interface{}{"Field 'name' must be of 'string' type."},
 			},
 		},
+		{
+			name:       "Invalid duration_calc",
+			statusCode: 400,
+			body: map[string]interface{}{
+				"name":          "123",
+				"duration_calc": "invalid",
+			},
+			resBody: map[string]interface{}{
+				"code":    "400",
+				"message": "Bad Request",
+				"errors":  []interface{}{"Field 'duration_calc' must be one out of euclidean, osrm, pgrouting, valhalla"},
+			},
+		},
+		{
+			name:       "Invalid solver options",
+			statusCode: 400,
+			body: map[string]interface{}{
+				"name":               "123",
+				"objective":          "invalid",
+				"vehicle_fixed_cost": -1,
+			},
+			resBody: map[string]interface{}{
+				"code":    "400",
+				"message": "Bad Request",
+				"errors": []interface{}{
+					"Field 'objective' must be one out of duration, vehicles, cost",
+					"Field 'vehicle_fixed_cost' must be non-negative",
+				},
+			},
+		},
+		{
+			name:       "Solver options",
+			statusCode: 201,
+			body: map[string]interface{}{
+				"name":                  "123",
+				"objective":             "cost",
+				"vehicle_fixed_cost":    5000,
+				"vehicle_cost_per_hour": 1800,
+			},
+			resBody: map[string]interface{}{
+				"data": map[string]interface{}{
+					"name":                  "123",
+					"data":                  map[string]interface{}{},
+					"duration_calc":         "euclidean",
+					"exploration_level":     5.0,
+					"timeout":               "00:10:00",
+					"max_shift":             "00:30:00",
+					"objective":             "cost",
+					"vehicle_fixed_cost":    5000.0,
+					"vehicle_cost_per_hour": 1800.0,
+					"horizon_start":         nil,
+					"horizon_end":           nil,
+					"timezone":              nil,
+				},
+				"code":    "201",
+				"message": "Created",
+			},
+		},
 		{
 			name:       "Integer data",
 			statusCode: 201,
@@ -115,12 +179,18 @@ func TestCreateProject(t *testing.T) {
 			},
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
-					"name":              "123",
-					"data":              float64(123),
-					"duration_calc":     "euclidean",
-					"exploration_level": 5.0,
-					"timeout":           "00:10:00",
-					"max_shift":         "00:30:00",
+					"name":                  "123",
+					"data":                  float64(123),
+					"duration_calc":         "euclidean",
+					"exploration_level":     5.0,
+					"timeout":               "00:10:00",
+					"max_shift":             "00:30:00",
+					"objective":             "duration",
+					"vehicle_fixed_cost":    0.0,
+					"vehicle_cost_per_hour": 3600.0,
+					"horizon_start":         nil,
+					"horizon_end":           nil,
+					"timezone":              nil,
 				},
 				"code":    "201",
 				"message": "Created",
@@ -135,12 +205,18 @@ func TestCreateProject(t *testing.T) {
 			},
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
-					"name":              "123",
-					"data":              map[string]interface{}{"key": "value"},
-					"duration_calc":     "euclidean",
-					"exploration_level": 5.0,
-					"timeout":           "00:10:00",
-					"max_shift":         "00:30:00",
+					"name":                  "123",
+					"data":                  map[string]interface{}{"key": "value"},
+					"duration_calc":         "euclidean",
+					"exploration_level":     5.0,
+					"timeout":               "00:10:00",
+					"max_shift":             "00:30:00",
+					"objective":             "duration",
+					"vehicle_fixed_cost":    0.0,
+					"vehicle_cost_per_hour": 3600.0,
+					"horizon_start":         nil,
+					"horizon_end":           nil,
+					"timezone":              nil,
 				},
 				"code":    "201",
 				"message": "Created",
@@ -211,15 +287,21 @@ func TestGetProject(t *testing.T) {
 			projectID:  3909655254191459782,
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
-					"id":                "3909655254191459782",
-					"name":              "Sample Project",
-					"data":              "random",
-					"duration_calc":     "osrm",
-					"exploration_level": 5.0,
-					"timeout":           "00:10:00",
-					"max_shift":         "00:30:00",
-					"created_at":        "2021-10-22T23:29:31",
-					"updated_at":        "2021-10-22T23:29:31",
+					"id":                    "3909655254191459782",
+					"name":                  "Sample Project",
+					"data":                  "random",
+					"duration_calc":         "osrm",
+					"exploration_level":     5.0,
+					"timeout":               "00:10:00",
+					"max_shift":             "00:30:00",
+					"objective":             "duration",
+					"vehicle_fixed_cost":    0.0,
+					"vehicle_cost_per_hour": 3600.0,
+					"horizon_start":         nil,
+					"horizon_end":           nil,
+					"timezone":              nil,
+					"created_at":            "2021-10-22T23:29:31",
+					"updated_at":            "2021-10-22T23:29:31",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -270,48 +352,72 @@ func TestListProjects(t *testing.T) {
 			resBody: map[string]interface{}{
 				"data": []interface{}{
 					map[string]interface{}{
-						"id":                "3909655254191459782",
-						"name":              "Sample Project",
-						"data":              "random",
-						"duration_calc":     "osrm",
-						"exploration_level": 5.0,
-						"timeout":           "00:10:00",
-						"max_shift":         "00:30:00",
-						"created_at":        "2021-10-22T23:29:31",
-						"updated_at":        "2021-10-22T23:29:31",
+						"id":                    "3909655254191459782",
+						"name":                  "Sample Project",
+						"data":                  "random",
+						"duration_calc":         "osrm",
+						"exploration_level":     5.0,
+						"timeout":               "00:10:00",
+						"max_shift":             "00:30:00",
+						"objective":             "duration",
+						"vehicle_fixed_cost":    0.0,
+						"vehicle_cost_per_hour": 3600.0,
+						"horizon_start":         nil,
+						"horizon_end":           nil,
+						"timezone":              nil,
+						"created_at":            "2021-10-22T23:29:31",
+						"updated_at":            "2021-10-22T23:29:31",
 					},
 					map[string]interface{}{
-						"id":                "3909655254191459783",
-						"name":              "Sample Project2",
-						"data":              "random",
-						"duration_calc":     "osrm",
-						"exploration_level": 5.0,
-						"timeout":           "00:10:00",
-						"max_shift":         "00:30:00",
-						"created_at":        "2021-10-22T23:29:31",
-						"updated_at":        "2021-10-22T23:29:31",
+						"id":                    "3909655254191459783",
+						"name":                  "Sample Project2",
+						"data":                  "random",
+						"duration_calc":         "osrm",
+						"exploration_level":     5.0,
+						"timeout":               "00:10:00",
+						"max_shift":             "00:30:00",
+						"objective":             "duration",
+						"vehicle_fixed_cost":    0.0,
+						"vehicle_cost_per_hour": 3600.0,
+						"horizon_start":         nil,
+						"horizon_end":           nil,
+						"timezone":              nil,
+						"created_at":            "2021-10-22T23:29:31",
+						"updated_at":            "2021-10-22T23:29:31",
 					},
 					map[string]interface{}{
-						"id":                "2593982828701335033",
-						"name":              "",
-						"data":              map[string]interface{}{"s": float64(1)},
-						"duration_calc":     "osrm",
-						"exploration_level": 5.0,
-						"timeout":           "00:10:00",
-						"max_shift":         "00:30:00",
-						"created_at":        "2021-10-24T19:52:52",
-						"updated_at":        "2021-10-24T19:52:52",
+						"id":                    "2593982828701335033",
+						"name":                  "",
+						"data":                  map[string]interface{}{"s": float64(1)},
+						"duration_calc":         "osrm",
+						"exploration_level":     5.0,
+						"timeout":               "00:10:00",
+						"max_shift":             "00:30:00",
+						"objective":             "duration",
+						"vehicle_fixed_cost":    0.0,
+						"vehicle_cost_per_hour": 3600.0,
+						"horizon_start":         nil,
+						"horizon_end":           nil,
+						"timezone":              nil,
+						"created_at":            "2021-10-24T19:52:52",
+						"updated_at":            "2021-10-24T19:52:52",
 					},
 					map[string]interface{}{
-						"id":                "8943284028902589305",
-						"name":              "",
-						"data":              map[string]interface{}{"s": float64(1)},
-						"duration_calc":     "osrm",
-						"exploration_level": 5.0,
-						"timeout":           "00:10:00",
-						"max_shift":         "00:30:00",
-						"created_at":        "2021-10-24T19:52:52",
-						"updated_at":        "2021-10-24T19:52:52",
+						"id":                    "8943284028902589305",
+						"name":                  "",
+						"data":                  map[string]interface{}{"s": float64(1)},
+						"duration_calc":         "osrm",
+						"exploration_level":     5.0,
+						"timeout":               "00:10:00",
+						"max_shift":             "00:30:00",
+						"objective":             "duration",
+						"vehicle_fixed_cost":    0.0,
+						"vehicle_cost_per_hour": 3600.0,
+						"horizon_start":         nil,
+						"horizon_end":           nil,
+						"timezone":              nil,
+						"created_at":            "2021-10-24T19:52:52",
+						"updated_at":            "2021-10-24T19:52:52",
 					},
 				},
 				"code":    "200",
@@ -365,14 +471,20 @@ func TestUpdateProject(t *testing.T) {
 			body:       map[string]interface{}{},
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
-					"id":                "3909655254191459782",
-					"name":              "Sample Project",
-					"data":              "random",
-					"duration_calc":     "osrm",
-					"exploration_level": 5.0,
-					"timeout":           "00:10:00",
-					"max_shift":         "00:30:00",
-					"created_at":        "2021-10-22T23:29:31",
+					"id":                    "3909655254191459782",
+					"name":                  "Sample Project",
+					"data":                  "random",
+					"duration_calc":         "osrm",
+					"exploration_level":     5.0,
+					"timeout":               "00:10:00",
+					"max_shift":             "00:30:00",
+					"objective":             "duration",
+					"vehicle_fixed_cost":    0.0,
+					"vehicle_cost_per_hour": 3600.0,
+					"horizon_start":         nil,
+					"horizon_end":           nil,
+					"timezone":              nil,
+					"created_at":            "2021-10-22T23:29:31",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -397,14 +509,20 @@ func TestUpdateProject(t *testing.T) {
 			},
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
-					"id":                "3909655254191459782",
-					"name":              "Another Sample Project",
-					"data":              "random",
-					"duration_calc":     "osrm",
-					"exploration_level": 5.0,
-					"timeout":           "00:10:00",
-					"max_shift":         "00:30:00",
-					"created_at":        "2021-10-22T23:29:31",
+					"id":                    "3909655254191459782",
+					"name":                  "Another Sample Project",
+					"data":                  "random",
+					"duration_calc":         "osrm",
+					"exploration_level":     5.0,
+					"timeout":               "00:10:00",
+					"max_shift":             "00:30:00",
+					"objective":             "duration",
+					"vehicle_fixed_cost":    0.0,
+					"vehicle_cost_per_hour": 3600.0,
+					"horizon_start":         nil,
+					"horizon_end":           nil,
+					"timezone":              nil,
+					"created_at":            "2021-10-22T23:29:31",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -419,14 +537,20 @@ func TestUpdateProject(t *testing.T) {
 			},
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
-					"id":                "3909655254191459782",
-					"name":              "Another Sample Project",
-					"data":              map[string]interface{}{"key": "value"},
-					"duration_calc":     "osrm",
-					"exploration_level": 5.0,
-					"timeout":           "00:10:00",
-					"max_shift":         "00:30:00",
-					"created_at":        "2021-10-22T23:29:31",
+					"id":                    "3909655254191459782",
+					"name":                  "Another Sample Project",
+					"data":                  map[string]interface{}{"key": "value"},
+					"duration_calc":         "osrm",
+					"exploration_level":     5.0,
+					"timeout":               "00:10:00",
+					"max_shift":             "00:30:00",
+					"objective":             "duration",
+					"vehicle_fixed_cost":    0.0,
+					"vehicle_cost_per_hour": 3600.0,
+					"horizon_start":         nil,
+					"horizon_end":           nil,
+					"timezone":              nil,
+					"created_at":            "2021-10-22T23:29:31",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -454,14 +578,20 @@ func TestUpdateProject(t *testing.T) {
 			},
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
-					"id":                "3909655254191459782",
-					"name":              "Another Sample Project",
-					"data":              float64(123),
-					"duration_calc":     "osrm",
-					"exploration_level": 5.0,
-					"timeout":           "00:10:00",
-					"max_shift":         "00:30:00",
-					"created_at":        "2021-10-22T23:29:31",
+					"id":                    "3909655254191459782",
+					"name":                  "Another Sample Project",
+					"data":                  float64(123),
+					"duration_calc":         "osrm",
+					"exploration_level":     5.0,
+					"timeout":               "00:10:00",
+					"max_shift":             "00:30:00",
+					"objective":             "duration",
+					"vehicle_fixed_cost":    0.0,
+					"vehicle_cost_per_hour": 3600.0,
+					"horizon_start":         nil,
+					"horizon_end":           nil,
+					"timezone":              nil,
+					"created_at":            "2021-10-22T23:29:31",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -477,14 +607,20 @@ func TestUpdateProject(t *testing.T) {
 			},
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
-					"id":                "3909655254191459782",
-					"name":              "Final Sample Project",
-					"data":              map[string]interface{}{"key": "value"},
-					"duration_calc":     "osrm",
-					"exploration_level": 5.0,
-					"timeout":           "00:10:00",
-					"max_shift":         "00:30:00",
-					"created_at":        "2021-10-22T23:29:31",
+					"id":                    "3909655254191459782",
+					"name":                  "Final Sample Project",
+					"data":                  map[string]interface{}{"key": "value"},
+					"duration_calc":         "osrm",
+					"exploration_level":     5.0,
+					"timeout":               "00:10:00",
+					"max_shift":             "00:30:00",
+					"objective":             "duration",
+					"vehicle_fixed_cost":    0.0,
+					"vehicle_cost_per_hour": 3600.0,
+					"horizon_start":         nil,
+					"horizon_end":           nil,
+					"timezone":              nil,
+					"created_at":            "2021-10-22T23:29:31",
 				},
 				"code":    "200",
 				"message": "OK",
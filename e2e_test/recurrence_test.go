@@ -0,0 +1,286 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: recurrence_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package e2etest
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurrences(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	type fixture struct {
+		projectID string
+		jobID     string
+		vehicleID string
+	}
+	listRows := func(t *testing.T, url string) []interface{} {
+		statusCode, m := sendJSON(t, mux, "GET", url, nil)
+		require.Equal(t, 200, statusCode)
+		return m["data"].([]interface{})
+	}
+	listOccurrences := func(t *testing.T, projectID string) map[string]map[string]interface{} {
+		occurrences := map[string]map[string]interface{}{}
+		for _, row := range listRows(t, fmt.Sprintf("/projects/%s/jobs", projectID)) {
+			job := row.(map[string]interface{})
+			if occurrence, ok := job["occurrence"].(string); ok {
+				occurrences[occurrence] = job
+			}
+		}
+		return occurrences
+	}
+	getDates := func(occurrences map[string]map[string]interface{}) []string {
+		dates := []string{}
+		for date := range occurrences {
+			dates = append(dates, date)
+		}
+		sort.Strings(dates)
+		return dates
+	}
+	schedule := func(t *testing.T, f fixture) {
+		createRow(t, mux, fmt.Sprintf("/projects/%s/schedule", f.projectID), nil)
+	}
+	update := func(t *testing.T, url string, body map[string]interface{}) map[string]interface{} {
+		statusCode, m := sendJSON(t, mux, "PATCH", url, body)
+		require.Equal(t, 200, statusCode, m)
+		return m["data"].(map[string]interface{})
+	}
+
+	location := map[string]interface{}{"latitude": 2.0, "longitude": 3.0}
+	testCases := []struct {
+		name       string
+		setup      func(t *testing.T, f fixture)
+		method     string
+		url        func(f fixture) string
+		body       map[string]interface{}
+		statusCode int
+		resBody    map[string]interface{}
+		errors     []interface{}
+		check      func(t *testing.T, f fixture, m map[string]interface{})
+	}{
+		{
+			name:       "Invalid recurrence",
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s/jobs", f.projectID) },
+			body:       map[string]interface{}{"location": location, "recurrence": "FREQ=YEARLY"},
+			statusCode: 400,
+			resBody: map[string]interface{}{
+				"errors":  []interface{}{"Field 'recurrence' must be a recurrence rule such as 'FREQ=WEEKLY;BYDAY=MO,WE'"},
+				"message": "Bad Request",
+				"code":    "400",
+			},
+		},
+		{
+			name:       "Invalid horizon",
+			method:     "PATCH",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s", f.projectID) },
+			body:       map[string]interface{}{"horizon_end": "2022-01-01"},
+			statusCode: 400,
+			errors:     []interface{}{"Field 'horizon_start' must be less than or equal to field 'horizon_end', within 366 days"},
+		},
+		{
+			name:       "Create the occurrences and the shifts",
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s/schedule", f.projectID) },
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				occurrences := listOccurrences(t, f.projectID)
+				for _, job := range occurrences {
+					assert.Equal(t, f.jobID, job["recurring_job_id"])
+					assert.Equal(t, "", job["recurrence"])
+				}
+				assert.Equal(t, []string{"2022-01-03", "2022-01-05", "2022-01-07"}, getDates(occurrences))
+
+				// The off-shift breaks are only listed on demand
+				assert.Equal(t, 0, len(listRows(t, fmt.Sprintf("/vehicles/%s/breaks", f.vehicleID))))
+				breaks := listRows(t, fmt.Sprintf("/vehicles/%s/breaks?off_shift=true", f.vehicleID))
+				assert.Equal(t, 4, len(breaks))
+				for _, row := range breaks {
+					assert.Equal(t, true, row.(map[string]interface{})["off_shift"])
+					assert.Equal(t, "15:00:00", row.(map[string]interface{})["service"])
+				}
+			},
+		},
+		{
+			name:       "Keep the occurrences when scheduling again",
+			setup:      schedule,
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s/schedule", f.projectID) },
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				assert.Equal(t, 4, len(listRows(t, fmt.Sprintf("/projects/%s/jobs", f.projectID))))
+				assert.Equal(t, 4, len(listRows(t, fmt.Sprintf("/vehicles/%s/breaks?off_shift=true", f.vehicleID))))
+			},
+		},
+		{
+			name:       "Group the schedule by day",
+			setup:      schedule,
+			method:     "GET",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s/schedule?group_by=day", f.projectID) },
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				dates := []string{}
+				for _, day := range m["data"].(map[string]interface{})["days"].([]interface{}) {
+					dates = append(dates, day.(map[string]interface{})["date"].(string))
+				}
+				for _, date := range []string{"2022-01-03", "2022-01-05", "2022-01-07"} {
+					assert.Contains(t, dates, date)
+				}
+				assert.Equal(t, 0, len(m["data"].(map[string]interface{})["metadata"].(map[string]interface{})["unassigned"].([]interface{})))
+			},
+		},
+		{
+			name:       "Group the schedule by an invalid period",
+			setup:      schedule,
+			method:     "GET",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s/schedule?group_by=week", f.projectID) },
+			statusCode: 400,
+		},
+		{
+			name: "Propagate the changes of the recurring job to the occurrences not overridden",
+			setup: func(t *testing.T, f fixture) {
+				schedule(t, f)
+
+				// Changing an occurrence overrides it
+				occurrenceID := listOccurrences(t, f.projectID)["2022-01-05"]["id"].(string)
+				occurrence := update(t, fmt.Sprintf("/jobs/%s", occurrenceID), map[string]interface{}{
+					"priority":     10,
+					"time_windows": [][]string{{"2022-01-05T14:00:00", "2022-01-05T16:00:00"}},
+				})
+				assert.Equal(t, true, occurrence["overridden"])
+			},
+			method: "PATCH",
+			url:    func(f fixture) string { return fmt.Sprintf("/jobs/%s", f.jobID) },
+			body: map[string]interface{}{
+				"priority":     50,
+				"service":      "00:10:00",
+				"time_windows": [][]string{{"2022-01-03T09:00:00", "2022-01-03T12:00:00"}},
+			},
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				schedule(t, f)
+				occurrences := listOccurrences(t, f.projectID)
+				assert.Equal(t, 3, len(occurrences))
+				monday := occurrences["2022-01-03"]
+				assert.Equal(t, false, monday["overridden"])
+				assert.Equal(t, float64(50), monday["priority"])
+				assert.Equal(t, "00:10:00", monday["service"])
+				assert.Equal(t, []interface{}{[]interface{}{"2022-01-03T09:00:00", "2022-01-03T12:00:00"}}, monday["time_windows"])
+				wednesday := occurrences["2022-01-05"]
+				assert.Equal(t, true, wednesday["overridden"])
+				assert.Equal(t, float64(10), wednesday["priority"])
+				assert.Equal(t, "00:00:00", wednesday["service"])
+				assert.Equal(t, []interface{}{[]interface{}{"2022-01-05T14:00:00", "2022-01-05T16:00:00"}}, wednesday["time_windows"])
+			},
+		},
+		{
+			name: "Delete the stale occurrences",
+			setup: func(t *testing.T, f fixture) {
+				schedule(t, f)
+				update(t, fmt.Sprintf("/jobs/%s", f.jobID), map[string]interface{}{"recurrence": "FREQ=WEEKLY;BYDAY=MO"})
+				update(t, fmt.Sprintf("/vehicles/%s", f.vehicleID), map[string]interface{}{"shift_recurrence": ""})
+			},
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s/schedule", f.projectID) },
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				assert.Equal(t, 2, len(listRows(t, fmt.Sprintf("/projects/%s/jobs", f.projectID))))
+				assert.Equal(t, 0, len(listRows(t, fmt.Sprintf("/vehicles/%s/breaks?off_shift=true", f.vehicleID))))
+			},
+		},
+		{
+			name:       "Keep the occurrences outside of the horizon",
+			setup:      schedule,
+			method:     "PATCH",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s", f.projectID) },
+			body:       map[string]interface{}{"horizon_start": "2022-01-10", "horizon_end": "2022-01-14"},
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				schedule(t, f)
+				assert.Equal(t, []string{
+					"2022-01-03", "2022-01-05", "2022-01-07", "2022-01-10", "2022-01-12", "2022-01-14",
+				}, getDates(listOccurrences(t, f.projectID)))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Each case has its own project with a recurring job and a vehicle with a shift recurrence
+			project := createRow(t, mux, "/projects", map[string]interface{}{
+				"name":          tc.name,
+				"duration_calc": "euclidean",
+				"horizon_start": "2022-01-03",
+				"horizon_end":   "2022-01-07",
+			})
+			f := fixture{projectID: project["id"].(string)}
+			job := createRow(t, mux, fmt.Sprintf("/projects/%s/jobs", f.projectID), map[string]interface{}{
+				"location":     location,
+				"recurrence":   "FREQ=WEEKLY;BYDAY=MO,WE,FR",
+				"time_windows": [][]string{{"2022-01-03T09:00:00", "2022-01-03T12:00:00"}},
+			})
+			f.jobID = job["id"].(string)
+			vehicle := createRow(t, mux, fmt.Sprintf("/projects/%s/vehicles", f.projectID), map[string]interface{}{
+				"start_location":   location,
+				"end_location":     location,
+				"tw_open":          "2022-01-03T08:00:00",
+				"tw_close":         "2022-01-03T17:00:00",
+				"shift_recurrence": "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			})
+			f.vehicleID = vehicle["id"].(string)
+			if tc.setup != nil {
+				tc.setup(t, f)
+			}
+
+			var body interface{}
+			if tc.body != nil {
+				body = tc.body
+			}
+			statusCode, m := sendJSON(t, mux, tc.method, tc.url(f), body)
+			assert.Equal(t, tc.statusCode, statusCode, m)
+			if tc.resBody != nil {
+				assert.Equal(t, tc.resBody, m)
+			}
+			if tc.errors != nil {
+				assert.Equal(t, tc.errors, m["errors"])
+			}
+			if tc.check != nil {
+				tc.check(t, f, m)
+			}
+		})
+	}
+}
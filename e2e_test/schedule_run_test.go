@@ -0,0 +1,384 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_run_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package e2etest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateScheduleAsync(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	server.StartWorkers()
+	defer server.Close()
+	mux := server.Router
+
+	testCases := []struct {
+		name       string
+		statusCode int
+		projectID  int
+		runStatus  string
+		runError   interface{}
+	}{
+		{
+			name:       "Invalid ID",
+			statusCode: 404,
+			projectID:  123,
+		},
+		{
+			name:       "Valid ID, but nothing to schedule",
+			statusCode: 202,
+			projectID:  8943284028902589305,
+			runStatus:  "failed",
+			runError:   "No locations present in the project",
+		},
+		{
+			name:       "Valid ID",
+			statusCode: 202,
+			projectID:  3909655254191459782,
+			runStatus:  "succeeded",
+			runError:   nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/projects/%d/schedule?fresh=true&async=true", tc.projectID)
+			request, err := http.NewRequest("POST", url, nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+
+			resp := recorder.Result()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Error(err)
+			}
+
+			assert.Equal(t, tc.statusCode, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+			if tc.statusCode != 202 {
+				return
+			}
+
+			m := map[string]interface{}{}
+			if err = json.Unmarshal(body, &m); err != nil {
+				t.Error(err)
+			}
+			run := m["data"].(map[string]interface{})
+			assert.Equal(t, "queued", run["status"])
+			assert.Equal(t, true, run["fresh"])
+			assert.Equal(t, fmt.Sprintf("%d", tc.projectID), run["project_id"])
+
+			location := fmt.Sprintf("/projects/%d/schedule/runs/%s", tc.projectID, run["id"])
+			assert.Equal(t, location, resp.Header.Get("Location"))
+
+			// Poll the run until the worker has finished it
+			var finishedRun map[string]interface{}
+			for i := 0; i < 120; i++ {
+				request, err := http.NewRequest("GET", location, nil)
+				require.NoError(t, err)
+
+				recorder := httptest.NewRecorder()
+				mux.ServeHTTP(recorder, request)
+
+				resp := recorder.Result()
+				body, err := io.ReadAll(resp.Body)
+				if err != nil {
+					t.Error(err)
+				}
+				assert.Equal(t, 200, resp.StatusCode)
+
+				m := map[string]interface{}{}
+				if err = json.Unmarshal(body, &m); err != nil {
+					t.Error(err)
+				}
+				run := m["data"].(map[string]interface{})
+				if run["status"] == "succeeded" || run["status"] == "failed" {
+					finishedRun = run
+					break
+				}
+				time.Sleep(500 * time.Millisecond)
+			}
+			require.NotNil(t, finishedRun)
+			assert.Equal(t, tc.runStatus, finishedRun["status"])
+			assert.Equal(t, tc.runError, finishedRun["error"])
+			assert.NotNil(t, finishedRun["started_at"])
+			assert.NotNil(t, finishedRun["finished_at"])
+		})
+	}
+}
+
+func TestGetScheduleRun(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	testCases := []struct {
+		name       string
+		statusCode int
+		projectID  int
+		runID      int
+		resBody    map[string]interface{}
+	}{
+		{
+			name:       "Invalid project ID",
+			statusCode: 404,
+			projectID:  123,
+			runID:      123,
+			resBody: map[string]interface{}{
+				"error": "Not Found",
+				"code":  "404",
+			},
+		},
+		{
+			name:       "Invalid run ID",
+			statusCode: 404,
+			projectID:  3909655254191459782,
+			runID:      123,
+			resBody: map[string]interface{}{
+				"error": "Not Found",
+				"code":  "404",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/projects/%d/schedule/runs/%d", tc.projectID, tc.runID)
+			request, err := http.NewRequest("GET", url, nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+
+			resp := recorder.Result()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Error(err)
+			}
+
+			assert.Equal(t, tc.statusCode, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+			m := map[string]interface{}{}
+			if err = json.Unmarshal(body, &m); err != nil {
+				t.Error(err)
+			}
+			assert.Equal(t, tc.resBody, m)
+		})
+	}
+}
+
+func TestListScheduleRuns(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	testCases := []struct {
+		name       string
+		statusCode int
+		projectID  int
+		resBody    map[string]interface{}
+	}{
+		{
+			name:       "Invalid ID",
+			statusCode: 404,
+			projectID:  123,
+			resBody: map[string]interface{}{
+				"error": "Not Found",
+				"code":  "404",
+			},
+		},
+		{
+			name:       "No runs",
+			statusCode: 200,
+			projectID:  3909655254191459782,
+			resBody: map[string]interface{}{
+				"data":    []interface{}{},
+				"code":    "200",
+				"message": "OK",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/projects/%d/schedule/runs", tc.projectID)
+			request, err := http.NewRequest("GET", url, nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+
+			resp := recorder.Result()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Error(err)
+			}
+
+			assert.Equal(t, tc.statusCode, resp.StatusCode)
+			assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+			m := map[string]interface{}{}
+			if err = json.Unmarshal(body, &m); err != nil {
+				t.Error(err)
+			}
+			assert.Equal(t, tc.resBody, m)
+		})
+	}
+}
+
+func TestConcurrentScheduleRuns(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	server.StartWorkers()
+	defer server.Close()
+	mux := server.Router
+
+	// The runs of the same project are executed one after the other by the workers
+	projectID := int64(3909655254191459782)
+	for i := 0; i < 2; i++ {
+		request, err := http.NewRequest("POST", fmt.Sprintf("/projects/%d/schedule?async=true", projectID), nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		require.Equal(t, 202, recorder.Code)
+	}
+
+	var unfinished int
+	for i := 0; i < 120; i++ {
+		sql := "SELECT COUNT(*) FROM schedule_runs WHERE project_id = $1 AND status IN ('queued', 'running')"
+		require.NoError(t, conn.QueryRow(context.Background(), sql, projectID).Scan(&unfinished))
+		if unfinished == 0 {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	require.Equal(t, 0, unfinished)
+
+	var succeeded, versions, active int
+	sql := "SELECT COUNT(*) FROM schedule_runs WHERE project_id = $1 AND status = 'succeeded'"
+	require.NoError(t, conn.QueryRow(context.Background(), sql, projectID).Scan(&succeeded))
+	assert.Equal(t, 2, succeeded)
+	sql = "SELECT COUNT(DISTINCT version), COUNT(*) FILTER (WHERE active) FROM schedule_versions WHERE project_id = $1"
+	require.NoError(t, conn.QueryRow(context.Background(), sql, projectID).Scan(&versions, &active))
+	assert.Equal(t, 2, versions)
+	assert.Equal(t, 1, active)
+}
+
+func TestRecoverStaleScheduleRuns(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+
+	// The runs left unfinished by a stopped server, without a recent heartbeat, are recovered
+	projectID := int64(3909655254191459782)
+	var staleRunID int64
+	err := conn.QueryRow(context.Background(), `
+	INSERT INTO schedule_runs (project_id, fresh, status, owner, heartbeat_at) VALUES
+		($1, TRUE, 'queued', 1, current_timestamp - INTERVAL '1 hour'),
+		($1, FALSE, 'running', 1, current_timestamp - INTERVAL '1 hour'),
+		($1, FALSE, 'succeeded', 1, current_timestamp - INTERVAL '1 hour')
+	RETURNING id`,
+		projectID).Scan(&staleRunID)
+	require.NoError(t, err)
+
+	// The runs of a live server are kept
+	var liveRunID int64
+	err = conn.QueryRow(context.Background(), `
+	INSERT INTO schedule_runs (project_id, fresh, status, owner) VALUES ($1, TRUE, 'queued', 2) RETURNING id`,
+		projectID).Scan(&liveRunID)
+	require.NoError(t, err)
+	require.NoError(t, server.DBFailStaleScheduleRuns(context.Background(), time.Minute))
+
+	runs, err := server.DBListScheduleRuns(context.Background(), projectID)
+	require.NoError(t, err)
+	statuses := map[string]int{}
+	for _, run := range runs {
+		statuses[run.Status]++
+		if run.Status == "failed" {
+			require.NotNil(t, run.Error)
+			assert.Equal(t, "The server was stopped before the run was finished", *run.Error)
+			assert.NotNil(t, run.FinishedAt)
+		}
+	}
+	assert.Equal(t, map[string]int{"failed": 1, "queued": 2, "succeeded": 1}, statuses)
+
+	// A failed run is not started again
+	var failedRunID int64
+	sql := "SELECT id FROM schedule_runs WHERE project_id = $1 AND status = 'failed' LIMIT 1"
+	require.NoError(t, conn.QueryRow(context.Background(), sql, projectID).Scan(&failedRunID))
+	started, err := server.DBStartScheduleRun(context.Background(), failedRunID, 3)
+	require.NoError(t, err)
+	assert.False(t, started)
+
+	// The queued run of the stopped server is claimed by another server, which starts it
+	claimed, err := server.DBClaimScheduleRuns(context.Background(), 3, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, staleRunID, claimed[0].ID)
+	started, err = server.DBStartScheduleRun(context.Background(), staleRunID, 3)
+	require.NoError(t, err)
+	assert.True(t, started)
+
+	// The queued run of the live server is only claimed once released
+	started, err = server.DBStartScheduleRun(context.Background(), liveRunID, 3)
+	require.NoError(t, err)
+	assert.False(t, started)
+	require.NoError(t, server.DBReleaseScheduleRuns(context.Background(), 2))
+	claimed, err = server.DBClaimScheduleRuns(context.Background(), 3, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, liveRunID, claimed[0].ID)
+	started, err = server.DBStartScheduleRun(context.Background(), liveRunID, 2)
+	require.NoError(t, err)
+	assert.False(t, started)
+
+	// A running run which is queued again is claimed by another server
+	require.NoError(t, server.DBRequeueScheduleRun(context.Background(), staleRunID))
+	claimed, err = server.DBClaimScheduleRuns(context.Background(), 4, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, staleRunID, claimed[0].ID)
+	assert.Nil(t, claimed[0].StartedAt)
+}
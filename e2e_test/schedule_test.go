@@ -29,16 +29,19 @@ along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
 package e2etest
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/xuri/excelize/v2"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -50,6 +53,8 @@ func TestCreateSchedule(t *testing.T) {
 	defer conn.Close()
 	mux := server.Router
 
+	// The locations of the test data can not be reached from each other by road, so that the distance between
+	// two different locations is util.UnreachableDistance
 	testCases := []struct {
 		name       string
 		statusCode int
@@ -95,12 +100,14 @@ func TestCreateSchedule(t *testing.T) {
 										"latitude":  -32.234,
 										"longitude": -23.2342,
 									},
-									"arrival":      "2020-01-01T10:10:00",
-									"departure":    "2020-01-01T10:10:00",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:00",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-01T10:10:00",
+									"departure":           "2020-01-01T10:10:00",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:00",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -114,12 +121,14 @@ func TestCreateSchedule(t *testing.T) {
 										"latitude":  -81.23,
 										"longitude": 12.0,
 									},
-									"arrival":      "2020-01-01T10:44:19",
-									"departure":    "2020-01-01T10:44:19",
-									"travel_time":  "00:34:19",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:00",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-01T10:44:19",
+									"departure":           "2020-01-01T10:44:19",
+									"travel_time":         "00:34:19",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:00",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(util.UnreachableDistance),
+									"cumulative_distance": float64(util.UnreachableDistance),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -135,12 +144,14 @@ func TestCreateSchedule(t *testing.T) {
 										"latitude":  -32.234,
 										"longitude": -23.2342,
 									},
-									"arrival":      "2020-01-01T11:18:38",
-									"departure":    "2020-01-01T11:18:39",
-									"travel_time":  "00:34:19",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:01",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-01T11:18:38",
+									"departure":           "2020-01-01T11:18:39",
+									"travel_time":         "00:34:19",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:01",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(util.UnreachableDistance),
+									"cumulative_distance": float64(2 * util.UnreachableDistance),
 									"load": []interface{}{
 										float64(3),
 										float64(5),
@@ -154,12 +165,14 @@ func TestCreateSchedule(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-01-01T11:52:58",
-									"departure":    "2020-01-01T11:53:01",
-									"travel_time":  "00:34:19",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:03",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-01T11:52:58",
+									"departure":           "2020-01-01T11:53:01",
+									"travel_time":         "00:34:19",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:03",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(util.UnreachableDistance),
+									"cumulative_distance": float64(3 * util.UnreachableDistance),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -173,12 +186,14 @@ func TestCreateSchedule(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-01-01T11:53:01",
-									"departure":    "2020-01-01T11:58:25",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:05:24",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-01T11:53:01",
+									"departure":           "2020-01-01T11:58:25",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:05:24",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(3 * util.UnreachableDistance),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -192,12 +207,14 @@ func TestCreateSchedule(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-01-01T11:58:25",
-									"departure":    "2020-01-01T11:58:25",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:00",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-01T11:58:25",
+									"departure":           "2020-01-01T11:58:25",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:00",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(3 * util.UnreachableDistance),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -210,21 +227,25 @@ func TestCreateSchedule(t *testing.T) {
 					"metadata": map[string]interface{}{
 						"summary": []interface{}{
 							map[string]interface{}{
-								"vehicle_id":   "7300272137290532980",
-								"travel_time":  "01:42:57",
-								"setup_time":   "00:00:00",
-								"service_time": "00:05:28",
-								"waiting_time": "00:00:00",
+								"vehicle_id":     "7300272137290532980",
+								"travel_time":    "01:42:57",
+								"setup_time":     "00:00:00",
+								"service_time":   "00:05:28",
+								"waiting_time":   "00:00:00",
+								"total_distance": float64(3 * util.UnreachableDistance),
+								"cost":           float64(6505),
 								"vehicle_data": map[string]interface{}{
 									"s": float64(1),
 								},
 							},
 						},
-						"unassigned":    []interface{}{},
-						"total_travel":  "01:42:57",
-						"total_setup":   "00:00:00",
-						"total_service": "00:05:28",
-						"total_waiting": "00:00:00",
+						"unassigned":     []interface{}{},
+						"total_travel":   "01:42:57",
+						"total_setup":    "00:00:00",
+						"total_service":  "00:05:28",
+						"total_waiting":  "00:00:00",
+						"total_distance": float64(3 * util.UnreachableDistance),
+						"total_cost":     float64(6505),
 					},
 					"project_id": "3909655254191459782",
 				},
@@ -252,12 +273,14 @@ func TestCreateSchedule(t *testing.T) {
 										"latitude":  -32.234,
 										"longitude": -23.2342,
 									},
-									"arrival":      "2020-10-09T23:27:06",
-									"departure":    "2020-10-09T23:27:06",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:00",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-10-09T23:27:06",
+									"departure":           "2020-10-09T23:27:06",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:00",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -271,12 +294,14 @@ func TestCreateSchedule(t *testing.T) {
 										"latitude":  -32.234,
 										"longitude": -23.2342,
 									},
-									"arrival":      "2020-10-09T23:27:06",
-									"departure":    "2020-10-09T23:28:07",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:01:01",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-10-09T23:27:06",
+									"departure":           "2020-10-09T23:28:07",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:01:01",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(6),
 										float64(8),
@@ -290,12 +315,14 @@ func TestCreateSchedule(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-10-10T00:00:00",
-									"departure":    "2020-10-10T00:02:03",
-									"travel_time":  "00:31:53",
-									"setup_time":   "00:00:00",
-									"service_time": "00:02:03",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-10-10T00:00:00",
+									"departure":           "2020-10-10T00:02:03",
+									"travel_time":         "00:31:53",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:02:03",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(util.UnreachableDistance),
+									"cumulative_distance": float64(util.UnreachableDistance),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -309,12 +336,14 @@ func TestCreateSchedule(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-10-10T00:02:03",
-									"departure":    "2020-10-10T00:02:03",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:00",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-10-10T00:02:03",
+									"departure":           "2020-10-10T00:02:03",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:00",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(util.UnreachableDistance),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -327,11 +356,13 @@ func TestCreateSchedule(t *testing.T) {
 					"metadata": map[string]interface{}{
 						"summary": []interface{}{
 							map[string]interface{}{
-								"vehicle_id":   "150202809001685363",
-								"travel_time":  "00:31:53",
-								"setup_time":   "00:00:00",
-								"service_time": "00:03:04",
-								"waiting_time": "00:00:00",
+								"vehicle_id":     "150202809001685363",
+								"travel_time":    "00:31:53",
+								"setup_time":     "00:00:00",
+								"service_time":   "00:03:04",
+								"waiting_time":   "00:00:00",
+								"total_distance": float64(util.UnreachableDistance),
+								"cost":           float64(2097),
 								"vehicle_data": map[string]interface{}{
 									"s": float64(1),
 								},
@@ -348,6 +379,10 @@ func TestCreateSchedule(t *testing.T) {
 								"task_data": map[string]interface{}{
 									"key": "value",
 								},
+								"reasons": []interface{}{
+									"No vehicle has the skills [5, 50, 100]",
+									"The amount [20, 30] exceeds the capacity of every vehicle",
+								},
 							},
 							map[string]interface{}{
 								"type":    "job",
@@ -362,6 +397,9 @@ func TestCreateSchedule(t *testing.T) {
 										2.0,
 									},
 								},
+								"reasons": []interface{}{
+									"The task is feasible on its own, but does not fit in the routes along with the other tasks",
+								},
 							},
 							map[string]interface{}{
 								"type":    "pickup",
@@ -371,6 +409,9 @@ func TestCreateSchedule(t *testing.T) {
 									"longitude": -23.2342,
 								},
 								"task_data": map[string]interface{}{},
+								"reasons": []interface{}{
+									"No vehicle has the skills [5, 10]",
+								},
 							},
 							map[string]interface{}{
 								"type":    "delivery",
@@ -380,12 +421,17 @@ func TestCreateSchedule(t *testing.T) {
 									"longitude": 2.3242,
 								},
 								"task_data": map[string]interface{}{},
+								"reasons": []interface{}{
+									"No vehicle has the skills [5, 10]",
+								},
 							},
 						},
-						"total_travel":  "00:31:53",
-						"total_setup":   "00:00:00",
-						"total_service": "00:03:04",
-						"total_waiting": "00:00:00",
+						"total_travel":   "00:31:53",
+						"total_setup":    "00:00:00",
+						"total_service":  "00:03:04",
+						"total_waiting":  "00:00:00",
+						"total_distance": float64(util.UnreachableDistance),
+						"total_cost":     float64(2097),
 					},
 					"project_id": "2593982828701335033",
 				},
@@ -443,6 +489,75 @@ func TestCreateSchedule(t *testing.T) {
 	}
 }
 
+func TestCreateScheduleDistances(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	// The euclidean distances do not depend on the routing engine
+	_, err := conn.Exec(context.Background(), "UPDATE projects SET duration_calc = 'euclidean' WHERE id = 3909655254191459783")
+	require.NoError(t, err)
+
+	request, err := http.NewRequest("POST", "/projects/3909655254191459783/schedule?fresh=true", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+	require.Equal(t, 201, recorder.Code)
+	m := map[string]interface{}{}
+	require.NoError(t, json.NewDecoder(recorder.Result().Body).Decode(&m))
+
+	// The only vehicle drives from its start location to the job, at the end location of the vehicle
+	data := m["data"].(map[string]interface{})
+	schedules := data["schedule"].([]interface{})
+	require.Len(t, schedules, 1)
+	route := schedules[0].(map[string]interface{})["route"].([]interface{})
+	require.Len(t, route, 3)
+	distances := [][]interface{}{}
+	for _, step := range route {
+		step := step.(map[string]interface{})
+		distances = append(distances, []interface{}{step["type"], step["distance"], step["cumulative_distance"]})
+	}
+	assert.Equal(t, [][]interface{}{
+		{"start", float64(0), float64(0)},
+		{"job", float64(6750411), float64(6750411)},
+		{"end", float64(0), float64(6750411)},
+	}, distances)
+
+	metadata := data["metadata"].(map[string]interface{})
+	assert.Equal(t, float64(6750411), metadata["summary"].([]interface{})[0].(map[string]interface{})["total_distance"])
+	assert.Equal(t, float64(6750411), metadata["total_distance"])
+}
+
+func TestCreateScheduleVehicleLimits(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	// The only vehicle of the project cannot leave its start location
+	_, err := conn.Exec(context.Background(), "UPDATE vehicles SET max_distance = 1 WHERE id = 150202809001685363")
+	require.NoError(t, err)
+
+	request, err := http.NewRequest("POST", "/projects/2593982828701335033/schedule?fresh=true", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+	require.Equal(t, 201, recorder.Code)
+	m := map[string]interface{}{}
+	require.NoError(t, json.NewDecoder(recorder.Result().Body).Decode(&m))
+
+	// The tasks exceeding the max distance of the vehicle are unassigned
+	data := m["data"].(map[string]interface{})
+	assert.Empty(t, data["schedule"])
+	unassigned := data["metadata"].(map[string]interface{})["unassigned"].([]interface{})
+	assert.Len(t, unassigned, 6)
+	for _, task := range unassigned {
+		assert.Contains(t, task.(map[string]interface{})["reasons"],
+			"The travel time or the distance of a route serving the task exceeds the max_travel_time or the max_distance of every vehicle")
+	}
+}
+
 func TestGetScheduleJson(t *testing.T) {
 	test_db := NewTestDatabase(t)
 	server, conn := setup(test_db, "testdata.sql")
@@ -472,12 +587,14 @@ func TestGetScheduleJson(t *testing.T) {
 				"data": map[string]interface{}{
 					"schedule": []interface{}{},
 					"metadata": map[string]interface{}{
-						"summary":       []interface{}{},
-						"unassigned":    []interface{}{},
-						"total_setup":   "00:00:00",
-						"total_service": "00:00:00",
-						"total_travel":  "00:00:00",
-						"total_waiting": "00:00:00",
+						"summary":        []interface{}{},
+						"unassigned":     []interface{}{},
+						"total_setup":    "00:00:00",
+						"total_service":  "00:00:00",
+						"total_travel":   "00:00:00",
+						"total_waiting":  "00:00:00",
+						"total_distance": float64(0),
+						"total_cost":     float64(0),
 					},
 				},
 				"code":    "200",
@@ -504,12 +621,14 @@ func TestGetScheduleJson(t *testing.T) {
 										"latitude":  -32.234,
 										"longitude": -23.2342,
 									},
-									"arrival":      "2020-01-01T10:10:00",
-									"departure":    "2020-01-01T10:10:00",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:00",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-01T10:10:00",
+									"departure":           "2020-01-01T10:10:00",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:00",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -525,12 +644,14 @@ func TestGetScheduleJson(t *testing.T) {
 										"latitude":  -32.234,
 										"longitude": -23.2342,
 									},
-									"arrival":      "2020-01-01T10:10:00",
-									"departure":    "2020-01-01T10:10:01",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:01",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-01T10:10:00",
+									"departure":           "2020-01-01T10:10:01",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:01",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(3),
 										float64(5),
@@ -546,12 +667,14 @@ func TestGetScheduleJson(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-01-03T20:52:34",
-									"departure":    "2020-01-03T20:52:37",
-									"travel_time":  "58:42:33",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:03",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-03T20:52:34",
+									"departure":           "2020-01-03T20:52:37",
+									"travel_time":         "58:42:33",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:03",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -567,12 +690,14 @@ func TestGetScheduleJson(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-01-03T20:52:37",
-									"departure":    "2020-01-03T20:58:01",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:05:24",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-03T20:52:37",
+									"departure":           "2020-01-03T20:58:01",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:05:24",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -588,12 +713,14 @@ func TestGetScheduleJson(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-01-03T20:58:01",
-									"departure":    "2020-01-03T20:58:01",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:00",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-03T20:58:01",
+									"departure":           "2020-01-03T20:58:01",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:00",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -608,21 +735,25 @@ func TestGetScheduleJson(t *testing.T) {
 					"metadata": map[string]interface{}{
 						"summary": []interface{}{
 							map[string]interface{}{
-								"vehicle_id":   "7300272137290532980",
-								"setup_time":   "00:00:00",
-								"service_time": "00:05:28",
-								"travel_time":  "58:42:33",
-								"waiting_time": "00:00:00",
+								"vehicle_id":     "7300272137290532980",
+								"setup_time":     "00:00:00",
+								"service_time":   "00:05:28",
+								"travel_time":    "58:42:33",
+								"waiting_time":   "00:00:00",
+								"total_distance": float64(0),
+								"cost":           float64(211681),
 								"vehicle_data": map[string]interface{}{
 									"s": float64(1),
 								},
 							},
 						},
-						"unassigned":    []interface{}{},
-						"total_setup":   "00:00:00",
-						"total_service": "00:05:28",
-						"total_travel":  "58:42:33",
-						"total_waiting": "00:00:00",
+						"unassigned":     []interface{}{},
+						"total_setup":    "00:00:00",
+						"total_service":  "00:05:28",
+						"total_travel":   "58:42:33",
+						"total_waiting":  "00:00:00",
+						"total_distance": float64(0),
+						"total_cost":     float64(211681),
 					},
 					"project_id": "3909655254191459782",
 				},
@@ -660,6 +791,341 @@ func TestGetScheduleJson(t *testing.T) {
 	}
 }
 
+func TestGetScheduleGeometry(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	// use straight segments between the locations, as the road path depends on the routing engine
+	_, err := conn.Exec(context.Background(), "UPDATE projects SET duration_calc = 'euclidean' WHERE id = 3909655254191459782")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		statusCode     int
+		projectID      int
+		geometryFormat string
+		resBody        map[string]interface{}
+	}{
+		{
+			name:           "Invalid geometry format",
+			statusCode:     400,
+			projectID:      3909655254191459782,
+			geometryFormat: "wkt",
+			resBody: map[string]interface{}{
+				"code":    "400",
+				"message": "Bad Request",
+				"errors":  []interface{}{"Invalid geometry format 'wkt', must be one out of geojson, polyline"},
+			},
+		},
+		{
+			name:           "GeoJSON geometry",
+			statusCode:     200,
+			projectID:      3909655254191459782,
+			geometryFormat: "geojson",
+		},
+		{
+			name:           "Polyline geometry",
+			statusCode:     200,
+			projectID:      3909655254191459782,
+			geometryFormat: "polyline",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/projects/%d/schedule?geometry=true&geometry_format=%s", tc.projectID, tc.geometryFormat)
+			request, err := http.NewRequest("GET", url, nil)
+			// Set the Accept headers to return json
+			request.Header.Set("Accept", "application/json")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+
+			resp := recorder.Result()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Error(err)
+			}
+
+			assert.Equal(t, tc.statusCode, resp.StatusCode)
+			m := map[string]interface{}{}
+			if err = json.Unmarshal(body, &m); err != nil {
+				t.Error(err)
+			}
+			if tc.resBody != nil {
+				assert.Equal(t, tc.resBody, m)
+				return
+			}
+
+			schedules := m["data"].(map[string]interface{})["schedule"].([]interface{})
+			require.NotEmpty(t, schedules)
+			for _, s := range schedules {
+				schedule := s.(map[string]interface{})
+
+				// the geometry passes through the distinct consecutive locations of the route
+				expected := [][]float64{}
+				for _, r := range schedule["route"].([]interface{}) {
+					location := r.(map[string]interface{})["location"].(map[string]interface{})
+					coordinate := []float64{location["longitude"].(float64), location["latitude"].(float64)}
+					if len(expected) == 0 || expected[len(expected)-1][0] != coordinate[0] || expected[len(expected)-1][1] != coordinate[1] {
+						expected = append(expected, coordinate)
+					}
+				}
+
+				var coordinates [][]float64
+				if tc.geometryFormat == "polyline" {
+					coordinates, err = util.DecodePolyline(schedule["geometry"].(string), 5)
+					require.NoError(t, err)
+				} else {
+					geometry := schedule["geometry"].(map[string]interface{})
+					assert.Equal(t, "LineString", geometry["type"])
+					for _, c := range geometry["coordinates"].([]interface{}) {
+						coordinate := c.([]interface{})
+						coordinates = append(coordinates, []float64{coordinate[0].(float64), coordinate[1].(float64)})
+					}
+				}
+				require.Equal(t, len(expected), len(coordinates))
+				for i := range expected {
+					assert.InDelta(t, expected[i][0], coordinates[i][0], 1e-4)
+					assert.InDelta(t, expected[i][1], coordinates[i][1], 1e-4)
+				}
+			}
+		})
+	}
+}
+
+func TestGetScheduleGeoJSON(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	stop := func(taskType string, taskID string, coordinates []interface{}, arrival string, departure string, load []interface{}) interface{} {
+		return map[string]interface{}{
+			"type": "Feature",
+			"geometry": map[string]interface{}{
+				"type":        "Point",
+				"coordinates": coordinates,
+			},
+			"properties": map[string]interface{}{
+				"type":       taskType,
+				"task_id":    taskID,
+				"vehicle_id": "7300272137290532980",
+				"arrival":    arrival,
+				"departure":  departure,
+				"load":       load,
+				"unassigned": false,
+			},
+		}
+	}
+	startLocation := []interface{}{-23.2342, -32.234}
+	endLocation := []interface{}{2.3242, 23.3458}
+
+	testCases := []struct {
+		name       string
+		statusCode int
+		url        string
+		resBody    map[string]interface{}
+	}{
+		{
+			name:       "Invalid ID",
+			statusCode: 404,
+			url:        "/projects/123/schedule",
+			resBody: map[string]interface{}{
+				"error": "Not Found",
+				"code":  "404",
+			},
+		},
+		{
+			name:       "Valid ID, no schedule",
+			statusCode: 200,
+			url:        "/projects/2593982828701335033/schedule",
+			resBody: map[string]interface{}{
+				"type":     "FeatureCollection",
+				"features": []interface{}{},
+			},
+		},
+		{
+			name:       "Project schedule",
+			statusCode: 200,
+			url:        "/projects/3909655254191459782/schedule",
+			resBody: map[string]interface{}{
+				"type": "FeatureCollection",
+				"features": []interface{}{
+					map[string]interface{}{
+						"type": "Feature",
+						"geometry": map[string]interface{}{
+							"type":        "LineString",
+							"coordinates": []interface{}{startLocation, endLocation},
+						},
+						"properties": map[string]interface{}{
+							"type":       "route",
+							"vehicle_id": "7300272137290532980",
+						},
+					},
+					stop("start", "-1", startLocation, "2020-01-01T10:10:00", "2020-01-01T10:10:00", []interface{}{float64(0), float64(0)}),
+					stop("pickup", "3341766951177830852", startLocation, "2020-01-01T10:10:00", "2020-01-01T10:10:01", []interface{}{float64(3), float64(5)}),
+					stop("delivery", "3341766951177830852", endLocation, "2020-01-03T20:52:34", "2020-01-03T20:52:37", []interface{}{float64(0), float64(0)}),
+					stop("break", "2349284092384902582", endLocation, "2020-01-03T20:52:37", "2020-01-03T20:58:01", []interface{}{float64(0), float64(0)}),
+					stop("end", "-1", endLocation, "2020-01-03T20:58:01", "2020-01-03T20:58:01", []interface{}{float64(0), float64(0)}),
+				},
+			},
+		},
+		{
+			name:       "Shipment schedule",
+			statusCode: 200,
+			url:        "/shipments/3341766951177830852/schedule",
+			resBody: map[string]interface{}{
+				"type": "FeatureCollection",
+				"features": []interface{}{
+					stop("pickup", "3341766951177830852", startLocation, "2020-01-01T10:10:00", "2020-01-01T10:10:01", []interface{}{float64(3), float64(5)}),
+					stop("delivery", "3341766951177830852", endLocation, "2020-01-03T20:52:34", "2020-01-03T20:52:37", []interface{}{float64(0), float64(0)}),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest("GET", tc.url, nil)
+			// Set the Accept headers to return geojson
+			request.Header.Set("Accept", "application/geo+json")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+
+			resp := recorder.Result()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Error(err)
+			}
+
+			assert.Equal(t, tc.statusCode, resp.StatusCode)
+			if tc.statusCode == 200 {
+				assert.Equal(t, "application/geo+json", resp.Header.Get("Content-Type"))
+			}
+			m := map[string]interface{}{}
+			if err = json.Unmarshal(body, &m); err != nil {
+				t.Error(err)
+			}
+			assert.Equal(t, tc.resBody, m)
+		})
+	}
+}
+
+func TestGetScheduleCSV(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	testCases := []struct {
+		name       string
+		statusCode int
+		projectID  int
+		resBody    string
+	}{
+		{
+			name:       "Valid ID, no schedule",
+			statusCode: 200,
+			projectID:  2593982828701335033,
+			resBody: "vehicle_id,sequence,type,task_id,latitude,longitude,arrival,departure,travel_time,setup_time,service_time,waiting_time,distance,load\n" +
+				"\n" +
+				"Unassigned\n" +
+				"type,task_id,latitude,longitude\n" +
+				"\n" +
+				"Summary\n" +
+				"vehicle_id,travel_time,setup_time,service_time,waiting_time,distance,cost\n",
+		},
+		{
+			name:       "Valid ID",
+			statusCode: 200,
+			projectID:  3909655254191459782,
+			resBody: "vehicle_id,sequence,type,task_id,latitude,longitude,arrival,departure,travel_time,setup_time,service_time,waiting_time,distance,load\n" +
+				"7300272137290532980,1,start,-1,-32.234,-23.2342,2020-01-01T10:10:00,2020-01-01T10:10:00,00:00:00,00:00:00,00:00:00,00:00:00,0,\"[0,0]\"\n" +
+				"7300272137290532980,2,pickup,3341766951177830852,-32.234,-23.2342,2020-01-01T10:10:00,2020-01-01T10:10:01,00:00:00,00:00:00,00:00:01,00:00:00,0,\"[3,5]\"\n" +
+				"7300272137290532980,3,delivery,3341766951177830852,23.3458,2.3242,2020-01-03T20:52:34,2020-01-03T20:52:37,58:42:33,00:00:00,00:00:03,00:00:00,0,\"[0,0]\"\n" +
+				"7300272137290532980,4,break,2349284092384902582,23.3458,2.3242,2020-01-03T20:52:37,2020-01-03T20:58:01,00:00:00,00:00:00,00:05:24,00:00:00,0,\"[0,0]\"\n" +
+				"7300272137290532980,5,end,-1,23.3458,2.3242,2020-01-03T20:58:01,2020-01-03T20:58:01,00:00:00,00:00:00,00:00:00,00:00:00,0,\"[0,0]\"\n" +
+				"\n" +
+				"Unassigned\n" +
+				"type,task_id,latitude,longitude\n" +
+				"\n" +
+				"Summary\n" +
+				"vehicle_id,travel_time,setup_time,service_time,waiting_time,distance,cost\n" +
+				"7300272137290532980,58:42:33,00:00:00,00:05:28,00:00:00,0,211681\n" +
+				"total,58:42:33,00:00:00,00:05:28,00:00:00,0,211681\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/projects/%d/schedule", tc.projectID)
+			request, err := http.NewRequest("GET", url, nil)
+			// Set the Accept headers to return csv
+			request.Header.Set("Accept", "text/csv")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+
+			resp := recorder.Result()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Error(err)
+			}
+
+			assert.Equal(t, tc.statusCode, resp.StatusCode)
+			assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+			assert.Equal(t, fmt.Sprintf("attachment; filename=schedule-%d.csv", tc.projectID), resp.Header.Get("Content-Disposition"))
+			assert.Equal(t, tc.resBody, string(body))
+		})
+	}
+}
+
+func TestGetScheduleXLSX(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	request, err := http.NewRequest("GET", "/projects/3909655254191459782/schedule", nil)
+	// Set the Accept headers to return xlsx
+	request.Header.Set("Accept", util.XLSXContentType)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+
+	resp := recorder.Result()
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, util.XLSXContentType, resp.Header.Get("Content-Type"))
+	assert.Equal(t, "attachment; filename=schedule-3909655254191459782.xlsx", resp.Header.Get("Content-Disposition"))
+
+	f, err := excelize.OpenReader(resp.Body)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{"Schedule", "Unassigned", "Summary"}, f.GetSheetList())
+
+	rows, err := f.GetRows("Schedule")
+	require.NoError(t, err)
+	require.Equal(t, 6, len(rows))
+	assert.Equal(t, []string{"vehicle_id", "sequence", "type", "task_id", "latitude", "longitude", "arrival", "departure", "travel_time", "setup_time", "service_time", "waiting_time", "distance", "load"}, rows[0])
+	assert.Equal(t, []string{"7300272137290532980", "3", "delivery", "3341766951177830852", "23.3458", "2.3242", "2020-01-03T20:52:34", "2020-01-03T20:52:37", "58:42:33", "00:00:00", "00:00:03", "00:00:00", "0", "[0,0]"}, rows[3])
+
+	rows, err = f.GetRows("Summary")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"vehicle_id", "travel_time", "setup_time", "service_time", "waiting_time", "distance", "cost"},
+		{"7300272137290532980", "58:42:33", "00:00:00", "00:05:28", "00:00:00", "0", "211681"},
+		{"total", "58:42:33", "00:00:00", "00:05:28", "00:00:00", "0", "211681"},
+	}, rows)
+}
+
 func TestGetScheduleICal(t *testing.T) {
 	test_db := NewTestDatabase(t)
 	server, conn := setup(test_db, "testdata.sql")
@@ -835,3 +1301,269 @@ func TestDeleteSchedule(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareSchedules(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	// Clone the project along with its schedule
+	request, err := http.NewRequest("POST", "/projects/3909655254191459782/clone?schedule=true", nil)
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+	require.Equal(t, 201, recorder.Code)
+	cloneBody := map[string]interface{}{}
+	require.NoError(t, json.NewDecoder(recorder.Result().Body).Decode(&cloneBody))
+	clonedProjectID := cloneBody["data"].(map[string]interface{})["project"].(map[string]interface{})["id"].(string)
+
+	zeroDelta := map[string]interface{}{
+		"travel_time":  "00:00:00",
+		"setup_time":   "00:00:00",
+		"service_time": "00:00:00",
+		"waiting_time": "00:00:00",
+		"distance":     float64(0),
+		"cost":         float64(0),
+	}
+
+	testCases := []struct {
+		name           string
+		statusCode     int
+		projectID      string
+		otherProjectID string
+	}{
+		{
+			name:           "Invalid ID",
+			statusCode:     404,
+			projectID:      "100",
+			otherProjectID: clonedProjectID,
+		},
+		{
+			name:           "Invalid other ID",
+			statusCode:     404,
+			projectID:      "3909655254191459782",
+			otherProjectID: "100",
+		},
+		{
+			name:           "Cloned project",
+			statusCode:     200,
+			projectID:      "3909655254191459782",
+			otherProjectID: clonedProjectID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			url := fmt.Sprintf("/projects/%s/schedule/compare/%s", tc.projectID, tc.otherProjectID)
+			request, err := http.NewRequest("GET", url, nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+
+			resp := recorder.Result()
+			assert.Equal(t, tc.statusCode, resp.StatusCode)
+			m := map[string]interface{}{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&m))
+			if tc.statusCode != 200 {
+				assert.Equal(t, map[string]interface{}{"error": "Not Found", "code": "404"}, m)
+				return
+			}
+
+			data := m["data"].(map[string]interface{})
+			assert.Equal(t, tc.otherProjectID, data["other_project_id"])
+			assert.Equal(t, zeroDelta, data["total"].(map[string]interface{})["delta"])
+
+			// The vehicles and tasks of the clone are matched with the original ones, without any change
+			vehicles := data["vehicles"].([]interface{})
+			require.NotEmpty(t, vehicles)
+			for _, vehicle := range vehicles {
+				vehicle := vehicle.(map[string]interface{})
+				assert.NotNil(t, vehicle["vehicle_id"])
+				assert.NotNil(t, vehicle["other_vehicle_id"])
+				assert.Equal(t, zeroDelta, vehicle["delta"])
+			}
+			tasks := data["tasks"].([]interface{})
+			require.NotEmpty(t, tasks)
+			for _, task := range tasks {
+				task := task.(map[string]interface{})
+				assert.NotEqual(t, task["task_id"], task["other_task_id"])
+				assert.Equal(t, []interface{}{}, task["changes"])
+			}
+		})
+	}
+}
+
+func TestEditSchedule(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	sendRequest := func(body string) (int, map[string]interface{}) {
+		request, err := http.NewRequest("PATCH", "/projects/3909655254191459782/schedule", strings.NewReader(body))
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		m := map[string]interface{}{}
+		require.NoError(t, json.NewDecoder(recorder.Result().Body).Decode(&m))
+		return recorder.Code, m
+	}
+	getVehicleRoute := func(m map[string]interface{}, vehicleID string) []interface{} {
+		for _, vehicle := range m["data"].(map[string]interface{})["schedule"].([]interface{}) {
+			vehicle := vehicle.(map[string]interface{})
+			if vehicle["vehicle_id"] == vehicleID {
+				return vehicle["route"].([]interface{})
+			}
+		}
+		return nil
+	}
+	getJobStatus := func(jobID int64) string {
+		var status string
+		err := conn.QueryRow(context.Background(), "SELECT status FROM jobs WHERE id = $1", jobID).Scan(&status)
+		require.NoError(t, err)
+		return status
+	}
+
+	_, err := conn.Exec(context.Background(), "UPDATE projects SET duration_calc = 'euclidean' WHERE id = 3909655254191459782")
+	require.NoError(t, err)
+
+	t.Run("Invalid operations", func(t *testing.T) {
+		statusCode, m := sendRequest(`[]`)
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, []interface{}{"Request body must contain the schedule edit operations"}, m["errors"])
+
+		statusCode, m = sendRequest(`{"operations": [{"op": "move", "type": "job", "task_id": "3324729385723589729", "vehicle_id": "2550908592071787332"}]}`)
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, []interface{}{"operations[0]: Job 3324729385723589729 is not assigned to a vehicle, use the 'insert' operation"}, m["errors"])
+
+		statusCode, m = sendRequest(`{"operations": [{"op": "move", "type": "pickup", "task_id": "3341766951177830852", "vehicle_id": "2550908592071787332"}]}`)
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, []interface{}{"Shipment 3341766951177830852: the pickup and the delivery must be in the same route, with the pickup before the delivery"}, m["errors"])
+	})
+
+	t.Run("Insert and remove a job", func(t *testing.T) {
+		statusCode, m := sendRequest(`{"operations": [{"op": "insert", "type": "job", "task_id": "3324729385723589729", "vehicle_id": "2550908592071787332"}], "force": true}`)
+		require.Equal(t, 200, statusCode)
+		route := getVehicleRoute(m, "2550908592071787332")
+		require.Len(t, route, 3)
+		assert.Equal(t, "job", route[1].(map[string]interface{})["type"])
+		assert.Equal(t, "3324729385723589729", route[1].(map[string]interface{})["task_id"])
+		assert.NotNil(t, m["data"].(map[string]interface{})["warnings"])
+		assert.Equal(t, "scheduled", getJobStatus(3324729385723589729))
+
+		// The other routes are not changed
+		assert.Len(t, getVehicleRoute(m, "7300272137290532980"), 5)
+
+		statusCode, m = sendRequest(`{"operations": [{"op": "remove", "type": "job", "task_id": "3324729385723589729"}]}`)
+		require.Equal(t, 200, statusCode)
+		assert.Nil(t, getVehicleRoute(m, "2550908592071787332"))
+		unassigned := m["data"].(map[string]interface{})["metadata"].(map[string]interface{})["unassigned"].([]interface{})
+		require.Len(t, unassigned, 1)
+		assert.Equal(t, "3324729385723589729", unassigned[0].(map[string]interface{})["task_id"])
+		assert.Equal(t, "unscheduled", getJobStatus(3324729385723589729))
+	})
+
+	t.Run("Reorder a route", func(t *testing.T) {
+		statusCode, m := sendRequest(`{"operations": [{"op": "reorder", "vehicle_id": "7300272137290532980", "stops": [
+			{"type": "pickup", "task_id": "3341766951177830852"},
+			{"type": "break", "task_id": "2349284092384902582"},
+			{"type": "delivery", "task_id": "3341766951177830852"}
+		]}], "force": true}`)
+		require.Equal(t, 200, statusCode)
+		route := getVehicleRoute(m, "7300272137290532980")
+		require.Len(t, route, 5)
+		assert.Equal(t, "break", route[2].(map[string]interface{})["type"])
+		assert.Equal(t, "delivery", route[3].(map[string]interface{})["type"])
+	})
+}
+
+func TestValidateSchedule(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	sendRequest := func(body string) (int, map[string]interface{}) {
+		request, err := http.NewRequest("POST", "/projects/3909655254191459782/schedule/validate", strings.NewReader(body))
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		m := map[string]interface{}{}
+		require.NoError(t, json.NewDecoder(recorder.Result().Body).Decode(&m))
+		return recorder.Code, m
+	}
+	getViolationTypes := func(m map[string]interface{}) []string {
+		types := []string{}
+		for _, violation := range m["data"].(map[string]interface{})["violations"].([]interface{}) {
+			types = append(types, violation.(map[string]interface{})["type"].(string))
+		}
+		return types
+	}
+
+	_, err := conn.Exec(context.Background(), "UPDATE projects SET duration_calc = 'euclidean' WHERE id = 3909655254191459782")
+	require.NoError(t, err)
+
+	t.Run("Invalid routes", func(t *testing.T) {
+		statusCode, m := sendRequest(`[]`)
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, []interface{}{"Request body must contain the proposed routes"}, m["errors"])
+
+		statusCode, m = sendRequest(`{"routes": [{"vehicle_id": "123", "stops": []}]}`)
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, []interface{}{"routes[0]: Vehicle with the given 'vehicle_id' does not exist in the project"}, m["errors"])
+
+		statusCode, m = sendRequest(`{"routes": [{"vehicle_id": "2550908592071787332", "stops": [{"type": "break", "task_id": "2349284092384902582"}]}]}`)
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, []interface{}{"routes[0].stops[0]: Break 2349284092384902582 can only be in the route of its vehicle"}, m["errors"])
+	})
+
+	t.Run("Delivery before pickup", func(t *testing.T) {
+		statusCode, m := sendRequest(`{"routes": [{"vehicle_id": "7300272137290532980", "stops": [
+			{"type": "delivery", "task_id": "3341766951177830852"},
+			{"type": "pickup", "task_id": "3341766951177830852"},
+			{"type": "break", "task_id": "2349284092384902582"}
+		]}]}`)
+		require.Equal(t, 200, statusCode)
+		data := m["data"].(map[string]interface{})
+		assert.Equal(t, false, data["feasible"])
+		assert.Contains(t, getViolationTypes(m), "pickup_delivery")
+
+		schedule := data["schedule"].([]interface{})
+		require.Len(t, schedule, 1)
+		route := schedule[0].(map[string]interface{})["route"].([]interface{})
+		require.Len(t, route, 5)
+		assert.Equal(t, "delivery", route[1].(map[string]interface{})["type"])
+
+		// The job which is not in any route is unassigned
+		unassigned := data["metadata"].(map[string]interface{})["unassigned"].([]interface{})
+		require.Len(t, unassigned, 1)
+		assert.Equal(t, "3324729385723589729", unassigned[0].(map[string]interface{})["task_id"])
+	})
+
+	t.Run("Schedule is not modified", func(t *testing.T) {
+		request, err := http.NewRequest("GET", "/projects/3909655254191459782/schedule", nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		m := map[string]interface{}{}
+		require.NoError(t, json.NewDecoder(recorder.Result().Body).Decode(&m))
+		route := m["data"].(map[string]interface{})["schedule"].([]interface{})[0].(map[string]interface{})["route"].([]interface{})
+		assert.Equal(t, "pickup", route[1].(map[string]interface{})["type"])
+	})
+
+	t.Run("Vehicle limits", func(t *testing.T) {
+		_, err := conn.Exec(context.Background(), "UPDATE vehicles SET max_travel_time = '00:00:01', max_distance = 1 WHERE id = 7300272137290532980")
+		require.NoError(t, err)
+
+		statusCode, m := sendRequest(`{"routes": [{"vehicle_id": "7300272137290532980", "stops": [
+			{"type": "pickup", "task_id": "3341766951177830852"},
+			{"type": "delivery", "task_id": "3341766951177830852"},
+			{"type": "break", "task_id": "2349284092384902582"}
+		]}]}`)
+		require.Equal(t, 200, statusCode)
+		assert.Equal(t, false, m["data"].(map[string]interface{})["feasible"])
+		assert.Subset(t, getViolationTypes(m), []string{"max_travel_time", "max_distance"})
+	})
+}
@@ -0,0 +1,200 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_version_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package e2etest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleVersions(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "")
+	defer conn.Close()
+	mux := server.Router
+
+	notFound := map[string]interface{}{"error": "Not Found", "code": "404"}
+	getActiveVersions := func(t *testing.T, projectID string) []interface{} {
+		statusCode, m := sendJSON(t, mux, "GET", fmt.Sprintf("/projects/%s/schedule/versions", projectID), nil)
+		require.Equal(t, 200, statusCode)
+		active := []interface{}{}
+		for _, version := range m["data"].([]interface{}) {
+			version := version.(map[string]interface{})
+			if version["active"] == true {
+				active = append(active, version["version"])
+			}
+		}
+		return active
+	}
+	getJobStatus := func(t *testing.T, jobID string) string {
+		var status string
+		err := conn.QueryRow(context.Background(), "SELECT status FROM jobs WHERE id = $1", jobID).Scan(&status)
+		require.NoError(t, err)
+		return status
+	}
+
+	testCases := []struct {
+		name       string
+		projectID  string
+		setup      []string
+		method     string
+		url        string
+		statusCode int
+		resBody    map[string]interface{}
+		check      func(t *testing.T, projectID string, jobID string, m map[string]interface{})
+	}{
+		{
+			name:       "Invalid ID",
+			projectID:  "100",
+			method:     "GET",
+			url:        "/schedule/versions",
+			statusCode: 404,
+			resBody:    notFound,
+		},
+		{
+			name:       "No versions",
+			method:     "GET",
+			url:        "/schedule/versions",
+			statusCode: 200,
+			resBody: map[string]interface{}{
+				"code":    "200",
+				"message": "OK",
+				"data":    []interface{}{},
+			},
+		},
+		{
+			name:       "Missing version",
+			method:     "GET",
+			url:        "/schedule/versions/1",
+			statusCode: 404,
+			resBody:    notFound,
+		},
+		{
+			name:       "Restore a missing version",
+			method:     "POST",
+			url:        "/schedule/versions/1/restore",
+			statusCode: 404,
+			resBody:    notFound,
+		},
+		{
+			name:       "Versions of the schedules",
+			setup:      []string{"POST /schedule?fresh=true", "POST /schedule"},
+			method:     "GET",
+			url:        "/schedule/versions",
+			statusCode: 200,
+			check: func(t *testing.T, projectID string, jobID string, m map[string]interface{}) {
+				versions := m["data"].([]interface{})
+				require.Len(t, versions, 2)
+				latest := versions[0].(map[string]interface{})
+				first := versions[1].(map[string]interface{})
+				assert.Equal(t, float64(2), latest["version"])
+				assert.Equal(t, false, latest["fresh"])
+				assert.Equal(t, float64(1), first["version"])
+				assert.Equal(t, true, first["fresh"])
+				assert.Equal(t, first["inputs_hash"], latest["inputs_hash"])
+				assert.Equal(t, "euclidean", latest["settings"].(map[string]interface{})["duration_calc"])
+				assert.NotEmpty(t, latest["metadata"].(map[string]interface{})["total_travel"])
+				assert.Equal(t, []interface{}{float64(2)}, getActiveVersions(t, projectID))
+			},
+		},
+		{
+			name:       "Schedule of a version",
+			setup:      []string{"POST /schedule?fresh=true", "POST /schedule"},
+			method:     "GET",
+			url:        "/schedule/versions/2",
+			statusCode: 200,
+			check: func(t *testing.T, projectID string, jobID string, m map[string]interface{}) {
+				// The version has the same schedule as the project
+				_, schedule := sendJSON(t, mux, "GET", fmt.Sprintf("/projects/%s/schedule", projectID), nil)
+				assert.Equal(t, schedule, m)
+			},
+		},
+		{
+			name:       "Restore a version",
+			setup:      []string{"POST /schedule?fresh=true", "POST /schedule", "DELETE /schedule"},
+			method:     "POST",
+			url:        "/schedule/versions/1/restore",
+			statusCode: 200,
+			check: func(t *testing.T, projectID string, jobID string, m map[string]interface{}) {
+				assert.Equal(t, []interface{}{float64(1)}, getActiveVersions(t, projectID))
+				_, versionSchedule := sendJSON(t, mux, "GET", fmt.Sprintf("/projects/%s/schedule/versions/1", projectID), nil)
+				assert.Equal(t, versionSchedule, m)
+				_, schedule := sendJSON(t, mux, "GET", fmt.Sprintf("/projects/%s/schedule", projectID), nil)
+				assert.Equal(t, versionSchedule, schedule)
+
+				// The status of the jobs is reset according to the restored schedule
+				var assigned bool
+				err := conn.QueryRow(context.Background(), `
+				SELECT EXISTS(SELECT 1 FROM schedules WHERE project_id = $1 AND task_id = $2 AND vehicle_id > 0)`,
+					projectID, jobID).Scan(&assigned)
+				require.NoError(t, err)
+				if assigned {
+					assert.Equal(t, "scheduled", getJobStatus(t, jobID))
+				} else {
+					assert.Equal(t, "unscheduled", getJobStatus(t, jobID))
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Each case schedules its own project with a vehicle and a job
+			project := createRow(t, mux, "/projects", map[string]interface{}{"name": tc.name, "duration_calc": "euclidean"})
+			projectID := project["id"].(string)
+			depot := map[string]interface{}{"latitude": 1.0, "longitude": 1.0}
+			createRow(t, mux, fmt.Sprintf("/projects/%s/vehicles", projectID), map[string]interface{}{"start_location": depot, "end_location": depot})
+			job := createRow(t, mux, fmt.Sprintf("/projects/%s/jobs", projectID), map[string]interface{}{"location": map[string]interface{}{"latitude": 1.0, "longitude": 1.01}})
+			jobID := job["id"].(string)
+			for _, request := range tc.setup {
+				var method, url string
+				_, err := fmt.Sscan(request, &method, &url)
+				require.NoError(t, err)
+				statusCode, m := sendJSON(t, mux, method, fmt.Sprintf("/projects/%s%s", projectID, url), nil)
+				require.Less(t, statusCode, 300, m)
+			}
+			if tc.projectID != "" {
+				projectID = tc.projectID
+			}
+
+			statusCode, m := sendJSON(t, mux, tc.method, fmt.Sprintf("/projects/%s%s", projectID, tc.url), nil)
+			assert.Equal(t, tc.statusCode, statusCode)
+			if tc.resBody != nil {
+				assert.Equal(t, tc.resBody, m)
+			}
+			if tc.check != nil {
+				tc.check(t, projectID, jobID, m)
+			}
+		})
+	}
+}
@@ -184,16 +184,18 @@ func TestCreateShipment(t *testing.T) {
 						"latitude":  -12.3457,
 						"longitude": -56.78,
 					},
-					"d_setup":        "00:00:00",
-					"d_service":      "00:00:00",
-					"amount":         []interface{}{},
-					"skills":         []interface{}{},
-					"priority":       float64(0),
-					"project_id":     "3909655254191459782",
-					"p_data":         map[string]interface{}{},
-					"d_data":         map[string]interface{}{},
-					"p_time_windows": []interface{}{},
-					"d_time_windows": []interface{}{},
+					"d_setup":           "00:00:00",
+					"d_service":         "00:00:00",
+					"amount":            []interface{}{},
+					"skills":            []interface{}{},
+					"priority":          float64(0),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "3909655254191459782",
+					"p_data":            map[string]interface{}{},
+					"d_data":            map[string]interface{}{},
+					"p_time_windows":    []interface{}{},
+					"d_time_windows":    []interface{}{},
 				},
 				"code":    "201",
 				"message": "Created",
@@ -340,16 +342,18 @@ func TestCreateShipment(t *testing.T) {
 						"latitude":  -12.3457,
 						"longitude": -56.78,
 					},
-					"d_setup":        "00:00:00",
-					"d_service":      "00:03:35",
-					"amount":         []interface{}{float64(15), float64(16)},
-					"skills":         []interface{}{float64(5), float64(50), float64(100)},
-					"priority":       float64(10),
-					"project_id":     "3909655254191459782",
-					"p_data":         map[string]interface{}{},
-					"d_data":         map[string]interface{}{},
-					"p_time_windows": []interface{}{},
-					"d_time_windows": []interface{}{},
+					"d_setup":           "00:00:00",
+					"d_service":         "00:03:35",
+					"amount":            []interface{}{float64(15), float64(16)},
+					"skills":            []interface{}{float64(5), float64(50), float64(100)},
+					"priority":          float64(10),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "3909655254191459782",
+					"p_data":            map[string]interface{}{},
+					"d_data":            map[string]interface{}{},
+					"p_time_windows":    []interface{}{},
+					"d_time_windows":    []interface{}{},
 				},
 				"code":    "201",
 				"message": "Created",
@@ -436,16 +440,18 @@ func TestListShipments(t *testing.T) {
 							"latitude":  23.3458,
 							"longitude": 2.3242,
 						},
-						"d_setup":    "00:00:00",
-						"d_service":  "00:01:00",
-						"amount":     []interface{}{float64(5), float64(7)},
-						"skills":     []interface{}{float64(5), float64(10)},
-						"priority":   float64(3),
-						"project_id": "2593982828701335033",
-						"p_data":     map[string]interface{}{},
-						"d_data":     map[string]interface{}{},
-						"created_at": "2021-10-26T00:00:03",
-						"updated_at": "2021-10-26T00:00:03",
+						"d_setup":           "00:00:00",
+						"d_service":         "00:01:00",
+						"amount":            []interface{}{float64(5), float64(7)},
+						"skills":            []interface{}{float64(5), float64(10)},
+						"priority":          float64(3),
+						"pinned_vehicle_id": nil,
+						"locked":            false,
+						"project_id":        "2593982828701335033",
+						"p_data":            map[string]interface{}{},
+						"d_data":            map[string]interface{}{},
+						"created_at":        "2021-10-26T00:00:03",
+						"updated_at":        "2021-10-26T00:00:03",
 						"p_time_windows": []interface{}{
 							[]interface{}{
 								"2020-10-10T00:00:00",
@@ -475,17 +481,19 @@ func TestListShipments(t *testing.T) {
 							"latitude":  23.3458,
 							"longitude": 2.3242,
 						},
-						"d_setup":        "00:00:00",
-						"d_service":      "00:02:03",
-						"amount":         []interface{}{float64(6), float64(8)},
-						"skills":         []interface{}{float64(1)},
-						"priority":       float64(1),
-						"project_id":     "2593982828701335033",
-						"p_data":         map[string]interface{}{},
-						"d_data":         map[string]interface{}{},
-						"created_at":     "2021-10-26T00:04:56",
-						"updated_at":     "2021-10-26T00:04:56",
-						"p_time_windows": []interface{}{},
+						"d_setup":           "00:00:00",
+						"d_service":         "00:02:03",
+						"amount":            []interface{}{float64(6), float64(8)},
+						"skills":            []interface{}{float64(1)},
+						"priority":          float64(1),
+						"pinned_vehicle_id": nil,
+						"locked":            false,
+						"project_id":        "2593982828701335033",
+						"p_data":            map[string]interface{}{},
+						"d_data":            map[string]interface{}{},
+						"created_at":        "2021-10-26T00:04:56",
+						"updated_at":        "2021-10-26T00:04:56",
+						"p_time_windows":    []interface{}{},
 						"d_time_windows": []interface{}{
 							[]interface{}{
 								"2020-10-10T00:00:00",
@@ -564,16 +572,18 @@ func TestGetShipment(t *testing.T) {
 						"latitude":  23.3458,
 						"longitude": 2.3242,
 					},
-					"d_setup":    "00:00:00",
-					"d_service":  "00:01:00",
-					"amount":     []interface{}{float64(5), float64(7)},
-					"skills":     []interface{}{float64(5), float64(10)},
-					"priority":   float64(3),
-					"project_id": "2593982828701335033",
-					"p_data":     map[string]interface{}{},
-					"d_data":     map[string]interface{}{},
-					"created_at": "2021-10-26T00:00:03",
-					"updated_at": "2021-10-26T00:00:03",
+					"d_setup":           "00:00:00",
+					"d_service":         "00:01:00",
+					"amount":            []interface{}{float64(5), float64(7)},
+					"skills":            []interface{}{float64(5), float64(10)},
+					"priority":          float64(3),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "2593982828701335033",
+					"p_data":            map[string]interface{}{},
+					"d_data":            map[string]interface{}{},
+					"created_at":        "2021-10-26T00:00:03",
+					"updated_at":        "2021-10-26T00:00:03",
 					"p_time_windows": []interface{}{
 						[]interface{}{
 							"2020-10-10T00:00:00",
@@ -655,17 +665,19 @@ func TestUpdateShipment(t *testing.T) {
 						"latitude":  23.3458,
 						"longitude": 2.3242,
 					},
-					"d_setup":        "00:00:00",
-					"d_service":      "00:01:00",
-					"amount":         []interface{}{float64(5), float64(7)},
-					"skills":         []interface{}{float64(5), float64(10)},
-					"priority":       float64(3),
-					"project_id":     "2593982828701335033",
-					"p_data":         map[string]interface{}{},
-					"d_data":         map[string]interface{}{},
-					"created_at":     "2021-10-26T00:00:03",
-					"p_time_windows": []interface{}{},
-					"d_time_windows": []interface{}{},
+					"d_setup":           "00:00:00",
+					"d_service":         "00:01:00",
+					"amount":            []interface{}{float64(5), float64(7)},
+					"skills":            []interface{}{float64(5), float64(10)},
+					"priority":          float64(3),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "2593982828701335033",
+					"p_data":            map[string]interface{}{},
+					"d_data":            map[string]interface{}{},
+					"created_at":        "2021-10-26T00:00:03",
+					"p_time_windows":    []interface{}{},
+					"d_time_windows":    []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -851,17 +863,19 @@ func TestUpdateShipment(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"d_setup":        "00:00:00",
-					"d_service":      "00:01:00",
-					"amount":         []interface{}{float64(5), float64(7)},
-					"skills":         []interface{}{float64(5), float64(10)},
-					"priority":       float64(3),
-					"project_id":     "2593982828701335033",
-					"p_data":         map[string]interface{}{},
-					"d_data":         map[string]interface{}{},
-					"created_at":     "2021-10-26T00:00:03",
-					"p_time_windows": []interface{}{},
-					"d_time_windows": []interface{}{},
+					"d_setup":           "00:00:00",
+					"d_service":         "00:01:00",
+					"amount":            []interface{}{float64(5), float64(7)},
+					"skills":            []interface{}{float64(5), float64(10)},
+					"priority":          float64(3),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "2593982828701335033",
+					"p_data":            map[string]interface{}{},
+					"d_data":            map[string]interface{}{},
+					"created_at":        "2021-10-26T00:00:03",
+					"p_time_windows":    []interface{}{},
+					"d_time_windows":    []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -888,17 +902,19 @@ func TestUpdateShipment(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"d_setup":        "00:03:00",
-					"d_service":      "00:01:00",
-					"amount":         []interface{}{float64(5), float64(7)},
-					"skills":         []interface{}{float64(5), float64(10)},
-					"priority":       float64(3),
-					"project_id":     "2593982828701335033",
-					"p_data":         map[string]interface{}{},
-					"d_data":         map[string]interface{}{},
-					"created_at":     "2021-10-26T00:00:03",
-					"p_time_windows": []interface{}{},
-					"d_time_windows": []interface{}{},
+					"d_setup":           "00:03:00",
+					"d_service":         "00:01:00",
+					"amount":            []interface{}{float64(5), float64(7)},
+					"skills":            []interface{}{float64(5), float64(10)},
+					"priority":          float64(3),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "2593982828701335033",
+					"p_data":            map[string]interface{}{},
+					"d_data":            map[string]interface{}{},
+					"created_at":        "2021-10-26T00:00:03",
+					"p_time_windows":    []interface{}{},
+					"d_time_windows":    []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -925,17 +941,19 @@ func TestUpdateShipment(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"d_setup":        "00:03:00",
-					"d_service":      "00:33:25",
-					"amount":         []interface{}{float64(5), float64(7)},
-					"skills":         []interface{}{float64(5), float64(10)},
-					"priority":       float64(3),
-					"project_id":     "2593982828701335033",
-					"p_data":         map[string]interface{}{},
-					"d_data":         map[string]interface{}{},
-					"created_at":     "2021-10-26T00:00:03",
-					"p_time_windows": []interface{}{},
-					"d_time_windows": []interface{}{},
+					"d_setup":           "00:03:00",
+					"d_service":         "00:33:25",
+					"amount":            []interface{}{float64(5), float64(7)},
+					"skills":            []interface{}{float64(5), float64(10)},
+					"priority":          float64(3),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "2593982828701335033",
+					"p_data":            map[string]interface{}{},
+					"d_data":            map[string]interface{}{},
+					"created_at":        "2021-10-26T00:00:03",
+					"p_time_windows":    []interface{}{},
+					"d_time_windows":    []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -961,17 +979,19 @@ func TestUpdateShipment(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"d_setup":        "00:03:00",
-					"d_service":      "00:33:25",
-					"amount":         []interface{}{float64(20), float64(30)},
-					"skills":         []interface{}{float64(5), float64(10)},
-					"priority":       float64(3),
-					"project_id":     "2593982828701335033",
-					"p_data":         map[string]interface{}{},
-					"d_data":         map[string]interface{}{},
-					"created_at":     "2021-10-26T00:00:03",
-					"p_time_windows": []interface{}{},
-					"d_time_windows": []interface{}{},
+					"d_setup":           "00:03:00",
+					"d_service":         "00:33:25",
+					"amount":            []interface{}{float64(20), float64(30)},
+					"skills":            []interface{}{float64(5), float64(10)},
+					"priority":          float64(3),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "2593982828701335033",
+					"p_data":            map[string]interface{}{},
+					"d_data":            map[string]interface{}{},
+					"created_at":        "2021-10-26T00:00:03",
+					"p_time_windows":    []interface{}{},
+					"d_time_windows":    []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -997,17 +1017,19 @@ func TestUpdateShipment(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"d_setup":        "00:03:00",
-					"d_service":      "00:33:25",
-					"amount":         []interface{}{float64(20), float64(30)},
-					"skills":         []interface{}{float64(5)},
-					"priority":       float64(3),
-					"project_id":     "2593982828701335033",
-					"p_data":         map[string]interface{}{},
-					"d_data":         map[string]interface{}{},
-					"created_at":     "2021-10-26T00:00:03",
-					"p_time_windows": []interface{}{},
-					"d_time_windows": []interface{}{},
+					"d_setup":           "00:03:00",
+					"d_service":         "00:33:25",
+					"amount":            []interface{}{float64(20), float64(30)},
+					"skills":            []interface{}{float64(5)},
+					"priority":          float64(3),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "2593982828701335033",
+					"p_data":            map[string]interface{}{},
+					"d_data":            map[string]interface{}{},
+					"created_at":        "2021-10-26T00:00:03",
+					"p_time_windows":    []interface{}{},
+					"d_time_windows":    []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -1033,17 +1055,19 @@ func TestUpdateShipment(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"d_setup":        "00:03:00",
-					"d_service":      "00:33:25",
-					"amount":         []interface{}{float64(20), float64(30)},
-					"skills":         []interface{}{float64(5)},
-					"priority":       float64(100),
-					"project_id":     "2593982828701335033",
-					"p_data":         map[string]interface{}{},
-					"d_data":         map[string]interface{}{},
-					"created_at":     "2021-10-26T00:00:03",
-					"p_time_windows": []interface{}{},
-					"d_time_windows": []interface{}{},
+					"d_setup":           "00:03:00",
+					"d_service":         "00:33:25",
+					"amount":            []interface{}{float64(20), float64(30)},
+					"skills":            []interface{}{float64(5)},
+					"priority":          float64(100),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "2593982828701335033",
+					"p_data":            map[string]interface{}{},
+					"d_data":            map[string]interface{}{},
+					"created_at":        "2021-10-26T00:00:03",
+					"p_time_windows":    []interface{}{},
+					"d_time_windows":    []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -1070,17 +1094,19 @@ func TestUpdateShipment(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"d_setup":        "00:03:00",
-					"d_service":      "00:33:25",
-					"amount":         []interface{}{float64(20), float64(30)},
-					"skills":         []interface{}{float64(5)},
-					"priority":       float64(100),
-					"project_id":     "2593982828701335033",
-					"p_data":         map[string]interface{}{"key": "value"},
-					"d_data":         map[string]interface{}{"key2": "value2"},
-					"created_at":     "2021-10-26T00:00:03",
-					"p_time_windows": []interface{}{},
-					"d_time_windows": []interface{}{},
+					"d_setup":           "00:03:00",
+					"d_service":         "00:33:25",
+					"amount":            []interface{}{float64(20), float64(30)},
+					"skills":            []interface{}{float64(5)},
+					"priority":          float64(100),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "2593982828701335033",
+					"p_data":            map[string]interface{}{"key": "value"},
+					"d_data":            map[string]interface{}{"key2": "value2"},
+					"created_at":        "2021-10-26T00:00:03",
+					"p_time_windows":    []interface{}{},
+					"d_time_windows":    []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -1131,17 +1157,19 @@ func TestUpdateShipment(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"d_setup":        "00:03:00",
-					"d_service":      "00:33:25",
-					"amount":         []interface{}{float64(20), float64(30)},
-					"skills":         []interface{}{float64(5)},
-					"priority":       float64(100),
-					"project_id":     "8943284028902589305",
-					"p_data":         map[string]interface{}{"key": "value"},
-					"d_data":         map[string]interface{}{"key2": "value2"},
-					"created_at":     "2021-10-26T00:00:03",
-					"p_time_windows": []interface{}{},
-					"d_time_windows": []interface{}{},
+					"d_setup":           "00:03:00",
+					"d_service":         "00:33:25",
+					"amount":            []interface{}{float64(20), float64(30)},
+					"skills":            []interface{}{float64(5)},
+					"priority":          float64(100),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "8943284028902589305",
+					"p_data":            map[string]interface{}{"key": "value"},
+					"d_data":            map[string]interface{}{"key2": "value2"},
+					"created_at":        "2021-10-26T00:00:03",
+					"p_time_windows":    []interface{}{},
+					"d_time_windows":    []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -1184,17 +1212,19 @@ func TestUpdateShipment(t *testing.T) {
 						"latitude":  -3.4567,
 						"longitude": 8.90,
 					},
-					"d_setup":        "00:00:20",
-					"d_service":      "00:00:25",
-					"amount":         []interface{}{float64(21)},
-					"skills":         []interface{}{float64(5), float64(6)},
-					"priority":       float64(20),
-					"project_id":     "2593982828701335033",
-					"p_data":         map[string]interface{}{"s": float64(1)},
-					"d_data":         map[string]interface{}{"s": float64(1)},
-					"created_at":     "2021-10-26T00:00:03",
-					"p_time_windows": []interface{}{},
-					"d_time_windows": []interface{}{},
+					"d_setup":           "00:00:20",
+					"d_service":         "00:00:25",
+					"amount":            []interface{}{float64(21)},
+					"skills":            []interface{}{float64(5), float64(6)},
+					"priority":          float64(20),
+					"pinned_vehicle_id": nil,
+					"locked":            false,
+					"project_id":        "2593982828701335033",
+					"p_data":            map[string]interface{}{"s": float64(1)},
+					"d_data":            map[string]interface{}{"s": float64(1)},
+					"created_at":        "2021-10-26T00:00:03",
+					"p_time_windows":    []interface{}{},
+					"d_time_windows":    []interface{}{},
 				},
 				"code":    "200",
 				"message": "OK",
@@ -1352,12 +1382,14 @@ func TestGetShipmentScheduleJson(t *testing.T) {
 										"latitude":  -32.234,
 										"longitude": -23.2342,
 									},
-									"arrival":      "2020-01-01T10:10:00",
-									"departure":    "2020-01-01T10:10:01",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:01",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-01T10:10:00",
+									"departure":           "2020-01-01T10:10:01",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:01",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(3),
 										float64(5),
@@ -1373,12 +1405,14 @@ func TestGetShipmentScheduleJson(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-01-03T20:52:34",
-									"departure":    "2020-01-03T20:52:37",
-									"travel_time":  "58:42:33",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:03",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-03T20:52:34",
+									"departure":           "2020-01-03T20:52:37",
+									"travel_time":         "58:42:33",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:03",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
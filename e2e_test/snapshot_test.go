@@ -0,0 +1,241 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: snapshot_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package e2etest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportProject(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	exportProject := func(projectID string, withSchedule bool) (int, []byte) {
+		url := "/projects/" + projectID + "/export"
+		if withSchedule {
+			url += "?schedule=true"
+		}
+		request, err := http.NewRequest("GET", url, nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		body, err := io.ReadAll(recorder.Result().Body)
+		require.NoError(t, err)
+		return recorder.Code, body
+	}
+	importProject := func(body []byte) (int, map[string]interface{}) {
+		request, err := http.NewRequest("POST", "/projects/import", bytes.NewReader(body))
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		resBody := map[string]interface{}{}
+		require.NoError(t, json.NewDecoder(recorder.Result().Body).Decode(&resBody))
+		return recorder.Code, resBody
+	}
+	countScheduleRows := func(projectID int64) int {
+		var count int
+		err := conn.QueryRow(context.Background(), "SELECT count(*) FROM schedules WHERE project_id = $1", projectID).Scan(&count)
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		statusCode, body := exportProject("100", false)
+		assert.Equal(t, 404, statusCode)
+		assert.JSONEq(t, `{"error":"Not Found","code":"404"}`, string(body))
+	})
+
+	t.Run("Invalid version", func(t *testing.T) {
+		statusCode, resBody := importProject([]byte(`{"version":2,"project":{"name":"Sample Project"}}`))
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, map[string]interface{}{
+			"code":    "400",
+			"message": "Bad Request",
+			"errors":  []interface{}{"Field 'version' must be 1"},
+		}, resBody)
+	})
+
+	t.Run("Invalid rows", func(t *testing.T) {
+		statusCode, resBody := importProject([]byte(`{
+			"version": 1,
+			"project": {"name": "Sample Project", "duration_calc": "euclidean", "exploration_level": 5, "timeout": "00:10:00", "max_shift": "00:30:00"},
+			"breaks": [{"id": "2", "vehicle_id": "3"}]
+		}`))
+		assert.Equal(t, 400, statusCode)
+		assert.Equal(t, map[string]interface{}{
+			"code":    "400",
+			"message": "Bad Request",
+			"errors":  []interface{}{"breaks[0]: Vehicle with the given 'vehicle_id' does not exist in the snapshot"},
+		}, resBody)
+	})
+
+	t.Run("Export and import with schedule", func(t *testing.T) {
+		oldProjectID := int64(3909655254191459782)
+		statusCode, body := exportProject(strconv.FormatInt(oldProjectID, 10), true)
+		require.Equal(t, 200, statusCode)
+
+		snapshot := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(body, &snapshot))
+		data := snapshot["data"].(map[string]interface{})
+		assert.Equal(t, float64(1), data["version"])
+		assert.NotEmpty(t, data["schedule"])
+
+		// The response of the export endpoint is imported as it is
+		statusCode, resBody := importProject(body)
+		require.Equal(t, 201, statusCode)
+		project := resBody["data"].(map[string]interface{})
+		newProjectID, err := strconv.ParseInt(project["id"].(string), 10, 64)
+		require.NoError(t, err)
+		assert.NotEqual(t, oldProjectID, newProjectID)
+		assert.Equal(t, data["project"].(map[string]interface{})["name"], project["name"])
+
+		assert.Equal(t, countProjectRows(t, conn, oldProjectID), countProjectRows(t, conn, newProjectID))
+		assert.Equal(t, countScheduleRows(oldProjectID), countScheduleRows(newProjectID))
+
+		// The exported schedule of the new project refers to the new vehicles
+		statusCode, body = exportProject(strconv.FormatInt(newProjectID, 10), true)
+		require.Equal(t, 200, statusCode)
+		newSnapshot := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(body, &newSnapshot))
+		newData := newSnapshot["data"].(map[string]interface{})
+		vehicleIDs := map[interface{}]bool{}
+		for _, vehicle := range newData["vehicles"].([]interface{}) {
+			vehicleIDs[vehicle.(map[string]interface{})["id"]] = true
+		}
+		for _, step := range newData["schedule"].([]interface{}) {
+			vehicleID := step.(map[string]interface{})["vehicle_id"]
+			if vehicleID != "0" && vehicleID != "-1" {
+				assert.True(t, vehicleIDs[vehicleID])
+			}
+		}
+	})
+
+	t.Run("Export without schedule", func(t *testing.T) {
+		statusCode, body := exportProject("3909655254191459782", false)
+		require.Equal(t, 200, statusCode)
+		snapshot := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(body, &snapshot))
+		assert.NotContains(t, snapshot["data"], "schedule")
+	})
+}
+
+func TestCloneProject(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	cloneProject := func(projectID string, withSchedule bool) (int, map[string]interface{}) {
+		url := "/projects/" + projectID + "/clone"
+		if withSchedule {
+			url += "?schedule=true"
+		}
+		request, err := http.NewRequest("POST", url, nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		resBody := map[string]interface{}{}
+		require.NoError(t, json.NewDecoder(recorder.Result().Body).Decode(&resBody))
+		return recorder.Code, resBody
+	}
+	getNewProjectID := func(resBody map[string]interface{}) int64 {
+		project := resBody["data"].(map[string]interface{})["project"].(map[string]interface{})
+		projectID, err := strconv.ParseInt(project["id"].(string), 10, 64)
+		require.NoError(t, err)
+		return projectID
+	}
+	countScheduleRows := func(projectID int64) int {
+		var count int
+		err := conn.QueryRow(context.Background(), "SELECT count(*) FROM schedules WHERE project_id = $1", projectID).Scan(&count)
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		statusCode, resBody := cloneProject("100", false)
+		assert.Equal(t, 404, statusCode)
+		assert.Equal(t, map[string]interface{}{"error": "Not Found", "code": "404"}, resBody)
+	})
+
+	t.Run("Clone with schedule", func(t *testing.T) {
+		oldProjectID := int64(3909655254191459782)
+		statusCode, resBody := cloneProject(strconv.FormatInt(oldProjectID, 10), true)
+		require.Equal(t, 201, statusCode)
+		newProjectID := getNewProjectID(resBody)
+
+		assert.Equal(t, countProjectRows(t, conn, oldProjectID), countProjectRows(t, conn, newProjectID))
+		assert.Equal(t, countScheduleRows(oldProjectID), countScheduleRows(newProjectID))
+
+		mapping := resBody["data"].(map[string]interface{})["id_mapping"].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"3909655254191459782": strconv.FormatInt(newProjectID, 10)}, mapping["projects"])
+
+		// The new vehicle belongs to the new project, and the schedule refers to it
+		newVehicleID, err := strconv.ParseInt(mapping["vehicles"].(map[string]interface{})["7300272137290532980"].(string), 10, 64)
+		require.NoError(t, err)
+		var vehicleProjectID int64
+		err = conn.QueryRow(context.Background(), "SELECT project_id FROM vehicles WHERE id = $1", newVehicleID).Scan(&vehicleProjectID)
+		require.NoError(t, err)
+		assert.Equal(t, newProjectID, vehicleProjectID)
+
+		var scheduleRows int
+		err = conn.QueryRow(context.Background(), "SELECT count(*) FROM schedules WHERE project_id = $1 AND vehicle_id = $2", newProjectID, newVehicleID).Scan(&scheduleRows)
+		require.NoError(t, err)
+		assert.NotZero(t, scheduleRows)
+
+		newBreakID := mapping["breaks"].(map[string]interface{})["2349284092384902582"]
+		assert.NotEqual(t, "2349284092384902582", newBreakID)
+	})
+
+	t.Run("Clone without schedule and deleted tasks", func(t *testing.T) {
+		_, err := conn.Exec(context.Background(), "UPDATE jobs SET deleted = TRUE WHERE id = 3324729385723589729")
+		require.NoError(t, err)
+
+		statusCode, resBody := cloneProject("3909655254191459782", false)
+		require.Equal(t, 201, statusCode)
+		newProjectID := getNewProjectID(resBody)
+
+		assert.Equal(t, 0, countProjectRows(t, conn, newProjectID)["jobs"])
+		assert.Equal(t, 1, countProjectRows(t, conn, newProjectID)["shipments"])
+		assert.Equal(t, 0, countScheduleRows(newProjectID))
+		mapping := resBody["data"].(map[string]interface{})["id_mapping"].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{}, mapping["jobs"])
+	})
+}
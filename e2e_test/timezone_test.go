@@ -0,0 +1,191 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: timezone_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package e2etest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimezone(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	type fixture struct {
+		projectID string
+		jobID     string
+		vehicleID string
+	}
+	location := map[string]interface{}{"latitude": 2.0, "longitude": 3.0}
+	job := map[string]interface{}{
+		"location":     location,
+		"time_windows": [][]string{{"2021-12-01T08:00:00Z", "2021-12-01T12:00:00+01:00"}},
+	}
+	vehicle := map[string]interface{}{
+		"start_location": location,
+		"end_location":   location,
+		"tw_open":        "2021-07-01T06:00:00Z",
+		"tw_close":       "2021-07-01T18:00:00",
+	}
+
+	testCases := []struct {
+		name       string
+		method     string
+		url        func(f fixture) string
+		body       map[string]interface{}
+		statusCode int
+		resBody    map[string]interface{}
+		check      func(t *testing.T, f fixture, data map[string]interface{})
+	}{
+		{
+			name:       "Invalid time zone",
+			method:     "POST",
+			url:        func(f fixture) string { return "/projects" },
+			body:       map[string]interface{}{"name": "Timezone", "timezone": "Europe/Unknown"},
+			statusCode: 400,
+			resBody: map[string]interface{}{
+				"errors":  []interface{}{"Field 'timezone' must be an IANA time zone such as 'Europe/Berlin'"},
+				"message": "Bad Request",
+				"code":    "400",
+			},
+		},
+		{
+			name:       "Project in a time zone",
+			method:     "POST",
+			url:        func(f fixture) string { return "/projects" },
+			body:       map[string]interface{}{"name": "Timezone", "timezone": "Europe/Berlin"},
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, data map[string]interface{}) {
+				assert.Equal(t, "Europe/Berlin", data["timezone"])
+				assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\+0[12]:00$`, data["created_at"])
+			},
+		},
+		{
+			name:       "Job with timestamps with an offset",
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s/jobs", f.projectID) },
+			body:       job,
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, data map[string]interface{}) {
+				assert.Equal(t, []interface{}{[]interface{}{"2021-12-01T09:00:00+01:00", "2021-12-01T12:00:00+01:00"}}, data["time_windows"])
+
+				// The timestamps are stored as the local times of the time zone
+				var twOpen string
+				err := conn.QueryRow(context.Background(), "SELECT tw_open::TEXT FROM jobs_time_windows WHERE id = $1", data["id"]).Scan(&twOpen)
+				require.NoError(t, err)
+				assert.Equal(t, "2021-12-01 09:00:00", twOpen)
+			},
+		},
+		{
+			name:       "Vehicle with timestamps with and without an offset",
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s/vehicles", f.projectID) },
+			body:       vehicle,
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, data map[string]interface{}) {
+				assert.Equal(t, "2021-07-01T08:00:00+02:00", data["tw_open"])
+				assert.Equal(t, "2021-07-01T18:00:00+02:00", data["tw_close"])
+			},
+		},
+		{
+			name:       "Update with a timestamp with another offset",
+			method:     "PATCH",
+			url:        func(f fixture) string { return fmt.Sprintf("/vehicles/%s", f.vehicleID) },
+			body:       map[string]interface{}{"tw_close": "2021-07-01T19:00:00+03:00"},
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, data map[string]interface{}) {
+				assert.Equal(t, "2021-07-01T18:00:00+02:00", data["tw_close"])
+			},
+		},
+		{
+			name:       "Schedule in the time zone",
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s/schedule", f.projectID) },
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, data map[string]interface{}) {
+				recorder := sendRequest(t, mux, "GET", fmt.Sprintf("/projects/%s/schedule", f.projectID), "", "text/calendar", nil)
+				require.Equal(t, 200, recorder.Code)
+				assert.Contains(t, recorder.Body.String(), "DTSTART:20210701T060000Z")
+			},
+		},
+		{
+			name:       "Change of the time zone",
+			method:     "PATCH",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s", f.projectID) },
+			body:       map[string]interface{}{"timezone": "UTC"},
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, data map[string]interface{}) {
+				assert.Equal(t, "UTC", data["timezone"])
+
+				// The local times are kept in the new time zone
+				statusCode, m := sendJSON(t, mux, "GET", fmt.Sprintf("/jobs/%s", f.jobID), nil)
+				require.Equal(t, 200, statusCode)
+				assert.Equal(t, []interface{}{[]interface{}{"2021-12-01T09:00:00Z", "2021-12-01T12:00:00Z"}}, m["data"].(map[string]interface{})["time_windows"])
+			},
+		},
+		{
+			name:       "Project without a time zone",
+			method:     "GET",
+			url:        func(f fixture) string { return "/jobs/6362411701075685873" },
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, data map[string]interface{}) {
+				assert.Equal(t, "2021-10-24T20:31:25", data["created_at"])
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Each case has its own project in a time zone with a job and a vehicle
+			project := createRow(t, mux, "/projects", map[string]interface{}{"name": tc.name, "timezone": "Europe/Berlin"})
+			f := fixture{projectID: project["id"].(string)}
+			f.jobID = createRow(t, mux, fmt.Sprintf("/projects/%s/jobs", f.projectID), job)["id"].(string)
+			f.vehicleID = createRow(t, mux, fmt.Sprintf("/projects/%s/vehicles", f.projectID), vehicle)["id"].(string)
+
+			var body interface{}
+			if tc.body != nil {
+				body = tc.body
+			}
+			statusCode, m := sendJSON(t, mux, tc.method, tc.url(f), body)
+			assert.Equal(t, tc.statusCode, statusCode, m)
+			if tc.resBody != nil {
+				assert.Equal(t, tc.resBody, m)
+			}
+			if tc.check != nil {
+				tc.check(t, f, m["data"].(map[string]interface{}))
+			}
+		})
+	}
+}
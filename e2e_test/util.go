@@ -29,18 +29,28 @@ along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
 package e2etest
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
+	"testing"
 
 	"github.com/Georepublic/pg_scheduleserv/internal/api"
+	"github.com/Georepublic/pg_scheduleserv/internal/config"
+	"github.com/Georepublic/pg_scheduleserv/internal/database"
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
 )
 
 func applyTestData(db_url string, filename string) {
@@ -54,11 +64,20 @@ func applyTestData(db_url string, filename string) {
 }
 
 func setup(db_url string, filename string) (*api.Server, *pgxpool.Pool) {
-	conn, err := pgxpool.Connect(context.Background(), db_url)
+	conn, err := database.Connect(context.Background(), db_url)
 	if err != nil {
 		logrus.Printf("Unable to connect to database: %v\n", err)
 		os.Exit(1)
 	}
+	config, err := config.LoadConfig("..")
+	if err != nil {
+		logrus.Errorf("Cannot load config: %s", err)
+	}
+	if err := util.RegisterMatrixProviders(config); err != nil {
+		logrus.Errorf("Cannot register the matrix providers: %s", err)
+	}
+	// The road network of the pgrouting provider is created by the tests using it
+	util.RegisterMatrixProvider("pgrouting", database.NewPgRoutingMatrixProvider(conn, "road_edges", ""))
 	server := api.NewServer(conn)
 	logrus.Error(db_url)
 	m, err := migrate.New("file://../migrations/", db_url)
@@ -75,3 +94,36 @@ func setup(db_url string, filename string) (*api.Server, *pgxpool.Pool) {
 	}
 	return server, conn
 }
+
+// sendRequest sends a request with the body of the given content type to the handler, which accepts the given type
+func sendRequest(t *testing.T, handler http.Handler, method string, url string, contentType string, accept string, body io.Reader) *httptest.ResponseRecorder {
+	request, err := http.NewRequest(method, url, body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("Accept", accept)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// sendJSON sends a request with the JSON encoded body, if any, to the handler, and returns the status code along with
+// the decoded JSON response
+func sendJSON(t *testing.T, handler http.Handler, method string, url string, body interface{}) (int, map[string]interface{}) {
+	var reader io.Reader
+	if body != nil {
+		jsonValue, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(jsonValue)
+	}
+	recorder := sendRequest(t, handler, method, url, "application/json", "application/json", reader)
+	m := map[string]interface{}{}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&m))
+	return recorder.Code, m
+}
+
+// createRow creates a row with a POST request, which must succeed, and returns the data of the created row
+func createRow(t *testing.T, handler http.Handler, url string, body interface{}) map[string]interface{} {
+	statusCode, m := sendJSON(t, handler, "POST", url, body)
+	require.Equal(t, 201, statusCode, m)
+	return m["data"].(map[string]interface{})
+}
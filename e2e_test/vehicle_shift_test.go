@@ -0,0 +1,261 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: vehicle_shift_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package e2etest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVehicleShifts(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	type fixture struct {
+		projectID string
+		vehicleID string
+		shiftID   string
+	}
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:shift",
+		"DTSTART:20211201T080000",
+		"DTEND:20211201T170000",
+		"RRULE:FREQ=DAILY;COUNT=3",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:holiday",
+		"DTSTART;VALUE=DATE:20211202",
+		"DTEND;VALUE=DATE:20211203",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	importCalendar := func(t *testing.T, f *fixture) {
+		recorder := sendRequest(t, mux, "POST", fmt.Sprintf("/vehicles/%s/shifts?replace=true", f.vehicleID), "text/calendar", "application/json", strings.NewReader(calendar))
+		require.Equal(t, 201, recorder.Code)
+	}
+	createShift := func(t *testing.T, f *fixture) {
+		shift := createRow(t, mux, fmt.Sprintf("/vehicles/%s/shifts", f.vehicleID), map[string]interface{}{"tw_open": "2021-12-01T08:00:00", "tw_close": "2021-12-01T17:00:00"})
+		f.shiftID = shift["id"].(string)
+	}
+	location := map[string]interface{}{"latitude": 2.0, "longitude": 3.0}
+
+	testCases := []struct {
+		name       string
+		setup      func(t *testing.T, f *fixture)
+		method     string
+		url        func(f fixture) string
+		body       map[string]interface{}
+		calendar   string
+		statusCode int
+		resBody    map[string]interface{}
+		check      func(t *testing.T, f fixture, m map[string]interface{})
+	}{
+		{
+			name:       "Shift closing before its opening",
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/vehicles/%s/shifts", f.vehicleID) },
+			body:       map[string]interface{}{"tw_open": "2021-12-01T17:00:00", "tw_close": "2021-12-01T08:00:00"},
+			statusCode: 400,
+			resBody: map[string]interface{}{
+				"errors":  []interface{}{"Field 'tw_open' must be less than field 'tw_close'"},
+				"message": "Bad Request",
+				"code":    "400",
+			},
+		},
+		{
+			name:       "Shift without closing",
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/vehicles/%s/shifts", f.vehicleID) },
+			body:       map[string]interface{}{"tw_open": "2021-12-01T08:00:00"},
+			statusCode: 400,
+		},
+		{
+			name:       "Shift of a missing vehicle",
+			method:     "POST",
+			url:        func(f fixture) string { return "/vehicles/123/shifts" },
+			body:       map[string]interface{}{"tw_open": "2021-12-01T08:00:00", "tw_close": "2021-12-01T17:00:00"},
+			statusCode: 400,
+		},
+		{
+			name:       "Create a shift",
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/vehicles/%s/shifts", f.vehicleID) },
+			body:       map[string]interface{}{"tw_open": "2021-12-01T08:00:00", "tw_close": "2021-12-01T17:00:00"},
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				data := m["data"].(map[string]interface{})
+				assert.Equal(t, f.vehicleID, data["vehicle_id"])
+				assert.Equal(t, "2021-12-01T08:00:00", data["tw_open"])
+				assert.Equal(t, "2021-12-01T17:00:00", data["tw_close"])
+				assert.Equal(t, false, data["day_off"])
+				assert.Equal(t, false, data["recurring"])
+			},
+		},
+		{
+			name:       "Update a shift",
+			setup:      createShift,
+			method:     "PATCH",
+			url:        func(f fixture) string { return fmt.Sprintf("/shifts/%s", f.shiftID) },
+			body:       map[string]interface{}{"tw_close": "2021-12-01T16:00:00"},
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				assert.Equal(t, "2021-12-01T16:00:00", m["data"].(map[string]interface{})["tw_close"])
+			},
+		},
+		{
+			name:       "Delete a shift",
+			setup:      createShift,
+			method:     "DELETE",
+			url:        func(f fixture) string { return fmt.Sprintf("/shifts/%s", f.shiftID) },
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				statusCode, _ := sendJSON(t, mux, "GET", fmt.Sprintf("/shifts/%s", f.shiftID), nil)
+				assert.Equal(t, 404, statusCode)
+			},
+		},
+		{
+			name:       "Import and export the calendar",
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/vehicles/%s/shifts?replace=true", f.vehicleID) },
+			calendar:   calendar,
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				assert.Equal(t, 4, len(m["data"].([]interface{})))
+
+				recorder := sendRequest(t, mux, "GET", fmt.Sprintf("/vehicles/%s/shifts", f.vehicleID), "", "text/calendar", nil)
+				require.Equal(t, 200, recorder.Code)
+				assert.Equal(t, "text/calendar", recorder.Header().Get("Content-Type"))
+				exported := recorder.Body.String()
+				assert.Equal(t, 4, strings.Count(exported, "BEGIN:VEVENT"))
+				assert.Contains(t, exported, "DTSTART;VALUE=DATE:20211202")
+				assert.Contains(t, exported, "CATEGORIES:DAY OFF")
+			},
+		},
+		{
+			name:       "Replace the calendar",
+			setup:      importCalendar,
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/vehicles/%s/shifts?replace=true", f.vehicleID) },
+			calendar:   calendar,
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				assert.Equal(t, 4, len(m["data"].([]interface{})))
+			},
+		},
+		{
+			name:       "Invalid calendar",
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/vehicles/%s/shifts", f.vehicleID) },
+			calendar:   "BEGIN:VCALENDAR",
+			statusCode: 400,
+		},
+		{
+			name: "Off-shift breaks when scheduling",
+			setup: func(t *testing.T, f *fixture) {
+				importCalendar(t, f)
+				createRow(t, mux, fmt.Sprintf("/projects/%s/jobs", f.projectID), map[string]interface{}{"location": location})
+			},
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s/schedule", f.projectID) },
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				// The shift of the day off is removed, so that a single break is between the two other shifts
+				statusCode, m := sendJSON(t, mux, "GET", fmt.Sprintf("/vehicles/%s/breaks?off_shift=true", f.vehicleID), nil)
+				require.Equal(t, 200, statusCode)
+				offShift := []interface{}{}
+				for _, row := range m["data"].([]interface{}) {
+					if row.(map[string]interface{})["off_shift"] == true {
+						offShift = append(offShift, row)
+					}
+				}
+				require.Equal(t, 1, len(offShift))
+				assert.Equal(t, "39:00:00", offShift[0].(map[string]interface{})["service"])
+				assert.Equal(t, []interface{}{[]interface{}{"2021-12-01T17:00:00", "2021-12-01T17:00:00"}}, offShift[0].(map[string]interface{})["time_windows"])
+
+				// The off-shift break is left out of the exported schedule
+				recorder := sendRequest(t, mux, "GET", fmt.Sprintf("/vehicles/%s/schedule", f.vehicleID), "", "text/csv", nil)
+				require.Equal(t, 200, recorder.Code)
+				assert.NotContains(t, recorder.Body.String(), fmt.Sprintf(",break,%s,", offShift[0].(map[string]interface{})["id"]))
+
+				var twOpen, twClose string
+				err := conn.QueryRow(context.Background(), `
+				SELECT tw_open::TEXT, tw_close::TEXT FROM get_vehicle_shifts_span($1) WHERE vehicle_id = $2`, f.projectID, f.vehicleID).Scan(&twOpen, &twClose)
+				require.NoError(t, err)
+				assert.Equal(t, "2021-12-01 08:00:00", twOpen)
+				assert.Equal(t, "2021-12-03 17:00:00", twClose)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Each case has its own project with a vehicle
+			project := createRow(t, mux, "/projects", map[string]interface{}{"name": tc.name, "duration_calc": "euclidean"})
+			f := fixture{projectID: project["id"].(string)}
+			vehicle := createRow(t, mux, fmt.Sprintf("/projects/%s/vehicles", f.projectID), map[string]interface{}{"start_location": location, "end_location": location})
+			f.vehicleID = vehicle["id"].(string)
+			if tc.setup != nil {
+				tc.setup(t, &f)
+			}
+
+			var statusCode int
+			m := map[string]interface{}{}
+			if tc.calendar != "" {
+				recorder := sendRequest(t, mux, tc.method, tc.url(f), "text/calendar", "application/json", strings.NewReader(tc.calendar))
+				statusCode = recorder.Code
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&m))
+			} else {
+				var body interface{}
+				if tc.body != nil {
+					body = tc.body
+				}
+				statusCode, m = sendJSON(t, mux, tc.method, tc.url(f), body)
+			}
+			assert.Equal(t, tc.statusCode, statusCode, m)
+			if tc.resBody != nil {
+				assert.Equal(t, tc.resBody, m)
+			}
+			if tc.check != nil {
+				tc.check(t, f, m)
+			}
+		})
+	}
+}
@@ -181,14 +181,21 @@ func TestCreateVehicle(t *testing.T) {
 						"latitude":  -12.3457,
 						"longitude": -56.78,
 					},
-					"capacity":     []interface{}{},
-					"skills":       []interface{}{},
-					"tw_open":      "1970-01-01T00:00:00",
-					"tw_close":     "2038-01-19T03:14:07",
-					"speed_factor": float64(1),
-					"max_tasks":    float64(2147483647),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{},
+					"capacity":         []interface{}{},
+					"skills":           []interface{}{},
+					"tw_open":          "1970-01-01T00:00:00",
+					"tw_close":         "2038-01-19T03:14:07",
+					"speed_factor":     float64(1),
+					"max_tasks":        float64(2147483647),
+					"max_travel_time":  nil,
+					"max_distance":     nil,
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{},
 				},
 				"code":    "201",
 				"message": "Created",
@@ -304,6 +311,56 @@ func TestCreateVehicle(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:       "Invalid type of vehicle limits",
+			statusCode: 400,
+			projectID:  3909655254191459782,
+			body: map[string]interface{}{
+				"start_location": map[string]interface{}{
+					"latitude":  12.34567,
+					"longitude": 56.78,
+				},
+				"end_location": map[string]interface{}{
+					"latitude":  -12.34567,
+					"longitude": -56.78,
+				},
+				"max_travel_time": 3600,
+				"max_distance":    "100 km",
+			},
+			resBody: map[string]interface{}{
+				"code":    "400",
+				"message": "Bad Request",
+				"errors": []interface{}{
+					"Field 'max_travel_time' must be of 'string' type.",
+					"Field 'max_distance' must be of 'int64' type.",
+				},
+			},
+		},
+		{
+			name:       "Invalid vehicle limits",
+			statusCode: 400,
+			projectID:  3909655254191459782,
+			body: map[string]interface{}{
+				"start_location": map[string]interface{}{
+					"latitude":  12.34567,
+					"longitude": 56.78,
+				},
+				"end_location": map[string]interface{}{
+					"latitude":  -12.34567,
+					"longitude": -56.78,
+				},
+				"max_travel_time": "08:00",
+				"max_distance":    float64(-100),
+			},
+			resBody: map[string]interface{}{
+				"code":    "400",
+				"message": "Bad Request",
+				"errors": []interface{}{
+					"Field 'max_travel_time' must be of 'HH:MM:SS' format",
+					"Field 'max_distance' must be non-negative",
+				},
+			},
+		},
 		{
 			name:       "All fields",
 			statusCode: 201,
@@ -317,13 +374,15 @@ func TestCreateVehicle(t *testing.T) {
 					"latitude":  -12.34567,
 					"longitude": -56.78,
 				},
-				"capacity":     []interface{}{15, 16},
-				"skills":       []interface{}{5, 50, 100},
-				"tw_open":      "2021-01-01T01:01:01",
-				"tw_close":     "2021-01-09T03:14:07",
-				"speed_factor": 10.45,
-				"max_tasks":    float64(25),
-				"data":         map[string]interface{}{"key": "value"},
+				"capacity":        []interface{}{15, 16},
+				"skills":          []interface{}{5, 50, 100},
+				"tw_open":         "2021-01-01T01:01:01",
+				"tw_close":        "2021-01-09T03:14:07",
+				"speed_factor":    10.45,
+				"max_tasks":       float64(25),
+				"max_travel_time": "08:00:00",
+				"max_distance":    float64(200000),
+				"data":            map[string]interface{}{"key": "value"},
 			},
 			resBody: map[string]interface{}{
 				"data": map[string]interface{}{
@@ -335,14 +394,21 @@ func TestCreateVehicle(t *testing.T) {
 						"latitude":  -12.3457,
 						"longitude": -56.78,
 					},
-					"capacity":     []interface{}{float64(15), float64(16)},
-					"skills":       []interface{}{float64(5), float64(50), float64(100)},
-					"tw_open":      "2021-01-01T01:01:01",
-					"tw_close":     "2021-01-09T03:14:07",
-					"speed_factor": 10.45,
-					"max_tasks":    float64(25),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{"key": "value"},
+					"capacity":         []interface{}{float64(15), float64(16)},
+					"skills":           []interface{}{float64(5), float64(50), float64(100)},
+					"tw_open":          "2021-01-01T01:01:01",
+					"tw_close":         "2021-01-09T03:14:07",
+					"speed_factor":     10.45,
+					"max_tasks":        float64(25),
+					"max_travel_time":  "08:00:00",
+					"max_distance":     float64(200000),
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{"key": "value"},
 				},
 				"code":    "201",
 				"message": "Created",
@@ -427,16 +493,23 @@ func TestListVehicles(t *testing.T) {
 							"latitude":  23.3458,
 							"longitude": 2.3242,
 						},
-						"capacity":     []interface{}{float64(10), float64(30)},
-						"skills":       []interface{}{float64(10)},
-						"tw_open":      "2020-01-01T00:00:00",
-						"tw_close":     "2020-01-10T07:14:07",
-						"speed_factor": 10.5,
-						"max_tasks":    float64(2147483647),
-						"project_id":   "3909655254191459782",
-						"data":         map[string]interface{}{"key": "value"},
-						"created_at":   "2021-10-26T10:46:41",
-						"updated_at":   "2021-10-26T10:46:41",
+						"capacity":         []interface{}{float64(10), float64(30)},
+						"skills":           []interface{}{float64(10)},
+						"tw_open":          "2020-01-01T00:00:00",
+						"tw_close":         "2020-01-10T07:14:07",
+						"speed_factor":     10.5,
+						"max_tasks":        float64(2147483647),
+						"max_travel_time":  nil,
+						"max_distance":     nil,
+						"fixed_cost":       nil,
+						"cost_per_hour":    nil,
+						"cost_per_km":      float64(0),
+						"vehicle_type_id":  nil,
+						"shift_recurrence": "",
+						"project_id":       "3909655254191459782",
+						"data":             map[string]interface{}{"key": "value"},
+						"created_at":       "2021-10-26T10:46:41",
+						"updated_at":       "2021-10-26T10:46:41",
 					},
 					map[string]interface{}{
 						"id": "7300272137290532980",
@@ -448,16 +521,23 @@ func TestListVehicles(t *testing.T) {
 							"latitude":  23.3458,
 							"longitude": 2.3242,
 						},
-						"capacity":     []interface{}{float64(30), float64(50)},
-						"skills":       []interface{}{float64(1)},
-						"tw_open":      "2020-01-01T10:10:00",
-						"tw_close":     "2020-01-11T03:14:07",
-						"speed_factor": 34.25,
-						"max_tasks":    float64(2147483647),
-						"project_id":   "3909655254191459782",
-						"data":         map[string]interface{}{"s": float64(1)},
-						"created_at":   "2021-10-26T10:47:54",
-						"updated_at":   "2021-10-26T10:47:54",
+						"capacity":         []interface{}{float64(30), float64(50)},
+						"skills":           []interface{}{float64(1)},
+						"tw_open":          "2020-01-01T10:10:00",
+						"tw_close":         "2020-01-11T03:14:07",
+						"speed_factor":     34.25,
+						"max_tasks":        float64(2147483647),
+						"max_travel_time":  nil,
+						"max_distance":     nil,
+						"fixed_cost":       nil,
+						"cost_per_hour":    nil,
+						"cost_per_km":      float64(0),
+						"vehicle_type_id":  nil,
+						"shift_recurrence": "",
+						"project_id":       "3909655254191459782",
+						"data":             map[string]interface{}{"s": float64(1)},
+						"created_at":       "2021-10-26T10:47:54",
+						"updated_at":       "2021-10-26T10:47:54",
 					},
 				},
 				"code":    "200",
@@ -528,16 +608,23 @@ func TestGetVehicle(t *testing.T) {
 						"latitude":  23.3458,
 						"longitude": 2.3242,
 					},
-					"capacity":     []interface{}{float64(10), float64(30)},
-					"skills":       []interface{}{float64(10)},
-					"tw_open":      "2020-01-01T00:00:00",
-					"tw_close":     "2020-01-10T07:14:07",
-					"speed_factor": 10.5,
-					"max_tasks":    float64(2147483647),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-26T10:46:41",
-					"updated_at":   "2021-10-26T10:46:41",
+					"capacity":         []interface{}{float64(10), float64(30)},
+					"skills":           []interface{}{float64(10)},
+					"tw_open":          "2020-01-01T00:00:00",
+					"tw_close":         "2020-01-10T07:14:07",
+					"speed_factor":     10.5,
+					"max_tasks":        float64(2147483647),
+					"max_travel_time":  nil,
+					"max_distance":     nil,
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{"key": "value"},
+					"created_at":       "2021-10-26T10:46:41",
+					"updated_at":       "2021-10-26T10:46:41",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -601,15 +688,22 @@ func TestUpdateVehicle(t *testing.T) {
 						"latitude":  23.3458,
 						"longitude": 2.3242,
 					},
-					"capacity":     []interface{}{float64(10), float64(30)},
-					"skills":       []interface{}{float64(10)},
-					"tw_open":      "2020-01-01T00:00:00",
-					"tw_close":     "2020-01-10T07:14:07",
-					"speed_factor": 10.5,
-					"max_tasks":    float64(2147483647),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-26T10:46:41",
+					"capacity":         []interface{}{float64(10), float64(30)},
+					"skills":           []interface{}{float64(10)},
+					"tw_open":          "2020-01-01T00:00:00",
+					"tw_close":         "2020-01-10T07:14:07",
+					"speed_factor":     10.5,
+					"max_tasks":        float64(2147483647),
+					"max_travel_time":  nil,
+					"max_distance":     nil,
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{"key": "value"},
+					"created_at":       "2021-10-26T10:46:41",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -797,15 +891,22 @@ func TestUpdateVehicle(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"capacity":     []interface{}{float64(10), float64(30)},
-					"skills":       []interface{}{float64(10)},
-					"tw_open":      "2020-01-01T00:00:00",
-					"tw_close":     "2020-01-10T07:14:07",
-					"speed_factor": 10.5,
-					"max_tasks":    float64(2147483647),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-26T10:46:41",
+					"capacity":         []interface{}{float64(10), float64(30)},
+					"skills":           []interface{}{float64(10)},
+					"tw_open":          "2020-01-01T00:00:00",
+					"tw_close":         "2020-01-10T07:14:07",
+					"speed_factor":     10.5,
+					"max_tasks":        float64(2147483647),
+					"max_travel_time":  nil,
+					"max_distance":     nil,
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{"key": "value"},
+					"created_at":       "2021-10-26T10:46:41",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -829,15 +930,22 @@ func TestUpdateVehicle(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"capacity":     []interface{}{float64(10), float64(30)},
-					"skills":       []interface{}{float64(10)},
-					"tw_open":      "2020-01-01T00:00:00",
-					"tw_close":     "2020-01-10T07:14:07",
-					"speed_factor": 10.5,
-					"max_tasks":    float64(2147483647),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-26T10:46:41",
+					"capacity":         []interface{}{float64(10), float64(30)},
+					"skills":           []interface{}{float64(10)},
+					"tw_open":          "2020-01-01T00:00:00",
+					"tw_close":         "2020-01-10T07:14:07",
+					"speed_factor":     10.5,
+					"max_tasks":        float64(2147483647),
+					"max_travel_time":  nil,
+					"max_distance":     nil,
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{"key": "value"},
+					"created_at":       "2021-10-26T10:46:41",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -861,15 +969,22 @@ func TestUpdateVehicle(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"capacity":     []interface{}{float64(10), float64(30)},
-					"skills":       []interface{}{float64(5)},
-					"tw_open":      "2020-01-01T00:00:00",
-					"tw_close":     "2020-01-10T07:14:07",
-					"speed_factor": 10.5,
-					"max_tasks":    float64(2147483647),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-26T10:46:41",
+					"capacity":         []interface{}{float64(10), float64(30)},
+					"skills":           []interface{}{float64(5)},
+					"tw_open":          "2020-01-01T00:00:00",
+					"tw_close":         "2020-01-10T07:14:07",
+					"speed_factor":     10.5,
+					"max_tasks":        float64(2147483647),
+					"max_travel_time":  nil,
+					"max_distance":     nil,
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{"key": "value"},
+					"created_at":       "2021-10-26T10:46:41",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -893,15 +1008,22 @@ func TestUpdateVehicle(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"capacity":     []interface{}{float64(10), float64(30)},
-					"skills":       []interface{}{float64(5)},
-					"tw_open":      "2020-01-01T00:00:00",
-					"tw_close":     "2020-01-10T07:14:07",
-					"speed_factor": 1.234,
-					"max_tasks":    float64(2147483647),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-26T10:46:41",
+					"capacity":         []interface{}{float64(10), float64(30)},
+					"skills":           []interface{}{float64(5)},
+					"tw_open":          "2020-01-01T00:00:00",
+					"tw_close":         "2020-01-10T07:14:07",
+					"speed_factor":     1.234,
+					"max_tasks":        float64(2147483647),
+					"max_travel_time":  nil,
+					"max_distance":     nil,
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{"key": "value"},
+					"created_at":       "2021-10-26T10:46:41",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -925,15 +1047,22 @@ func TestUpdateVehicle(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"capacity":     []interface{}{float64(10), float64(30)},
-					"skills":       []interface{}{float64(5)},
-					"tw_open":      "2020-01-01T00:00:00",
-					"tw_close":     "2020-01-10T07:14:07",
-					"speed_factor": 1.234,
-					"max_tasks":    float64(15),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{"key": "value"},
-					"created_at":   "2021-10-26T10:46:41",
+					"capacity":         []interface{}{float64(10), float64(30)},
+					"skills":           []interface{}{float64(5)},
+					"tw_open":          "2020-01-01T00:00:00",
+					"tw_close":         "2020-01-10T07:14:07",
+					"speed_factor":     1.234,
+					"max_tasks":        float64(15),
+					"max_travel_time":  nil,
+					"max_distance":     nil,
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{"key": "value"},
+					"created_at":       "2021-10-26T10:46:41",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -957,15 +1086,119 @@ func TestUpdateVehicle(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"capacity":     []interface{}{float64(10), float64(30)},
-					"skills":       []interface{}{float64(5)},
-					"tw_open":      "2020-01-01T00:00:00",
-					"tw_close":     "2020-01-10T07:14:07",
-					"speed_factor": 1.234,
-					"max_tasks":    float64(15),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{},
-					"created_at":   "2021-10-26T10:46:41",
+					"capacity":         []interface{}{float64(10), float64(30)},
+					"skills":           []interface{}{float64(5)},
+					"tw_open":          "2020-01-01T00:00:00",
+					"tw_close":         "2020-01-10T07:14:07",
+					"speed_factor":     1.234,
+					"max_tasks":        float64(15),
+					"max_travel_time":  nil,
+					"max_distance":     nil,
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{},
+					"created_at":       "2021-10-26T10:46:41",
+				},
+				"code":    "200",
+				"message": "OK",
+			},
+		},
+		{
+			name:       "Invalid vehicle limits",
+			statusCode: 400,
+			vehicleID:  2550908592071787332,
+			body: map[string]interface{}{
+				"max_travel_time": "10 hours",
+				"max_distance":    float64(-1),
+			},
+			resBody: map[string]interface{}{
+				"code":    "400",
+				"message": "Bad Request",
+				"errors": []interface{}{
+					"Field 'max_travel_time' must be of 'HH:MM:SS' format",
+					"Field 'max_distance' must be non-negative",
+				},
+			},
+		},
+		{
+			name:       "Only vehicle limits",
+			statusCode: 200,
+			vehicleID:  2550908592071787332,
+			body: map[string]interface{}{
+				"max_travel_time": "10:30:00",
+				"max_distance":    float64(150000),
+			},
+			resBody: map[string]interface{}{
+				"data": map[string]interface{}{
+					"id": "2550908592071787332",
+					"start_location": map[string]interface{}{
+						"latitude":  23.4567,
+						"longitude": -78.90,
+					},
+					"end_location": map[string]interface{}{
+						"latitude":  -23.4567,
+						"longitude": 78.90,
+					},
+					"capacity":         []interface{}{float64(10), float64(30)},
+					"skills":           []interface{}{float64(5)},
+					"tw_open":          "2020-01-01T00:00:00",
+					"tw_close":         "2020-01-10T07:14:07",
+					"speed_factor":     1.234,
+					"max_tasks":        float64(15),
+					"max_travel_time":  "10:30:00",
+					"max_distance":     float64(150000),
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{},
+					"created_at":       "2021-10-26T10:46:41",
+				},
+				"code":    "200",
+				"message": "OK",
+			},
+		},
+		{
+			name:       "Clear vehicle limits",
+			statusCode: 200,
+			vehicleID:  2550908592071787332,
+			body: map[string]interface{}{
+				"max_travel_time": "00:00:00",
+				"max_distance":    float64(0),
+			},
+			resBody: map[string]interface{}{
+				"data": map[string]interface{}{
+					"id": "2550908592071787332",
+					"start_location": map[string]interface{}{
+						"latitude":  23.4567,
+						"longitude": -78.90,
+					},
+					"end_location": map[string]interface{}{
+						"latitude":  -23.4567,
+						"longitude": 78.90,
+					},
+					"capacity":         []interface{}{float64(10), float64(30)},
+					"skills":           []interface{}{float64(5)},
+					"tw_open":          "2020-01-01T00:00:00",
+					"tw_close":         "2020-01-10T07:14:07",
+					"speed_factor":     1.234,
+					"max_tasks":        float64(15),
+					"max_travel_time":  nil,
+					"max_distance":     nil,
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{},
+					"created_at":       "2021-10-26T10:46:41",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -1013,15 +1246,22 @@ func TestUpdateVehicle(t *testing.T) {
 						"latitude":  -23.4567,
 						"longitude": 78.90,
 					},
-					"capacity":     []interface{}{float64(10), float64(30)},
-					"skills":       []interface{}{float64(5)},
-					"tw_open":      "2020-01-01T00:00:00",
-					"tw_close":     "2020-01-10T07:14:07",
-					"speed_factor": 1.234,
-					"max_tasks":    float64(15),
-					"project_id":   "8943284028902589305",
-					"data":         map[string]interface{}{},
-					"created_at":   "2021-10-26T10:46:41",
+					"capacity":         []interface{}{float64(10), float64(30)},
+					"skills":           []interface{}{float64(5)},
+					"tw_open":          "2020-01-01T00:00:00",
+					"tw_close":         "2020-01-10T07:14:07",
+					"speed_factor":     1.234,
+					"max_tasks":        float64(15),
+					"max_travel_time":  nil,
+					"max_distance":     nil,
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "8943284028902589305",
+					"data":             map[string]interface{}{},
+					"created_at":       "2021-10-26T10:46:41",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -1060,15 +1300,22 @@ func TestUpdateVehicle(t *testing.T) {
 						"latitude":  -3.4567,
 						"longitude": 8.90,
 					},
-					"capacity":     []interface{}{float64(21)},
-					"skills":       []interface{}{float64(5), float64(6)},
-					"tw_open":      "2021-11-01T00:00:00",
-					"tw_close":     "2021-11-10T03:14:07",
-					"speed_factor": 11.234,
-					"max_tasks":    float64(35),
-					"project_id":   "3909655254191459782",
-					"data":         map[string]interface{}{"s": float64(1)},
-					"created_at":   "2021-10-26T10:46:41",
+					"capacity":         []interface{}{float64(21)},
+					"skills":           []interface{}{float64(5), float64(6)},
+					"tw_open":          "2021-11-01T00:00:00",
+					"tw_close":         "2021-11-10T03:14:07",
+					"speed_factor":     11.234,
+					"max_tasks":        float64(35),
+					"max_travel_time":  nil,
+					"max_distance":     nil,
+					"fixed_cost":       nil,
+					"cost_per_hour":    nil,
+					"cost_per_km":      float64(0),
+					"vehicle_type_id":  nil,
+					"shift_recurrence": "",
+					"project_id":       "3909655254191459782",
+					"data":             map[string]interface{}{"s": float64(1)},
+					"created_at":       "2021-10-26T10:46:41",
 				},
 				"code":    "200",
 				"message": "OK",
@@ -1201,12 +1448,14 @@ func TestGetVehicleScheduleJson(t *testing.T) {
 				"data": map[string]interface{}{
 					"schedule": []interface{}{},
 					"metadata": map[string]interface{}{
-						"summary":       []interface{}{},
-						"unassigned":    []interface{}{},
-						"total_setup":   "00:00:00",
-						"total_service": "00:00:00",
-						"total_travel":  "00:00:00",
-						"total_waiting": "00:00:00",
+						"summary":        []interface{}{},
+						"unassigned":     []interface{}{},
+						"total_setup":    "00:00:00",
+						"total_service":  "00:00:00",
+						"total_travel":   "00:00:00",
+						"total_waiting":  "00:00:00",
+						"total_distance": float64(0),
+						"total_cost":     float64(0),
 					},
 				},
 				"code":    "200",
@@ -1233,12 +1482,14 @@ func TestGetVehicleScheduleJson(t *testing.T) {
 										"latitude":  -32.234,
 										"longitude": -23.2342,
 									},
-									"arrival":      "2020-01-01T10:10:00",
-									"departure":    "2020-01-01T10:10:00",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:00",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-01T10:10:00",
+									"departure":           "2020-01-01T10:10:00",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:00",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -1254,12 +1505,14 @@ func TestGetVehicleScheduleJson(t *testing.T) {
 										"latitude":  -32.234,
 										"longitude": -23.2342,
 									},
-									"arrival":      "2020-01-01T10:10:00",
-									"departure":    "2020-01-01T10:10:01",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:01",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-01T10:10:00",
+									"departure":           "2020-01-01T10:10:01",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:01",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(3),
 										float64(5),
@@ -1275,12 +1528,14 @@ func TestGetVehicleScheduleJson(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-01-03T20:52:34",
-									"departure":    "2020-01-03T20:52:37",
-									"travel_time":  "58:42:33",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:03",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-03T20:52:34",
+									"departure":           "2020-01-03T20:52:37",
+									"travel_time":         "58:42:33",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:03",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -1296,12 +1551,14 @@ func TestGetVehicleScheduleJson(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-01-03T20:52:37",
-									"departure":    "2020-01-03T20:58:01",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:05:24",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-03T20:52:37",
+									"departure":           "2020-01-03T20:58:01",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:05:24",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -1317,12 +1574,14 @@ func TestGetVehicleScheduleJson(t *testing.T) {
 										"latitude":  23.3458,
 										"longitude": 2.3242,
 									},
-									"arrival":      "2020-01-03T20:58:01",
-									"departure":    "2020-01-03T20:58:01",
-									"travel_time":  "00:00:00",
-									"setup_time":   "00:00:00",
-									"service_time": "00:00:00",
-									"waiting_time": "00:00:00",
+									"arrival":             "2020-01-03T20:58:01",
+									"departure":           "2020-01-03T20:58:01",
+									"travel_time":         "00:00:00",
+									"setup_time":          "00:00:00",
+									"service_time":        "00:00:00",
+									"waiting_time":        "00:00:00",
+									"distance":            float64(0),
+									"cumulative_distance": float64(0),
 									"load": []interface{}{
 										float64(0),
 										float64(0),
@@ -1337,21 +1596,25 @@ func TestGetVehicleScheduleJson(t *testing.T) {
 					"metadata": map[string]interface{}{
 						"summary": []interface{}{
 							map[string]interface{}{
-								"vehicle_id":   "7300272137290532980",
-								"setup_time":   "00:00:00",
-								"service_time": "00:05:28",
-								"travel_time":  "58:42:33",
-								"waiting_time": "00:00:00",
+								"vehicle_id":     "7300272137290532980",
+								"setup_time":     "00:00:00",
+								"service_time":   "00:05:28",
+								"travel_time":    "58:42:33",
+								"waiting_time":   "00:00:00",
+								"total_distance": float64(0),
+								"cost":           float64(211681),
 								"vehicle_data": map[string]interface{}{
 									"s": float64(1),
 								},
 							},
 						},
-						"unassigned":    []interface{}{},
-						"total_setup":   "00:00:00",
-						"total_service": "00:05:28",
-						"total_travel":  "58:42:33",
-						"total_waiting": "00:00:00",
+						"unassigned":     []interface{}{},
+						"total_setup":    "00:00:00",
+						"total_service":  "00:05:28",
+						"total_travel":   "58:42:33",
+						"total_waiting":  "00:00:00",
+						"total_distance": float64(0),
+						"total_cost":     float64(211681),
 					},
 					"project_id": "3909655254191459782",
 				},
@@ -0,0 +1,331 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: vehicle_type_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package e2etest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVehicleTypes(t *testing.T) {
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	mux := server.Router
+
+	type fixture struct {
+		projectID     string
+		vehicleTypeID string
+	}
+	listBreaks := func(t *testing.T, vehicleID string) []interface{} {
+		statusCode, m := sendJSON(t, mux, "GET", fmt.Sprintf("/vehicles/%s/breaks", vehicleID), nil)
+		require.Equal(t, 200, statusCode)
+		return m["data"].([]interface{})
+	}
+	listVehicles := func(t *testing.T, projectID string) []interface{} {
+		statusCode, m := sendJSON(t, mux, "GET", fmt.Sprintf("/projects/%s/vehicles", projectID), nil)
+		require.Equal(t, 200, statusCode)
+		return m["data"].([]interface{})
+	}
+
+	location := map[string]interface{}{"latitude": 2.0, "longitude": 3.0}
+	van := map[string]interface{}{
+		"name":          "Van",
+		"capacity":      []int64{50, 25},
+		"skills":        []int32{1, 5},
+		"tw_open":       "2021-10-26T08:00:00",
+		"tw_close":      "2021-10-26T18:00:00",
+		"max_tasks":     20,
+		"fixed_cost":    5000,
+		"cost_per_hour": 3600,
+		"breaks": []interface{}{
+			map[string]interface{}{
+				"service":      "00:30:00",
+				"time_windows": [][]string{{"2021-10-26T12:00:00", "2021-10-26T14:00:00"}},
+			},
+		},
+	}
+	otherProject := []interface{}{"Field 'vehicle_type_id' must be the ID of a vehicle type of the same project"}
+
+	testCases := []struct {
+		name       string
+		vehicles   int
+		method     string
+		url        func(f fixture) string
+		body       func(f fixture) interface{}
+		statusCode int
+		resBody    map[string]interface{}
+		errors     []interface{}
+		check      func(t *testing.T, f fixture, m map[string]interface{})
+	}{
+		{
+			name:   "Breaks with an invalid service type",
+			method: "POST",
+			url:    func(f fixture) string { return fmt.Sprintf("/projects/%s/vehicle_types", f.projectID) },
+			body: func(f fixture) interface{} {
+				return map[string]interface{}{"breaks": []interface{}{map[string]interface{}{"service": 100}}}
+			},
+			statusCode: 400,
+			resBody: map[string]interface{}{
+				"errors":  []interface{}{"Field 'service' must be of 'string' type."},
+				"message": "Bad Request",
+				"code":    "400",
+			},
+		},
+		{
+			name:   "Breaks with an invalid service format",
+			method: "POST",
+			url:    func(f fixture) string { return fmt.Sprintf("/projects/%s/vehicle_types", f.projectID) },
+			body: func(f fixture) interface{} {
+				return map[string]interface{}{"breaks": []interface{}{map[string]interface{}{"service": "-00:10:00"}}}
+			},
+			statusCode: 400,
+			errors:     []interface{}{"Field 'service' must be of 'HH:MM:SS' format"},
+		},
+		{
+			name:       "Create a vehicle type",
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s/vehicle_types", f.projectID) },
+			body:       func(f fixture) interface{} { return van },
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				data := m["data"].(map[string]interface{})
+				assert.Equal(t, "Van", data["name"])
+				assert.Equal(t, []interface{}{float64(50), float64(25)}, data["capacity"])
+				assert.Equal(t, nil, data["speed_factor"])
+				assert.Equal(t, []interface{}{
+					map[string]interface{}{
+						"service":      "00:30:00",
+						"time_windows": []interface{}{[]interface{}{"2021-10-26T12:00:00", "2021-10-26T14:00:00"}},
+						"data":         map[string]interface{}{},
+					},
+				}, data["breaks"])
+
+				statusCode, m := sendJSON(t, mux, "GET", fmt.Sprintf("/projects/%s/vehicle_types", f.projectID), nil)
+				require.Equal(t, 200, statusCode)
+				assert.Len(t, m["data"], 2)
+			},
+		},
+		{
+			name:   "Create a vehicle of the type",
+			method: "POST",
+			url:    func(f fixture) string { return fmt.Sprintf("/projects/%s/vehicles", f.projectID) },
+			body: func(f fixture) interface{} {
+				return map[string]interface{}{
+					"start_location":  location,
+					"end_location":    location,
+					"capacity":        []int64{10, 10},
+					"vehicle_type_id": f.vehicleTypeID,
+				}
+			},
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				data := m["data"].(map[string]interface{})
+				assert.Equal(t, f.vehicleTypeID, data["vehicle_type_id"])
+				assert.Equal(t, []interface{}{float64(10), float64(10)}, data["capacity"])
+				assert.Equal(t, []interface{}{float64(1), float64(5)}, data["skills"])
+				assert.Equal(t, "2021-10-26T08:00:00", data["tw_open"])
+				assert.Equal(t, float64(20), data["max_tasks"])
+				assert.Equal(t, float64(5000), data["fixed_cost"])
+
+				breaks := listBreaks(t, data["id"].(string))
+				require.Len(t, breaks, 1)
+				assert.Equal(t, "00:30:00", breaks[0].(map[string]interface{})["service"])
+				assert.Equal(t, []interface{}{[]interface{}{"2021-10-26T12:00:00", "2021-10-26T14:00:00"}}, breaks[0].(map[string]interface{})["time_windows"])
+			},
+		},
+		{
+			name:   "Create a vehicle of the type in another project",
+			method: "POST",
+			url:    func(f fixture) string { return "/projects/2593982828701335033/vehicles" },
+			body: func(f fixture) interface{} {
+				return map[string]interface{}{"start_location": location, "end_location": location, "vehicle_type_id": f.vehicleTypeID}
+			},
+			statusCode: 400,
+			resBody: map[string]interface{}{
+				"errors":  otherProject,
+				"message": "Bad Request",
+				"code":    "400",
+			},
+		},
+		{
+			name:   "Update a vehicle of another project to the type",
+			method: "PATCH",
+			url:    func(f fixture) string { return "/vehicles/150202809001685363" },
+			body: func(f fixture) interface{} {
+				return map[string]interface{}{"vehicle_type_id": f.vehicleTypeID}
+			},
+			statusCode: 400,
+			errors:     otherProject,
+		},
+		{
+			name:   "Create no vehicles of the type",
+			method: "POST",
+			url:    func(f fixture) string { return fmt.Sprintf("/vehicle_types/%s/vehicles", f.vehicleTypeID) },
+			body: func(f fixture) interface{} {
+				return map[string]interface{}{"count": 0, "start_location": location}
+			},
+			statusCode: 400,
+			errors:     []interface{}{"Field 'count' must be greater than or equal to 1"},
+		},
+		{
+			name:   "Create vehicles of the type",
+			method: "POST",
+			url:    func(f fixture) string { return fmt.Sprintf("/vehicle_types/%s/vehicles", f.vehicleTypeID) },
+			body: func(f fixture) interface{} {
+				return map[string]interface{}{"count": 3, "start_location": location}
+			},
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				vehicles := m["data"].([]interface{})
+				require.Len(t, vehicles, 3)
+				for _, v := range vehicles {
+					vehicle := v.(map[string]interface{})
+					assert.Equal(t, f.vehicleTypeID, vehicle["vehicle_type_id"])
+					assert.Equal(t, f.projectID, vehicle["project_id"])
+					assert.Equal(t, location, vehicle["start_location"])
+					assert.Equal(t, location, vehicle["end_location"])
+					assert.Equal(t, []interface{}{float64(50), float64(25)}, vehicle["capacity"])
+					assert.Len(t, listBreaks(t, vehicle["id"].(string)), 1)
+				}
+				assert.Len(t, listVehicles(t, f.projectID), 3)
+			},
+		},
+		{
+			name:       "Clone the project with its vehicle types",
+			vehicles:   2,
+			method:     "POST",
+			url:        func(f fixture) string { return fmt.Sprintf("/projects/%s/clone", f.projectID) },
+			body:       func(f fixture) interface{} { return nil },
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				clonedProjectID := m["data"].(map[string]interface{})["project"].(map[string]interface{})["id"].(string)
+
+				statusCode, m := sendJSON(t, mux, "GET", fmt.Sprintf("/projects/%s/vehicle_types", clonedProjectID), nil)
+				require.Equal(t, 200, statusCode)
+				vehicleTypes := m["data"].([]interface{})
+				require.Len(t, vehicleTypes, 1)
+				clonedVehicleType := vehicleTypes[0].(map[string]interface{})
+				assert.NotEqual(t, f.vehicleTypeID, clonedVehicleType["id"])
+				assert.Equal(t, "Van", clonedVehicleType["name"])
+				assert.Len(t, clonedVehicleType["breaks"], 1)
+
+				vehicles := listVehicles(t, clonedProjectID)
+				require.Len(t, vehicles, 2)
+				for _, v := range vehicles {
+					assert.Equal(t, clonedVehicleType["id"], v.(map[string]interface{})["vehicle_type_id"])
+				}
+			},
+		},
+		{
+			name:   "Import vehicles of the type in another project",
+			method: "POST",
+			url:    func(f fixture) string { return "/projects/2593982828701335033/import" },
+			body: func(f fixture) interface{} {
+				return []interface{}{
+					map[string]interface{}{"type": "vehicle", "start_location": location, "vehicle_type_id": f.vehicleTypeID},
+				}
+			},
+			statusCode: 400,
+			errors:     []interface{}{"Row 1: Vehicle type with the given 'vehicle_type_id' does not exist in the project"},
+		},
+		{
+			name:   "Import vehicles of the type",
+			method: "POST",
+			url:    func(f fixture) string { return fmt.Sprintf("/projects/%s/import", f.projectID) },
+			body: func(f fixture) interface{} {
+				return []interface{}{
+					map[string]interface{}{"type": "vehicle", "start_location": location, "vehicle_type_id": f.vehicleTypeID},
+				}
+			},
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				vehicles := listVehicles(t, f.projectID)
+				require.Len(t, vehicles, 1)
+				vehicle := vehicles[0].(map[string]interface{})
+				assert.Equal(t, f.vehicleTypeID, vehicle["vehicle_type_id"])
+				assert.Equal(t, float64(20), vehicle["max_tasks"])
+				assert.Len(t, listBreaks(t, vehicle["id"].(string)), 1)
+			},
+		},
+		{
+			name:   "Update the vehicle type",
+			method: "PATCH",
+			url:    func(f fixture) string { return fmt.Sprintf("/vehicle_types/%s", f.vehicleTypeID) },
+			body: func(f fixture) interface{} {
+				return map[string]interface{}{"breaks": []interface{}{}}
+			},
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				assert.Equal(t, []interface{}{}, m["data"].(map[string]interface{})["breaks"])
+			},
+		},
+		{
+			name:       "Delete the vehicle type",
+			method:     "DELETE",
+			url:        func(f fixture) string { return fmt.Sprintf("/vehicle_types/%s", f.vehicleTypeID) },
+			body:       func(f fixture) interface{} { return nil },
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				statusCode, _ := sendJSON(t, mux, "GET", fmt.Sprintf("/vehicle_types/%s", f.vehicleTypeID), nil)
+				assert.Equal(t, 404, statusCode)
+				statusCode, _ = sendJSON(t, mux, "POST", fmt.Sprintf("/vehicle_types/%s/vehicles", f.vehicleTypeID), map[string]interface{}{"count": 1, "start_location": location})
+				assert.Equal(t, 404, statusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Each case has its own project with a vehicle type, and the vehicles of the type it needs
+			project := createRow(t, mux, "/projects", map[string]interface{}{"name": tc.name})
+			f := fixture{projectID: project["id"].(string)}
+			vehicleType := createRow(t, mux, fmt.Sprintf("/projects/%s/vehicle_types", f.projectID), van)
+			f.vehicleTypeID = vehicleType["id"].(string)
+			if tc.vehicles > 0 {
+				createRow(t, mux, fmt.Sprintf("/vehicle_types/%s/vehicles", f.vehicleTypeID), map[string]interface{}{"count": tc.vehicles, "start_location": location})
+			}
+
+			statusCode, m := sendJSON(t, mux, tc.method, tc.url(f), tc.body(f))
+			assert.Equal(t, tc.statusCode, statusCode)
+			if tc.resBody != nil {
+				assert.Equal(t, tc.resBody, m)
+			}
+			if tc.errors != nil {
+				assert.Equal(t, tc.errors, m["errors"])
+			}
+			if tc.check != nil {
+				tc.check(t, f, m)
+			}
+		})
+	}
+}
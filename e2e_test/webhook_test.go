@@ -0,0 +1,366 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: webhook_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package e2etest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/api"
+	"github.com/Georepublic/pg_scheduleserv/internal/database"
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver is a local HTTP server receiving the webhook deliveries, which fails the first request of each
+// event when failFirst is true
+type webhookReceiver struct {
+	*httptest.Server
+	mu        sync.Mutex
+	failFirst bool
+	failed    map[string]bool
+	events    []map[string]interface{}
+	invalid   int
+}
+
+func newWebhookReceiver(secret string, failFirst bool) *webhookReceiver {
+	receiver := &webhookReceiver{failFirst: failFirst, failed: map[string]bool{}}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		if r.Header.Get("X-Scheduleserv-Signature") != util.SignWebhookPayload(secret, body) {
+			receiver.invalid++
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		delivery := r.Header.Get("X-Scheduleserv-Delivery")
+		if receiver.failFirst && !receiver.failed[delivery] {
+			receiver.failed[delivery] = true
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		event := map[string]interface{}{}
+		_ = json.Unmarshal(body, &event)
+		receiver.events = append(receiver.events, event)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	return receiver
+}
+
+// received returns the events received, in any order
+func (receiver *webhookReceiver) received() []map[string]interface{} {
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	return append([]map[string]interface{}{}, receiver.events...)
+}
+
+func TestWebhooks(t *testing.T) {
+	// Poll and retry the deliveries quickly, as the defaults are meant for production
+	api.WebhookPollInterval = 50 * time.Millisecond
+	api.WebhookRetryDelay = 100 * time.Millisecond
+	api.WebhookMaxAttempts = 2
+	// The receivers of the tests listen on the loopback address
+	util.AllowPrivateWebhookURLs = true
+	defer func() { util.AllowPrivateWebhookURLs = false }()
+
+	test_db := NewTestDatabase(t)
+	server, conn := setup(test_db, "testdata.sql")
+	defer conn.Close()
+	server.StartWorkers()
+	defer server.Close()
+	mux := server.Router
+
+	type fixture struct {
+		projectID string
+		webhookID string
+		receiver  *webhookReceiver
+	}
+	listDeliveries := func(t *testing.T, webhookID string, status string) []interface{} {
+		statusCode, m := sendJSON(t, mux, "GET", fmt.Sprintf("/webhooks/%s/deliveries?status=%s", webhookID, status), nil)
+		require.Equal(t, 200, statusCode, m)
+		return m["data"].([]interface{})
+	}
+	createJob := func(t *testing.T, f fixture) string {
+		job := createRow(t, mux, fmt.Sprintf("/projects/%s/jobs", f.projectID), map[string]interface{}{
+			"location": map[string]interface{}{"latitude": 2.0, "longitude": 3.0},
+		})
+		return job["id"].(string)
+	}
+	send := func(t *testing.T, method string, url string, body interface{}) {
+		statusCode, m := sendJSON(t, mux, method, url, body)
+		require.Less(t, statusCode, 300, m)
+	}
+
+	secret := "a5b4c3d2e1"
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	testCases := []struct {
+		name       string
+		setup      func(t *testing.T, f fixture)
+		method     string
+		url        func(f fixture) string
+		body       func(f fixture) map[string]interface{}
+		statusCode int
+		errors     []interface{}
+		check      func(t *testing.T, f fixture, m map[string]interface{})
+	}{
+		{
+			name:   "Missing secret",
+			method: "POST",
+			url:    func(f fixture) string { return fmt.Sprintf("/projects/%s/webhooks", f.projectID) },
+			body: func(f fixture) map[string]interface{} {
+				return map[string]interface{}{"url": f.receiver.URL}
+			},
+			statusCode: 400,
+			errors:     []interface{}{"Field 'secret' of type 'string' is required"},
+		},
+		{
+			name:   "Invalid URL",
+			method: "POST",
+			url:    func(f fixture) string { return fmt.Sprintf("/projects/%s/webhooks", f.projectID) },
+			body: func(f fixture) map[string]interface{} {
+				return map[string]interface{}{"url": "ftp://example.com", "secret": secret}
+			},
+			statusCode: 400,
+			errors:     []interface{}{"Field 'url' must be an http or https URL of a public host"},
+		},
+		{
+			name:   "Invalid event",
+			method: "POST",
+			url:    func(f fixture) string { return fmt.Sprintf("/projects/%s/webhooks", f.projectID) },
+			body: func(f fixture) map[string]interface{} {
+				return map[string]interface{}{"url": f.receiver.URL, "secret": secret, "events": []string{"job.created"}}
+			},
+			statusCode: 400,
+			errors: []interface{}{"Field 'events[0]' must be one out of schedule.created, schedule.deleted, " +
+				"job.status_changed, shipment.status_changed, task.created, task.updated, task.deleted"},
+		},
+		{
+			name:   "Webhook of a missing project",
+			method: "POST",
+			url:    func(f fixture) string { return "/projects/123/webhooks" },
+			body: func(f fixture) map[string]interface{} {
+				return map[string]interface{}{"url": f.receiver.URL, "secret": secret}
+			},
+			statusCode: 404,
+		},
+		{
+			name:   "Create a webhook",
+			method: "POST",
+			url:    func(f fixture) string { return fmt.Sprintf("/projects/%s/webhooks", f.projectID) },
+			body: func(f fixture) map[string]interface{} {
+				return map[string]interface{}{"url": f.receiver.URL, "secret": secret}
+			},
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				data := m["data"].(map[string]interface{})
+				assert.Equal(t, f.receiver.URL, data["url"])
+				assert.Equal(t, []interface{}{}, data["events"])
+				assert.Equal(t, true, data["active"])
+				assert.NotContains(t, data, "secret")
+
+				statusCode, m := sendJSON(t, mux, "GET", fmt.Sprintf("/projects/%s/webhooks", f.projectID), nil)
+				require.Equal(t, 200, statusCode)
+				assert.Equal(t, 2, len(m["data"].([]interface{})))
+			},
+		},
+		{
+			name:   "Deliveries of the task and schedule events",
+			method: "POST",
+			url:    func(f fixture) string { return fmt.Sprintf("/projects/%s/jobs", f.projectID) },
+			body: func(f fixture) map[string]interface{} {
+				return map[string]interface{}{"location": map[string]interface{}{"latitude": 2.0, "longitude": 3.0}}
+			},
+			statusCode: 201,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				jobID := m["data"].(map[string]interface{})["id"].(string)
+				send(t, "PATCH", fmt.Sprintf("/jobs/%s", jobID), map[string]interface{}{"priority": 10})
+				send(t, "POST", fmt.Sprintf("/projects/%s/schedule", f.projectID), nil)
+				send(t, "DELETE", fmt.Sprintf("/projects/%s/schedule", f.projectID), nil)
+				send(t, "DELETE", fmt.Sprintf("/jobs/%s", jobID), nil)
+
+				// Each delivery fails once, and succeeds when it is retried
+				require.Eventually(t, func() bool {
+					return len(listDeliveries(t, f.webhookID, "pending")) == 0
+				}, 10*time.Second, 50*time.Millisecond)
+				received := map[string][]map[string]interface{}{}
+				for _, event := range f.receiver.received() {
+					received[event["event"].(string)] = append(received[event["event"].(string)], event)
+				}
+				for _, event := range []string{"task.created", "task.updated", "job.status_changed", "schedule.created", "schedule.deleted", "task.deleted"} {
+					assert.Contains(t, received, event)
+				}
+				f.receiver.mu.Lock()
+				assert.Equal(t, 0, f.receiver.invalid)
+				f.receiver.mu.Unlock()
+
+				deliveries := listDeliveries(t, f.webhookID, "")
+				assert.Equal(t, len(f.receiver.received()), len(deliveries))
+				assert.Equal(t, len(deliveries), len(listDeliveries(t, f.webhookID, "succeeded")))
+				delivery := deliveries[0].(map[string]interface{})
+				assert.Equal(t, "task.deleted", delivery["event"])
+				assert.Equal(t, 2.0, delivery["attempts"])
+				assert.Equal(t, 204.0, delivery["response_code"])
+				assert.Nil(t, delivery["next_attempt_at"])
+				payload := delivery["payload"].(map[string]interface{})
+				assert.Equal(t, f.projectID, payload["project_id"])
+				assert.Equal(t, map[string]interface{}{"task_id": jobID, "type": "job"}, payload["data"])
+
+				// The scheduled jobs are unscheduled when the schedule is deleted
+				statusChanges := []interface{}{}
+				for _, event := range received["job.status_changed"] {
+					data := event["data"].(map[string]interface{})
+					statusChanges = append(statusChanges, []interface{}{data["previous_status"], data["status"]})
+				}
+				assert.Contains(t, statusChanges, []interface{}{"scheduled", "unscheduled"})
+				assert.Equal(t, map[string]interface{}{"version": 1.0, "fresh": false}, received["schedule.created"][0]["data"])
+			},
+		},
+		{
+			name:   "Failed deliveries",
+			method: "PATCH",
+			url:    func(f fixture) string { return fmt.Sprintf("/webhooks/%s", f.webhookID) },
+			body: func(f fixture) map[string]interface{} {
+				return map[string]interface{}{"url": failing.URL, "events": []string{"schedule.deleted"}}
+			},
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				assert.Equal(t, []interface{}{"schedule.deleted"}, m["data"].(map[string]interface{})["events"])
+
+				// The events which the webhook is not subscribed to are not delivered
+				createJob(t, f)
+				send(t, "DELETE", fmt.Sprintf("/projects/%s/schedule", f.projectID), nil)
+
+				var failed []interface{}
+				require.Eventually(t, func() bool {
+					failed = listDeliveries(t, f.webhookID, "failed")
+					return len(failed) == 1
+				}, 10*time.Second, 50*time.Millisecond)
+				delivery := failed[0].(map[string]interface{})
+				assert.Equal(t, "schedule.deleted", delivery["event"])
+				assert.Equal(t, 2.0, delivery["attempts"])
+				assert.Equal(t, 503.0, delivery["response_code"])
+				assert.Equal(t, "Unexpected response status code 503", delivery["error"])
+				assert.Equal(t, 0, len(listDeliveries(t, f.webhookID, "pending")))
+				assert.Equal(t, 1, len(listDeliveries(t, f.webhookID, "")))
+			},
+		},
+		{
+			name:       "Invalid status of the deliveries",
+			method:     "GET",
+			url:        func(f fixture) string { return fmt.Sprintf("/webhooks/%s/deliveries?status=unknown", f.webhookID) },
+			statusCode: 400,
+			errors:     []interface{}{"Field 'status' must be one out of pending, succeeded, failed"},
+		},
+		{
+			name: "Prune the finished deliveries",
+			setup: func(t *testing.T, f fixture) {
+				_, err := conn.Exec(context.Background(), `
+				INSERT INTO webhook_deliveries (webhook_id, event, payload, status, next_attempt_at, updated_at) VALUES
+				($1, 'task.created', '{}', 'succeeded', NULL, current_timestamp - interval '2 hours'),
+				($1, 'task.updated', '{}', 'failed', NULL, current_timestamp - interval '2 hours'),
+				($1, 'task.deleted', '{}', 'pending', current_timestamp + interval '1 day', current_timestamp - interval '2 hours')`, f.webhookID)
+				require.NoError(t, err)
+			},
+			method:     "GET",
+			url:        func(f fixture) string { return fmt.Sprintf("/webhooks/%s/deliveries", f.webhookID) },
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				assert.Equal(t, 3, len(m["data"].([]interface{})))
+				queries := database.New(conn)
+
+				// The deliveries are kept during the retention
+				pruned, err := queries.DBPruneWebhookDeliveries(context.Background(), 24*time.Hour)
+				require.NoError(t, err)
+				assert.Equal(t, int64(0), pruned)
+
+				// The finished deliveries are deleted after the retention, and the pending one is kept
+				pruned, err = queries.DBPruneWebhookDeliveries(context.Background(), time.Hour)
+				require.NoError(t, err)
+				assert.Equal(t, int64(2), pruned)
+				assert.Equal(t, 0, len(listDeliveries(t, f.webhookID, "succeeded")))
+				assert.Equal(t, 0, len(listDeliveries(t, f.webhookID, "failed")))
+				assert.Equal(t, 1, len(listDeliveries(t, f.webhookID, "pending")))
+			},
+		},
+		{
+			name:       "Delete a webhook",
+			method:     "DELETE",
+			url:        func(f fixture) string { return fmt.Sprintf("/webhooks/%s", f.webhookID) },
+			statusCode: 200,
+			check: func(t *testing.T, f fixture, m map[string]interface{}) {
+				statusCode, _ := sendJSON(t, mux, "GET", fmt.Sprintf("/webhooks/%s", f.webhookID), nil)
+				assert.Equal(t, 404, statusCode)
+				statusCode, _ = sendJSON(t, mux, "GET", fmt.Sprintf("/webhooks/%s/deliveries", f.webhookID), nil)
+				assert.Equal(t, 404, statusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Each case has its own project with a vehicle and a webhook to a receiver failing the first request of each event
+			project := createRow(t, mux, "/projects", map[string]interface{}{"name": tc.name, "duration_calc": "euclidean"})
+			f := fixture{projectID: project["id"].(string), receiver: newWebhookReceiver(secret, true)}
+			defer f.receiver.Close()
+			depot := map[string]interface{}{"latitude": 2.0, "longitude": 3.0}
+			send(t, "POST", fmt.Sprintf("/projects/%s/vehicles", f.projectID), map[string]interface{}{"start_location": depot, "end_location": depot})
+			webhook := createRow(t, mux, fmt.Sprintf("/projects/%s/webhooks", f.projectID), map[string]interface{}{"url": f.receiver.URL, "secret": secret})
+			f.webhookID = webhook["id"].(string)
+			if tc.setup != nil {
+				tc.setup(t, f)
+			}
+
+			var body interface{}
+			if tc.body != nil {
+				body = tc.body(f)
+			}
+			statusCode, m := sendJSON(t, mux, tc.method, tc.url(f), body)
+			assert.Equal(t, tc.statusCode, statusCode, m)
+			if tc.errors != nil {
+				assert.Equal(t, map[string]interface{}{"errors": tc.errors, "message": "Bad Request", "code": "400"}, m)
+			}
+			if tc.check != nil {
+				tc.check(t, f, m)
+			}
+		})
+	}
+}
@@ -96,11 +96,12 @@ func (server *Server) CreateBreak(w http.ResponseWriter, r *http.Request) {
 
 // ListBreaks godoc
 // @Summary List breaks
-// @Description Get a list of breaks
+// @Description Get a list of breaks, without the off-shift breaks generated from the shifts of the vehicle unless off_shift is true
 // @Tags Break
 // @Accept application/json
 // @Produce application/json
 // @Param vehicle_id path int true "Vehicle ID"
+// @Param off_shift query bool false "Include the off-shift breaks"
 // @Success 200 {object} util.SuccessResponse{data=[]database.Break}
 // @Failure 400 {object} util.ErrorResponse
 // @Router /vehicles/{vehicle_id}/breaks [get]
@@ -112,8 +113,10 @@ func (server *Server) ListBreaks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	offShift := r.URL.Query().Get("off_shift") == "true"
+
 	ctx := r.Context()
-	created_break, err := server.DBListBreaks(ctx, vehicle_id)
+	created_break, err := server.DBListBreaks(ctx, vehicle_id, offShift)
 	if err != nil {
 		server.FormatJSON(w, http.StatusBadRequest, err)
 		return
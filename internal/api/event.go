@@ -0,0 +1,227 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: event.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/database"
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// Interval between two keep-alive comments of the event streams, so that the idle streams are not closed by proxies.
+// It is a variable so that it can be lowered in the tests.
+var EventsKeepAliveInterval = 15 * time.Second
+
+const (
+	// Number of events buffered for each stream, the events are dropped when a client is too slow to read them
+	eventsBufferSize = 100
+
+	// Delay before listening again to the events after the listening connection failed, which is also the longest
+	// time a stream waits for the server to listen before it is opened
+	eventsRetryDelay = 5 * time.Second
+)
+
+// eventHub dispatches the events received from the database to the streams of their project. The events are only
+// listened to while there are streams.
+type eventHub struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[chan database.ProjectEvent]bool
+	ready       chan struct{}
+	cancel      context.CancelFunc
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subscribers: map[int64]map[chan database.ProjectEvent]bool{}}
+}
+
+// StreamProjectEvents godoc
+// @Summary Stream the events of a project
+// @Description Stream the events of a project as Server-Sent Events (Content-Type = text/event-stream), so that the clients do not have to poll the schedule.
+// @Description
+// @Description Each event has the "id" and the "event" fields of the event, and its "data" is the JSON payload of the event, which is the same as the body of the webhook deliveries. The events are "schedule.created" (when the project is scheduled, its schedule is edited or a version is restored), "schedule.deleted", "job.status_changed" and "shipment.status_changed" (when the status of a task is different after a schedule is created or deleted), "task.created", "task.updated" (when the fields of a task are changed) and "task.deleted".
+// @Description
+// @Description The events are sent with PostgreSQL NOTIFY by the triggers of the tables, so that the changes made through the other servers sharing the database are streamed as well. The events sent while a client is disconnected are not replayed. A keep-alive comment is sent every 15 seconds.
+// @Tags Event
+// @Produce text/event-stream
+// @Param project_id path int true "Project ID"
+// @Param events query string false "Comma separated list of the events to stream, all the events by default"
+// @Success 200 {string} string "Stream of the events"
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/events [get]
+func (server *Server) StreamProjectEvents(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	project_id, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Stream only the given events
+	var filter map[string]bool
+	if events := r.URL.Query().Get("events"); events != "" {
+		filter = map[string]bool{}
+		for _, event := range strings.Split(events, ",") {
+			if !util.IsWebhookEvent(event) {
+				err := fmt.Errorf("Field 'events' must be one out of %s", strings.Join(util.WebhookEvents, ", "))
+				server.FormatJSON(w, http.StatusBadRequest, err)
+				return
+			}
+			filter[event] = true
+		}
+	}
+
+	ctx := r.Context()
+	if _, err := server.DBGetProject(ctx, project_id); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		server.FormatJSON(w, http.StatusInternalServerError, fmt.Errorf("Streaming is not supported"))
+		return
+	}
+
+	events, ready, unsubscribe := server.subscribeEvents(project_id)
+	defer unsubscribe()
+
+	// Open the stream once the events are listened to, so that the changes made after the stream is opened are sent
+	select {
+	case <-ready:
+	case <-time.After(eventsRetryDelay):
+		logrus.Error("The events are not listened to, opening the stream anyway")
+	case <-ctx.Done():
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(EventsKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-server.streamsDone:
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event := <-events:
+			if filter != nil && !filter[event.Event] {
+				continue
+			}
+			// The JSON payload of the events is on a single line
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Event, event.Payload)
+		}
+		flusher.Flush()
+	}
+}
+
+// subscribeEvents returns a channel receiving the events of the project, along with a channel which is closed once
+// the events are listened to, and a function to stop receiving the events. The events are listened to from the first
+// subscription to the last unsubscription.
+func (server *Server) subscribeEvents(projectID int64) (chan database.ProjectEvent, chan struct{}, func()) {
+	hub := server.events
+	events := make(chan database.ProjectEvent, eventsBufferSize)
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	if hub.subscribers[projectID] == nil {
+		hub.subscribers[projectID] = map[chan database.ProjectEvent]bool{}
+	}
+	hub.subscribers[projectID][events] = true
+	if hub.cancel == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		hub.ready = make(chan struct{})
+		hub.cancel = cancel
+		go server.listenEvents(ctx, hub.ready)
+	}
+
+	unsubscribe := func() {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		delete(hub.subscribers[projectID], events)
+		if len(hub.subscribers[projectID]) == 0 {
+			delete(hub.subscribers, projectID)
+		}
+		if len(hub.subscribers) == 0 && hub.cancel != nil {
+			hub.cancel()
+			hub.cancel = nil
+		}
+	}
+	return events, hub.ready, unsubscribe
+}
+
+// listenEvents listens to the events until the context is done, listening again when the connection fails
+func (server *Server) listenEvents(ctx context.Context, ready chan struct{}) {
+	var once sync.Once
+	onListen := func() {
+		once.Do(func() { close(ready) })
+	}
+	for {
+		err := server.ListenEvents(ctx, onListen, server.events.publish)
+		if ctx.Err() != nil {
+			return
+		}
+		logrus.Error(err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventsRetryDelay):
+		}
+	}
+}
+
+// publish sends the event to the streams of its project, without blocking
+func (hub *eventHub) publish(event database.ProjectEvent) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	for events := range hub.subscribers[event.ProjectID] {
+		select {
+		case events <- event:
+		default:
+			logrus.Warnf("Dropping the event %s of the project %d, as the client is too slow", event.ID, event.ProjectID)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: import.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/database"
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-multierror"
+)
+
+// ImportProject godoc
+// @Summary Import jobs, shipments, vehicles and breaks
+// @Description Import the jobs, shipments, vehicles and breaks of a project in a single transaction.
+// @Description
+// @Description The rows are given as a JSON array of objects (Content-Type = application/json), or as a CSV file with a header row (Content-Type = text/csv, or multipart/form-data with the file in the "file" field). Each row has a "type" field (job, shipment, vehicle or break), along with the fields of the corresponding create endpoint. In a CSV file, the nested fields are given with a dot in the column name (e.g. "location.latitude"), and the array and object fields are given in JSON format (e.g. "[10,20]").
+// @Description
+// @Description A break refers to an existing vehicle of the project with the "vehicle_id" field, or to an imported vehicle with the "vehicle_ref" field, matching the "ref" field of the vehicle. A vehicle with a "vehicle_type_id" gets the fields of the vehicle type which are not given, and the breaks of the type.
+// @Description
+// @Description All the rows are validated before any insertion, and the errors of all the rows are returned together. When dry_run = true, the rows are only validated. Default value is false.
+// @Tags Project
+// @Accept application/json,text/csv,multipart/form-data
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Param dry_run query bool false "Dry run"
+// @Param Rows body []object true "Rows to import"
+// @Success 200 {object} util.SuccessResponse{data=database.ImportResult}
+// @Success 201 {object} util.SuccessResponse{data=database.ImportResult}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/import [post]
+func (server *Server) ImportProject(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	if _, err := server.DBGetProject(ctx, projectID); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rows, err := readImportRows(r)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(rows) == 0 {
+		server.FormatJSON(w, http.StatusBadRequest, fmt.Errorf("No rows to import"))
+		return
+	}
+	// The timestamps of the JSON rows are already converted by the timezone middleware, but not the CSV rows
+	for _, row := range rows {
+		util.NormalizeTimestamps(row, util.GetResponseLocation(w))
+	}
+
+	params, err := server.getImportParams(r, projectID, rows)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if r.URL.Query().Get("dry_run") == "true" {
+		server.FormatJSON(w, http.StatusOK, database.ImportResult{
+			Jobs:      len(params.Jobs),
+			Shipments: len(params.Shipments),
+			Vehicles:  len(params.Vehicles),
+			Breaks:    len(params.Breaks),
+			DryRun:    true,
+		})
+		return
+	}
+
+	result, err := server.DBImport(ctx, params)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusCreated, result)
+}
+
+// readImportRows reads the rows from the JSON array or the CSV file in the request body
+func readImportRows(r *http.Request) ([]map[string]interface{}, error) {
+	rows := []map[string]interface{}{}
+	if r.Body == nil {
+		return rows, nil
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch contentType {
+	case "text/csv":
+		return util.ReadImportCSV(r.Body)
+	case "multipart/form-data":
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("Field 'file' with the CSV file is required")
+		}
+		defer file.Close()
+		return util.ReadImportCSV(file)
+	default:
+		if err := json.NewDecoder(r.Body).Decode(&rows); err != nil {
+			return nil, fmt.Errorf("Request body must be a JSON array of objects")
+		}
+		return rows, nil
+	}
+}
+
+// decodeImportRow validates the type of the fields of the row, and decodes the row into params
+func decodeImportRow(row map[string]interface{}, originalStruct interface{}, params interface{}) error {
+	// Validate the input type
+	if err := util.ValidateInput(row, originalStruct); err != nil {
+		return err
+	}
+
+	// Decode map[string]interface{} to struct
+	rowString, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(rowString, params)
+}
+
+// getImportParams validates all the rows, and returns the errors of all the rows along with the row number
+func (server *Server) getImportParams(r *http.Request, projectID int64, rows []map[string]interface{}) (database.ImportParams, error) {
+	params := database.ImportParams{}
+	var errs error
+	appendError := func(row int, err error) {
+		for _, msg := range util.ErrorMessages(err) {
+			errs = multierror.Append(errs, fmt.Errorf("Row %d: %s", row, msg))
+		}
+	}
+
+	// Get the index of the vehicles with a ref, so that the breaks can refer to them
+	vehicleRefs := map[string]int{}
+	vehicleIndex := 0
+	for i, row := range rows {
+		if row["type"] != "vehicle" {
+			continue
+		}
+		if row["ref"] != nil {
+			ref := fmt.Sprint(row["ref"])
+			if _, found := vehicleRefs[ref]; found {
+				appendError(i+1, fmt.Errorf("Field 'ref' must be unique, '%s' is already used", ref))
+			}
+			vehicleRefs[ref] = vehicleIndex
+		}
+		vehicleIndex++
+	}
+
+	for i, row := range rows {
+		rowNumber := i + 1
+		rowType := row["type"]
+		vehicleRef := row["vehicle_ref"]
+
+		// Remove the fields which are only used by the import
+		delete(row, "type")
+		delete(row, "ref")
+		delete(row, "vehicle_ref")
+
+		switch rowType {
+		case "job":
+			row["project_id"] = strconv.FormatInt(projectID, 10)
+			job := database.ImportJobParams{Row: rowNumber}
+			if err := decodeImportRow(row, database.CreateJobParams{}, &job); err != nil {
+				appendError(rowNumber, err)
+			} else if err := server.validate.Struct(job); err != nil {
+				appendError(rowNumber, err)
+			} else {
+				params.Jobs = append(params.Jobs, job)
+			}
+		case "shipment":
+			row["project_id"] = strconv.FormatInt(projectID, 10)
+			shipment := database.ImportShipmentParams{Row: rowNumber}
+			if err := decodeImportRow(row, database.CreateShipmentParams{}, &shipment); err != nil {
+				appendError(rowNumber, err)
+			} else if err := server.validate.Struct(shipment); err != nil {
+				appendError(rowNumber, err)
+			} else {
+				params.Shipments = append(params.Shipments, shipment)
+			}
+		case "vehicle":
+			row["project_id"] = strconv.FormatInt(projectID, 10)
+			vehicle := database.ImportVehicleParams{Row: rowNumber}
+			if err := decodeImportRow(row, database.CreateVehicleParams{}, &vehicle); err != nil {
+				appendError(rowNumber, err)
+			} else if err := server.validate.Struct(vehicle); err != nil {
+				appendError(rowNumber, err)
+			} else if vehicle.VehicleTypeID != nil && *vehicle.VehicleTypeID != 0 && !server.isProjectVehicleType(r, projectID, *vehicle.VehicleTypeID) {
+				appendError(rowNumber, fmt.Errorf("Vehicle type with the given 'vehicle_type_id' does not exist in the project"))
+			} else {
+				params.Vehicles = append(params.Vehicles, vehicle)
+			}
+		case "break":
+			vBreak := database.ImportBreakParams{Row: rowNumber, VehicleIndex: -1}
+			if err := decodeImportRow(row, database.CreateBreakParams{}, &vBreak); err != nil {
+				appendError(rowNumber, err)
+				continue
+			}
+
+			// Refer to an imported vehicle using vehicle_ref, or to an existing vehicle using vehicle_id
+			if vehicleRef != nil {
+				index, found := vehicleRefs[fmt.Sprint(vehicleRef)]
+				if !found {
+					appendError(rowNumber, fmt.Errorf("Field 'vehicle_ref' must be the 'ref' of an imported vehicle"))
+					continue
+				}
+				vBreak.VehicleIndex = index
+				vBreak.VehicleID = new(int64)
+			}
+			if err := server.validate.Struct(vBreak); err != nil {
+				appendError(rowNumber, err)
+				continue
+			}
+			if vBreak.VehicleIndex == -1 {
+				vehicle, err := server.DBGetVehicle(r.Context(), *vBreak.VehicleID)
+				if err != nil || vehicle.ProjectID != projectID {
+					appendError(rowNumber, fmt.Errorf("Vehicle with the given 'vehicle_id' does not exist in the project"))
+					continue
+				}
+			}
+			params.Breaks = append(params.Breaks, vBreak)
+		default:
+			appendError(rowNumber, fmt.Errorf("Field 'type' must be one out of job, shipment, vehicle, break"))
+		}
+	}
+	return params, errs
+}
+
+// isProjectVehicleType returns whether the vehicle type exists in the project
+func (server *Server) isProjectVehicleType(r *http.Request, projectID int64, vehicleTypeID int64) bool {
+	vehicleType, err := server.DBGetVehicleType(r.Context(), vehicleTypeID)
+	return err == nil && vehicleType.ProjectID == projectID
+}
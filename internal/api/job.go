@@ -30,6 +30,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -42,6 +43,12 @@ import (
 // CreateJob godoc
 // @Summary Create a new job
 // @Description Create a new job with the input payload
+// @Description
+// @Description When "recurrence" is given as a recurrence rule (FREQ=DAILY, WEEKLY or MONTHLY, with INTERVAL, BYDAY, BYMONTHDAY, COUNT and UNTIL), the job is a recurring job which is not scheduled itself.
+// @Description Instead, a job is created for each occurrence within the planning horizon of the project when it is scheduled, with the time windows of the recurring job moved to the date of the occurrence, and the "recurring_job_id" and "occurrence" fields set.
+// @Description The occurrences are kept when the project is scheduled again, and updated with the changes of their recurring job. An occurrence modified like any other job is marked as "overridden", and keeps its changes instead.
+// @Description
+// @Description The "skills" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.
 // @Tags Job
 // @Accept application/json
 // @Produce application/json
@@ -154,6 +161,8 @@ func (server *Server) GetJob(w http.ResponseWriter, r *http.Request) {
 // GetJob godoc
 // @Summary Update a job
 // @Description Update a job (partial update) with its job_id
+// @Description
+// @Description The "skills" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.
 // @Tags Job
 // @Accept application/json
 // @Produce application/json
@@ -242,9 +251,13 @@ func (server *Server) DeleteJob(w http.ResponseWriter, r *http.Request) {
 // GetJobSchedule godoc
 // @Summary Get the schedule for a job
 // @Description Get the schedule for a job using job_id
+// @Description
+// @Description **For GeoJSON content type**: A FeatureCollection is returned, with a Point feature for each step of the job, or with "unassigned" = true if the job is unassigned.
+// @Description
+// @Description **For CSV and XLSX content types**: A row is returned for the step of the job, with the flattened task_data keys, in the Schedule section (CSV) or sheet (XLSX), or in the Unassigned one if the job is unassigned.
 // @Tags Job
 // @Accept application/json
-// @Produce text/calendar,application/json
+// @Produce text/calendar,application/json,application/geo+json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param job_id path int true "Job ID"
 // @Success 200 {object} util.SuccessResponse{data=[]util.ScheduleDataTask}
 // @Failure 400 {object} util.ErrorResponse
@@ -274,6 +287,14 @@ func (server *Server) GetJobSchedule(w http.ResponseWriter, r *http.Request) {
 			Schedule:  schedule.Schedule,
 			ProjectID: schedule.ProjectID,
 		})
+	case "application/geo+json":
+		server.FormatGeoJSON(w, http.StatusOK, server.GetScheduleGeoJSON(schedule, false))
+	case "text/csv":
+		filename := fmt.Sprintf("schedule-%d.csv", schedule.ProjectID)
+		server.FormatCSV(w, http.StatusOK, server.GetScheduleTables(schedule), filename)
+	case util.XLSXContentType:
+		filename := fmt.Sprintf("schedule-%d.xlsx", schedule.ProjectID)
+		server.FormatXLSX(w, http.StatusOK, server.GetScheduleTables(schedule), filename)
 	default:
 		calendar, filename := server.GetScheduleICal(schedule)
 		server.FormatICAL(w, http.StatusOK, calendar, filename)
@@ -0,0 +1,67 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: matrix.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// ClearMatrix godoc
+// @Summary Clear the matrix cache
+// @Description Clear the cached durations between the locations of a project, so that they are computed again by the routing engine in the next schedule request.
+// @Description
+// @Description The cached durations are also cleared automatically when the "duration_calc" field of the project is changed. The durations between the locations which are still used by another project with the same "duration_calc" are kept.
+// @Tags Matrix
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Success 200 {object} util.Success
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/matrix [delete]
+func (server *Server) ClearMatrix(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	project_id, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	err = server.DBClearMatrix(ctx, project_id)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, nil)
+}
@@ -42,7 +42,17 @@ import (
 // CreateProject godoc
 // @Summary Create a new project
 // @Description Create a new project with the input payload
-// @Description The "duration_calc" parameter must be either "euclidean", "valhalla" or "osrm"
+// @Description The "duration_calc" parameter must be one of the registered matrix providers: "euclidean", "valhalla" or "osrm", and "graphhopper", "openrouteservice", "pgrouting" or "static" when configured
+// @Description
+// @Description The solver options are passed to vrp_vroom when the project is scheduled:
+// @Description - "objective": minimise the total "duration" of the routes (default), the number of "vehicles", or the total "cost".
+// @Description - "vehicle_fixed_cost" and "vehicle_cost_per_hour": cost of using a vehicle, and cost of an hour of its route, used with the "cost" objective. Default values are 0 and 3600.
+// @Description The objective is given to vrp_vroom as the costs of the travels between the locations: the duration of a travel times the cost per hour, plus the fixed cost for the travels leaving the start of a vehicle.
+// @Description The tasks and the vehicles are given to the solver ordered by their id, so that the schedule of the same project is reproducible.
+// @Description
+// @Description The planning horizon "horizon_start" and "horizon_end" (dates in the YYYY-MM-DD format, at most 366 days apart) is used to create the occurrences of the recurring jobs and the shifts of the vehicles when the project is scheduled.
+// @Description
+// @Description The "timezone" is the IANA time zone of the project, such as "Europe/Berlin". The timestamps of the project are then local times of the time zone: the timestamps with an offset (RFC 3339) are converted to the local time, the timestamps without an offset are local times, and the timestamps are returned with the offset of the time zone. When the time zone is changed, the local times of the timestamps are kept. Without a time zone, the timestamps are returned without an offset, and the timestamps with an offset are converted to UTC.
 // @Tags Project
 // @Accept application/json
 // @Produce application/json
@@ -142,7 +152,17 @@ func (server *Server) GetProject(w http.ResponseWriter, r *http.Request) {
 // UpdateProject godoc
 // @Summary Update a project
 // @Description Update a project with its project_id
-// @Description The "duration_calc" parameter must be either "euclidean", "valhalla" or "osrm"
+// @Description The "duration_calc" parameter must be one of the registered matrix providers: "euclidean", "valhalla" or "osrm", and "graphhopper", "openrouteservice", "pgrouting" or "static" when configured
+// @Description
+// @Description The solver options are passed to vrp_vroom when the project is scheduled:
+// @Description - "objective": minimise the total "duration" of the routes (default), the number of "vehicles", or the total "cost".
+// @Description - "vehicle_fixed_cost" and "vehicle_cost_per_hour": cost of using a vehicle, and cost of an hour of its route, used with the "cost" objective. Default values are 0 and 3600.
+// @Description The objective is given to vrp_vroom as the costs of the travels between the locations: the duration of a travel times the cost per hour, plus the fixed cost for the travels leaving the start of a vehicle.
+// @Description The tasks and the vehicles are given to the solver ordered by their id, so that the schedule of the same project is reproducible.
+// @Description
+// @Description The planning horizon "horizon_start" and "horizon_end" (dates in the YYYY-MM-DD format, at most 366 days apart) is used to create the occurrences of the recurring jobs and the shifts of the vehicles when the project is scheduled.
+// @Description
+// @Description The "timezone" is the IANA time zone of the project, such as "Europe/Berlin". The timestamps of the project are then local times of the time zone: the timestamps with an offset (RFC 3339) are converted to the local time, the timestamps without an offset are local times, and the timestamps are returned with the offset of the time zone. When the time zone is changed, the local times of the timestamps are kept. Without a time zone, the timestamps are returned without an offset, and the timestamps with an offset are converted to UTC.
 // @Tags Project
 // @Accept application/json
 // @Produce application/json
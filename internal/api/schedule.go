@@ -29,6 +29,8 @@ along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
 package api
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -42,14 +44,19 @@ import (
 // @Description
 // @Description When fresh = true, the old schedule is ignored and a fresh schedule is created. Otherwise, the old schedule of each task is altered such that it remains in the "max_shift" interval. Default value is false.
 // @Description **For JSON content type**: When overview = true, only the metadata is returned. Default value is false, which also returns the summary object.
+// @Description When async = true, the schedule run is queued and 202 Accepted is returned immediately with the run. The status of the run can be polled using the "/projects/{project_id}/schedule/runs/{run_id}" endpoint, and the schedule can be fetched once the run has succeeded. Default value is false.
+// @Description The schedule fails when the tasks, the vehicles or the solver settings of the project are changed while it is solved, and the previous schedule is kept.
 // @Tags Schedule
 // @Accept application/json
 // @Produce application/json
 // @Param project_id path int true "Project ID"
 // @Param fresh query bool false "Fresh"
 // @Param overview query bool false "Overview"
+// @Param async query bool false "Async"
 // @Success 201 {object} util.SuccessResponse{data=util.ScheduleData}
+// @Success 202 {object} util.SuccessResponse{data=database.ScheduleRun}
 // @Failure 400 {object} util.ErrorResponse
+// @Failure 503 {object} util.ErrorResponse
 // @Router /projects/{project_id}/schedule [post]
 func (server *Server) CreateSchedule(w http.ResponseWriter, r *http.Request) {
 	// Add the project_id path variable
@@ -62,6 +69,23 @@ func (server *Server) CreateSchedule(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 	fresh := r.URL.Query().Get("fresh")
+
+	// Queue the schedule run to be processed by a worker, and return the run
+	if r.URL.Query().Get("async") == "true" {
+		run, err := server.DBCreateScheduleRun(ctx, projectID, fresh == "true", server.id)
+		if err != nil {
+			server.FormatJSON(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := server.queueScheduleRun(ctx, run); err != nil {
+			server.FormatJSON(w, http.StatusServiceUnavailable, err)
+			return
+		}
+		w.Header().Set("Location", fmt.Sprintf("/projects/%d/schedule/runs/%d", projectID, run.ID))
+		server.FormatJSON(w, http.StatusAccepted, run)
+		return
+	}
+
 	err = server.DBCreateSchedule(ctx, projectID, fresh)
 	if err != nil {
 		server.FormatJSON(w, http.StatusBadRequest, err)
@@ -90,11 +114,22 @@ func (server *Server) CreateSchedule(w http.ResponseWriter, r *http.Request) {
 // @Description Get the schedule for a project.
 // @Description
 // @Description **For JSON content type**: When overview = true, only the metadata is returned. Default value is false, which also returns the summary object.
+// @Description
+// @Description When group_by = day, the steps of the vehicle routes are grouped by the day of their arrival, which is useful for the schedules over a planning horizon of several days.
+// @Description
+// @Description When geometry = true, the route geometry of each vehicle is returned, as a GeoJSON LineString (geometry_format = geojson, default) or an encoded polyline with precision 5 (geometry_format = polyline). Straight segments between the locations are returned when the duration calculation method can not compute the road path.
+// @Description
+// @Description **For GeoJSON content type**: A FeatureCollection is returned, with a Point feature for each step of the routes, a LineString feature for each vehicle route (following the road path when geometry = true), and a Point feature with "unassigned" = true for each unassigned task.
+// @Description
+// @Description **For CSV and XLSX content types**: A row is returned for each step of the routes, except the off-shift breaks, with the flattened task_data keys, followed by the unassigned tasks and the summary of each vehicle, in separate sections (CSV) or sheets (XLSX). The task_data values starting with "=", "+", "-" or "@" are prefixed with a quote in the CSV, so that they are not evaluated as formulas.
 // @Tags Schedule
 // @Accept application/json
-// @Produce text/calendar,application/json
+// @Produce text/calendar,application/json,application/geo+json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param project_id path int true "Project ID"
 // @Param overview query bool false "Overview"
+// @Param geometry query bool false "Geometry"
+// @Param geometry_format query string false "Geometry format (geojson or polyline)"
+// @Param group_by query string false "Group the routes by (day)"
 // @Success 200 {object} util.SuccessResponse{data=util.ScheduleData}
 // @Failure 400 {object} util.ErrorResponse
 // @Failure 404 {object} util.NotFound
@@ -126,8 +161,31 @@ func (server *Server) GetSchedule(w http.ResponseWriter, r *http.Request) {
 				ProjectID: schedule.ProjectID,
 			})
 		} else {
-			server.FormatJSON(w, http.StatusOK, schedule)
+			if err := server.addScheduleGeometry(r, &schedule, r.URL.Query().Get("geometry_format")); err != nil {
+				server.FormatJSON(w, http.StatusBadRequest, err)
+				return
+			}
+			switch r.URL.Query().Get("group_by") {
+			case "":
+				server.FormatJSON(w, http.StatusOK, schedule)
+			case "day":
+				server.FormatJSON(w, http.StatusOK, util.GroupScheduleByDay(schedule))
+			default:
+				server.FormatJSON(w, http.StatusBadRequest, fmt.Errorf("Invalid group_by '%s', must be 'day'", r.URL.Query().Get("group_by")))
+			}
 		}
+	case "application/geo+json":
+		if err := server.addScheduleGeometry(r, &schedule, "geojson"); err != nil {
+			server.FormatJSON(w, http.StatusBadRequest, err)
+			return
+		}
+		server.FormatGeoJSON(w, http.StatusOK, server.GetScheduleGeoJSON(schedule, true))
+	case "text/csv":
+		filename := fmt.Sprintf("schedule-%d.csv", schedule.ProjectID)
+		server.FormatCSV(w, http.StatusOK, server.GetScheduleTables(schedule), filename)
+	case util.XLSXContentType:
+		filename := fmt.Sprintf("schedule-%d.xlsx", schedule.ProjectID)
+		server.FormatXLSX(w, http.StatusOK, server.GetScheduleTables(schedule), filename)
 	default:
 		calendar, filename := server.GetScheduleICal(schedule)
 		server.FormatICAL(w, http.StatusOK, calendar, filename)
@@ -163,3 +221,153 @@ func (server *Server) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
 
 	server.FormatJSON(w, http.StatusOK, nil)
 }
+
+// EditSchedule godoc
+// @Summary Edit the schedule
+// @Description Edit the routes of the schedule of a project manually, applying the operations in the given order, and return the new schedule.
+// @Description
+// @Description The operations are:
+// @Description - "move": move an assigned stop (type and task_id) to the route of the vehicle with vehicle_id, at the given position of the route (starting from 0). The stop is added at the end of the route when the position is not given.
+// @Description - "insert": insert an unassigned stop in the route of a vehicle, in the same way as "move".
+// @Description - "remove": remove a stop from its route, making it unassigned. Removing the pickup or the delivery of a shipment removes both.
+// @Description - "swap": swap a stop (type and task_id) with another stop (other_type and other_task_id), in the same route or in the routes of two vehicles.
+// @Description - "reorder": change the order of the stops of the route of the vehicle with vehicle_id, given in the "stops" field.
+// @Description
+// @Description The type of a stop is "job", "pickup", "delivery" or "break". The pickup and the delivery of a shipment must be in the same route, with the pickup before the delivery, and a break can only be in the route of its vehicle.
+// @Description
+// @Description The arrival, departure, waiting time and load of the edited routes are computed again using the cached matrix. When the edited routes do not satisfy the time windows, capacity, skills, max tasks, pinned vehicles or locked tasks, the edit is rejected, unless force = true, in which case the violations are returned in the "warnings" field. The edited schedule is saved as a new version.
+// @Tags Schedule
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Param ScheduleEdit body util.ScheduleEditParams true "Schedule edit operations"
+// @Success 200 {object} util.SuccessResponse{data=util.ScheduleEditData}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/schedule [patch]
+func (server *Server) EditSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	params := util.ScheduleEditParams{}
+	if r.Body == nil || json.NewDecoder(r.Body).Decode(&params) != nil {
+		server.FormatJSON(w, http.StatusBadRequest, fmt.Errorf("Request body must contain the schedule edit operations"))
+		return
+	}
+
+	// Validate the struct
+	if err := server.validate.Struct(params); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	schedule, err := server.DBEditSchedule(ctx, projectID, params)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, schedule)
+}
+
+// ValidateSchedule godoc
+// @Summary Validate a proposed schedule
+// @Description Simulate the proposed routes of the vehicles of a project, given as the ordered stops of each vehicle, against the jobs, shipments, vehicles and breaks of the project, and return the computed timings along with the violations of the constraints. The schedule of the project is not modified.
+// @Description
+// @Description The type of a stop is "job", "pickup", "delivery" or "break", and a break can only be in the route of its vehicle. The jobs and shipments which are not in any route are unassigned.
+// @Description
+// @Description The arrival, departure, waiting time and load of the routes are computed using the cached matrix, starting each route at the start of the time window of the vehicle. The "violations" field lists the constraints which are not satisfied, with the type "time_window", "capacity" (for each dimension of the load), "skills", "max_tasks", "max_travel_time", "max_distance", "vehicle_time_window", "pinned_vehicle" or "pickup_delivery", and "feasible" is true when there are no violations.
+// @Tags Schedule
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Param ScheduleValidate body util.ScheduleValidateParams true "Proposed routes"
+// @Success 200 {object} util.SuccessResponse{data=util.ScheduleValidation}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/schedule/validate [post]
+func (server *Server) ValidateSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	params := util.ScheduleValidateParams{}
+	if r.Body == nil || json.NewDecoder(r.Body).Decode(&params) != nil {
+		server.FormatJSON(w, http.StatusBadRequest, fmt.Errorf("Request body must contain the proposed routes"))
+		return
+	}
+
+	// Validate the struct
+	if err := server.validate.Struct(params); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	validation, err := server.DBValidateSchedule(ctx, projectID, params)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, validation)
+}
+
+// CompareSchedules godoc
+// @Summary Compare two schedules
+// @Description Compare the schedule of a project with the schedule of another project, such as a clone of the project.
+// @Description
+// @Description The response contains the overall and per-vehicle travel, setup, service, waiting time and distance of both schedules, along with the delta (other - base). The vehicles and tasks of a project are matched with the ones of its clones.
+// @Description
+// @Description For each task, the "changes" field lists whether the task "moved" to another vehicle, was "resequenced" in the same vehicle, became "assigned" or "unassigned", or was "added" or "removed". The "arrival_shift" field is the shift of the arrival time of the tasks assigned in both schedules.
+// @Tags Schedule
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Param other_project_id path int true "Other Project ID"
+// @Success 200 {object} util.SuccessResponse{data=util.ScheduleComparison}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/schedule/compare/{other_project_id} [get]
+func (server *Server) CompareSchedules(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+	otherProjectID, err := strconv.ParseInt(vars["other_project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	comparison, err := server.DBCompareSchedules(ctx, projectID, otherProjectID)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, comparison)
+}
+
+// addScheduleGeometry adds the route geometry in the given format to the schedule when requested with geometry = true
+func (server *Server) addScheduleGeometry(r *http.Request, schedule *util.ScheduleData, format string) error {
+	if r.URL.Query().Get("geometry") != "true" || schedule.ProjectID == 0 {
+		return nil
+	}
+	project, err := server.DBGetProject(r.Context(), schedule.ProjectID)
+	if err != nil {
+		return err
+	}
+	return util.AddScheduleGeometry(r.Context(), schedule, project.DurationCalc, format)
+}
@@ -0,0 +1,276 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_run.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/database"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Number of schedule runs that are solved concurrently
+	scheduleWorkers = 2
+
+	// Number of schedule runs that can wait in the queue for a worker
+	scheduleQueueSize = 100
+)
+
+// Settings of the heartbeat of the schedule runs, which are variables so that they can be lowered in the tests
+var (
+	// Interval between two heartbeats of the runs queued or running on the server
+	ScheduleRunHeartbeatInterval = 10 * time.Second
+
+	// Duration without heartbeat after which the runs of a stopped server are executed by another server when they
+	// are queued, or marked as failed when they are running
+	ScheduleRunStaleAfter = time.Minute
+)
+
+// ListScheduleRuns godoc
+// @Summary List the schedule runs
+// @Description Get a list of the schedule runs for a project, latest first
+// @Tags Schedule
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Success 200 {object} util.SuccessResponse{data=[]database.ScheduleRun}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/schedule/runs [get]
+func (server *Server) ListScheduleRuns(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	runs, err := server.DBListScheduleRuns(ctx, projectID)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, runs)
+}
+
+// GetScheduleRun godoc
+// @Summary Fetch a schedule run
+// @Description Fetch the status of a schedule run created with async = true.
+// @Description
+// @Description The status is one of "queued", "running", "succeeded" or "failed". The error field contains the error message of a failed run.
+// @Description
+// @Description The runs of a project are executed one at a time. When the server is stopped, the runs being executed are finished, and the queued runs are executed by the other servers or when it starts again. The runs left running by a server which stopped abruptly are marked as failed a minute after its last heartbeat.
+// @Tags Schedule
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Param run_id path int true "Run ID"
+// @Success 200 {object} util.SuccessResponse{data=database.ScheduleRun}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/schedule/runs/{run_id} [get]
+func (server *Server) GetScheduleRun(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+	runID, err := strconv.ParseInt(vars["run_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	run, err := server.DBGetScheduleRun(ctx, projectID, runID)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, run)
+}
+
+// startScheduleWorkers starts the workers executing the queued runs, until the workers are stopped
+func (server *Server) startScheduleWorkers() {
+	for i := 0; i < scheduleWorkers; i++ {
+		server.runWorkers.Add(1)
+		go func() {
+			defer server.runWorkers.Done()
+			for {
+				select {
+				case <-server.stopping:
+					return
+				case <-server.ctx.Done():
+					return
+				case run := <-server.scheduleRuns:
+					server.executeScheduleRun(run)
+				}
+			}
+		}()
+	}
+}
+
+// stopScheduleWorkers stops the workers from starting the queued runs, which are released to the other servers, and
+// waits for the runs being executed until the context is done, after which they are cancelled
+func (server *Server) stopScheduleWorkers(ctx context.Context) {
+	close(server.stopping)
+	if err := server.DBReleaseScheduleRuns(context.Background(), server.id); err != nil {
+		logrus.Error(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		server.runWorkers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		server.cancel()
+		<-done
+	}
+}
+
+// startScheduleHeartbeat records the heartbeat of the runs of the server, and recovers the runs of the stopped
+// servers, until the server is closed
+func (server *Server) startScheduleHeartbeat() {
+	server.workers.Add(1)
+	go func() {
+		defer server.workers.Done()
+		for {
+			server.recoverScheduleRuns()
+			select {
+			case <-server.ctx.Done():
+				return
+			case <-time.After(ScheduleRunHeartbeatInterval):
+			}
+			if err := server.DBHeartbeatScheduleRuns(server.ctx, server.id); err != nil {
+				logrus.Error(err)
+			}
+		}
+	}()
+}
+
+// recoverScheduleRuns marks as failed the runs left running by the stopped servers, and queues the runs they left
+// queued, while the workers of the server are not stopped
+func (server *Server) recoverScheduleRuns() {
+	if err := server.DBFailStaleScheduleRuns(server.ctx, ScheduleRunStaleAfter); err != nil {
+		logrus.Error(err)
+	}
+	select {
+	case <-server.stopping:
+		return
+	default:
+	}
+
+	limit := cap(server.scheduleRuns) - len(server.scheduleRuns)
+	if limit == 0 {
+		return
+	}
+	runs, err := server.DBClaimScheduleRuns(server.ctx, server.id, ScheduleRunStaleAfter, limit)
+	if err != nil {
+		logrus.Error(err)
+		return
+	}
+	for _, run := range runs {
+		if err := server.queueScheduleRun(server.ctx, run); err != nil {
+			logrus.Error(err)
+		}
+	}
+}
+
+// newServerID returns a random ID of the server, recorded as the owner of its schedule runs
+func newServerID() int64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		logrus.Error(err)
+	}
+	return int64(binary.BigEndian.Uint64(b[:]) >> 1)
+}
+
+// Add the run to the queue without blocking, the run is marked as failed if the queue is full
+func (server *Server) queueScheduleRun(ctx context.Context, run database.ScheduleRun) error {
+	select {
+	case server.scheduleRuns <- run:
+		return nil
+	default:
+		err := fmt.Errorf("Too many schedule runs in the queue, try again later")
+		if dbErr := server.DBFinishScheduleRun(ctx, run.ID, err); dbErr != nil {
+			logrus.Error(dbErr)
+		}
+		return err
+	}
+}
+
+func (server *Server) executeScheduleRun(run database.ScheduleRun) {
+	// The request context is already cancelled, so the run uses the context of the server, and is cancelled when
+	// the server is closed
+	ctx := server.ctx
+	started, err := server.DBStartScheduleRun(ctx, run.ID, server.id)
+	if err != nil {
+		logrus.Error(err)
+		if err := server.DBFinishScheduleRun(context.Background(), run.ID, err); err != nil {
+			logrus.Error(err)
+		}
+		return
+	}
+	// The run was marked as failed or released while it was in the queue
+	if !started {
+		return
+	}
+
+	fresh := strconv.FormatBool(run.Fresh)
+	runErr := server.DBCreateSchedule(ctx, run.ProjectID, fresh)
+	if runErr != nil {
+		logrus.Error(runErr)
+	}
+	// The run cancelled by the server being closed is executed again by another server
+	if runErr != nil && ctx.Err() != nil {
+		if err := server.DBRequeueScheduleRun(context.Background(), run.ID); err != nil {
+			logrus.Error(err)
+		}
+		return
+	}
+	// The result is recorded even when the run is cancelled
+	if err := server.DBFinishScheduleRun(context.Background(), run.ID, runErr); err != nil {
+		logrus.Error(err)
+	}
+}
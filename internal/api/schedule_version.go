@@ -0,0 +1,143 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_version.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// getScheduleVersionVars returns the project_id and version of the request
+func getScheduleVersionVars(r *http.Request) (int64, int32, error) {
+	vars := mux.Vars(r)
+	projectID, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	version, err := strconv.ParseInt(vars["version"], 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	return projectID, int32(version), nil
+}
+
+// ListScheduleVersions godoc
+// @Summary List the schedule versions
+// @Description Get a list of the saved versions of the schedule for a project, latest first.
+// @Description
+// @Description Every schedule created for the project is saved as a numbered version, along with the hash of the inputs (jobs, shipments, vehicles and breaks), the solver settings and the metadata of the schedule. The active version is the current schedule of the project.
+// @Tags Schedule
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Success 200 {object} util.SuccessResponse{data=[]database.ScheduleVersion}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/schedule/versions [get]
+func (server *Server) ListScheduleVersions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	versions, err := server.DBListScheduleVersions(ctx, projectID)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, versions)
+}
+
+// GetScheduleVersion godoc
+// @Summary Fetch a schedule version
+// @Description Fetch the schedule saved in a version, in the same format as the schedule of the project
+// @Tags Schedule
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Param version path int true "Version"
+// @Success 200 {object} util.SuccessResponse{data=util.ScheduleData}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/schedule/versions/{version} [get]
+func (server *Server) GetScheduleVersion(w http.ResponseWriter, r *http.Request) {
+	projectID, version, err := getScheduleVersionVars(r)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	schedule, err := server.DBGetScheduleVersionData(ctx, projectID, version)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+	schedule.ProjectID = projectID
+
+	server.FormatJSON(w, http.StatusOK, schedule)
+}
+
+// RestoreScheduleVersion godoc
+// @Summary Restore a schedule version
+// @Description Replace the schedule of the project with the schedule saved in a version, and make it the active version.
+// @Description
+// @Description The status of the jobs and shipments is reset according to the restored schedule.
+// @Tags Schedule
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Param version path int true "Version"
+// @Success 200 {object} util.SuccessResponse{data=util.ScheduleData}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/schedule/versions/{version}/restore [post]
+func (server *Server) RestoreScheduleVersion(w http.ResponseWriter, r *http.Request) {
+	projectID, version, err := getScheduleVersionVars(r)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	schedule, err := server.DBRestoreScheduleVersion(ctx, projectID, version)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+	schedule.ProjectID = projectID
+
+	server.FormatJSON(w, http.StatusOK, schedule)
+}
@@ -29,7 +29,11 @@ along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
 package api
 
 import (
+	"context"
+	"errors"
+	"net"
 	"net/http"
+	"sync"
 
 	"github.com/Georepublic/pg_scheduleserv/internal/database"
 	"github.com/Georepublic/pg_scheduleserv/internal/util"
@@ -42,33 +46,90 @@ import (
 )
 
 type Server struct {
-	conn     *pgxpool.Pool
-	Router   *mux.Router
-	validate *validator.Validate
+	conn         *pgxpool.Pool
+	Router       *mux.Router
+	validate     *validator.Validate
+	scheduleRuns chan database.ScheduleRun
+	id           int64
+	events       *eventHub
+	httpServer   *http.Server
+	streamsDone  chan struct{}
+	stopping     chan struct{}
+	runWorkers   sync.WaitGroup
+	workers      sync.WaitGroup
+	ctx          context.Context
+	cancel       context.CancelFunc
 	*database.Store
 	*util.Formatter
 }
 
 func NewServer(conn *pgxpool.Pool) *Server {
 	router := mux.NewRouter().StrictSlash(true)
+	ctx, cancel := context.WithCancel(context.Background())
 	server := &Server{
-		conn:      conn,
-		Router:    router,
-		validate:  util.NewValidator(),
-		Store:     database.NewStore(conn),
-		Formatter: util.NewFormatter(),
+		conn:         conn,
+		Router:       router,
+		validate:     util.NewValidator(),
+		scheduleRuns: make(chan database.ScheduleRun, scheduleQueueSize),
+		id:           newServerID(),
+		events:       newEventHub(),
+		streamsDone:  make(chan struct{}),
+		stopping:     make(chan struct{}),
+		ctx:          ctx,
+		cancel:       cancel,
+		Store:        database.NewStore(conn),
+		Formatter:    util.NewFormatter(),
 	}
 
 	server.handleRoutes(router)
+	router.Use(server.TimezoneMiddleware)
 	serveSwagger(router)
+
+	// handle CORS
+	server.httpServer = &http.Server{Handler: util.Logger(cors.AllowAll().Handler(router))}
+	// The event streams never end by themselves, and are closed when the server is shut down
+	server.httpServer.RegisterOnShutdown(func() { close(server.streamsDone) })
 	return server
 }
 
+// StartWorkers starts the background workers of the server, executing the schedule runs and sending the webhook
+// deliveries, until the server is closed
+func (server *Server) StartWorkers() {
+	server.startScheduleWorkers()
+	server.startScheduleHeartbeat()
+	server.startWebhookDispatcher()
+}
+
+// Start starts the background workers of the server, and serves the requests until the server is shut down
 func (server *Server) Start(port string) error {
+	server.StartWorkers()
+
+	listener, err := net.Listen("tcp", port)
+	if err != nil {
+		return err
+	}
 	logrus.Info("Serving requests on port", port)
+	if err := server.httpServer.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
 
-	// handle CORS
-	return http.ListenAndServe(port, util.Logger(cors.AllowAll().Handler(server.Router)))
+// Shutdown stops serving the requests, waiting for the requests being served until the context is done, and then
+// stops the schedule workers, waiting for the schedule runs being executed until the context is done. The queued runs
+// are released to the other servers, or to the next start of the server. The server is then closed.
+func (server *Server) Shutdown(ctx context.Context) error {
+	err := server.httpServer.Shutdown(ctx)
+	server.stopScheduleWorkers(ctx)
+	server.Close()
+	return err
+}
+
+// Close stops the background workers of the server, and waits for them to stop. The schedule runs being executed are
+// cancelled, and queued again for the other servers.
+func (server *Server) Close() {
+	server.cancel()
+	server.workers.Wait()
 }
 
 func (server *Server) handleRoutes(router *mux.Router) {
@@ -77,14 +138,30 @@ func (server *Server) handleRoutes(router *mux.Router) {
 	// Projects endpoints
 	router.HandleFunc("/projects", server.CreateProject).Methods("POST")
 	router.HandleFunc("/projects", server.ListProjects).Methods("GET")
+	router.HandleFunc("/projects/import", server.ImportProjectSnapshot).Methods("POST")
 	router.HandleFunc("/projects/{project_id}", server.GetProject).Methods("GET")
 	router.HandleFunc("/projects/{project_id}", server.UpdateProject).Methods("PATCH")
 	router.HandleFunc("/projects/{project_id}", server.DeleteProject).Methods("DELETE")
+	router.HandleFunc("/projects/{project_id}/import", server.ImportProject).Methods("POST")
+	router.HandleFunc("/projects/{project_id}/export", server.ExportProject).Methods("GET")
+	router.HandleFunc("/projects/{project_id}/clone", server.CloneProject).Methods("POST")
+	router.HandleFunc("/projects/{project_id}/events", server.StreamProjectEvents).Methods("GET")
 
 	// Schedule related endpoints
 	router.HandleFunc("/projects/{project_id}/schedule", server.GetSchedule).Methods("GET")
 	router.HandleFunc("/projects/{project_id}/schedule", server.CreateSchedule).Methods("POST")
+	router.HandleFunc("/projects/{project_id}/schedule", server.EditSchedule).Methods("PATCH")
 	router.HandleFunc("/projects/{project_id}/schedule", server.DeleteSchedule).Methods("DELETE")
+	router.HandleFunc("/projects/{project_id}/schedule/compare/{other_project_id}", server.CompareSchedules).Methods("GET")
+	router.HandleFunc("/projects/{project_id}/schedule/validate", server.ValidateSchedule).Methods("POST")
+	router.HandleFunc("/projects/{project_id}/schedule/runs", server.ListScheduleRuns).Methods("GET")
+	router.HandleFunc("/projects/{project_id}/schedule/runs/{run_id}", server.GetScheduleRun).Methods("GET")
+	router.HandleFunc("/projects/{project_id}/schedule/versions", server.ListScheduleVersions).Methods("GET")
+	router.HandleFunc("/projects/{project_id}/schedule/versions/{version}", server.GetScheduleVersion).Methods("GET")
+	router.HandleFunc("/projects/{project_id}/schedule/versions/{version}/restore", server.RestoreScheduleVersion).Methods("POST")
+
+	// Matrix endpoints
+	router.HandleFunc("/projects/{project_id}/matrix", server.ClearMatrix).Methods("DELETE")
 
 	// Job endpoints
 	router.HandleFunc("/projects/{project_id}/jobs", server.CreateJob).Methods("POST")
@@ -110,12 +187,35 @@ func (server *Server) handleRoutes(router *mux.Router) {
 	router.HandleFunc("/vehicles/{vehicle_id}", server.DeleteVehicle).Methods("DELETE")
 	router.HandleFunc("/vehicles/{vehicle_id}/schedule", server.GetVehicleSchedule).Methods("GET")
 
+	// Vehicle type endpoints
+	router.HandleFunc("/projects/{project_id}/vehicle_types", server.CreateVehicleType).Methods("POST")
+	router.HandleFunc("/projects/{project_id}/vehicle_types", server.ListVehicleTypes).Methods("GET")
+	router.HandleFunc("/vehicle_types/{vehicle_type_id}", server.GetVehicleType).Methods("GET")
+	router.HandleFunc("/vehicle_types/{vehicle_type_id}", server.UpdateVehicleType).Methods("PATCH")
+	router.HandleFunc("/vehicle_types/{vehicle_type_id}", server.DeleteVehicleType).Methods("DELETE")
+	router.HandleFunc("/vehicle_types/{vehicle_type_id}/vehicles", server.CreateVehiclesOfType).Methods("POST")
+
 	// Vehicle breaks endpoints
 	router.HandleFunc("/vehicles/{vehicle_id}/breaks", server.CreateBreak).Methods("POST")
 	router.HandleFunc("/vehicles/{vehicle_id}/breaks", server.ListBreaks).Methods("GET")
 	router.HandleFunc("/breaks/{break_id}", server.GetBreak).Methods("GET")
 	router.HandleFunc("/breaks/{break_id}", server.UpdateBreak).Methods("PATCH")
 	router.HandleFunc("/breaks/{break_id}", server.DeleteBreak).Methods("DELETE")
+
+	// Vehicle shifts endpoints
+	router.HandleFunc("/vehicles/{vehicle_id}/shifts", server.CreateVehicleShift).Methods("POST")
+	router.HandleFunc("/vehicles/{vehicle_id}/shifts", server.ListVehicleShifts).Methods("GET")
+	router.HandleFunc("/shifts/{shift_id}", server.GetVehicleShift).Methods("GET")
+	router.HandleFunc("/shifts/{shift_id}", server.UpdateVehicleShift).Methods("PATCH")
+	router.HandleFunc("/shifts/{shift_id}", server.DeleteVehicleShift).Methods("DELETE")
+
+	// Webhook endpoints
+	router.HandleFunc("/projects/{project_id}/webhooks", server.CreateWebhook).Methods("POST")
+	router.HandleFunc("/projects/{project_id}/webhooks", server.ListWebhooks).Methods("GET")
+	router.HandleFunc("/webhooks/{webhook_id}", server.GetWebhook).Methods("GET")
+	router.HandleFunc("/webhooks/{webhook_id}", server.UpdateWebhook).Methods("PATCH")
+	router.HandleFunc("/webhooks/{webhook_id}", server.DeleteWebhook).Methods("DELETE")
+	router.HandleFunc("/webhooks/{webhook_id}/deliveries", server.ListWebhookDeliveries).Methods("GET")
 }
 
 func serveSwagger(router *mux.Router) {
@@ -30,6 +30,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -42,6 +43,8 @@ import (
 // CreateShipments godoc
 // @Summary Create a new shipment
 // @Description Create a new shipment with the input payload
+// @Description
+// @Description The "skills" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.
 // @Tags Shipment
 // @Accept application/json
 // @Produce application/json
@@ -154,6 +157,8 @@ func (server *Server) GetShipment(w http.ResponseWriter, r *http.Request) {
 // UpdateShipment godoc
 // @Summary Update a shipment
 // @Description Update a shipment with its shipment_id
+// @Description
+// @Description The "skills" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.
 // @Tags Shipment
 // @Accept application/json
 // @Produce application/json
@@ -242,9 +247,13 @@ func (server *Server) DeleteShipment(w http.ResponseWriter, r *http.Request) {
 // GetShipmentSchedule godoc
 // @Summary Get the schedule for a shipment
 // @Description Get the schedule for a shipment using shipment_id
+// @Description
+// @Description **For GeoJSON content type**: A FeatureCollection is returned, with a Point feature for each step of the shipment, or with "unassigned" = true if the shipment is unassigned.
+// @Description
+// @Description **For CSV and XLSX content types**: A row is returned for the pickup and the delivery of the shipment, with the flattened task_data keys, in the Schedule section (CSV) or sheet (XLSX), or in the Unassigned one if the shipment is unassigned.
 // @Tags Shipment
 // @Accept application/json
-// @Produce text/calendar,application/json
+// @Produce text/calendar,application/json,application/geo+json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param shipment_id path int true "Shipment ID"
 // @Success 200 {object} util.SuccessResponse{data=[]util.ScheduleDataTask}
 // @Failure 400 {object} util.ErrorResponse
@@ -274,6 +283,14 @@ func (server *Server) GetShipmentSchedule(w http.ResponseWriter, r *http.Request
 			Schedule:  schedule.Schedule,
 			ProjectID: schedule.ProjectID,
 		})
+	case "application/geo+json":
+		server.FormatGeoJSON(w, http.StatusOK, server.GetScheduleGeoJSON(schedule, false))
+	case "text/csv":
+		filename := fmt.Sprintf("schedule-%d.csv", schedule.ProjectID)
+		server.FormatCSV(w, http.StatusOK, server.GetScheduleTables(schedule), filename)
+	case util.XLSXContentType:
+		filename := fmt.Sprintf("schedule-%d.xlsx", schedule.ProjectID)
+		server.FormatXLSX(w, http.StatusOK, server.GetScheduleTables(schedule), filename)
 	default:
 		calendar, filename := server.GetScheduleICal(schedule)
 		server.FormatICAL(w, http.StatusOK, calendar, filename)
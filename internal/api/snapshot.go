@@ -0,0 +1,226 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: snapshot.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/database"
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-multierror"
+)
+
+// ExportProject godoc
+// @Summary Export a project
+// @Description Export a project along with its jobs, shipments, vehicles and breaks as a single JSON document, which can be imported again with the POST /projects/import endpoint.
+// @Description
+// @Description When schedule = true, the schedule of the project is also exported. Default value is false.
+// @Tags Project
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Param schedule query bool false "Export the schedule"
+// @Success 200 {object} util.SuccessResponse{data=database.ProjectSnapshot}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/export [get]
+func (server *Server) ExportProject(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	withSchedule := r.URL.Query().Get("schedule") == "true"
+
+	ctx := r.Context()
+	snapshot, err := server.DBExportProject(ctx, projectID, withSchedule)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, snapshot)
+}
+
+// CloneProject godoc
+// @Summary Clone a project
+// @Description Copy a project along with its jobs, shipments, vehicles and breaks into a new project, in a single transaction. The deleted tasks and vehicles are not copied.
+// @Description
+// @Description When schedule = true, the schedule of the project is also copied, referring to the new tasks and vehicles. Default value is false.
+// @Description
+// @Description The response contains the new project, and the mapping of the old IDs to the new IDs of the project, jobs, shipments, vehicles and breaks.
+// @Tags Project
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Param schedule query bool false "Copy the schedule"
+// @Success 201 {object} util.SuccessResponse{data=database.ClonedProject}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/clone [post]
+func (server *Server) CloneProject(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	projectID, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	withSchedule := r.URL.Query().Get("schedule") == "true"
+
+	ctx := r.Context()
+	clonedProject, err := server.DBCloneProject(ctx, projectID, withSchedule)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusCreated, clonedProject)
+}
+
+// ImportProjectSnapshot godoc
+// @Summary Import a project
+// @Description Create a new project from a snapshot exported with the GET /projects/{project_id}/export endpoint, in a single transaction.
+// @Description
+// @Description The project, jobs, shipments, vehicle types, vehicles and breaks are created with new IDs, and the references between them (the vehicle of a break, the vehicle type of a vehicle, and the vehicles and tasks of the schedule) are updated to the new IDs. The response of the export endpoint can be given as it is, or only its "data" field.
+// @Tags Project
+// @Accept application/json
+// @Produce application/json
+// @Param Snapshot body database.ProjectSnapshot true "Project snapshot"
+// @Success 201 {object} util.SuccessResponse{data=database.Project}
+// @Failure 400 {object} util.ErrorResponse
+// @Router /projects/import [post]
+func (server *Server) ImportProjectSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := readProjectSnapshot(r)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := server.validateProjectSnapshot(snapshot); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	project, err := server.DBImportProject(ctx, snapshot)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusCreated, project)
+}
+
+// readProjectSnapshot reads the snapshot from the request body, which can be the response of the export endpoint
+func readProjectSnapshot(r *http.Request) (database.ProjectSnapshot, error) {
+	errInvalid := fmt.Errorf("Request body must be a project snapshot")
+	if r.Body == nil {
+		return database.ProjectSnapshot{}, errInvalid
+	}
+
+	body := map[string]json.RawMessage{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return database.ProjectSnapshot{}, errInvalid
+	}
+
+	// Unwrap the response of the export endpoint
+	if data, found := body["data"]; found {
+		body = map[string]json.RawMessage{}
+		if err := json.Unmarshal(data, &body); err != nil {
+			return database.ProjectSnapshot{}, errInvalid
+		}
+	}
+
+	bodyString, err := json.Marshal(body)
+	if err != nil {
+		return database.ProjectSnapshot{}, errInvalid
+	}
+	snapshot := database.ProjectSnapshot{}
+	if err := json.Unmarshal(bodyString, &snapshot); err != nil {
+		return database.ProjectSnapshot{}, errInvalid
+	}
+	return snapshot, nil
+}
+
+// validateProjectSnapshot validates the snapshot, and returns the errors of all the rows along with their position
+func (server *Server) validateProjectSnapshot(snapshot database.ProjectSnapshot) error {
+	if snapshot.Version != database.SnapshotVersion {
+		return fmt.Errorf("Field 'version' must be %d", database.SnapshotVersion)
+	}
+
+	var errs error
+	appendError := func(name string, err error) {
+		for _, msg := range util.ErrorMessages(err) {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %s", name, msg))
+		}
+	}
+
+	if err := server.validate.Struct(snapshot.GetCreateParams()); err != nil {
+		appendError("project", err)
+	}
+
+	// The project does not exist yet, so the params are validated with a placeholder project id
+	params, err := snapshot.GetImportParams(0)
+	if err != nil {
+		return err
+	}
+	for i, job := range params.Jobs {
+		if err := server.validate.Struct(job); err != nil {
+			appendError(fmt.Sprintf("jobs[%d]", i), err)
+		}
+	}
+	for i, shipment := range params.Shipments {
+		if err := server.validate.Struct(shipment); err != nil {
+			appendError(fmt.Sprintf("shipments[%d]", i), err)
+		}
+	}
+	for i, vehicleType := range snapshot.GetVehicleTypeParams(0) {
+		if err := server.validate.Struct(vehicleType); err != nil {
+			appendError(fmt.Sprintf("vehicle_types[%d]", i), err)
+		}
+	}
+	for i, vehicle := range params.Vehicles {
+		if err := server.validate.Struct(vehicle); err != nil {
+			appendError(fmt.Sprintf("vehicles[%d]", i), err)
+		}
+	}
+	for i, vBreak := range params.Breaks {
+		if err := server.validate.Struct(vBreak); err != nil {
+			appendError(fmt.Sprintf("breaks[%d]", i), err)
+		}
+	}
+	return errs
+}
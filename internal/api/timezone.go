@@ -0,0 +1,149 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: timezone.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// timezoneVars are the path variables identifying the row from which the project of a request is found, along with
+// the table of the row, in the order of precedence
+var timezoneVars = []struct {
+	name      string
+	tableName string
+}{
+	{"project_id", "projects"},
+	{"job_id", "jobs"},
+	{"shipment_id", "shipments"},
+	{"vehicle_id", "vehicles"},
+	{"vehicle_type_id", "vehicle_types"},
+	{"break_id", "breaks"},
+	{"shift_id", "vehicle_shifts"},
+	{"webhook_id", "webhooks"},
+}
+
+// TimezoneMiddleware finds the time zone of the project of the request, in which the RFC 3339 timestamps of the JSON
+// request body are converted to local times, and the timestamps of the response are returned with their offset. The
+// time zone is only looked up when the request body or the response has timestamps.
+func (server *Server) TimezoneMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lw := util.NewLocationRespWr(w, func() *time.Location {
+			return server.getRequestLocation(r)
+		})
+		if err := normalizeRequestBody(r, lw.Location); err != nil {
+			logrus.Error(err)
+		}
+		next.ServeHTTP(lw, r)
+	})
+}
+
+// getRequestLocation returns the location of the time zone of the project of the request, or nil when it is not set
+// or the project is not found
+func (server *Server) getRequestLocation(r *http.Request) *time.Location {
+	vars := mux.Vars(r)
+	for _, v := range timezoneVars {
+		value, ok := vars[v.name]
+		if !ok {
+			continue
+		}
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil
+		}
+		timezone, err := server.DBGetTimezone(r.Context(), v.tableName, id)
+		if err != nil {
+			return nil
+		}
+		return util.LoadLocation(timezone)
+	}
+	return nil
+}
+
+// normalizeRequestBody converts the RFC 3339 timestamps of a JSON request body to the local times of the location.
+// The snapshots of a project are imported in the time zone of their project.
+func normalizeRequestBody(r *http.Request, getLocation func() *time.Location) error {
+	if r.Body == nil || r.Method == http.MethodGet {
+		return nil
+	}
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType != "" && contentType != "application/json" {
+		return nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		// invalid bodies are left unchanged, to be reported by the handlers
+		return nil
+	}
+	var loc *time.Location
+	var once sync.Once
+	location := func() *time.Location {
+		once.Do(func() {
+			loc = getLocation()
+			if snapshot, ok := data.(map[string]interface{}); ok && loc == nil {
+				if project, ok := snapshot["project"].(map[string]interface{}); ok {
+					if timezone, ok := project["timezone"].(string); ok {
+						loc = util.LoadLocation(&timezone)
+					}
+				}
+			}
+		})
+		return loc
+	}
+	if !util.NormalizeTimestampsWith(data, location) {
+		return nil
+	}
+
+	normalized, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(normalized))
+	r.ContentLength = int64(len(normalized))
+	return nil
+}
@@ -30,6 +30,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -42,6 +43,23 @@ import (
 // CreateVehicles godoc
 // @Summary Create a new vehicle
 // @Description Create a new vehicle with the input payload
+// @Description
+// @Description The costs of the vehicle are reported in the schedule summary. The solver is only given the "vehicle_fixed_cost" and "vehicle_cost_per_hour" of the project, as vrp_vroom does not take the costs of each vehicle:
+// @Description - "fixed_cost": cost of using the vehicle. Defaults to the "vehicle_fixed_cost" of the project.
+// @Description - "cost_per_hour": cost of an hour of the route of the vehicle. Defaults to the "vehicle_cost_per_hour" of the project.
+// @Description - "cost_per_km": cost of a kilometer of the route of the vehicle. Default value is 0.
+// @Description
+// @Description The limits of the route of the vehicle are enforced after scheduling, by unassigning the last tasks of a route exceeding them:
+// @Description - "max_travel_time": max travel time of the route, in the HH:MM:SS format.
+// @Description - "max_distance": max distance of the route, in meters.
+// @Description The limits are not set by default, and a zero value removes a limit.
+// @Description
+// @Description When "vehicle_type_id" is given, the fields of the vehicle type are used for the fields which are not given, and the breaks of the vehicle type are created for the vehicle.
+// @Description
+// @Description When "shift_recurrence" is given as a recurrence rule, the time window of the vehicle is its first shift, and a shift at the same time of the day is created on each date of the recurrence within the planning horizon of the project when it is scheduled. The shifts and the days off of the vehicle are edited with the /vehicles/{vehicle_id}/shifts endpoints.
+// @Description The vehicle can then serve tasks from the start of its first shift to the end of its last shift, and an off-shift break (with "off_shift" = true) is created between two consecutive shifts.
+// @Description
+// @Description The "skills" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.
 // @Tags Vehicle
 // @Accept application/json
 // @Produce application/json
@@ -154,6 +172,8 @@ func (server *Server) GetVehicle(w http.ResponseWriter, r *http.Request) {
 // UpdateVehicle godoc
 // @Summary Update a vehicle
 // @Description Update a vehicle with its vehicle_id
+// @Description
+// @Description The "skills" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.
 // @Tags Vehicle
 // @Accept application/json
 // @Produce application/json
@@ -244,11 +264,19 @@ func (server *Server) DeleteVehicle(w http.ResponseWriter, r *http.Request) {
 // @Description Get the schedule for a vehicle using vehicle_id
 // @Description
 // @Description **For JSON content type**: When overview = true, only the metadata is returned. Default value is false, which also returns the summary object.
+// @Description
+// @Description When geometry = true, the route geometry of each vehicle is returned, as a GeoJSON LineString (geometry_format = geojson, default) or an encoded polyline with precision 5 (geometry_format = polyline). Straight segments between the locations are returned when the duration calculation method can not compute the road path.
+// @Description
+// @Description **For GeoJSON content type**: A FeatureCollection is returned, with a Point feature for each step of the routes, a LineString feature for each vehicle route (following the road path when geometry = true), and a Point feature with "unassigned" = true for each unassigned task.
+// @Description
+// @Description **For CSV and XLSX content types**: A row is returned for each step of the route of the vehicle, except the off-shift breaks, with the flattened task_data keys, followed by the summary of the vehicle in a separate section (CSV) or sheet (XLSX).
 // @Tags Vehicle
 // @Accept application/json
-// @Produce text/calendar,application/json
+// @Produce text/calendar,application/json,application/geo+json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param vehicle_id path int true "Vehicle ID"
 // @Param overview query bool false "Overview"
+// @Param geometry query bool false "Geometry"
+// @Param geometry_format query string false "Geometry format (geojson or polyline)"
 // @Success 200 {object} util.SuccessResponse{data=[]util.ScheduleDB}
 // @Failure 400 {object} util.ErrorResponse
 // @Failure 404 {object} util.NotFound
@@ -280,8 +308,24 @@ func (server *Server) GetVehicleSchedule(w http.ResponseWriter, r *http.Request)
 				ProjectID: schedule.ProjectID,
 			})
 		} else {
+			if err := server.addScheduleGeometry(r, &schedule, r.URL.Query().Get("geometry_format")); err != nil {
+				server.FormatJSON(w, http.StatusBadRequest, err)
+				return
+			}
 			server.FormatJSON(w, http.StatusOK, schedule)
 		}
+	case "application/geo+json":
+		if err := server.addScheduleGeometry(r, &schedule, "geojson"); err != nil {
+			server.FormatJSON(w, http.StatusBadRequest, err)
+			return
+		}
+		server.FormatGeoJSON(w, http.StatusOK, server.GetScheduleGeoJSON(schedule, true))
+	case "text/csv":
+		filename := fmt.Sprintf("schedule-%d.csv", schedule.ProjectID)
+		server.FormatCSV(w, http.StatusOK, server.GetScheduleTables(schedule), filename)
+	case util.XLSXContentType:
+		filename := fmt.Sprintf("schedule-%d.xlsx", schedule.ProjectID)
+		server.FormatXLSX(w, http.StatusOK, server.GetScheduleTables(schedule), filename)
 	default:
 		calendar, filename := server.GetScheduleICal(schedule)
 		server.FormatICAL(w, http.StatusOK, calendar, filename)
@@ -0,0 +1,324 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: vehicle_shift.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/database"
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateVehicleShift godoc
+// @Summary Create vehicle shifts
+// @Description Create a shift or a day off in the availability calendar of a vehicle with the input payload (Content-Type = application/json).
+// @Description
+// @Description When the vehicle has shifts, it is available from the start of its first shift to the end of its last shift when the project is scheduled, and an off-shift break is created between two consecutive shifts. A day off (with "day_off" = true) removes the shifts which it overlaps, such as the recurring shifts of the vehicle on a holiday.
+// @Description
+// @Description The shifts are imported from an iCalendar file with Content-Type = text/calendar, returning all the shifts of the vehicle. The all-day events and the events with the "DAY OFF" category are days off, and the other events are shifts. The recurring events are expanded within the planning horizon of the project, or during a year without one. When replace = true, the shifts of the vehicle which are not recurring are replaced.
+// @Tags Vehicle Shift
+// @Accept application/json,text/calendar
+// @Produce application/json
+// @Param vehicle_id path int true "Vehicle ID"
+// @Param replace query bool false "Replace the shifts (iCalendar import)"
+// @Param VehicleShift body database.CreateVehicleShiftParams true "Create vehicle shift"
+// @Success 201 {object} util.SuccessResponse{data=database.VehicleShift}
+// @Failure 400 {object} util.ErrorResponse
+// @Router /vehicles/{vehicle_id}/shifts [post]
+func (server *Server) CreateVehicleShift(w http.ResponseWriter, r *http.Request) {
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType == "text/calendar" {
+		server.importVehicleShifts(w, r)
+		return
+	}
+
+	userInput := make(map[string]interface{})
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&userInput); err != nil {
+			logrus.Error(err)
+		}
+	}
+
+	// Add the vehicle_id path variable
+	vars := mux.Vars(r)
+	userInput["vehicle_id"] = vars["vehicle_id"]
+
+	// Validate the input type
+	if err := util.ValidateInput(userInput, database.CreateVehicleShiftParams{}); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Decode map[string]interface{} to struct
+	userInputString, err := json.Marshal(userInput)
+	if err != nil {
+		logrus.Error(err)
+	}
+	shift := database.CreateVehicleShiftParams{}
+	if err = json.Unmarshal(userInputString, &shift); err != nil {
+		logrus.Error(err)
+	}
+
+	// Validate the struct
+	if err := server.validate.Struct(shift); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	created_shift, err := server.DBCreateVehicleShift(ctx, shift)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusCreated, created_shift)
+}
+
+// importVehicleShifts creates the shifts of a vehicle from the iCalendar file in the request body
+func (server *Server) importVehicleShifts(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	vehicle_id, err := strconv.ParseInt(vars["vehicle_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	vehicle, err := server.DBGetVehicle(ctx, vehicle_id)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+	project, err := server.DBGetProject(ctx, vehicle.ProjectID)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Expand the recurring events within the planning horizon of the project
+	var to time.Time
+	if project.HorizonEnd != nil {
+		if to, err = time.Parse(util.DateLayout, *project.HorizonEnd); err != nil {
+			server.FormatJSON(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if r.Body == nil {
+		server.FormatJSON(w, http.StatusBadRequest, fmt.Errorf("Request body must be an iCalendar file"))
+		return
+	}
+	shifts, err := util.ReadShiftsICal(r.Body, to, util.GetResponseLocation(w))
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	replace := r.URL.Query().Get("replace") == "true"
+	imported_shifts, err := server.DBImportVehicleShifts(ctx, vehicle_id, shifts, replace)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusCreated, imported_shifts)
+}
+
+// ListVehicleShifts godoc
+// @Summary List vehicle shifts
+// @Description Get the shifts and the days off of a vehicle, ordered by their start.
+// @Description
+// @Description **For iCalendar content type**: An event is returned for each shift and day off, with the "SHIFT" or "DAY OFF" category, along with the "RECURRING" category for the shifts created from the shift recurrence of the vehicle.
+// @Tags Vehicle Shift
+// @Accept application/json
+// @Produce application/json,text/calendar
+// @Param vehicle_id path int true "Vehicle ID"
+// @Success 200 {object} util.SuccessResponse{data=[]database.VehicleShift}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /vehicles/{vehicle_id}/shifts [get]
+func (server *Server) ListVehicleShifts(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	vehicle_id, err := strconv.ParseInt(vars["vehicle_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	shifts, err := server.DBListVehicleShifts(ctx, vehicle_id)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	switch r.Header.Get("Accept") {
+	case "text/calendar":
+		calendar := []util.CalendarShift{}
+		for _, shift := range shifts {
+			calendar = append(calendar, util.CalendarShift{
+				ID:        shift.ID,
+				VehicleID: shift.VehicleID,
+				TwOpen:    shift.TwOpen,
+				TwClose:   shift.TwClose,
+				DayOff:    shift.DayOff,
+				Recurring: shift.Recurring,
+				CreatedAt: shift.CreatedAt,
+				UpdatedAt: shift.UpdatedAt,
+			})
+		}
+		filename := fmt.Sprintf("shifts-%d.ics", vehicle_id)
+		server.FormatShiftsICAL(w, http.StatusOK, calendar, filename)
+	default:
+		server.FormatJSON(w, http.StatusOK, shifts)
+	}
+}
+
+// GetVehicleShift godoc
+// @Summary Fetch a vehicle shift
+// @Description Fetch a vehicle shift with its shift_id
+// @Tags Vehicle Shift
+// @Accept application/json
+// @Produce application/json
+// @Param shift_id path int true "Shift ID"
+// @Success 200 {object} util.SuccessResponse{data=database.VehicleShift}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /shifts/{shift_id} [get]
+func (server *Server) GetVehicleShift(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	shift_id, err := strconv.ParseInt(vars["shift_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	shift, err := server.DBGetVehicleShift(ctx, shift_id)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, shift)
+}
+
+// UpdateVehicleShift godoc
+// @Summary Update a vehicle shift
+// @Description Update a vehicle shift (partial update) with its shift_id. The recurring shifts can not be updated.
+// @Tags Vehicle Shift
+// @Accept application/json
+// @Produce application/json
+// @Param shift_id path int true "Shift ID"
+// @Param VehicleShift body database.UpdateVehicleShiftParams true "Update vehicle shift"
+// @Success 200 {object} util.SuccessResponse{data=database.VehicleShift}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /shifts/{shift_id} [patch]
+func (server *Server) UpdateVehicleShift(w http.ResponseWriter, r *http.Request) {
+	userInput := make(map[string]interface{})
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&userInput); err != nil {
+			logrus.Error(err)
+		}
+	}
+
+	vars := mux.Vars(r)
+	shift_id, err := strconv.ParseInt(vars["shift_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Validate the input type
+	if err := util.ValidateInput(userInput, database.UpdateVehicleShiftParams{}); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Decode map[string]interface{} to struct
+	userInputString, err := json.Marshal(userInput)
+	if err != nil {
+		logrus.Error(err)
+	}
+	shift := database.UpdateVehicleShiftParams{}
+	if err = json.Unmarshal(userInputString, &shift); err != nil {
+		logrus.Error(err)
+	}
+
+	// Validate the struct
+	if err := server.validate.Struct(shift); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	updated_shift, err := server.DBUpdateVehicleShift(ctx, shift, shift_id)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, updated_shift)
+}
+
+// DeleteVehicleShift godoc
+// @Summary Delete a vehicle shift
+// @Description Delete a vehicle shift with its shift_id. The recurring shifts can not be deleted.
+// @Tags Vehicle Shift
+// @Accept application/json
+// @Produce application/json
+// @Param shift_id path int true "Shift ID"
+// @Success 200 {object} util.Success
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /shifts/{shift_id} [delete]
+func (server *Server) DeleteVehicleShift(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	shift_id, err := strconv.ParseInt(vars["shift_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	if err := server.DBDeleteVehicleShift(ctx, shift_id); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, nil)
+}
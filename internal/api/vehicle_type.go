@@ -0,0 +1,309 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: vehicle_type.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/database"
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateVehicleType godoc
+// @Summary Create a new vehicle type
+// @Description Create a new vehicle type with the input payload
+// @Description
+// @Description A vehicle type is a template of the vehicles of a project. A vehicle created with a "vehicle_type_id" gets the fields of the type which are not given in its payload, and a break is created for the vehicle with each of the "breaks" of the type.
+// @Description Changing a vehicle type does not change the vehicles already created with the type.
+// @Description
+// @Description The "skills" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.
+// @Tags Vehicle Type
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Param VehicleType body database.CreateVehicleTypeParams true "Create vehicle type"
+// @Success 201 {object} util.SuccessResponse{data=database.VehicleType}
+// @Failure 400 {object} util.ErrorResponse
+// @Router /projects/{project_id}/vehicle_types [post]
+func (server *Server) CreateVehicleType(w http.ResponseWriter, r *http.Request) {
+	userInput := make(map[string]interface{})
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&userInput); err != nil {
+			logrus.Error(err)
+		}
+	}
+
+	// Add the project_id path variable
+	vars := mux.Vars(r)
+	userInput["project_id"] = vars["project_id"]
+
+	// Validate the input type
+	if err := util.ValidateInput(userInput, database.CreateVehicleTypeParams{}); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Decode map[string]interface{} to struct
+	userInputString, err := json.Marshal(userInput)
+	if err != nil {
+		logrus.Error(err)
+	}
+	vehicleType := database.CreateVehicleTypeParams{}
+	if err = json.Unmarshal(userInputString, &vehicleType); err != nil {
+		logrus.Error(err)
+	}
+
+	// Validate the struct
+	if err := server.validate.Struct(vehicleType); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	created_vehicle_type, err := server.DBCreateVehicleType(ctx, vehicleType)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusCreated, created_vehicle_type)
+}
+
+// ListVehicleTypes godoc
+// @Summary List vehicle types for a project
+// @Description Get a list of vehicle types for a project with project_id
+// @Tags Vehicle Type
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Success 200 {object} util.SuccessResponse{data=[]database.VehicleType}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/vehicle_types [get]
+func (server *Server) ListVehicleTypes(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	project_id, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	vehicle_types, err := server.DBListVehicleTypes(ctx, project_id)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, vehicle_types)
+}
+
+// GetVehicleType godoc
+// @Summary Fetch a vehicle type
+// @Description Fetch a vehicle type with its vehicle_type_id
+// @Tags Vehicle Type
+// @Accept application/json
+// @Produce application/json
+// @Param vehicle_type_id path int true "Vehicle Type ID"
+// @Success 200 {object} util.SuccessResponse{data=database.VehicleType}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /vehicle_types/{vehicle_type_id} [get]
+func (server *Server) GetVehicleType(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	vehicle_type_id, err := strconv.ParseInt(vars["vehicle_type_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	vehicle_type, err := server.DBGetVehicleType(ctx, vehicle_type_id)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, vehicle_type)
+}
+
+// UpdateVehicleType godoc
+// @Summary Update a vehicle type
+// @Description Update a vehicle type with its vehicle_type_id. The "breaks" are replaced when they are given.
+// @Description
+// @Description The "skills" are integers from 0 to 1073741823, the greater skills being reserved to pin the tasks to a vehicle.
+// @Tags Vehicle Type
+// @Accept application/json
+// @Produce application/json
+// @Param vehicle_type_id path int true "Vehicle Type ID"
+// @Param VehicleType body database.UpdateVehicleTypeParams true "Update vehicle type"
+// @Success 200 {object} util.SuccessResponse{data=database.VehicleType}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /vehicle_types/{vehicle_type_id} [patch]
+func (server *Server) UpdateVehicleType(w http.ResponseWriter, r *http.Request) {
+	userInput := make(map[string]interface{})
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&userInput); err != nil {
+			logrus.Error(err)
+		}
+	}
+
+	vars := mux.Vars(r)
+	vehicle_type_id, err := strconv.ParseInt(vars["vehicle_type_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Validate the input type
+	if err := util.ValidateInput(userInput, database.UpdateVehicleTypeParams{}); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Decode map[string]interface{} to struct
+	userInputString, err := json.Marshal(userInput)
+	if err != nil {
+		logrus.Error(err)
+	}
+	vehicleType := database.UpdateVehicleTypeParams{}
+	if err = json.Unmarshal(userInputString, &vehicleType); err != nil {
+		logrus.Error(err)
+	}
+
+	// Validate the struct
+	if err := server.validate.Struct(vehicleType); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	updated_vehicle_type, err := server.DBUpdateVehicleType(ctx, vehicleType, vehicle_type_id)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, updated_vehicle_type)
+}
+
+// DeleteVehicleType godoc
+// @Summary Delete a vehicle type
+// @Description Delete a vehicle type with its vehicle_type_id. The vehicles created with the type are not deleted.
+// @Tags Vehicle Type
+// @Accept application/json
+// @Produce application/json
+// @Param vehicle_type_id path int true "Vehicle Type ID"
+// @Success 200 {object} util.Success
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /vehicle_types/{vehicle_type_id} [delete]
+func (server *Server) DeleteVehicleType(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	vehicle_type_id, err := strconv.ParseInt(vars["vehicle_type_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	_, err = server.DBDeleteVehicleType(ctx, vehicle_type_id)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, nil)
+}
+
+// CreateVehiclesOfType godoc
+// @Summary Create vehicles of a vehicle type
+// @Description Create "count" vehicles of a vehicle type at the same depot, in a single transaction.
+// @Description
+// @Description The vehicles get the fields of the vehicle type, and a break is created for each vehicle with each of the "breaks" of the type. The "end_location" defaults to the "start_location".
+// @Tags Vehicle Type
+// @Accept application/json
+// @Produce application/json
+// @Param vehicle_type_id path int true "Vehicle Type ID"
+// @Param Vehicles body database.CreateVehiclesOfTypeParams true "Create vehicles of the type"
+// @Success 201 {object} util.SuccessResponse{data=[]database.Vehicle}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /vehicle_types/{vehicle_type_id}/vehicles [post]
+func (server *Server) CreateVehiclesOfType(w http.ResponseWriter, r *http.Request) {
+	userInput := make(map[string]interface{})
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&userInput); err != nil {
+			logrus.Error(err)
+		}
+	}
+
+	vars := mux.Vars(r)
+	vehicle_type_id, err := strconv.ParseInt(vars["vehicle_type_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Validate the input type
+	if err := util.ValidateInput(userInput, database.CreateVehiclesOfTypeParams{}); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Decode map[string]interface{} to struct
+	userInputString, err := json.Marshal(userInput)
+	if err != nil {
+		logrus.Error(err)
+	}
+	vehicles := database.CreateVehiclesOfTypeParams{}
+	if err = json.Unmarshal(userInputString, &vehicles); err != nil {
+		logrus.Error(err)
+	}
+
+	// Validate the struct
+	if err := server.validate.Struct(vehicles); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	created_vehicles, err := server.DBCreateVehiclesOfType(ctx, vehicle_type_id, vehicles)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusCreated, created_vehicles)
+}
@@ -0,0 +1,405 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: webhook.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/database"
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// Settings of the webhook dispatcher, which are variables so that they can be lowered in the tests
+var (
+	// Interval between two polls of the pending webhook deliveries
+	WebhookPollInterval = time.Second
+
+	// Delay before the first retry of a failed delivery, doubled after each attempt
+	WebhookRetryDelay = 30 * time.Second
+
+	// Number of attempts after which a delivery is marked as failed
+	WebhookMaxAttempts = 5
+
+	// Timeout of the request of a delivery
+	WebhookTimeout = 10 * time.Second
+
+	// Duration for which the succeeded and failed deliveries are kept in the delivery log
+	WebhookRetention = 7 * 24 * time.Hour
+
+	// Interval between two deletions of the deliveries older than the retention
+	WebhookPruneInterval = time.Hour
+)
+
+// Number of deliveries claimed at each poll, which are sent concurrently
+const webhookBatchSize = 20
+
+// CreateWebhook godoc
+// @Summary Create a webhook
+// @Description Subscribe to the events of a project with the input payload. The events are delivered with a POST request to the URL of the webhook, with a JSON body containing the "id", "event", "project_id", "created_at" and "data" of the event.
+// @Description
+// @Description The events are "schedule.created" (when the project is scheduled, its schedule is edited or a version is restored), "schedule.deleted", "job.status_changed" and "shipment.status_changed" (when the status of a task is different after a schedule is created or deleted), "task.created", "task.updated" (when the fields of a task are changed) and "task.deleted". A webhook without events is subscribed to all the events.
+// @Description
+// @Description The request contains the "X-Scheduleserv-Event" and "X-Scheduleserv-Delivery" headers, along with the "X-Scheduleserv-Signature" header which is "sha256=" followed by the hex encoded HMAC-SHA256 of the body with the secret of the webhook. A delivery succeeds when the response has a 2xx status code, and is otherwise retried with an exponential backoff.
+// @Description
+// @Description The URL of the webhook is an http or https URL. The URLs of the local host, and the hosts resolving to a loopback, link-local or private (RFC 1918) address, are rejected unless WEBHOOK_ALLOW_PRIVATE is set to true.
+// @Tags Webhook
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Param Webhook body database.CreateWebhookParams true "Create webhook"
+// @Success 201 {object} util.SuccessResponse{data=database.Webhook}
+// @Failure 400 {object} util.ErrorResponse
+// @Router /projects/{project_id}/webhooks [post]
+func (server *Server) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userInput := make(map[string]interface{})
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&userInput); err != nil {
+			logrus.Error(err)
+		}
+	}
+
+	// Add the project_id path variable
+	vars := mux.Vars(r)
+	userInput["project_id"] = vars["project_id"]
+
+	// Validate the input type
+	if err := util.ValidateInput(userInput, database.CreateWebhookParams{}); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Decode map[string]interface{} to struct
+	userInputString, err := json.Marshal(userInput)
+	if err != nil {
+		logrus.Error(err)
+	}
+	webhook := database.CreateWebhookParams{}
+	if err = json.Unmarshal(userInputString, &webhook); err != nil {
+		logrus.Error(err)
+	}
+
+	// Validate the struct
+	if err := server.validate.Struct(webhook); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	created_webhook, err := server.DBCreateWebhook(ctx, webhook)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusCreated, created_webhook)
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description Get the webhooks of a project. The secrets of the webhooks are not returned.
+// @Tags Webhook
+// @Accept application/json
+// @Produce application/json
+// @Param project_id path int true "Project ID"
+// @Success 200 {object} util.SuccessResponse{data=[]database.Webhook}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /projects/{project_id}/webhooks [get]
+func (server *Server) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	project_id, err := strconv.ParseInt(vars["project_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	webhooks, err := server.DBListWebhooks(ctx, project_id)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, webhooks)
+}
+
+// GetWebhook godoc
+// @Summary Fetch a webhook
+// @Description Fetch a webhook with its webhook_id
+// @Tags Webhook
+// @Accept application/json
+// @Produce application/json
+// @Param webhook_id path int true "Webhook ID"
+// @Success 200 {object} util.SuccessResponse{data=database.Webhook}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /webhooks/{webhook_id} [get]
+func (server *Server) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	webhook_id, err := strconv.ParseInt(vars["webhook_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	webhook, err := server.DBGetWebhook(ctx, webhook_id)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, webhook)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook
+// @Description Update a webhook (partial update) with its webhook_id. The pending deliveries of an inactive webhook are not sent until it is active again.
+// @Tags Webhook
+// @Accept application/json
+// @Produce application/json
+// @Param webhook_id path int true "Webhook ID"
+// @Param Webhook body database.UpdateWebhookParams true "Update webhook"
+// @Success 200 {object} util.SuccessResponse{data=database.Webhook}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /webhooks/{webhook_id} [patch]
+func (server *Server) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	userInput := make(map[string]interface{})
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&userInput); err != nil {
+			logrus.Error(err)
+		}
+	}
+
+	vars := mux.Vars(r)
+	webhook_id, err := strconv.ParseInt(vars["webhook_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Validate the input type
+	if err := util.ValidateInput(userInput, database.UpdateWebhookParams{}); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Decode map[string]interface{} to struct
+	userInputString, err := json.Marshal(userInput)
+	if err != nil {
+		logrus.Error(err)
+	}
+	webhook := database.UpdateWebhookParams{}
+	if err = json.Unmarshal(userInputString, &webhook); err != nil {
+		logrus.Error(err)
+	}
+
+	// Validate the struct
+	if err := server.validate.Struct(webhook); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	updated_webhook, err := server.DBUpdateWebhook(ctx, webhook, webhook_id)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, updated_webhook)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook with its webhook_id, along with its deliveries
+// @Tags Webhook
+// @Accept application/json
+// @Produce application/json
+// @Param webhook_id path int true "Webhook ID"
+// @Success 200 {object} util.Success
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /webhooks/{webhook_id} [delete]
+func (server *Server) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	webhook_id, err := strconv.ParseInt(vars["webhook_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx := r.Context()
+	if err := server.DBDeleteWebhook(ctx, webhook_id); err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, nil)
+}
+
+// ListWebhookDeliveries godoc
+// @Summary List the deliveries of a webhook
+// @Description Get the delivery log of a webhook, latest first.
+// @Description
+// @Description The status of a delivery is one of "pending", "succeeded" or "failed". A pending delivery is attempted again at "next_attempt_at", and a delivery is marked as failed after 5 attempts. The response code and the error of the last attempt are returned. The succeeded and failed deliveries are deleted 7 days after their last attempt.
+// @Tags Webhook
+// @Accept application/json
+// @Produce application/json
+// @Param webhook_id path int true "Webhook ID"
+// @Param status query string false "Status of the deliveries" Enums(pending, succeeded, failed)
+// @Success 200 {object} util.SuccessResponse{data=[]database.WebhookDelivery}
+// @Failure 400 {object} util.ErrorResponse
+// @Failure 404 {object} util.NotFound
+// @Router /webhooks/{webhook_id}/deliveries [get]
+func (server *Server) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	webhook_id, err := strconv.ParseInt(vars["webhook_id"], 10, 64)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+	status := r.URL.Query().Get("status")
+	if status != "" && status != "pending" && status != "succeeded" && status != "failed" {
+		server.FormatJSON(w, http.StatusBadRequest, fmt.Errorf("Field 'status' must be one out of pending, succeeded, failed"))
+		return
+	}
+
+	ctx := r.Context()
+	deliveries, err := server.DBListWebhookDeliveries(ctx, webhook_id, status)
+	if err != nil {
+		server.FormatJSON(w, http.StatusBadRequest, err)
+		return
+	}
+
+	server.FormatJSON(w, http.StatusOK, deliveries)
+}
+
+// startWebhookDispatcher polls the pending webhook deliveries until the server is closed, and deletes the finished
+// deliveries older than the retention
+func (server *Server) startWebhookDispatcher() {
+	// The deliveries are not sent through a proxy, so that the dialer checks the address of the webhooks
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: WebhookTimeout, Control: util.WebhookDialControl}).DialContext
+	client := &http.Client{Timeout: WebhookTimeout, Transport: transport}
+	server.workers.Add(1)
+	go func() {
+		defer server.workers.Done()
+		var prunedAt time.Time
+		for {
+			select {
+			case <-server.ctx.Done():
+				return
+			case <-time.After(WebhookPollInterval):
+			}
+			if err := server.dispatchWebhookDeliveries(client); err != nil {
+				logrus.Error(err)
+			}
+			if time.Since(prunedAt) >= WebhookPruneInterval {
+				prunedAt = time.Now()
+				if _, err := server.DBPruneWebhookDeliveries(context.Background(), WebhookRetention); err != nil {
+					logrus.Error(err)
+				}
+			}
+		}
+	}()
+}
+
+// dispatchWebhookDeliveries sends the pending deliveries which are due. The deliveries are claimed for twice the
+// timeout of their request, after which they are sent again if the server stopped before recording the result.
+func (server *Server) dispatchWebhookDeliveries(client *http.Client) error {
+	ctx := context.Background()
+	deliveries, err := server.DBClaimWebhookDeliveries(ctx, webhookBatchSize, 2*WebhookTimeout)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery database.PendingWebhookDelivery) {
+			defer wg.Done()
+			responseCode, deliveryErr := sendWebhookDelivery(ctx, client, delivery)
+
+			// Retry the failed delivery with an exponential backoff, until the max attempts are reached
+			var retryDelay *time.Duration
+			if deliveryErr != nil && int(delivery.Attempts) < WebhookMaxAttempts {
+				delay := util.WebhookRetryDelay(WebhookRetryDelay, int(delivery.Attempts))
+				retryDelay = &delay
+			}
+			if err := server.DBFinishWebhookDelivery(ctx, delivery.ID, responseCode, deliveryErr, retryDelay); err != nil {
+				logrus.Error(err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+	return nil
+}
+
+// sendWebhookDelivery sends the signed payload of the delivery to the URL of its webhook, and returns the status code
+// of the response, if any, along with an error when the delivery did not succeed
+func sendWebhookDelivery(ctx context.Context, client *http.Client, delivery database.PendingWebhookDelivery) (*int32, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "pg_scheduleserv")
+	request.Header.Set("X-Scheduleserv-Event", delivery.Event)
+	request.Header.Set("X-Scheduleserv-Delivery", strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set("X-Scheduleserv-Signature", util.SignWebhookPayload(delivery.Secret, delivery.Payload))
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if _, err := io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16)); err != nil {
+		logrus.Error(err)
+	}
+
+	responseCode := int32(response.StatusCode)
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return &responseCode, fmt.Errorf("Unexpected response status code %d", response.StatusCode)
+	}
+	return &responseCode, nil
+}
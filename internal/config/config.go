@@ -39,6 +39,16 @@ type Config struct {
 	ServerPort       string `mapstructure:"SERVER_PORT"`
 	OsrmUrl          string `mapstructure:"OSRM_URL"`
 	ValhallaUrl      string `mapstructure:"VALHALLA_URL"`
+
+	GraphHopperUrl         string `mapstructure:"GRAPHHOPPER_URL"`
+	GraphHopperApiKey      string `mapstructure:"GRAPHHOPPER_API_KEY"`
+	OpenRouteServiceUrl    string `mapstructure:"ORS_URL"`
+	OpenRouteServiceApiKey string `mapstructure:"ORS_API_KEY"`
+	PgRoutingEdgesTable    string `mapstructure:"PGROUTING_EDGES_TABLE"`
+	PgRoutingVerticesTable string `mapstructure:"PGROUTING_VERTICES_TABLE"`
+	StaticMatrixFile       string `mapstructure:"STATIC_MATRIX_FILE"`
+
+	WebhookAllowPrivate bool `mapstructure:"WEBHOOK_ALLOW_PRIVATE"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
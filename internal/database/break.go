@@ -72,7 +72,8 @@ func (q *Queries) DBGetBreak(ctx context.Context, id int64) (Break, error) {
 	return scanBreakRow(row)
 }
 
-func (q *Queries) DBListBreaks(ctx context.Context, vehicleID int64) ([]Break, error) {
+// DBListBreaks lists the breaks of a vehicle, along with its off-shift breaks when offShift is true
+func (q *Queries) DBListBreaks(ctx context.Context, vehicleID int64, offShift bool) ([]Break, error) {
 	_, err := q.DBGetVehicle(ctx, vehicleID)
 	if err != nil {
 		return nil, err
@@ -84,9 +85,9 @@ func (q *Queries) DBListBreaks(ctx context.Context, vehicleID int64) ([]Break, e
 		util.GetFormattedTimestamp("tw_close"),
 	)
 	joinTableQuery := fmt.Sprintf(" LEFT JOIN breaks_time_windows TW on(%s.id = TW.id) ", tableName)
-	additionalQuery := fmt.Sprintf(" WHERE vehicle_id = $1 AND deleted = FALSE GROUP BY %s.id ORDER BY %s.created_at", tableName, tableName)
+	additionalQuery := fmt.Sprintf(" WHERE vehicle_id = $1 AND deleted = FALSE AND (off_shift = FALSE OR $2) GROUP BY %s.id ORDER BY %s.created_at", tableName, tableName)
 	sql := "SELECT " + util.GetOutputFields(Break{}, tableName) + joinSelectQuery + " FROM " + tableName + joinTableQuery + additionalQuery
-	rows, err := q.db.Query(ctx, sql, vehicleID)
+	rows, err := q.db.Query(ctx, sql, vehicleID, offShift)
 	if err != nil {
 		return nil, err
 	}
@@ -185,6 +186,7 @@ func scanBreakRow(row pgx.Row) (Break, error) {
 		&i.ID,
 		&i.VehicleID,
 		&i.Service,
+		&i.OffShift,
 		&i.Data,
 		&i.CreatedAt,
 		&i.UpdatedAt,
@@ -204,6 +206,7 @@ func scanBreakRows(rows pgx.Rows) ([]Break, error) {
 			&i.ID,
 			&i.VehicleID,
 			&i.Service,
+			&i.OffShift,
 			&i.Data,
 			&i.CreatedAt,
 			&i.UpdatedAt,
@@ -0,0 +1,86 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: compare.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+)
+
+// DBCompareSchedules compares the schedule of a project with the schedule of another project,
+// matching the vehicles and tasks of a project with the ones of its clones
+func (q *Queries) DBCompareSchedules(ctx context.Context, projectID int64, otherProjectID int64) (util.ScheduleComparison, error) {
+	base, err := q.DBGetSchedule(ctx, projectID)
+	if err != nil {
+		return util.ScheduleComparison{}, err
+	}
+	other, err := q.DBGetSchedule(ctx, otherProjectID)
+	if err != nil {
+		return util.ScheduleComparison{}, err
+	}
+	baseOrigins, err := q.getProjectOrigins(ctx, projectID)
+	if err != nil {
+		return util.ScheduleComparison{}, err
+	}
+	otherOrigins, err := q.getProjectOrigins(ctx, otherProjectID)
+	if err != nil {
+		return util.ScheduleComparison{}, err
+	}
+
+	base.ProjectID = projectID
+	other.ProjectID = otherProjectID
+	return util.CompareSchedules(base, other, baseOrigins, otherOrigins), nil
+}
+
+// getProjectOrigins returns the ids of the original rows of the cloned tasks and vehicles of a project
+func (q *Queries) getProjectOrigins(ctx context.Context, projectID int64) (map[int64]int64, error) {
+	sql := `
+	SELECT id, source_id FROM jobs WHERE project_id = $1 AND source_id IS NOT NULL UNION ALL
+	SELECT id, source_id FROM shipments WHERE project_id = $1 AND source_id IS NOT NULL UNION ALL
+	SELECT id, source_id FROM vehicles WHERE project_id = $1 AND source_id IS NOT NULL UNION ALL
+	SELECT B.id, B.source_id FROM breaks B JOIN vehicles V ON (B.vehicle_id = V.id)
+	WHERE V.project_id = $1 AND B.source_id IS NOT NULL`
+
+	rows, err := q.db.Query(ctx, sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	origins := map[int64]int64{}
+	for rows.Next() {
+		var id, sourceID int64
+		if err := rows.Scan(&id, &sourceID); err != nil {
+			return nil, err
+		}
+		origins[id] = sourceID
+	}
+	return origins, rows.Err()
+}
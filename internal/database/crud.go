@@ -52,6 +52,11 @@ func createResource(resource string, resourceStruct interface{}) (sql string, ar
 			val = val + "::INTERVAL"
 		}
 
+		// Convert any zero value of a nullable field to NULL
+		if fieldType, nullableFieldFound := util.NullableFields[field]; nullableFieldFound {
+			val = fmt.Sprintf("NULLIF($%d::%s, '0')", i+1, fieldType)
+		}
+
 		if i == 0 {
 			sqlFields += field
 			values += val
@@ -86,6 +91,11 @@ func updateResource(resource string, resourceStruct interface{}, id int64) (sql
 			val = val + "::INTERVAL"
 		}
 
+		// Convert any zero value of a nullable field to NULL
+		if fieldType, nullableFieldFound := util.NullableFields[field]; nullableFieldFound {
+			val = fmt.Sprintf("NULLIF($%d::%s, '0')", i+1, fieldType)
+		}
+
 		if i == 0 {
 			restSQL += field + " = " + val
 		} else {
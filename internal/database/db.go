@@ -83,3 +83,29 @@ func (q *Queries) execUpdateTx(ctx context.Context, fn func(*Queries) error) err
 
 	return tx.Commit(ctx)
 }
+
+// execTx runs fn with queries executed in a single transaction, which is committed when fn succeeds. The
+// transactions started by fn are savepoints of this transaction.
+func (q *Queries) execTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := q.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := fn(New(nestedTx{tx})); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// nestedTx is a transaction used as a DBTX, where starting a transaction creates a savepoint
+type nestedTx struct {
+	pgx.Tx
+}
+
+func (tx nestedTx) BeginTx(ctx context.Context, _ pgx.TxOptions) (pgx.Tx, error) {
+	return tx.Begin(ctx)
+}
@@ -0,0 +1,77 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: event.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// eventsChannel is the channel on which the events of the projects are sent by the create_event function
+const eventsChannel = "project_events"
+
+// ProjectEvent is an event of a project received on the events channel, along with its JSON payload
+type ProjectEvent struct {
+	ID        string `json:"id"`
+	Event     string `json:"event"`
+	ProjectID int64  `json:"project_id,string"`
+	Payload   string `json:"-"`
+}
+
+// ListenEvents listens to the events of all the projects on a dedicated connection, which is not taken from the pool,
+// calling onListen once the server listens and handle for each event, until the context is done or the connection
+// fails. The events created by the other servers sharing the database are received as well.
+func (store *Store) ListenEvents(ctx context.Context, onListen func(), handle func(ProjectEvent)) error {
+	conn, err := pgx.ConnectConfig(ctx, store.db.Config().ConnConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+eventsChannel); err != nil {
+		return err
+	}
+	onListen()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		event := ProjectEvent{Payload: notification.Payload}
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			logrus.Error(err)
+			continue
+		}
+		handle(event)
+	}
+}
@@ -0,0 +1,275 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: import.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/jackc/pgx/v4"
+)
+
+type ImportJobParams struct {
+	CreateJobParams
+	Row int `json:"-"`
+}
+
+type ImportShipmentParams struct {
+	CreateShipmentParams
+	Row int `json:"-"`
+}
+
+type ImportVehicleParams struct {
+	CreateVehicleParams
+	Row int `json:"-"`
+}
+
+// ImportBreakParams is a break of an existing vehicle (VehicleIndex = -1),
+// or of the vehicle at index VehicleIndex in the imported vehicles
+type ImportBreakParams struct {
+	CreateBreakParams
+	Row          int `json:"-"`
+	VehicleIndex int `json:"-"`
+}
+
+type ImportParams struct {
+	Jobs      []ImportJobParams
+	Shipments []ImportShipmentParams
+	Vehicles  []ImportVehicleParams
+	Breaks    []ImportBreakParams
+}
+
+type ImportResult struct {
+	Jobs      int  `json:"jobs" example:"2000"`
+	Shipments int  `json:"shipments" example:"100"`
+	Vehicles  int  `json:"vehicles" example:"20"`
+	Breaks    int  `json:"breaks" example:"20"`
+	DryRun    bool `json:"dry_run" example:"false"`
+}
+
+// batchItem is a query of a batch, along with the name of the imported row it belongs to
+type batchItem struct {
+	name string
+	id   *int64
+}
+
+// importIDs are the ids of the imported rows, in the same order as the rows
+type importIDs struct {
+	jobs      []int64
+	shipments []int64
+	vehicles  []int64
+	breaks    []int64
+}
+
+// getRowName returns the name of a row of the import, used in the error messages. The rows of all the kinds are
+// numbered in the order of the import, so the kind is not needed to find a row.
+func getRowName(row int) string {
+	return fmt.Sprintf("Row %d", row)
+}
+
+// DBImport inserts all the jobs, shipments, vehicles and breaks in a single transaction,
+// using batches of inserts
+func (q *Queries) DBImport(ctx context.Context, arg ImportParams) (ImportResult, error) {
+	tx, err := q.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return ImportResult{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := q.applyImportVehicleTypes(ctx, &arg); err != nil {
+		return ImportResult{}, err
+	}
+	if _, err := importRows(ctx, tx, arg, func(kind string, row int) string { return getRowName(row) }); err != nil {
+		return ImportResult{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ImportResult{}, err
+	}
+	return ImportResult{
+		Jobs:      len(arg.Jobs),
+		Shipments: len(arg.Shipments),
+		Vehicles:  len(arg.Vehicles),
+		Breaks:    len(arg.Breaks),
+	}, nil
+}
+
+// applyImportVehicleTypes sets the fields of the imported vehicles which are not given to the ones of their vehicle
+// type, and adds the breaks of the type for each of them, as when the vehicles are created one by one
+func (q *Queries) applyImportVehicleTypes(ctx context.Context, arg *ImportParams) error {
+	vehicleTypes := map[int64]VehicleType{}
+	for i := range arg.Vehicles {
+		vehicle := &arg.Vehicles[i]
+		if vehicle.VehicleTypeID == nil || *vehicle.VehicleTypeID == 0 {
+			continue
+		}
+		vehicleType, found := vehicleTypes[*vehicle.VehicleTypeID]
+		if !found {
+			var err error
+			vehicleType, err = q.getProjectVehicleType(ctx, *vehicle.ProjectID, *vehicle.VehicleTypeID)
+			if err != nil {
+				return fmt.Errorf("%s: %s", getRowName(vehicle.Row), err)
+			}
+			vehicleTypes[vehicleType.ID] = vehicleType
+		}
+		applyVehicleType(vehicleType, &vehicle.CreateVehicleParams)
+		for j := range vehicleType.Breaks {
+			vBreak := vehicleType.Breaks[j]
+			arg.Breaks = append(arg.Breaks, ImportBreakParams{
+				Row:          vehicle.Row,
+				VehicleIndex: i,
+				CreateBreakParams: CreateBreakParams{
+					Service:     &vBreak.Service,
+					TimeWindows: &vBreak.TimeWindows,
+					Data:        getDataParam(vBreak.Data),
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// importRows inserts the rows in the transaction, and returns their ids.
+// The name of a row in the error messages is given by rowName, using the kind ("jobs", "shipments", "vehicles"
+// or "breaks") and the row number.
+func importRows(ctx context.Context, tx pgx.Tx, arg ImportParams, rowName func(kind string, row int) string) (importIDs, error) {
+	ids := importIDs{
+		jobs:      make([]int64, len(arg.Jobs)),
+		shipments: make([]int64, len(arg.Shipments)),
+		vehicles:  make([]int64, len(arg.Vehicles)),
+		breaks:    make([]int64, len(arg.Breaks)),
+	}
+
+	// Insert the jobs, shipments and vehicles
+	batch := &pgx.Batch{}
+	items := []batchItem{}
+	for i, job := range arg.Jobs {
+		sql, args := createResource("jobs", job.CreateJobParams)
+		batch.Queue(sql+" RETURNING id", args...)
+		items = append(items, batchItem{name: rowName("jobs", job.Row), id: &ids.jobs[i]})
+	}
+	for i, shipment := range arg.Shipments {
+		sql, args := createResource("shipments", shipment.CreateShipmentParams)
+		batch.Queue(sql+" RETURNING id", args...)
+		items = append(items, batchItem{name: rowName("shipments", shipment.Row), id: &ids.shipments[i]})
+	}
+	for i, vehicle := range arg.Vehicles {
+		sql, args := createResource("vehicles", vehicle.CreateVehicleParams)
+		batch.Queue(sql+" RETURNING id", args...)
+		items = append(items, batchItem{name: rowName("vehicles", vehicle.Row), id: &ids.vehicles[i]})
+	}
+	if err := sendImportBatch(ctx, tx, batch, items); err != nil {
+		return importIDs{}, err
+	}
+	if err := checkPinnedVehicles(ctx, tx, "jobs", ids.jobs); err != nil {
+		return importIDs{}, err
+	}
+	if err := checkPinnedVehicles(ctx, tx, "shipments", ids.shipments); err != nil {
+		return importIDs{}, err
+	}
+
+	// Insert the breaks, once the ids of the vehicles are known
+	batch = &pgx.Batch{}
+	items = []batchItem{}
+	for i, vBreak := range arg.Breaks {
+		params := vBreak.CreateBreakParams
+		if vBreak.VehicleIndex >= 0 {
+			params.VehicleID = &ids.vehicles[vBreak.VehicleIndex]
+		}
+		sql, args := createResource("breaks", params)
+		batch.Queue(sql+" RETURNING id", args...)
+		items = append(items, batchItem{name: rowName("breaks", vBreak.Row), id: &ids.breaks[i]})
+	}
+	if err := sendImportBatch(ctx, tx, batch, items); err != nil {
+		return importIDs{}, err
+	}
+
+	// Insert the time windows of the jobs, shipments and breaks
+	batch = &pgx.Batch{}
+	items = []batchItem{}
+	for i, job := range arg.Jobs {
+		if job.TimeWindows == nil {
+			continue
+		}
+		for _, tw := range *job.TimeWindows {
+			batch.Queue("INSERT INTO jobs_time_windows (id, tw_open, tw_close) VALUES ($1, $2, $3)", ids.jobs[i], tw[0], tw[1])
+			items = append(items, batchItem{name: rowName("jobs", job.Row)})
+		}
+	}
+	for i, shipment := range arg.Shipments {
+		kindTimeWindows := map[string]*[][]string{"p": shipment.PTimeWindows, "d": shipment.DTimeWindows}
+		for _, kind := range []string{"p", "d"} {
+			if kindTimeWindows[kind] == nil {
+				continue
+			}
+			for _, tw := range *kindTimeWindows[kind] {
+				batch.Queue("INSERT INTO shipments_time_windows (id, kind, tw_open, tw_close) VALUES ($1, $2, $3, $4)", ids.shipments[i], kind, tw[0], tw[1])
+				items = append(items, batchItem{name: rowName("shipments", shipment.Row)})
+			}
+		}
+	}
+	for i, vBreak := range arg.Breaks {
+		if vBreak.TimeWindows == nil {
+			continue
+		}
+		for _, tw := range *vBreak.TimeWindows {
+			batch.Queue("INSERT INTO breaks_time_windows (id, tw_open, tw_close) VALUES ($1, $2, $3)", ids.breaks[i], tw[0], tw[1])
+			items = append(items, batchItem{name: rowName("breaks", vBreak.Row)})
+		}
+	}
+	if err := sendImportBatch(ctx, tx, batch, items); err != nil {
+		return importIDs{}, err
+	}
+	return ids, nil
+}
+
+// sendImportBatch executes the batch, scanning the returned id of the items which need it.
+// The error of a query is returned along with the name of the row it belongs to.
+func sendImportBatch(ctx context.Context, tx pgx.Tx, batch *pgx.Batch, items []batchItem) error {
+	if batch.Len() == 0 {
+		return nil
+	}
+	results := tx.SendBatch(ctx, batch)
+	for _, item := range items {
+		var err error
+		if item.id != nil {
+			err = results.QueryRow().Scan(item.id)
+		} else {
+			_, err = results.Exec()
+		}
+		if err != nil {
+			results.Close()
+			return fmt.Errorf("%s: %s", item.name, util.HandleDBError(err))
+		}
+	}
+	return results.Close()
+}
@@ -37,29 +37,35 @@ import (
 )
 
 type CreateJobParams struct {
-	Location    *util.LocationParams `json:"location" validate:"required"`
-	Setup       *string              `json:"setup"    validate:"omitempty" example:"00:00:00"`
-	Service     *string              `json:"service"  validate:"omitempty" example:"00:02:00"`
-	Delivery    *[]int64             `json:"delivery" validate:"omitempty,dive,min=0" example:"10,20"`
-	Pickup      *[]int64             `json:"pickup"   validate:"omitempty,dive,min=0" example:"5,15"`
-	Skills      *[]int32             `json:"skills"   validate:"omitempty,dive,min=0" example:"1,5"`
-	Priority    *int32               `json:"priority" validate:"omitempty,min=0,max=100" example:"10"`
-	TimeWindows *[][]string          `json:"time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
-	ProjectID   *int64               `json:"project_id,string" validate:"required" swaggerignore:"true"`
-	Data        *interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	Location        *util.LocationParams `json:"location" validate:"required"`
+	Setup           *string              `json:"setup"    validate:"omitempty" example:"00:00:00"`
+	Service         *string              `json:"service"  validate:"omitempty" example:"00:02:00"`
+	Delivery        *[]int64             `json:"delivery" validate:"omitempty,dive,min=0" example:"10,20"`
+	Pickup          *[]int64             `json:"pickup"   validate:"omitempty,dive,min=0" example:"5,15"`
+	Skills          *[]int32             `json:"skills"   validate:"omitempty,dive,skill" example:"1,5"`
+	Priority        *int32               `json:"priority" validate:"omitempty,min=0,max=100" example:"10"`
+	PinnedVehicleID *int64               `json:"pinned_vehicle_id,string" validate:"omitempty,min=0" example:"1234567812345678"`
+	Locked          *bool                `json:"locked" example:"false"`
+	Recurrence      *string              `json:"recurrence" validate:"omitempty,rrule" example:"FREQ=WEEKLY;BYDAY=MO,WE"`
+	TimeWindows     *[][]string          `json:"time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
+	ProjectID       *int64               `json:"project_id,string" validate:"required" swaggerignore:"true"`
+	Data            *interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
 }
 
 type UpdateJobParams struct {
-	Location    *util.LocationParams `json:"location"`
-	Setup       *string              `json:"setup"    validate:"omitempty" example:"00:00:00"`
-	Service     *string              `json:"service"  validate:"omitempty" example:"00:02:00"`
-	Delivery    *[]int64             `json:"delivery" validate:"omitempty,dive,min=0" example:"10,20"`
-	Pickup      *[]int64             `json:"pickup"   validate:"omitempty,dive,min=0" example:"5,15"`
-	Skills      *[]int32             `json:"skills"   validate:"omitempty,dive,min=0" example:"1,5"`
-	Priority    *int32               `json:"priority" validate:"omitempty,min=0,max=100" example:"10"`
-	TimeWindows *[][]string          `json:"time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
-	ProjectID   *int64               `json:"project_id,string" swaggerignore:"true"`
-	Data        *interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	Location        *util.LocationParams `json:"location"`
+	Setup           *string              `json:"setup"    validate:"omitempty" example:"00:00:00"`
+	Service         *string              `json:"service"  validate:"omitempty" example:"00:02:00"`
+	Delivery        *[]int64             `json:"delivery" validate:"omitempty,dive,min=0" example:"10,20"`
+	Pickup          *[]int64             `json:"pickup"   validate:"omitempty,dive,min=0" example:"5,15"`
+	Skills          *[]int32             `json:"skills"   validate:"omitempty,dive,skill" example:"1,5"`
+	Priority        *int32               `json:"priority" validate:"omitempty,min=0,max=100" example:"10"`
+	PinnedVehicleID *int64               `json:"pinned_vehicle_id,string" validate:"omitempty,min=0" example:"1234567812345678"`
+	Locked          *bool                `json:"locked" example:"false"`
+	Recurrence      *string              `json:"recurrence" validate:"omitempty,rrule" example:"FREQ=WEEKLY;BYDAY=MO,WE"`
+	TimeWindows     *[][]string          `json:"time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
+	ProjectID       *int64               `json:"project_id,string" swaggerignore:"true"`
+	Data            *interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
 }
 
 func (q *Queries) DBCreateJob(ctx context.Context, arg CreateJobParams) (int64, error) {
@@ -127,6 +133,9 @@ func (q *Queries) DBCreateJobWithTw(ctx context.Context, arg CreateJobParams) (J
 		if err != nil {
 			return 0, err
 		}
+		if err := checkPinnedVehicles(ctx, q.db, "jobs", []int64{id}); err != nil {
+			return 0, err
+		}
 
 		// create time windows from arg and pass to DBCreateJobTimeWindows
 		timeWindows := []TimeWindowParams{}
@@ -148,28 +157,17 @@ func (q *Queries) DBCreateJobWithTw(ctx context.Context, arg CreateJobParams) (J
 	return q.DBGetJob(ctx, id)
 }
 
+// DBUpdateJobWithTw updates a job along with its time windows. An occurrence of a recurring job is marked as
+// overridden when one of the fields copied from its recurring job is changed.
 func (q *Queries) DBUpdateJobWithTw(ctx context.Context, arg UpdateJobParams, job_id int64) (Job, error) {
 	err := q.execUpdateTx(ctx, func(q *Queries) error {
-		if err := q.DBUpdateJob(ctx, arg, job_id); err != nil {
-			return err
-		}
-		// delete all time windows
-		if err := q.DBDeleteJobTimeWindows(ctx, job_id); err != nil {
+		if err := q.updateJobWithTw(ctx, arg, job_id); err != nil {
 			return err
 		}
-
-		// create time windows from arg and pass to DBCreateJobTimeWindows
-		timeWindows := []TimeWindowParams{}
-		if arg.TimeWindows != nil {
-			for _, tw := range *arg.TimeWindows {
-				// append time window to timeWindows
-				timeWindows = append(timeWindows, TimeWindowParams{
-					TwOpen:  tw[0],
-					TwClose: tw[1],
-				})
-			}
+		if !overridesOccurrence(arg) {
+			return nil
 		}
-		err := q.DBCreateJobTimeWindows(ctx, job_id, timeWindows)
+		_, err := q.db.Exec(ctx, "UPDATE jobs SET overridden = TRUE WHERE id = $1 AND recurring_job_id IS NOT NULL", job_id)
 		return err
 	})
 	if err != nil {
@@ -178,6 +176,39 @@ func (q *Queries) DBUpdateJobWithTw(ctx context.Context, arg UpdateJobParams, jo
 	return q.DBGetJob(ctx, job_id)
 }
 
+func (q *Queries) updateJobWithTw(ctx context.Context, arg UpdateJobParams, job_id int64) error {
+	if err := q.DBUpdateJob(ctx, arg, job_id); err != nil {
+		return err
+	}
+	if err := checkPinnedVehicles(ctx, q.db, "jobs", []int64{job_id}); err != nil {
+		return err
+	}
+	// delete all time windows
+	if err := q.DBDeleteJobTimeWindows(ctx, job_id); err != nil {
+		return err
+	}
+
+	// create time windows from arg and pass to DBCreateJobTimeWindows
+	timeWindows := []TimeWindowParams{}
+	if arg.TimeWindows != nil {
+		for _, tw := range *arg.TimeWindows {
+			// append time window to timeWindows
+			timeWindows = append(timeWindows, TimeWindowParams{
+				TwOpen:  tw[0],
+				TwClose: tw[1],
+			})
+		}
+	}
+	return q.DBCreateJobTimeWindows(ctx, job_id, timeWindows)
+}
+
+// overridesOccurrence returns whether the update changes one of the fields which the occurrences copy from their
+// recurring job
+func overridesOccurrence(arg UpdateJobParams) bool {
+	return arg.Location != nil || arg.Setup != nil || arg.Service != nil || arg.Delivery != nil || arg.Pickup != nil ||
+		arg.Skills != nil || arg.Priority != nil || arg.PinnedVehicleID != nil || arg.TimeWindows != nil || arg.Data != nil
+}
+
 func (q *Queries) DBDeleteJobWithTw(ctx context.Context, job_id int64) error {
 	err := q.execUpdateTx(ctx, func(q *Queries) error {
 		// delete all time windows
@@ -210,6 +241,12 @@ func scanJobRow(row pgx.Row) (Job, error) {
 		&i.Pickup,
 		&i.Skills,
 		&i.Priority,
+		&i.PinnedVehicleID,
+		&i.Locked,
+		&i.Recurrence,
+		&i.RecurringJobID,
+		&i.Occurrence,
+		&i.Overridden,
 		&i.ProjectID,
 		&i.Data,
 		&i.CreatedAt,
@@ -242,6 +279,12 @@ func scanJobRows(rows pgx.Rows) ([]Job, error) {
 			&i.Pickup,
 			&i.Skills,
 			&i.Priority,
+			&i.PinnedVehicleID,
+			&i.Locked,
+			&i.Recurrence,
+			&i.RecurringJobID,
+			&i.Occurrence,
+			&i.Overridden,
 			&i.ProjectID,
 			&i.Data,
 			&i.CreatedAt,
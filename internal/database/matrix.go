@@ -0,0 +1,348 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: matrix.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/jackc/pgx/v4"
+)
+
+// DBGetMatrix returns the durations between all the pairs of locations. The cached durations
+// are reused, and only the missing pairs are computed and then stored in the cache.
+func (q *Queries) DBGetMatrix(ctx context.Context, locationIds []int64, durationCalc string) (startIds []int64, endIds []int64, durations []int64, err error) {
+	sql := `
+	SELECT start_id, end_id, duration, distance FROM matrix
+	WHERE duration_calc = $1 AND start_id = ANY($2) AND end_id = ANY($2)`
+
+	rows, err := q.db.Query(ctx, sql, durationCalc, locationIds)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cached, err := scanMatrixRows(rows)
+	rows.Close()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// find the pairs of locations which are not present in the cache
+	missing := make(map[int64][]int64)
+	for _, startId := range locationIds {
+		for _, endId := range locationIds {
+			if _, ok := cached[[2]int64{startId, endId}]; !ok {
+				missing[startId] = append(missing[startId], endId)
+			}
+		}
+	}
+
+	if len(missing) != 0 {
+		entries, err := util.GetMatrix(ctx, missing, durationCalc)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if err := q.DBCreateMatrix(ctx, entries, durationCalc); err != nil {
+			return nil, nil, nil, err
+		}
+		for _, entry := range entries {
+			cached[[2]int64{entry.StartID, entry.EndID}] = entry
+		}
+	}
+
+	for _, startId := range locationIds {
+		for _, endId := range locationIds {
+			startIds = append(startIds, startId)
+			endIds = append(endIds, endId)
+			durations = append(durations, cached[[2]int64{startId, endId}].Duration)
+		}
+	}
+	return startIds, endIds, durations, nil
+}
+
+func (q *Queries) DBCreateMatrix(ctx context.Context, entries []util.MatrixEntry, durationCalc string) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	startIds := make([]int64, len(entries))
+	endIds := make([]int64, len(entries))
+	durations := make([]int64, len(entries))
+	distances := make([]int64, len(entries))
+	for i, entry := range entries {
+		startIds[i] = entry.StartID
+		endIds[i] = entry.EndID
+		durations[i] = entry.Duration
+		distances[i] = entry.Distance
+	}
+
+	sql := `
+	INSERT INTO matrix (start_id, end_id, duration_calc, duration, distance)
+	SELECT unnest($1::BIGINT[]), unnest($2::BIGINT[]), $3::VARCHAR, unnest($4::BIGINT[]), unnest($5::BIGINT[])
+	ON CONFLICT (start_id, end_id, duration_calc)
+	DO UPDATE SET duration = EXCLUDED.duration, distance = EXCLUDED.distance`
+	_, err := q.db.Exec(ctx, sql, startIds, endIds, durationCalc, durations, distances)
+	return err
+}
+
+func (q *Queries) DBClearMatrix(ctx context.Context, projectID int64) error {
+	_, err := q.DBGetProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	_, err = q.db.Exec(ctx, "SELECT clear_matrix($1)", projectID)
+	return err
+}
+
+// getMatrixDistances returns the cached distances (in meters) between the pairs of locations, which are
+// cached along with the durations by DBGetMatrix
+func (q *Queries) getMatrixDistances(ctx context.Context, locationIds []int64, durationCalc string) (map[[2]int64]int64, error) {
+	sql := `
+	SELECT start_id, end_id, duration, distance FROM matrix
+	WHERE duration_calc = $1 AND start_id = ANY($2) AND end_id = ANY($2)`
+
+	rows, err := q.db.Query(ctx, sql, durationCalc, locationIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cached, err := scanMatrixRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	distances := make(map[[2]int64]int64, len(cached))
+	for key, entry := range cached {
+		distances[key] = entry.Distance
+	}
+	return distances, nil
+}
+
+func scanMatrixRows(rows pgx.Rows) (map[[2]int64]util.MatrixEntry, error) {
+	items := make(map[[2]int64]util.MatrixEntry)
+	for rows.Next() {
+		var i util.MatrixEntry
+		if err := rows.Scan(&i.StartID, &i.EndID, &i.Duration, &i.Distance); err != nil {
+			return nil, err
+		}
+		items[[2]int64{i.StartID, i.EndID}] = i
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// PgRoutingMatrixProvider computes the durations with pgr_dijkstraCostMatrix on a road network table in the
+// database, having the "id, source, target, cost, reverse_cost, length_m" columns with the cost in seconds and the
+// length in meters. The distances are the lengths of the same fastest paths, computed with pgr_dijkstra. Each location
+// is snapped to the nearest vertex of the vertices table having the "id, the_geom" columns. The route geometry is the
+// path through the vertices, as the edges table has no geometry.
+type PgRoutingMatrixProvider struct {
+	db            DBTX
+	edgesTable    string
+	verticesTable string
+}
+
+func NewPgRoutingMatrixProvider(db DBTX, edgesTable string, verticesTable string) *PgRoutingMatrixProvider {
+	if verticesTable == "" {
+		verticesTable = edgesTable + "_vertices_pgr"
+	}
+	return &PgRoutingMatrixProvider{
+		db:            db,
+		edgesTable:    pgx.Identifier(strings.Split(edgesTable, ".")).Sanitize(),
+		verticesTable: pgx.Identifier(strings.Split(verticesTable, ".")).Sanitize(),
+	}
+}
+
+func (p *PgRoutingMatrixProvider) GetMatrix(ctx context.Context, startIds []int64, endIds []int64) ([][]int64, [][]int64, error) {
+	// snap the locations to the nearest vertices
+	locationIds := append(append([]int64{}, startIds...), endIds...)
+	vertices, err := p.getVertices(ctx, locationIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// compute the costs between all the vertices, and the length of the fastest paths for the distances
+	edgesSql := fmt.Sprintf("SELECT id, source, target, cost, reverse_cost FROM %s", p.edgesTable)
+	durationCosts, err := p.getCostMatrix(ctx, edgesSql, vertices)
+	if err != nil {
+		return nil, nil, err
+	}
+	distanceCosts, err := p.getPathLengths(ctx, edgesSql, vertices)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	durations := make([][]int64, 0)
+	distances := make([][]int64, 0)
+	for i := range startIds {
+		durationsRow := make([]int64, 0)
+		distancesRow := make([]int64, 0)
+		for j := range endIds {
+			vertexPair := [2]int64{vertices[i], vertices[len(startIds)+j]}
+			durationsRow = append(durationsRow, getCost(durationCosts, vertexPair, util.UnreachableDuration))
+			distancesRow = append(distancesRow, getCost(distanceCosts, vertexPair, util.UnreachableDistance))
+		}
+		durations = append(durations, durationsRow)
+		distances = append(distances, distancesRow)
+	}
+	return durations, distances, nil
+}
+
+// GetRoute returns the path through the vertices of the fastest paths between the consecutive locations, computed
+// with pgr_dijkstraVia. The locations which can not be reached are skipped.
+func (p *PgRoutingMatrixProvider) GetRoute(ctx context.Context, locationIds []int64) ([][]float64, error) {
+	vertices, err := p.getVertices(ctx, locationIds)
+	if err != nil {
+		return nil, err
+	}
+
+	edgesSql := fmt.Sprintf("SELECT id, source, target, cost, reverse_cost FROM %s", p.edgesTable)
+	sql := fmt.Sprintf(`
+	SELECT ST_X(V.the_geom), ST_Y(V.the_geom)
+	FROM pgr_dijkstraVia($1, $2::BIGINT[], strict => FALSE, U_turn_on_edge => TRUE) AS P
+	JOIN %s V ON V.id = P.node
+	ORDER BY P.seq`, p.verticesTable)
+	rows, err := p.db.Query(ctx, sql, edgesSql, vertices)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	coordinates := make([][]float64, 0)
+	for rows.Next() {
+		var longitude, latitude float64
+		if err := rows.Scan(&longitude, &latitude); err != nil {
+			return nil, err
+		}
+		// the vertex of a location ends a path and starts the next one
+		last := len(coordinates) - 1
+		if last >= 0 && coordinates[last][0] == longitude && coordinates[last][1] == latitude {
+			continue
+		}
+		coordinates = append(coordinates, []float64{longitude, latitude})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// all the locations are snapped to the same vertex, or can not be reached
+	if len(coordinates) < 2 {
+		coordinates = make([][]float64, 0)
+		for _, id := range locationIds {
+			latitude, longitude := util.GetCoordinates(id)
+			coordinates = append(coordinates, []float64{longitude, latitude})
+		}
+	}
+	return coordinates, nil
+}
+
+// getVertices returns the nearest vertex of each location
+func (p *PgRoutingMatrixProvider) getVertices(ctx context.Context, locationIds []int64) ([]int64, error) {
+	sql := fmt.Sprintf(`
+	SELECT (SELECT V.id FROM %s V ORDER BY V.the_geom <-> id_to_geom(L.id) LIMIT 1)
+	FROM unnest($1::BIGINT[]) WITH ORDINALITY AS L(id, idx) ORDER BY L.idx`, p.verticesTable)
+	rows, err := p.db.Query(ctx, sql, locationIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	vertices, err := scanProjectLocationRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(vertices) != len(locationIds) {
+		return nil, fmt.Errorf("No vertices present in the road network")
+	}
+	return vertices, nil
+}
+
+func (p *PgRoutingMatrixProvider) getCostMatrix(ctx context.Context, edgesSql string, vertices []int64) (map[[2]int64]float64, error) {
+	sql := "SELECT start_vid, end_vid, agg_cost FROM pgr_dijkstraCostMatrix($1, $2::BIGINT[])"
+	rows, err := p.db.Query(ctx, sql, edgesSql, vertices)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	costs := make(map[[2]int64]float64)
+	for rows.Next() {
+		var startVid, endVid int64
+		var cost float64
+		if err := rows.Scan(&startVid, &endVid, &cost); err != nil {
+			return nil, err
+		}
+		costs[[2]int64{startVid, endVid}] = cost
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return costs, nil
+}
+
+// getPathLengths returns the sum of the length_m of the edges along the fastest path between all the vertices, so that
+// the distances match the paths of the durations
+func (p *PgRoutingMatrixProvider) getPathLengths(ctx context.Context, edgesSql string, vertices []int64) (map[[2]int64]float64, error) {
+	sql := fmt.Sprintf(`
+	SELECT P.start_vid, P.end_vid, sum(E.length_m)::FLOAT8
+	FROM pgr_dijkstra($1, $2::BIGINT[], $2::BIGINT[]) AS P
+	JOIN %s E ON E.id = P.edge
+	GROUP BY P.start_vid, P.end_vid`, p.edgesTable)
+	rows, err := p.db.Query(ctx, sql, edgesSql, vertices)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lengths := make(map[[2]int64]float64)
+	for rows.Next() {
+		var startVid, endVid int64
+		var length float64
+		if err := rows.Scan(&startVid, &endVid, &length); err != nil {
+			return nil, err
+		}
+		lengths[[2]int64{startVid, endVid}] = length
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lengths, nil
+}
+
+// get the cost between a pair of vertices, with the unreachable vertices as the unreachable value
+func getCost(costs map[[2]int64]float64, vertexPair [2]int64, unreachable int64) int64 {
+	if vertexPair[0] == vertexPair[1] {
+		return 0
+	}
+	if cost, ok := costs[vertexPair]; ok {
+		return int64(cost)
+	}
+	return unreachable
+}
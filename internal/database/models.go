@@ -36,6 +36,7 @@ type Break struct {
 	ID          int64       `json:"id,string" example:"1234567812345678"`
 	VehicleID   int64       `json:"vehicle_id,string" example:"1234567812345678"`
 	Service     string      `json:"service" example:"00:02:00"`
+	OffShift    bool        `json:"off_shift" example:"false"`
 	Data        interface{} `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
 	CreatedAt   string      `json:"created_at" example:"2021-12-01T13:00:00"`
 	UpdatedAt   string      `json:"updated_at" example:"2021-12-01T13:00:00"`
@@ -43,65 +44,189 @@ type Break struct {
 }
 
 type Job struct {
-	ID          int64               `json:"id,string" example:"1234567812345678"`
-	Location    util.LocationParams `json:"location"`
-	Setup       string              `json:"setup" example:"00:00:00"`
-	Service     string              `json:"service" example:"00:02:00"`
-	Delivery    []int64             `json:"delivery" example:"10,20"`
-	Pickup      []int64             `json:"pickup" example:"5,15"`
-	Skills      []int32             `json:"skills" example:"1,5"`
-	Priority    int32               `json:"priority" example:"10"`
-	ProjectID   int64               `json:"project_id,string" example:"1234567812345678"`
-	Data        interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
-	CreatedAt   string              `json:"created_at" example:"2021-12-01T13:00:00"`
-	UpdatedAt   string              `json:"updated_at" example:"2021-12-01T13:00:00"`
-	TimeWindows [][]string          `json:"time_windows"`
+	ID              int64               `json:"id,string" example:"1234567812345678"`
+	Location        util.LocationParams `json:"location"`
+	Setup           string              `json:"setup" example:"00:00:00"`
+	Service         string              `json:"service" example:"00:02:00"`
+	Delivery        []int64             `json:"delivery" example:"10,20"`
+	Pickup          []int64             `json:"pickup" example:"5,15"`
+	Skills          []int32             `json:"skills" example:"1,5"`
+	Priority        int32               `json:"priority" example:"10"`
+	PinnedVehicleID *int64              `json:"pinned_vehicle_id,string" example:"1234567812345678"`
+	Locked          bool                `json:"locked" example:"false"`
+	Recurrence      string              `json:"recurrence" example:"FREQ=WEEKLY;BYDAY=MO,WE"`
+	RecurringJobID  *int64              `json:"recurring_job_id,string" example:"1234567812345678"`
+	Occurrence      *string             `json:"occurrence" example:"2021-12-01"`
+	Overridden      bool                `json:"overridden" example:"false"`
+	ProjectID       int64               `json:"project_id,string" example:"1234567812345678"`
+	Data            interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	CreatedAt       string              `json:"created_at" example:"2021-12-01T13:00:00"`
+	UpdatedAt       string              `json:"updated_at" example:"2021-12-01T13:00:00"`
+	TimeWindows     [][]string          `json:"time_windows"`
 }
 
 type Project struct {
-	ID               int64       `json:"id,string" example:"1234567812345678"`
-	Name             string      `json:"name" example:"Sample Project"`
-	DurationCalc     string      `json:"duration_calc" example:"euclidean"`
-	ExplorationLevel int64       `json:"exploration_level" example:"5"`
-	Timeout          string      `json:"timeout" example:"00:10:00"`
-	MaxShift         string      `json:"max_shift" example:"00:30:00"`
-	Data             interface{} `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
-	CreatedAt        string      `json:"created_at" example:"2021-12-01T13:00:00"`
-	UpdatedAt        string      `json:"updated_at" example:"2021-12-01T13:00:00"`
+	ID                 int64       `json:"id,string" example:"1234567812345678"`
+	Name               string      `json:"name" example:"Sample Project"`
+	DurationCalc       string      `json:"duration_calc" example:"euclidean"`
+	ExplorationLevel   int64       `json:"exploration_level" example:"5"`
+	Timeout            string      `json:"timeout" example:"00:10:00"`
+	MaxShift           string      `json:"max_shift" example:"00:30:00"`
+	Objective          string      `json:"objective" example:"duration"`
+	VehicleFixedCost   int64       `json:"vehicle_fixed_cost" example:"0"`
+	VehicleCostPerHour int64       `json:"vehicle_cost_per_hour" example:"3600"`
+	HorizonStart       *string     `json:"horizon_start" example:"2021-12-01"`
+	HorizonEnd         *string     `json:"horizon_end" example:"2021-12-07"`
+	Timezone           *string     `json:"timezone" example:"Europe/Berlin"`
+	Data               interface{} `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	CreatedAt          string      `json:"created_at" example:"2021-12-01T13:00:00"`
+	UpdatedAt          string      `json:"updated_at" example:"2021-12-01T13:00:00"`
+}
+
+type ScheduleRun struct {
+	ID         int64   `json:"id,string" example:"1234567812345678"`
+	ProjectID  int64   `json:"project_id,string" example:"1234567812345678"`
+	Status     string  `json:"status" example:"succeeded"`
+	Fresh      bool    `json:"fresh" example:"false"`
+	Error      *string `json:"error" example:"No locations present in the project"`
+	StartedAt  *string `json:"started_at" example:"2021-12-01T13:00:00"`
+	FinishedAt *string `json:"finished_at" example:"2021-12-01T13:00:00"`
+	CreatedAt  string  `json:"created_at" example:"2021-12-01T13:00:00"`
+	UpdatedAt  string  `json:"updated_at" example:"2021-12-01T13:00:00"`
+}
+
+type ScheduleSettings struct {
+	DurationCalc       string `json:"duration_calc" example:"euclidean"`
+	ExplorationLevel   int64  `json:"exploration_level" example:"5"`
+	Timeout            string `json:"timeout" example:"00:10:00"`
+	MaxShift           string `json:"max_shift" example:"00:30:00"`
+	Objective          string `json:"objective" example:"duration"`
+	VehicleFixedCost   int64  `json:"vehicle_fixed_cost" example:"0"`
+	VehicleCostPerHour int64  `json:"vehicle_cost_per_hour" example:"3600"`
+}
+
+type ScheduleVersion struct {
+	ProjectID  int64                 `json:"project_id,string" example:"1234567812345678"`
+	Version    int32                 `json:"version" example:"1"`
+	InputsHash string                `json:"inputs_hash" example:"9e107d9d372bb6826bd81d3542a419d6"`
+	Fresh      bool                  `json:"fresh" example:"false"`
+	Settings   ScheduleSettings      `json:"settings"`
+	Metadata   util.MetadataResponse `json:"metadata"`
+	Active     bool                  `json:"active" example:"true"`
+	CreatedAt  string                `json:"created_at" example:"2021-12-01T13:00:00"`
+	UpdatedAt  string                `json:"updated_at" example:"2021-12-01T13:00:00"`
 }
 
 type Shipment struct {
-	ID           int64               `json:"id,string" example:"1234567812345678"`
-	PLocation    util.LocationParams `json:"p_location" `
-	PSetup       string              `json:"p_setup" example:"00:00:00"`
-	PService     string              `json:"p_service" example:"00:02:00"`
-	DLocation    util.LocationParams `json:"d_location"`
-	DSetup       string              `json:"d_setup" example:"00:00:00"`
-	DService     string              `json:"d_service" example:"00:02:00"`
-	Amount       []int64             `json:"amount" example:"5,15"`
-	Skills       []int32             `json:"skills" example:"1,5"`
-	Priority     int32               `json:"priority" example:"10"`
-	ProjectID    int64               `json:"project_id,string" example:"1234567812345678"`
-	PData        interface{}         `json:"p_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
-	DData        interface{}         `json:"d_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
-	CreatedAt    string              `json:"created_at" example:"2021-12-01T13:00:00"`
-	UpdatedAt    string              `json:"updated_at" example:"2021-12-01T13:00:00"`
-	PTimeWindows [][]string          `json:"p_time_windows"`
-	DTimeWindows [][]string          `json:"d_time_windows"`
+	ID              int64               `json:"id,string" example:"1234567812345678"`
+	PLocation       util.LocationParams `json:"p_location" `
+	PSetup          string              `json:"p_setup" example:"00:00:00"`
+	PService        string              `json:"p_service" example:"00:02:00"`
+	DLocation       util.LocationParams `json:"d_location"`
+	DSetup          string              `json:"d_setup" example:"00:00:00"`
+	DService        string              `json:"d_service" example:"00:02:00"`
+	Amount          []int64             `json:"amount" example:"5,15"`
+	Skills          []int32             `json:"skills" example:"1,5"`
+	Priority        int32               `json:"priority" example:"10"`
+	PinnedVehicleID *int64              `json:"pinned_vehicle_id,string" example:"1234567812345678"`
+	Locked          bool                `json:"locked" example:"false"`
+	ProjectID       int64               `json:"project_id,string" example:"1234567812345678"`
+	PData           interface{}         `json:"p_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	DData           interface{}         `json:"d_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	CreatedAt       string              `json:"created_at" example:"2021-12-01T13:00:00"`
+	UpdatedAt       string              `json:"updated_at" example:"2021-12-01T13:00:00"`
+	PTimeWindows    [][]string          `json:"p_time_windows"`
+	DTimeWindows    [][]string          `json:"d_time_windows"`
 }
 
 type Vehicle struct {
-	ID            int64               `json:"id,string" example:"1234567812345678"`
-	StartLocation util.LocationParams `json:"start_location"`
-	EndLocation   util.LocationParams `json:"end_location"`
-	Capacity      []int64             `json:"capacity" example:"50,25"`
-	Skills        []int32             `json:"skills" example:"1,5"`
-	TwOpen        string              `json:"tw_open" example:"2021-12-31T23:00:00"`
-	TwClose       string              `json:"tw_close" example:"2021-12-31T23:59:00"`
-	SpeedFactor   float64             `json:"speed_factor" example:"1.0"`
-	MaxTasks      int32               `json:"max_tasks" example:"20"`
-	ProjectID     int64               `json:"project_id,string" example:"1234567812345678"`
-	Data          interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
-	CreatedAt     string              `json:"created_at" example:"2021-12-01T13:00:00"`
-	UpdatedAt     string              `json:"updated_at" example:"2021-12-01T13:00:00"`
+	ID              int64               `json:"id,string" example:"1234567812345678"`
+	StartLocation   util.LocationParams `json:"start_location"`
+	EndLocation     util.LocationParams `json:"end_location"`
+	Capacity        []int64             `json:"capacity" example:"50,25"`
+	Skills          []int32             `json:"skills" example:"1,5"`
+	TwOpen          string              `json:"tw_open" example:"2021-12-31T23:00:00"`
+	TwClose         string              `json:"tw_close" example:"2021-12-31T23:59:00"`
+	SpeedFactor     float64             `json:"speed_factor" example:"1.0"`
+	MaxTasks        int32               `json:"max_tasks" example:"20"`
+	MaxTravelTime   *string             `json:"max_travel_time" example:"08:00:00"`
+	MaxDistance     *int64              `json:"max_distance" example:"200000"`
+	FixedCost       *int64              `json:"fixed_cost" example:"5000"`
+	CostPerHour     *int64              `json:"cost_per_hour" example:"3600"`
+	CostPerKm       int64               `json:"cost_per_km" example:"100"`
+	VehicleTypeID   *int64              `json:"vehicle_type_id,string" example:"1234567812345678"`
+	ShiftRecurrence string              `json:"shift_recurrence" example:"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR"`
+	ProjectID       int64               `json:"project_id,string" example:"1234567812345678"`
+	Data            interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	CreatedAt       string              `json:"created_at" example:"2021-12-01T13:00:00"`
+	UpdatedAt       string              `json:"updated_at" example:"2021-12-01T13:00:00"`
+}
+
+// VehicleShift is a shift of a vehicle, or a day off when DayOff is true.
+// The recurring shifts are created from the shift recurrence of the vehicle when its project is scheduled.
+type VehicleShift struct {
+	ID        int64  `json:"id,string" example:"1234567812345678"`
+	VehicleID int64  `json:"vehicle_id,string" example:"1234567812345678"`
+	TwOpen    string `json:"tw_open" example:"2021-12-01T08:00:00"`
+	TwClose   string `json:"tw_close" example:"2021-12-01T17:00:00"`
+	DayOff    bool   `json:"day_off" example:"false"`
+	Recurring bool   `json:"recurring" example:"false"`
+	CreatedAt string `json:"created_at" example:"2021-12-01T13:00:00"`
+	UpdatedAt string `json:"updated_at" example:"2021-12-01T13:00:00"`
+}
+
+// Webhook is a subscription to the events of a project, which are delivered with a POST request to its URL.
+// A webhook without events is subscribed to all the events.
+type Webhook struct {
+	ID        int64    `json:"id,string" example:"1234567812345678"`
+	ProjectID int64    `json:"project_id,string" example:"1234567812345678"`
+	URL       string   `json:"url" example:"https://example.com/webhook"`
+	Events    []string `json:"events" example:"schedule.created,job.status_changed"`
+	Active    bool     `json:"active" example:"true"`
+	CreatedAt string   `json:"created_at" example:"2021-12-01T13:00:00"`
+	UpdatedAt string   `json:"updated_at" example:"2021-12-01T13:00:00"`
+}
+
+// WebhookDelivery is a delivery of an event to a webhook, with the status "pending", "succeeded" or "failed".
+// The pending deliveries are attempted again at NextAttemptAt.
+type WebhookDelivery struct {
+	ID            int64       `json:"id,string" example:"1234567812345678"`
+	WebhookID     int64       `json:"webhook_id,string" example:"1234567812345678"`
+	Event         string      `json:"event" example:"job.status_changed"`
+	Payload       interface{} `json:"payload" swaggertype:"object,string" example:"event:job.status_changed,project_id:1234567812345678"`
+	Status        string      `json:"status" example:"succeeded"`
+	Attempts      int32       `json:"attempts" example:"1"`
+	NextAttemptAt *string     `json:"next_attempt_at" example:"2021-12-01T13:00:30"`
+	ResponseCode  *int32      `json:"response_code" example:"200"`
+	Error         *string     `json:"error" example:"Unexpected response status code 500"`
+	CreatedAt     string      `json:"created_at" example:"2021-12-01T13:00:00"`
+	UpdatedAt     string      `json:"updated_at" example:"2021-12-01T13:00:00"`
+}
+
+// VehicleTypeBreak is a break created with each vehicle of a vehicle type
+type VehicleTypeBreak struct {
+	Service     string      `json:"service" example:"00:30:00"`
+	TimeWindows [][]string  `json:"time_windows"`
+	Data        interface{} `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+}
+
+type VehicleType struct {
+	ID            int64              `json:"id,string" example:"1234567812345678"`
+	Name          string             `json:"name" example:"Van"`
+	Capacity      []int64            `json:"capacity" example:"50,25"`
+	Skills        []int32            `json:"skills" example:"1,5"`
+	TwOpen        *string            `json:"tw_open" example:"2021-12-31T23:00:00"`
+	TwClose       *string            `json:"tw_close" example:"2021-12-31T23:59:00"`
+	SpeedFactor   *float64           `json:"speed_factor" example:"1.0"`
+	MaxTasks      *int32             `json:"max_tasks" example:"20"`
+	MaxTravelTime *string            `json:"max_travel_time" example:"08:00:00"`
+	MaxDistance   *int64             `json:"max_distance" example:"200000"`
+	FixedCost     *int64             `json:"fixed_cost" example:"5000"`
+	CostPerHour   *int64             `json:"cost_per_hour" example:"3600"`
+	CostPerKm     *int64             `json:"cost_per_km" example:"100"`
+	Breaks        []VehicleTypeBreak `json:"breaks"`
+	ProjectID     int64              `json:"project_id,string" example:"1234567812345678"`
+	Data          interface{}        `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	CreatedAt     string             `json:"created_at" example:"2021-12-01T13:00:00"`
+	UpdatedAt     string             `json:"updated_at" example:"2021-12-01T13:00:00"`
 }
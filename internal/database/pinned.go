@@ -0,0 +1,110 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: pinned.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+type rowQuerier interface {
+	QueryRow(context.Context, string, ...interface{}) pgx.Row
+}
+
+// Verify that the pinned vehicles of the jobs or shipments are vehicles of the same project
+func checkPinnedVehicles(ctx context.Context, db rowQuerier, tableName string, ids []int64) error {
+	sql := fmt.Sprintf(`
+		SELECT count(*) FROM %s T JOIN vehicles V ON (T.pinned_vehicle_id = V.id)
+		WHERE T.id = ANY($1) AND (V.project_id != T.project_id OR V.deleted = TRUE)`, tableName)
+	var count int64
+	if err := db.QueryRow(ctx, sql, ids).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("Field 'pinned_vehicle_id' must be the ID of a vehicle of the same project")
+	}
+	return nil
+}
+
+// lockedTask is a task of the schedule which must keep its vehicle and its service start in the new schedule
+type lockedTask struct {
+	Type         string
+	ID           int64
+	VehicleID    int64
+	ServiceStart time.Time
+}
+
+// getLockedTasks returns the locked tasks of the current schedule of a project
+func (q *Queries) getLockedTasks(ctx context.Context, projectID int64) ([]lockedTask, error) {
+	sql := `
+		SELECT type::TEXT, id, vehicle_id, service_start FROM get_pinned_tasks($1)
+		WHERE locked ORDER BY type, id`
+	rows, err := q.db.Query(ctx, sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tasks := []lockedTask{}
+	for rows.Next() {
+		var task lockedTask
+		if err := rows.Scan(&task.Type, &task.ID, &task.VehicleID, &task.ServiceStart); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// checkLockedTasks verifies that the locked tasks are still assigned to their vehicle with the same service start in
+// the new schedule of a project, as the solver may leave them unassigned when their vehicle can not reach them in time
+func (q *Queries) checkLockedTasks(ctx context.Context, projectID int64, tasks []lockedTask) error {
+	sql := `
+		SELECT EXISTS(
+			SELECT 1 FROM schedules
+			WHERE project_id = $1 AND type = $2::STEP_TYPE AND task_id = $3 AND vehicle_id = $4
+				AND arrival + waiting_time = $5
+		)`
+	for _, task := range tasks {
+		var kept bool
+		if err := q.db.QueryRow(ctx, sql, projectID, task.Type, task.ID, task.VehicleID, task.ServiceStart).Scan(&kept); err != nil {
+			return err
+		}
+		if !kept {
+			taskType := "job"
+			if task.Type != "job" {
+				taskType = "shipment"
+			}
+			return fmt.Errorf("Locked %s %d can not keep its vehicle and its arrival time in the new schedule", taskType, task.ID)
+		}
+	}
+	return nil
+}
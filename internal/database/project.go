@@ -30,27 +30,40 @@ package database
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Georepublic/pg_scheduleserv/internal/util"
 	"github.com/jackc/pgx/v4"
 )
 
 type CreateProjectParams struct {
-	Name             *string      `json:"name" example:"Sample Project" validate:"required"`
-	DurationCalc     *string      `json:"duration_calc" example:"euclidean" validate:"omitempty,oneof=euclidean valhalla osrm"`
-	ExplorationLevel *int64       `json:"exploration_level" example:"5" validate:"omitempty,lte=5,gte=0"`
-	Timeout          *string      `json:"timeout" example:"00:10:00"`
-	MaxShift         *string      `json:"max_shift" example:"00:30:00" validate:"omitempty"`
-	Data             *interface{} `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	Name               *string      `json:"name" example:"Sample Project" validate:"required"`
+	DurationCalc       *string      `json:"duration_calc" example:"euclidean" validate:"omitempty,duration_calc"`
+	ExplorationLevel   *int64       `json:"exploration_level" example:"5" validate:"omitempty,lte=5,gte=0"`
+	Timeout            *string      `json:"timeout" example:"00:10:00"`
+	MaxShift           *string      `json:"max_shift" example:"00:30:00" validate:"omitempty"`
+	Objective          *string      `json:"objective" example:"duration" validate:"omitempty,oneof=duration vehicles cost"`
+	VehicleFixedCost   *int64       `json:"vehicle_fixed_cost" example:"0" validate:"omitempty,min=0"`
+	VehicleCostPerHour *int64       `json:"vehicle_cost_per_hour" example:"3600" validate:"omitempty,min=0"`
+	HorizonStart       *string      `json:"horizon_start" example:"2021-12-01" validate:"omitempty,datetime=2006-01-02"`
+	HorizonEnd         *string      `json:"horizon_end" example:"2021-12-07" validate:"omitempty,datetime=2006-01-02"`
+	Timezone           *string      `json:"timezone" example:"Europe/Berlin" validate:"omitempty,timezone"`
+	Data               *interface{} `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
 }
 
 type UpdateProjectParams struct {
-	Name             *string      `json:"name" example:"Sample Project"`
-	DurationCalc     *string      `json:"duration_calc" example:"euclidean" validate:"omitempty,oneof=euclidean valhalla osrm"`
-	ExplorationLevel *int64       `json:"exploration_level" example:"5" validate:"omitempty,lte=5,gte=0"`
-	Timeout          *string      `json:"timeout" example:"00:10:00"`
-	MaxShift         *string      `json:"max_shift" example:"00:30:00" validate:"omitempty"`
-	Data             *interface{} `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	Name               *string      `json:"name" example:"Sample Project"`
+	DurationCalc       *string      `json:"duration_calc" example:"euclidean" validate:"omitempty,duration_calc"`
+	ExplorationLevel   *int64       `json:"exploration_level" example:"5" validate:"omitempty,lte=5,gte=0"`
+	Timeout            *string      `json:"timeout" example:"00:10:00"`
+	MaxShift           *string      `json:"max_shift" example:"00:30:00" validate:"omitempty"`
+	Objective          *string      `json:"objective" example:"duration" validate:"omitempty,oneof=duration vehicles cost"`
+	VehicleFixedCost   *int64       `json:"vehicle_fixed_cost" example:"0" validate:"omitempty,min=0"`
+	VehicleCostPerHour *int64       `json:"vehicle_cost_per_hour" example:"3600" validate:"omitempty,min=0"`
+	HorizonStart       *string      `json:"horizon_start" example:"2021-12-01" validate:"omitempty,datetime=2006-01-02"`
+	HorizonEnd         *string      `json:"horizon_end" example:"2021-12-07" validate:"omitempty,datetime=2006-01-02"`
+	Timezone           *string      `json:"timezone" example:"Europe/Berlin" validate:"omitempty,timezone"`
+	Data               *interface{} `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
 }
 
 func (q *Queries) DBCreateProject(ctx context.Context, arg CreateProjectParams) (Project, error) {
@@ -97,6 +110,29 @@ func (q *Queries) DBDeleteProject(ctx context.Context, id int64) (Project, error
 	return scanProjectRow(row)
 }
 
+// timezoneQueries are the queries of the time zone of the project of a row, for each table
+var timezoneQueries = map[string]string{
+	"projects":       "SELECT timezone FROM projects WHERE id = $1",
+	"jobs":           "SELECT P.timezone FROM jobs J JOIN projects P ON (P.id = J.project_id) WHERE J.id = $1",
+	"shipments":      "SELECT P.timezone FROM shipments S JOIN projects P ON (P.id = S.project_id) WHERE S.id = $1",
+	"vehicles":       "SELECT P.timezone FROM vehicles V JOIN projects P ON (P.id = V.project_id) WHERE V.id = $1",
+	"vehicle_types":  "SELECT P.timezone FROM vehicle_types T JOIN projects P ON (P.id = T.project_id) WHERE T.id = $1",
+	"breaks":         "SELECT P.timezone FROM breaks B JOIN vehicles V ON (V.id = B.vehicle_id) JOIN projects P ON (P.id = V.project_id) WHERE B.id = $1",
+	"vehicle_shifts": "SELECT P.timezone FROM vehicle_shifts S JOIN vehicles V ON (V.id = S.vehicle_id) JOIN projects P ON (P.id = V.project_id) WHERE S.id = $1",
+	"webhooks":       "SELECT P.timezone FROM webhooks W JOIN projects P ON (P.id = W.project_id) WHERE W.id = $1",
+}
+
+// DBGetTimezone returns the time zone of the project of a row of the table, which is nil when it is not set
+func (q *Queries) DBGetTimezone(ctx context.Context, tableName string, id int64) (*string, error) {
+	sql, ok := timezoneQueries[tableName]
+	if !ok {
+		return nil, fmt.Errorf("Invalid table '%s'", tableName)
+	}
+	var timezone *string
+	err := q.db.QueryRow(ctx, sql, id).Scan(&timezone)
+	return timezone, util.HandleDBError(err)
+}
+
 func scanProjectRow(row pgx.Row) (Project, error) {
 	var i Project
 	err := row.Scan(
@@ -106,6 +142,12 @@ func scanProjectRow(row pgx.Row) (Project, error) {
 		&i.ExplorationLevel,
 		&i.Timeout,
 		&i.MaxShift,
+		&i.Objective,
+		&i.VehicleFixedCost,
+		&i.VehicleCostPerHour,
+		&i.HorizonStart,
+		&i.HorizonEnd,
+		&i.Timezone,
 		&i.Data,
 		&i.CreatedAt,
 		&i.UpdatedAt,
@@ -125,6 +167,12 @@ func scanProjectRows(rows pgx.Rows) ([]Project, error) {
 			&i.ExplorationLevel,
 			&i.Timeout,
 			&i.MaxShift,
+			&i.Objective,
+			&i.VehicleFixedCost,
+			&i.VehicleCostPerHour,
+			&i.HorizonStart,
+			&i.HorizonEnd,
+			&i.Timezone,
 			&i.Data,
 			&i.CreatedAt,
 			&i.UpdatedAt,
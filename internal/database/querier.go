@@ -32,6 +32,7 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/Georepublic/pg_scheduleserv/internal/util"
 )
@@ -39,11 +40,14 @@ import (
 type Querier interface {
 	// Break
 	DBCreateBreakWithTw(ctx context.Context, arg CreateBreakParams) (Break, error)
-	DBListBreaks(ctx context.Context, vehicleID int64) ([]Break, error)
+	DBListBreaks(ctx context.Context, vehicleID int64, offShift bool) ([]Break, error)
 	DBGetBreak(ctx context.Context, id int64) (Break, error)
 	DBUpdateBreakWithTw(ctx context.Context, arg UpdateBreakParams, break_id int64) (Break, error)
 	DBDeleteBreakWithTw(ctx context.Context, id int64) error
 
+	// Import
+	DBImport(ctx context.Context, arg ImportParams) (ImportResult, error)
+
 	// Job
 	DBCreateJobWithTw(ctx context.Context, arg CreateJobParams) (Job, error)
 	DBListJobs(ctx context.Context, projectID int64) ([]Job, error)
@@ -51,12 +55,18 @@ type Querier interface {
 	DBUpdateJobWithTw(ctx context.Context, arg UpdateJobParams, job_id int64) (Job, error)
 	DBDeleteJobWithTw(ctx context.Context, id int64) error
 
+	// Matrix
+	DBGetMatrix(ctx context.Context, locationIds []int64, durationCalc string) ([]int64, []int64, []int64, error)
+	DBCreateMatrix(ctx context.Context, entries []util.MatrixEntry, durationCalc string) error
+	DBClearMatrix(ctx context.Context, projectID int64) error
+
 	// Project
 	DBCreateProject(ctx context.Context, arg CreateProjectParams) (Project, error)
 	DBListProjects(ctx context.Context) ([]Project, error)
 	DBGetProject(ctx context.Context, id int64) (Project, error)
 	DBUpdateProject(ctx context.Context, arg UpdateProjectParams, project_id int64) (Project, error)
 	DBDeleteProject(ctx context.Context, id int64) (Project, error)
+	DBGetTimezone(ctx context.Context, tableName string, id int64) (*string, error)
 
 	// Schedule
 	DBCreateSchedule(ctx context.Context, id int64, fresh string) error
@@ -65,6 +75,27 @@ type Querier interface {
 	DBGetScheduleShipment(ctx context.Context, id int64) (util.ScheduleData, error)
 	DBGetScheduleVehicle(ctx context.Context, id int64) (util.ScheduleData, error)
 	DBDeleteSchedule(ctx context.Context, id int64) error
+	DBCompareSchedules(ctx context.Context, projectID int64, otherProjectID int64) (util.ScheduleComparison, error)
+	DBEditSchedule(ctx context.Context, projectID int64, arg util.ScheduleEditParams) (util.ScheduleEditData, error)
+	DBValidateSchedule(ctx context.Context, projectID int64, arg util.ScheduleValidateParams) (util.ScheduleValidation, error)
+
+	// Schedule Version
+	DBListScheduleVersions(ctx context.Context, projectID int64) ([]ScheduleVersion, error)
+	DBGetScheduleVersion(ctx context.Context, projectID int64, version int32) (ScheduleVersion, error)
+	DBGetScheduleVersionData(ctx context.Context, projectID int64, version int32) (util.ScheduleData, error)
+	DBRestoreScheduleVersion(ctx context.Context, projectID int64, version int32) (util.ScheduleData, error)
+
+	// Schedule Run
+	DBCreateScheduleRun(ctx context.Context, projectID int64, fresh bool, owner int64) (ScheduleRun, error)
+	DBGetScheduleRun(ctx context.Context, projectID int64, runID int64) (ScheduleRun, error)
+	DBListScheduleRuns(ctx context.Context, projectID int64) ([]ScheduleRun, error)
+	DBStartScheduleRun(ctx context.Context, runID int64, owner int64) (bool, error)
+	DBFinishScheduleRun(ctx context.Context, runID int64, runErr error) error
+	DBRequeueScheduleRun(ctx context.Context, runID int64) error
+	DBReleaseScheduleRuns(ctx context.Context, owner int64) error
+	DBClaimScheduleRuns(ctx context.Context, owner int64, staleAfter time.Duration, limit int) ([]ScheduleRun, error)
+	DBHeartbeatScheduleRuns(ctx context.Context, owner int64) error
+	DBFailStaleScheduleRuns(ctx context.Context, staleAfter time.Duration) error
 
 	// Shipment
 	DBCreateShipmentWithTw(ctx context.Context, arg CreateShipmentParams) (Shipment, error)
@@ -73,6 +104,11 @@ type Querier interface {
 	DBUpdateShipmentWithTw(ctx context.Context, arg UpdateShipmentParams, shipment_id int64) (Shipment, error)
 	DBDeleteShipmentWithTw(ctx context.Context, id int64) error
 
+	// Snapshot
+	DBExportProject(ctx context.Context, projectID int64, withSchedule bool) (ProjectSnapshot, error)
+	DBImportProject(ctx context.Context, snapshot ProjectSnapshot) (Project, error)
+	DBCloneProject(ctx context.Context, projectID int64, withSchedule bool) (ClonedProject, error)
+
 	// Vehicle
 	DBCreateVehicle(ctx context.Context, arg CreateVehicleParams) (Vehicle, error)
 	DBListVehicles(ctx context.Context, projectID int64) ([]Vehicle, error)
@@ -80,6 +116,33 @@ type Querier interface {
 	DBUpdateVehicle(ctx context.Context, arg UpdateVehicleParams, vehicle_id int64) (Vehicle, error)
 	DBDeleteVehicle(ctx context.Context, id int64) (Vehicle, error)
 
+	// Vehicle Type
+	DBCreateVehicleType(ctx context.Context, arg CreateVehicleTypeParams) (VehicleType, error)
+	DBListVehicleTypes(ctx context.Context, projectID int64) ([]VehicleType, error)
+	DBGetVehicleType(ctx context.Context, id int64) (VehicleType, error)
+	DBUpdateVehicleType(ctx context.Context, arg UpdateVehicleTypeParams, vehicle_type_id int64) (VehicleType, error)
+	DBDeleteVehicleType(ctx context.Context, id int64) (VehicleType, error)
+	DBCreateVehiclesOfType(ctx context.Context, vehicleTypeID int64, arg CreateVehiclesOfTypeParams) ([]Vehicle, error)
+
+	// Vehicle Shift
+	DBCreateVehicleShift(ctx context.Context, arg CreateVehicleShiftParams) (VehicleShift, error)
+	DBListVehicleShifts(ctx context.Context, vehicleID int64) ([]VehicleShift, error)
+	DBGetVehicleShift(ctx context.Context, id int64) (VehicleShift, error)
+	DBUpdateVehicleShift(ctx context.Context, arg UpdateVehicleShiftParams, shiftID int64) (VehicleShift, error)
+	DBDeleteVehicleShift(ctx context.Context, shiftID int64) error
+	DBImportVehicleShifts(ctx context.Context, vehicleID int64, shifts []util.CalendarShift, replace bool) ([]VehicleShift, error)
+
+	// Webhook
+	DBCreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
+	DBListWebhooks(ctx context.Context, projectID int64) ([]Webhook, error)
+	DBGetWebhook(ctx context.Context, id int64) (Webhook, error)
+	DBUpdateWebhook(ctx context.Context, arg UpdateWebhookParams, webhookID int64) (Webhook, error)
+	DBDeleteWebhook(ctx context.Context, webhookID int64) error
+	DBListWebhookDeliveries(ctx context.Context, webhookID int64, status string) ([]WebhookDelivery, error)
+	DBClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]PendingWebhookDelivery, error)
+	DBFinishWebhookDelivery(ctx context.Context, deliveryID int64, responseCode *int32, deliveryErr error, retryDelay *time.Duration) error
+	DBPruneWebhookDeliveries(ctx context.Context, retention time.Duration) (int64, error)
+
 	// Locations
 	DBGetProjectLocations(ctx context.Context, project_id int64) ([]int64, error)
 }
@@ -0,0 +1,432 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: recurrence.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/jackc/pgx/v4"
+)
+
+// expandRecurrences creates the occurrences of the recurring jobs of a project, and the shifts of its vehicles with a
+// shift recurrence, on the dates of the planning horizon of the project. The off-shift breaks of the vehicles are then
+// set from their available shifts.
+func (q *Queries) expandRecurrences(ctx context.Context, project Project) error {
+	var from, to time.Time
+	if project.HorizonStart != nil && project.HorizonEnd != nil {
+		var err error
+		if from, err = time.Parse(util.DateLayout, *project.HorizonStart); err != nil {
+			return err
+		}
+		if to, err = time.Parse(util.DateLayout, *project.HorizonEnd); err != nil {
+			return err
+		}
+		if err := q.expandRecurringJobs(ctx, project.ID, from, to); err != nil {
+			return err
+		}
+	}
+	return q.expandVehicleShifts(ctx, project.ID, from, to)
+}
+
+// expandRecurringJobs creates a job for each occurrence of the recurring jobs of a project between the dates from and
+// to, which does not exist yet. The occurrences between these dates which are not on a date of their recurring job
+// anymore are deleted. The other occurrences are updated with the changes of their recurring job, unless they were
+// changed by the user, in which case they are kept along with their changes.
+func (q *Queries) expandRecurringJobs(ctx context.Context, projectID int64, from time.Time, to time.Time) error {
+	jobs, err := q.DBListJobs(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	templates := []Job{}
+	occurrences := map[int64]map[string]Job{}
+	fromDay, toDay := from.Format(util.DateLayout), to.Format(util.DateLayout)
+	for _, job := range jobs {
+		if job.Recurrence != "" {
+			templates = append(templates, job)
+		}
+		// the occurrences outside of the planning horizon are kept, as they may belong to a previous horizon
+		if job.RecurringJobID != nil && job.Occurrence != nil && *job.Occurrence >= fromDay && *job.Occurrence <= toDay {
+			if occurrences[*job.RecurringJobID] == nil {
+				occurrences[*job.RecurringJobID] = map[string]Job{}
+			}
+			occurrences[*job.RecurringJobID][*job.Occurrence] = job
+		}
+	}
+
+	params := ImportParams{}
+	templateIDs, dates := []int64{}, []string{}
+	updatedIDs, updates := []int64{}, []UpdateJobParams{}
+	for _, template := range templates {
+		recurrence, err := util.ParseRecurrence(template.Recurrence)
+		if err != nil {
+			return fmt.Errorf("Job %d: %s", template.ID, err)
+		}
+		dtstart := getRecurrenceStart(template, from)
+		for _, date := range recurrence.Dates(dtstart, from, to) {
+			day := date.Format(util.DateLayout)
+			occurrenceParams := getOccurrenceParams(template, int(date.Sub(dtstart).Hours()/24))
+			if occurrence, found := occurrences[template.ID][day]; found {
+				delete(occurrences[template.ID], day)
+				if !occurrence.Overridden && !equalOccurrence(occurrence, occurrenceParams) {
+					updatedIDs = append(updatedIDs, occurrence.ID)
+					updates = append(updates, UpdateJobParams(occurrenceParams))
+				}
+				continue
+			}
+			params.Jobs = append(params.Jobs, ImportJobParams{
+				Row:             len(params.Jobs) + 1,
+				CreateJobParams: occurrenceParams,
+			})
+			templateIDs = append(templateIDs, template.ID)
+			dates = append(dates, day)
+		}
+	}
+	staleIDs := []int64{}
+	for _, days := range occurrences {
+		for _, job := range days {
+			staleIDs = append(staleIDs, job.ID)
+		}
+	}
+	if len(params.Jobs) == 0 && len(staleIDs) == 0 && len(updatedIDs) == 0 {
+		return nil
+	}
+
+	tx, err := q.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	ids, err := importRows(ctx, tx, params, func(kind string, row int) string {
+		return fmt.Sprintf("Occurrence %s of the job %d", dates[row-1], templateIDs[row-1])
+	})
+	if err != nil {
+		return err
+	}
+	sql := `
+	UPDATE jobs J SET recurring_job_id = O.recurring_job_id, occurrence = O.occurrence::DATE
+	FROM unnest($1::BIGINT[], $2::BIGINT[], $3::TEXT[]) AS O(id, recurring_job_id, occurrence)
+	WHERE J.id = O.id`
+	if _, err := tx.Exec(ctx, sql, ids.jobs, templateIDs, dates); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE jobs SET deleted = TRUE WHERE id = ANY($1)", staleIDs); err != nil {
+		return err
+	}
+	for i, id := range updatedIDs {
+		if err := New(nestedTx{tx}).updateJobWithTw(ctx, updates[i], id); err != nil {
+			return fmt.Errorf("Occurrence %d: %s", id, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// equalOccurrence returns whether the fields of an occurrence are the ones of its params from the recurring job
+func equalOccurrence(job Job, params CreateJobParams) bool {
+	var data interface{}
+	if params.Data != nil {
+		data = *params.Data
+	}
+	return *job.Location.Latitude == *params.Location.Latitude && *job.Location.Longitude == *params.Location.Longitude &&
+		job.Setup == *params.Setup && job.Service == *params.Service &&
+		reflect.DeepEqual(job.Delivery, *params.Delivery) && reflect.DeepEqual(job.Pickup, *params.Pickup) &&
+		reflect.DeepEqual(job.Skills, *params.Skills) && job.Priority == *params.Priority &&
+		reflect.DeepEqual(job.PinnedVehicleID, params.PinnedVehicleID) &&
+		reflect.DeepEqual(job.TimeWindows, *params.TimeWindows) && reflect.DeepEqual(job.Data, data)
+}
+
+// getRecurrenceStart returns the date of the first occurrence of a recurring job, which is the date of its earliest
+// time window, or the start of the planning horizon when it has no time window
+func getRecurrenceStart(template Job, from time.Time) time.Time {
+	dtstart := from
+	for i, tw := range template.TimeWindows {
+		twOpen, err := time.Parse("2006-01-02T15:04:05", tw[0])
+		if err == nil && (i == 0 || twOpen.Before(dtstart)) {
+			dtstart = twOpen
+		}
+	}
+	return time.Date(dtstart.Year(), dtstart.Month(), dtstart.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// getOccurrenceParams returns the params of an occurrence of a recurring job, whose time windows are the ones of the
+// recurring job moved by the given number of days. Without time windows, the occurrence is served on its date.
+func getOccurrenceParams(template Job, days int) CreateJobParams {
+	timeWindows := [][]string{}
+	for _, tw := range template.TimeWindows {
+		timeWindows = append(timeWindows, []string{addDays(tw[0], days), addDays(tw[1], days)})
+	}
+	if len(timeWindows) == 0 {
+		date := getRecurrenceStart(template, time.Time{}).AddDate(0, 0, days)
+		timeWindows = append(timeWindows, []string{
+			date.Format("2006-01-02T15:04:05"),
+			date.Add(24*time.Hour - time.Second).Format("2006-01-02T15:04:05"),
+		})
+	}
+	return CreateJobParams{
+		Location:        &template.Location,
+		Setup:           &template.Setup,
+		Service:         &template.Service,
+		Delivery:        &template.Delivery,
+		Pickup:          &template.Pickup,
+		Skills:          &template.Skills,
+		Priority:        &template.Priority,
+		PinnedVehicleID: template.PinnedVehicleID,
+		TimeWindows:     &timeWindows,
+		ProjectID:       &template.ProjectID,
+		Data:            getDataParam(template.Data),
+	}
+}
+
+func addDays(timestamp string, days int) string {
+	t, err := time.Parse("2006-01-02T15:04:05", timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return t.AddDate(0, 0, days).Format("2006-01-02T15:04:05")
+}
+
+// expandVehicleShifts sets the recurring shifts of the vehicles of a project with a shift recurrence between the dates
+// from and to, which are zero without a planning horizon. The off-shift breaks of each vehicle are then set between its
+// available shifts, which are its recurring shifts and the shifts of its calendar not overlapping any of its days off.
+// The recurring shifts and the breaks of a vehicle are only created again when they change.
+func (q *Queries) expandVehicleShifts(ctx context.Context, projectID int64, from time.Time, to time.Time) error {
+	vehicles, err := q.DBListVehicles(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	calendars, err := q.listProjectVehicleShifts(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	currentBreaks, err := q.listOffShiftBreaks(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	recurringIDs, breakIDs := []int64{}, []int64{}
+	shiftVehicleIDs, shiftOpens, shiftCloses := []int64{}, []string{}, []string{}
+	params := ImportParams{}
+	for _, vehicle := range vehicles {
+		recurring := []util.ShiftWindow{}
+		if vehicle.ShiftRecurrence != "" && !from.IsZero() {
+			recurring, err = util.GetRecurringShifts(vehicle.ShiftRecurrence, vehicle.TwOpen, vehicle.TwClose, from, to)
+			if err != nil {
+				return fmt.Errorf("Vehicle %d: %s", vehicle.ID, err)
+			}
+		}
+		currentRecurring, shifts, daysOff := []util.ShiftWindow{}, []util.ShiftWindow{}, []util.ShiftWindow{}
+		for _, shift := range calendars[vehicle.ID] {
+			window := util.ShiftWindow{TwOpen: shift.TwOpen, TwClose: shift.TwClose}
+			switch {
+			case shift.Recurring:
+				currentRecurring = append(currentRecurring, window)
+			case shift.DayOff:
+				daysOff = append(daysOff, window)
+			default:
+				shifts = append(shifts, window)
+			}
+		}
+		if !equalShifts(recurring, currentRecurring) {
+			recurringIDs = append(recurringIDs, vehicle.ID)
+			for _, shift := range recurring {
+				shiftVehicleIDs = append(shiftVehicleIDs, vehicle.ID)
+				shiftOpens = append(shiftOpens, shift.TwOpen)
+				shiftCloses = append(shiftCloses, shift.TwClose)
+			}
+		}
+
+		windows, services := util.GetOffShiftBreaks(util.GetAvailableShifts(append(shifts, recurring...), daysOff))
+		breaks := []offShiftBreak{}
+		for i := range windows {
+			breaks = append(breaks, offShiftBreak{Window: windows[i], Service: services[i]})
+		}
+		if equalOffShiftBreaks(breaks, currentBreaks[vehicle.ID]) {
+			continue
+		}
+		breakIDs = append(breakIDs, vehicle.ID)
+		for i := range windows {
+			vehicleID := vehicle.ID
+			params.Breaks = append(params.Breaks, ImportBreakParams{
+				Row:          len(params.Breaks) + 1,
+				VehicleIndex: -1,
+				CreateBreakParams: CreateBreakParams{
+					VehicleID:   &vehicleID,
+					Service:     &services[i],
+					TimeWindows: &[][]string{{windows[i].TwOpen, windows[i].TwClose}},
+				},
+			})
+		}
+	}
+	if len(recurringIDs) == 0 && len(breakIDs) == 0 {
+		return nil
+	}
+
+	tx, err := q.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	if _, err := tx.Exec(ctx, "DELETE FROM vehicle_shifts WHERE recurring AND vehicle_id = ANY($1)", recurringIDs); err != nil {
+		return err
+	}
+	sql := `
+	INSERT INTO vehicle_shifts (vehicle_id, tw_open, tw_close, recurring)
+	SELECT vehicle_id, tw_open::TIMESTAMP, tw_close::TIMESTAMP, TRUE
+	FROM unnest($1::BIGINT[], $2::TEXT[], $3::TEXT[]) AS S(vehicle_id, tw_open, tw_close)`
+	if _, err := tx.Exec(ctx, sql, shiftVehicleIDs, shiftOpens, shiftCloses); err != nil {
+		return err
+	}
+	deleteSQL := []string{
+		"DELETE FROM breaks_time_windows WHERE id IN (SELECT id FROM breaks WHERE off_shift AND vehicle_id = ANY($1))",
+		"DELETE FROM breaks WHERE off_shift AND vehicle_id = ANY($1)",
+	}
+	for _, sql := range deleteSQL {
+		if _, err := tx.Exec(ctx, sql, breakIDs); err != nil {
+			return err
+		}
+	}
+	ids, err := importRows(ctx, tx, params, func(kind string, row int) string {
+		return fmt.Sprintf("Off-shift break of the vehicle %d", *params.Breaks[row-1].VehicleID)
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE breaks SET off_shift = TRUE WHERE id = ANY($1)", ids.breaks); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// offShiftBreak is the time window and the service time of an off-shift break
+type offShiftBreak struct {
+	Window  util.ShiftWindow
+	Service string
+}
+
+// listProjectVehicleShifts returns the shifts and the days off of each vehicle of a project, ordered by their start
+func (q *Queries) listProjectVehicleShifts(ctx context.Context, projectID int64) (map[int64][]VehicleShift, error) {
+	tableName := "vehicle_shifts"
+	joinTableQuery := fmt.Sprintf(" JOIN vehicles V ON (V.id = %s.vehicle_id)", tableName)
+	additionalQuery := fmt.Sprintf(" WHERE V.project_id = $1 ORDER BY %s.tw_open, %s.tw_close", tableName, tableName)
+	sql := "SELECT " + util.GetOutputFields(VehicleShift{}, tableName) + " FROM " + tableName + joinTableQuery + additionalQuery
+	rows, err := q.db.Query(ctx, sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items, err := scanVehicleShiftRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	shifts := map[int64][]VehicleShift{}
+	for _, shift := range items {
+		shifts[shift.VehicleID] = append(shifts[shift.VehicleID], shift)
+	}
+	return shifts, nil
+}
+
+// listOffShiftBreaks returns the off-shift breaks of each vehicle of a project, ordered by their start
+func (q *Queries) listOffShiftBreaks(ctx context.Context, projectID int64) (map[int64][]offShiftBreak, error) {
+	sql := fmt.Sprintf(`
+	SELECT B.vehicle_id, %s, %s, %s
+	FROM breaks B
+	JOIN breaks_time_windows TW ON (TW.id = B.id)
+	JOIN vehicles V ON (V.id = B.vehicle_id)
+	WHERE V.project_id = $1 AND B.off_shift AND B.deleted = FALSE
+	ORDER BY B.vehicle_id, TW.tw_open`,
+		util.GetFormattedTimestamp("TW.tw_open"), util.GetFormattedTimestamp("TW.tw_close"), util.GetFormattedInterval("B.service"))
+	rows, err := q.db.Query(ctx, sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	breaks := map[int64][]offShiftBreak{}
+	for rows.Next() {
+		var vehicleID int64
+		var vBreak offShiftBreak
+		if err := rows.Scan(&vehicleID, &vBreak.Window.TwOpen, &vBreak.Window.TwClose, &vBreak.Service); err != nil {
+			return nil, err
+		}
+		breaks[vehicleID] = append(breaks[vehicleID], vBreak)
+	}
+	return breaks, rows.Err()
+}
+
+// getVehicleShiftsSpans returns the time window of each vehicle of a project with shifts, from the start of its
+// first available shift to the end of its last available shift, which is used instead of the time window of the vehicle
+func (q *Queries) getVehicleShiftsSpans(ctx context.Context, projectID int64) (map[int64]util.ShiftWindow, error) {
+	sql := fmt.Sprintf("SELECT vehicle_id, %s, %s FROM get_vehicle_shifts_span($1)",
+		util.GetFormattedTimestamp("tw_open"), util.GetFormattedTimestamp("tw_close"))
+	rows, err := q.db.Query(ctx, sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	spans := map[int64]util.ShiftWindow{}
+	for rows.Next() {
+		var vehicleID int64
+		var span util.ShiftWindow
+		if err := rows.Scan(&vehicleID, &span.TwOpen, &span.TwClose); err != nil {
+			return nil, err
+		}
+		spans[vehicleID] = span
+	}
+	return spans, rows.Err()
+}
+
+func equalShifts(shifts []util.ShiftWindow, other []util.ShiftWindow) bool {
+	if len(shifts) != len(other) {
+		return false
+	}
+	for i := range shifts {
+		if shifts[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalOffShiftBreaks(breaks []offShiftBreak, other []offShiftBreak) bool {
+	if len(breaks) != len(other) {
+		return false
+	}
+	for i := range breaks {
+		if breaks[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
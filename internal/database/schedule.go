@@ -37,6 +37,10 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// DBCreateSchedule schedules the project and saves the schedule as a new version. The matrix is fetched and the
+// solver runs outside of any transaction, so that the tasks of the project can be edited meanwhile. The solved
+// schedule is then saved in a short transaction under the schedule lock of the project, only when the inputs of
+// the project are unchanged, leaving the schedule and its active version unchanged when any step fails.
 func (q *Queries) DBCreateSchedule(ctx context.Context, projectID int64, fresh string) error {
 	// get the project
 	project, err := q.DBGetProject(ctx, projectID)
@@ -44,6 +48,22 @@ func (q *Queries) DBCreateSchedule(ctx context.Context, projectID int64, fresh s
 		return err
 	}
 
+	// create the occurrences of the recurring jobs and the shifts of the vehicles in the planning horizon
+	err = q.execTx(ctx, func(q *Queries) error {
+		if err := q.lockSchedule(ctx, projectID); err != nil {
+			return err
+		}
+		return q.expandRecurrences(ctx, project)
+	})
+	if err != nil {
+		return err
+	}
+
+	inputsHash, err := q.getScheduleInputsHash(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
 	// get project locations by calling DBGetProjectLocations
 	locationIds, err := q.DBGetProjectLocations(ctx, projectID)
 	if err != nil {
@@ -55,7 +75,7 @@ func (q *Queries) DBCreateSchedule(ctx context.Context, projectID int64, fresh s
 		return fmt.Errorf("No locations present in the project")
 	}
 
-	startIds, endIds, durations, err := util.GetMatrix(locationIds, project.DurationCalc)
+	startIds, endIds, durations, err := q.DBGetMatrix(ctx, locationIds, project.DurationCalc)
 	if err != nil {
 		return err
 	}
@@ -65,15 +85,119 @@ func (q *Queries) DBCreateSchedule(ctx context.Context, projectID int64, fresh s
 		return fmt.Errorf("No locations present in the project")
 	}
 
-	var query string
+	// the locked tasks of the current schedule, which must keep their vehicle and their arrival time
+	lockedTasks, err := q.getLockedTasks(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := q.solveSchedule(ctx, projectID, fresh, startIds, endIds, durations)
+	if err != nil {
+		return err
+	}
+
+	return q.execTx(ctx, func(q *Queries) error {
+		if err := q.lockSchedule(ctx, projectID); err != nil {
+			return err
+		}
+
+		// the solved schedule is outdated when the project changed while it was solved
+		currentHash, err := q.getScheduleInputsHash(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		if currentHash != inputsHash {
+			return fmt.Errorf("The project changed while it was scheduled, schedule it again")
+		}
+		return q.saveSchedule(ctx, projectID, fresh, schedule, lockedTasks)
+	})
+}
+
+// lockSchedule locks the schedule of a project until the end of the transaction, so that the schedule of a project
+// is changed one at a time, including by the other servers
+func (q *Queries) lockSchedule(ctx context.Context, projectID int64) error {
+	_, err := q.db.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", projectID)
+	return err
+}
+
+// getScheduleInputsHash returns the hash of the state of a project used to solve its schedule
+func (q *Queries) getScheduleInputsHash(ctx context.Context, projectID int64) (string, error) {
+	var hash string
+	err := q.db.QueryRow(ctx, "SELECT get_schedule_inputs_hash($1)", projectID).Scan(&hash)
+	return hash, err
+}
+
+// solveSchedule runs the solver on the project with the given matrix, and returns the rows of the solved schedule
+// without changing the schedule of the project
+func (q *Queries) solveSchedule(ctx context.Context, projectID int64, fresh string, startIds []int64, endIds []int64, durations []int64) ([]util.ScheduleDB, error) {
+	tableName := "schedules"
 	// call appropriate function based on the "fresh" parameter
+	function := "solve_schedule"
 	if fresh == "true" {
-		query = "SELECT create_fresh_schedule($1, $2, $3, $4)"
-	} else {
-		query = "SELECT create_schedule($1, $2, $3, $4)"
+		function = "solve_fresh_schedule"
 	}
-	_, err = q.db.Exec(ctx, query, projectID, startIds, endIds, durations)
-	return err
+	sql := "SELECT " + util.GetOutputFields(util.ScheduleDB{}, tableName) + " FROM " + function + "($1, $2, $3, $4) AS " + tableName
+	rows, err := q.db.Query(ctx, sql, projectID, startIds, endIds, durations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanScheduleSteps(rows)
+}
+
+// saveSchedule replaces the schedule of a project with the solved schedule, and saves it as a new version
+func (q *Queries) saveSchedule(ctx context.Context, projectID int64, fresh string, schedule []util.ScheduleDB, lockedTasks []lockedTask) error {
+	// replace the rows and update the distances of the steps using the cached matrix
+	if err := q.replaceSchedule(ctx, projectID, schedule); err != nil {
+		return err
+	}
+
+	// unassign the last tasks of the routes exceeding the max travel time or the max distance of their vehicle
+	if err := q.applyVehicleLimits(ctx, projectID); err != nil {
+		return err
+	}
+
+	// fail the schedule, leaving the previous one unchanged, when a locked task lost its vehicle or its arrival time
+	if err := q.checkLockedTasks(ctx, projectID, lockedTasks); err != nil {
+		return err
+	}
+
+	// explain why the tasks are unassigned, once for the new schedule
+	if err := q.setUnassignedReasons(ctx, projectID); err != nil {
+		return err
+	}
+
+	// save the schedule as a new version, so that it can be restored later
+	return q.createScheduleVersion(ctx, projectID, fresh == "true")
+}
+
+// applyVehicleLimits unassigns the last tasks of the routes of the schedule of a project which exceed the max travel
+// time or the max distance of their vehicle, as these limits are not given to vrp_vroom. The shortened routes are
+// computed again using the cached matrix.
+func (q *Queries) applyVehicleLimits(ctx context.Context, projectID int64) error {
+	sql := `
+	SELECT EXISTS(
+		SELECT 1 FROM vehicles
+		WHERE project_id = $1 AND deleted = FALSE AND (max_travel_time IS NOT NULL OR max_distance IS NOT NULL)
+	)`
+	var limited bool
+	if err := q.db.QueryRow(ctx, sql, projectID).Scan(&limited); err != nil || !limited {
+		return err
+	}
+
+	snapshot, err := q.DBExportProject(ctx, projectID, true)
+	if err != nil {
+		return err
+	}
+	problem, err := q.getScheduleProblem(ctx, snapshot)
+	if err != nil {
+		return err
+	}
+	schedule, removed, err := util.ApplyVehicleLimits(snapshot.Schedule, problem)
+	if err != nil || len(removed) == 0 {
+		return err
+	}
+	return q.replaceSchedule(ctx, projectID, schedule)
 }
 
 func (q *Queries) DBGetSchedule(ctx context.Context, projectID int64) (util.ScheduleData, error) {
@@ -89,8 +213,7 @@ func (q *Queries) DBGetSchedule(ctx context.Context, projectID int64) (util.Sche
 	if err != nil {
 		return util.ScheduleData{}, err
 	}
-	defer rows.Close()
-	return scanScheduleRows(rows)
+	return q.readScheduleData(ctx, rows)
 }
 
 func (q *Queries) DBGetScheduleJob(ctx context.Context, jobID int64) (util.ScheduleData, error) {
@@ -106,8 +229,7 @@ func (q *Queries) DBGetScheduleJob(ctx context.Context, jobID int64) (util.Sched
 	if err != nil {
 		return util.ScheduleData{}, err
 	}
-	defer rows.Close()
-	return scanScheduleRows(rows)
+	return q.readScheduleData(ctx, rows)
 }
 
 func (q *Queries) DBGetScheduleShipment(ctx context.Context, shipmentID int64) (util.ScheduleData, error) {
@@ -123,8 +245,7 @@ func (q *Queries) DBGetScheduleShipment(ctx context.Context, shipmentID int64) (
 	if err != nil {
 		return util.ScheduleData{}, err
 	}
-	defer rows.Close()
-	return scanScheduleRows(rows)
+	return q.readScheduleData(ctx, rows)
 }
 
 func (q *Queries) DBGetScheduleVehicle(ctx context.Context, vehicleID int64) (util.ScheduleData, error) {
@@ -140,40 +261,188 @@ func (q *Queries) DBGetScheduleVehicle(ctx context.Context, vehicleID int64) (ut
 	if err != nil {
 		return util.ScheduleData{}, err
 	}
-	defer rows.Close()
-	return scanScheduleRows(rows)
+	return q.readScheduleData(ctx, rows)
 }
 
-const deleteSchedule = `DELETE FROM schedules WHERE project_id = $1`
+// readScheduleData returns the schedule in the rows, with the reasons of the unassigned tasks, the off-shift breaks
+// marked and the costs of the routes, so that every read path of the schedule returns the same data
+func (q *Queries) readScheduleData(ctx context.Context, rows pgx.Rows) (util.ScheduleData, error) {
+	data, err := scanScheduleRows(rows)
+	rows.Close()
+	if err != nil {
+		return util.ScheduleData{}, err
+	}
+	if err := q.addUnassignedReasons(ctx, &data); err != nil {
+		return util.ScheduleData{}, err
+	}
+	if err := q.markOffShiftBreaks(ctx, &data); err != nil {
+		return util.ScheduleData{}, err
+	}
+	return data, q.addScheduleCosts(ctx, &data)
+}
 
-func (q *Queries) DBDeleteSchedule(ctx context.Context, projectID int64) error {
-	_, err := q.DBGetProject(ctx, projectID)
+// setUnassignedReasons computes the reasons of the unassigned tasks of the schedule of a project, analysing the
+// tasks against the vehicles of the project, and stores them with the schedule, so that reading the schedule does
+// not analyse the project again
+func (q *Queries) setUnassignedReasons(ctx context.Context, projectID int64) error {
+	sql := "SELECT type::TEXT, task_id FROM schedules WHERE project_id = $1 AND vehicle_id = -1"
+	rows, err := q.db.Query(ctx, sql, projectID)
 	if err != nil {
 		return err
 	}
-	_, err = q.db.Exec(ctx, deleteSchedule, projectID)
-	return err
+	stops := []util.ScheduleStop{}
+	for rows.Next() {
+		var stop util.ScheduleStop
+		if err := rows.Scan(&stop.Type, &stop.TaskID); err != nil {
+			rows.Close()
+			return err
+		}
+		stops = append(stops, stop)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || len(stops) == 0 {
+		return err
+	}
+
+	snapshot, err := q.DBExportProject(ctx, projectID, false)
+	if err != nil {
+		return err
+	}
+	problem, err := q.getScheduleProblem(ctx, snapshot)
+	if err != nil {
+		return err
+	}
+	sql = "UPDATE schedules SET reasons = $4 WHERE project_id = $1 AND type = $2::STEP_TYPE AND task_id = $3 AND vehicle_id = -1"
+	for _, stop := range stops {
+		reasons := util.GetUnassignedReasons(stop, problem)
+		if _, err := q.db.Exec(ctx, sql, projectID, stop.Type, stop.TaskID, reasons); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func scanScheduleRows(rows pgx.Rows) (util.ScheduleData, error) {
-	var projectID int64
-	schedule := []util.ScheduleResponse{}
+// addUnassignedReasons sets the reasons of the unassigned tasks of a schedule to the ones stored with the schedule
+func (q *Queries) addUnassignedReasons(ctx context.Context, data *util.ScheduleData) error {
+	if len(data.Metadata.Unassigned) == 0 {
+		return nil
+	}
+	sql := "SELECT type::TEXT, task_id, reasons FROM schedules WHERE project_id = $1 AND vehicle_id = -1 AND reasons IS NOT NULL"
+	rows, err := q.db.Query(ctx, sql, data.ProjectID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	reasons := map[util.ScheduleStop][]string{}
+	for rows.Next() {
+		var stop util.ScheduleStop
+		var stopReasons []string
+		if err := rows.Scan(&stop.Type, &stop.TaskID, &stopReasons); err != nil {
+			return err
+		}
+		reasons[stop] = stopReasons
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for i, task := range data.Metadata.Unassigned {
+		if taskReasons, found := reasons[util.ScheduleStop{Type: task.Type, TaskID: task.TaskID}]; found {
+			data.Metadata.Unassigned[i].Reasons = taskReasons
+		}
+	}
+	return nil
+}
 
-	summary := []util.ScheduleSummary{}
-	var totalSummary map[string]string = map[string]string{
-		"total_travel":  "00:00:00",
-		"total_setup":   "00:00:00",
-		"total_service": "00:00:00",
-		"total_waiting": "00:00:00",
+// markOffShiftBreaks marks the break steps of a schedule that are off-shift breaks, generated from the shifts of
+// the vehicles, so that the exports of the schedule leave them out
+func (q *Queries) markOffShiftBreaks(ctx context.Context, data *util.ScheduleData) error {
+	breakIDs := []int64{}
+	for _, schedule := range data.Schedule {
+		for _, route := range schedule.Route {
+			if route.Type == "break" {
+				breakIDs = append(breakIDs, route.TaskID)
+			}
+		}
 	}
-	unassigned := []util.ScheduleUnassigned{}
+	if len(breakIDs) == 0 {
+		return nil
+	}
+	rows, err := q.db.Query(ctx, "SELECT id FROM breaks WHERE off_shift = TRUE AND id = ANY($1)", breakIDs)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	offShift := map[int64]bool{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		offShift[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for i := range data.Schedule {
+		for j, route := range data.Schedule[i].Route {
+			if route.Type == "break" && offShift[route.TaskID] {
+				data.Schedule[i].Route[j].OffShift = true
+			}
+		}
+	}
+	return nil
+}
 
-	var route []util.ScheduleRoute
+// addScheduleCosts sets the cost of the route of each vehicle of a schedule, and the total cost, using the costs
+// of the vehicles, or the costs of the project when they are not set
+func (q *Queries) addScheduleCosts(ctx context.Context, data *util.ScheduleData) error {
+	if len(data.Metadata.Summary) == 0 {
+		return nil
+	}
+	sql := `
+	SELECT V.id, COALESCE(V.fixed_cost, P.vehicle_fixed_cost), COALESCE(V.cost_per_hour, P.vehicle_cost_per_hour), V.cost_per_km
+	FROM vehicles V JOIN projects P ON (P.id = V.project_id)
+	WHERE V.project_id = $1`
+	rows, err := q.db.Query(ctx, sql, data.ProjectID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	costs := map[int64]util.VehicleCost{}
+	for rows.Next() {
+		var vehicleID int64
+		var cost util.VehicleCost
+		if err := rows.Scan(&vehicleID, &cost.FixedCost, &cost.CostPerHour, &cost.CostPerKm); err != nil {
+			return err
+		}
+		costs[vehicleID] = cost
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	util.AddScheduleCosts(data, costs)
+	return nil
+}
 
-	var i, prevI util.ScheduleDB
-	fullSummaryFound := false
+// listScheduleRows returns the rows of the schedule of a project, as stored in the schedules table
+func (q *Queries) listScheduleRows(ctx context.Context, projectID int64) ([]util.ScheduleDB, error) {
+	tableName := "schedules"
+	filter := " WHERE project_id = $1"
+	orderBy := " ORDER BY vehicle_id, arrival, type"
+	sql := "SELECT " + util.GetOutputFields(util.ScheduleDB{}, tableName) + " FROM " + tableName + filter + orderBy
+	rows, err := q.db.Query(ctx, sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanScheduleSteps(rows)
+}
 
+// scanScheduleSteps scans the rows of a schedule, with the coordinates of their location
+func scanScheduleSteps(rows pgx.Rows) ([]util.ScheduleDB, error) {
+	items := []util.ScheduleDB{}
 	for rows.Next() {
+		var i util.ScheduleDB
 		var locationID int64
 		if err := rows.Scan(
 			&i.Type,
@@ -187,21 +456,84 @@ func scanScheduleRows(rows pgx.Rows) (util.ScheduleData, error) {
 			&i.SetupTime,
 			&i.ServiceTime,
 			&i.WaitingTime,
+			&i.Distance,
 			&i.Load,
 			&i.VehicleData,
 			&i.TaskData,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
-			return util.ScheduleData{}, err
+			return nil, err
 		}
-
 		latitude, longitude := util.GetCoordinates(locationID)
 		i.Location = util.LocationParams{
 			Latitude:  &latitude,
 			Longitude: &longitude,
 		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteSchedule = `DELETE FROM schedules WHERE project_id = $1`
+
+// DBDeleteSchedule deletes the schedule of a project in a single transaction, waiting for the concurrent changes of
+// the schedule of the same project
+func (q *Queries) DBDeleteSchedule(ctx context.Context, projectID int64) error {
+	return q.execTx(ctx, func(q *Queries) error {
+		if err := q.lockSchedule(ctx, projectID); err != nil {
+			return err
+		}
+		if _, err := q.DBGetProject(ctx, projectID); err != nil {
+			return err
+		}
+		if _, err := q.db.Exec(ctx, deleteSchedule, projectID); err != nil {
+			return err
+		}
+
+		// the saved versions are kept, but none of them is active anymore
+		sql := "UPDATE schedule_versions SET active = FALSE WHERE project_id = $1 AND active"
+		if _, err := q.db.Exec(ctx, sql, projectID); err != nil {
+			return err
+		}
+		return q.createEvent(ctx, projectID, "schedule.deleted", map[string]interface{}{})
+	})
+}
+
+func scanScheduleRows(rows pgx.Rows) (util.ScheduleData, error) {
+	steps, err := scanScheduleSteps(rows)
+	if err != nil {
+		return util.ScheduleData{}, err
+	}
+	return getScheduleData(steps), nil
+}
 
+// getScheduleData groups the rows of a schedule, ordered by vehicle, into the routes of the vehicles,
+// their summaries and the unassigned tasks
+func getScheduleData(steps []util.ScheduleDB) util.ScheduleData {
+	var projectID int64
+	schedule := []util.ScheduleResponse{}
+
+	summary := []util.ScheduleSummary{}
+	var totalSummary map[string]string = map[string]string{
+		"total_travel":  "00:00:00",
+		"total_setup":   "00:00:00",
+		"total_service": "00:00:00",
+		"total_waiting": "00:00:00",
+	}
+	var totalDistance int64
+	unassigned := []util.ScheduleUnassigned{}
+
+	var route []util.ScheduleRoute
+	var cumulativeDistance int64
+
+	var prevI util.ScheduleDB
+	fullSummaryFound := false
+
+	for _, i := range steps {
 		if i.VehicleID > 0 && i.Type != "summary" {
 			// Complete schedule of tasks
 			currentRoute := util.ScheduleRoute{
@@ -214,11 +546,17 @@ func scanScheduleRows(rows pgx.Rows) (util.ScheduleData, error) {
 				SetupTime:   i.SetupTime,
 				ServiceTime: i.ServiceTime,
 				WaitingTime: i.WaitingTime,
+				Distance:    i.Distance,
 				Load:        i.Load,
 				TaskData:    i.TaskData,
 				CreatedAt:   i.CreatedAt,
 				UpdatedAt:   i.UpdatedAt,
 			}
+			if i.VehicleID != prevI.VehicleID {
+				cumulativeDistance = 0
+			}
+			cumulativeDistance += i.Distance
+			currentRoute.CumulativeDistance = cumulativeDistance
 			if i.VehicleID == prevI.VehicleID {
 				route = append(route, currentRoute)
 			} else {
@@ -236,12 +574,13 @@ func scanScheduleRows(rows pgx.Rows) (util.ScheduleData, error) {
 		} else if i.VehicleID > 0 {
 			// Schedule summary for a vehicle
 			summary = append(summary, util.ScheduleSummary{
-				VehicleID:   i.VehicleID,
-				TravelTime:  i.TravelTime,
-				SetupTime:   i.SetupTime,
-				ServiceTime: i.ServiceTime,
-				WaitingTime: i.WaitingTime,
-				VehicleData: i.VehicleData,
+				VehicleID:     i.VehicleID,
+				TravelTime:    i.TravelTime,
+				SetupTime:     i.SetupTime,
+				ServiceTime:   i.ServiceTime,
+				WaitingTime:   i.WaitingTime,
+				TotalDistance: i.Distance,
+				VehicleData:   i.VehicleData,
 			})
 		} else if i.VehicleID == 0 {
 			fullSummaryFound = true
@@ -252,6 +591,7 @@ func scanScheduleRows(rows pgx.Rows) (util.ScheduleData, error) {
 				"total_service": i.ServiceTime,
 				"total_waiting": i.WaitingTime,
 			}
+			totalDistance = i.Distance
 		} else if i.VehicleID == -1 {
 			// Unassigned tasks
 			unassigned = append(unassigned, util.ScheduleUnassigned{
@@ -259,15 +599,13 @@ func scanScheduleRows(rows pgx.Rows) (util.ScheduleData, error) {
 				TaskID:   i.TaskID,
 				Location: i.Location,
 				TaskData: i.TaskData,
+				Reasons:  []string{},
 			})
 		} else {
 			logrus.Error("Got Invalid Schedule Response")
 		}
 		projectID = i.ProjectID
 	}
-	if err := rows.Err(); err != nil {
-		return util.ScheduleData{}, err
-	}
 
 	if route != nil {
 		schedule = append(schedule, util.ScheduleResponse{
@@ -287,19 +625,21 @@ func scanScheduleRows(rows pgx.Rows) (util.ScheduleData, error) {
 			"total_service": summary[0].ServiceTime,
 			"total_waiting": summary[0].WaitingTime,
 		}
+		totalDistance = summary[0].TotalDistance
 	}
 
 	items := util.ScheduleData{
 		Schedule: schedule,
 		Metadata: util.MetadataResponse{
-			Summary:      summary,
-			Unassigned:   unassigned,
-			TotalTravel:  totalSummary["total_travel"],
-			TotalSetup:   totalSummary["total_setup"],
-			TotalService: totalSummary["total_service"],
-			TotalWaiting: totalSummary["total_waiting"],
+			Summary:       summary,
+			Unassigned:    unassigned,
+			TotalTravel:   totalSummary["total_travel"],
+			TotalSetup:    totalSummary["total_setup"],
+			TotalService:  totalSummary["total_service"],
+			TotalWaiting:  totalSummary["total_waiting"],
+			TotalDistance: totalDistance,
 		},
 		ProjectID: projectID,
 	}
-	return items, nil
+	return items
 }
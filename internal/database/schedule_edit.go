@@ -0,0 +1,237 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_edit.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/hashicorp/go-multierror"
+	"github.com/jackc/pgx/v4"
+)
+
+// DBEditSchedule applies the operations to the schedule of a project, computing again the edited routes with
+// the cached matrix. The edit is rejected when the edited routes do not satisfy the constraints of the tasks
+// and the vehicles, unless it is forced, in which case the violations are returned as warnings. The edited
+// schedule and its version are saved in a single transaction, under the schedule lock of the project.
+func (q *Queries) DBEditSchedule(ctx context.Context, projectID int64, arg util.ScheduleEditParams) (util.ScheduleEditData, error) {
+	var data util.ScheduleEditData
+	err := q.execTx(ctx, func(q *Queries) error {
+		if err := q.lockSchedule(ctx, projectID); err != nil {
+			return err
+		}
+		snapshot, err := q.DBExportProject(ctx, projectID, true)
+		if err != nil {
+			return err
+		}
+		problem, err := q.getScheduleProblem(ctx, snapshot)
+		if err != nil {
+			return err
+		}
+
+		schedule, warnings, err := util.EditSchedule(snapshot.Schedule, problem, arg.Operations)
+		if err != nil {
+			return err
+		}
+		if len(warnings) != 0 && !arg.Force {
+			var errs error
+			for _, warning := range warnings {
+				errs = multierror.Append(errs, errors.New(warning))
+			}
+			return errs
+		}
+
+		if err := q.replaceSchedule(ctx, projectID, schedule); err != nil {
+			return err
+		}
+		if err := q.setUnassignedReasons(ctx, projectID); err != nil {
+			return err
+		}
+
+		// save the edited schedule as a new version, so that the edit can be undone
+		if err := q.createScheduleVersion(ctx, projectID, false); err != nil {
+			return err
+		}
+		scheduleData, err := q.DBGetSchedule(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		data = util.ScheduleEditData{ScheduleData: scheduleData, Warnings: warnings}
+		return nil
+	})
+	return data, err
+}
+
+// replaceSchedule replaces the rows of the schedule of a project in a transaction, updating the distances
+// of the steps using the cached matrix
+func (q *Queries) replaceSchedule(ctx context.Context, projectID int64, schedule []util.ScheduleDB) error {
+	tx, err := q.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if _, err := tx.Exec(ctx, deleteSchedule, projectID); err != nil {
+		return err
+	}
+	if err := createScheduleRows(ctx, tx, projectID, schedule); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "SELECT update_schedule_distances($1)", projectID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// getScheduleProblem returns the vehicles and the tasks of the snapshot of a project, along with the durations
+// and the distances between all the locations of the project
+func (q *Queries) getScheduleProblem(ctx context.Context, snapshot ProjectSnapshot) (util.ScheduleProblem, error) {
+	problem := util.ScheduleProblem{
+		Vehicles:  map[int64]util.ScheduleVehicle{},
+		Tasks:     map[util.ScheduleStop]util.ScheduleTask{},
+		Durations: map[[2]int64]int64{},
+		Distances: map[[2]int64]int64{},
+	}
+	spans, err := q.getVehicleShiftsSpans(ctx, snapshot.Project.ID)
+	if err != nil {
+		return problem, err
+	}
+	for _, vehicle := range snapshot.Vehicles {
+		if span, found := spans[vehicle.ID]; found {
+			vehicle.TwOpen, vehicle.TwClose = span.TwOpen, span.TwClose
+		}
+		problem.Vehicles[vehicle.ID] = util.ScheduleVehicle{
+			StartLocationID: getLocationID(vehicle.StartLocation),
+			EndLocationID:   getLocationID(vehicle.EndLocation),
+			Capacity:        vehicle.Capacity,
+			Skills:          vehicle.Skills,
+			TwOpen:          vehicle.TwOpen,
+			TwClose:         vehicle.TwClose,
+			SpeedFactor:     vehicle.SpeedFactor,
+			MaxTasks:        vehicle.MaxTasks,
+			MaxTravelTime:   getMaxTravelTime(vehicle.MaxTravelTime),
+			MaxDistance:     getMaxDistance(vehicle.MaxDistance),
+			Data:            vehicle.Data,
+		}
+	}
+	for _, job := range snapshot.Jobs {
+		if job.Recurrence != "" {
+			continue
+		}
+		problem.Tasks[util.ScheduleStop{Type: "job", TaskID: job.ID}] = util.ScheduleTask{
+			LocationID:  getLocationID(job.Location),
+			Setup:       job.Setup,
+			Service:     job.Service,
+			Delivery:    job.Delivery,
+			Pickup:      job.Pickup,
+			Skills:      job.Skills,
+			TimeWindows: job.TimeWindows,
+			VehicleID:   getPinnedVehicleID(job.PinnedVehicleID),
+			Locked:      job.Locked,
+			Data:        job.Data,
+		}
+	}
+	for _, shipment := range snapshot.Shipments {
+		problem.Tasks[util.ScheduleStop{Type: "pickup", TaskID: shipment.ID}] = util.ScheduleTask{
+			LocationID:  getLocationID(shipment.PLocation),
+			Setup:       shipment.PSetup,
+			Service:     shipment.PService,
+			Pickup:      shipment.Amount,
+			Skills:      shipment.Skills,
+			TimeWindows: shipment.PTimeWindows,
+			VehicleID:   getPinnedVehicleID(shipment.PinnedVehicleID),
+			Locked:      shipment.Locked,
+			Data:        shipment.PData,
+		}
+		problem.Tasks[util.ScheduleStop{Type: "delivery", TaskID: shipment.ID}] = util.ScheduleTask{
+			LocationID:  getLocationID(shipment.DLocation),
+			Setup:       shipment.DSetup,
+			Service:     shipment.DService,
+			Delivery:    shipment.Amount,
+			Skills:      shipment.Skills,
+			TimeWindows: shipment.DTimeWindows,
+			VehicleID:   getPinnedVehicleID(shipment.PinnedVehicleID),
+			Locked:      shipment.Locked,
+			Data:        shipment.DData,
+		}
+	}
+	for _, vBreak := range snapshot.Breaks {
+		problem.Tasks[util.ScheduleStop{Type: "break", TaskID: vBreak.ID}] = util.ScheduleTask{
+			Service:     vBreak.Service,
+			TimeWindows: vBreak.TimeWindows,
+			VehicleID:   vBreak.VehicleID,
+			Data:        vBreak.Data,
+		}
+	}
+
+	locationIDs, err := q.DBGetProjectLocations(ctx, snapshot.Project.ID)
+	if err != nil || len(locationIDs) == 0 {
+		return problem, err
+	}
+	startIDs, endIDs, durations, err := q.DBGetMatrix(ctx, locationIDs, snapshot.Project.DurationCalc)
+	if err != nil {
+		return problem, err
+	}
+	for i := range durations {
+		problem.Durations[[2]int64{startIDs[i], endIDs[i]}] = durations[i]
+	}
+	problem.Distances, err = q.getMatrixDistances(ctx, locationIDs, snapshot.Project.DurationCalc)
+	return problem, err
+}
+
+func getLocationID(location util.LocationParams) int64 {
+	return util.GetLocationId(*location.Latitude, *location.Longitude)
+}
+
+func getPinnedVehicleID(pinnedVehicleID *int64) int64 {
+	if pinnedVehicleID == nil {
+		return 0
+	}
+	return *pinnedVehicleID
+}
+
+// getMaxTravelTime returns the max travel time of a vehicle in seconds, or 0 when it is not limited
+func getMaxTravelTime(maxTravelTime *string) int64 {
+	if maxTravelTime == nil {
+		return 0
+	}
+	seconds, _ := util.ParseDuration(*maxTravelTime)
+	return seconds
+}
+
+// getMaxDistance returns the max distance of a vehicle in meters, or 0 when it is not limited
+func getMaxDistance(maxDistance *int64) int64 {
+	if maxDistance == nil {
+		return 0
+	}
+	return *maxDistance
+}
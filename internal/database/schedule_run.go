@@ -0,0 +1,216 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_run.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/jackc/pgx/v4"
+)
+
+// DBCreateScheduleRun creates a queued run of the project, owned by the server which executes it
+func (q *Queries) DBCreateScheduleRun(ctx context.Context, projectID int64, fresh bool, owner int64) (ScheduleRun, error) {
+	tableName := "schedule_runs"
+	_, err := q.DBGetProject(ctx, projectID)
+	if err != nil {
+		return ScheduleRun{}, err
+	}
+	sql := "INSERT INTO " + tableName + " (project_id, fresh, owner) VALUES ($1, $2, $3)"
+	return_sql := " RETURNING " + util.GetOutputFields(ScheduleRun{}, tableName)
+	row := q.db.QueryRow(ctx, sql+return_sql, projectID, fresh, owner)
+	return scanScheduleRunRow(row)
+}
+
+func (q *Queries) DBGetScheduleRun(ctx context.Context, projectID int64, runID int64) (ScheduleRun, error) {
+	tableName := "schedule_runs"
+	_, err := q.DBGetProject(ctx, projectID)
+	if err != nil {
+		return ScheduleRun{}, err
+	}
+	additionalQuery := " WHERE id = $1 AND project_id = $2 LIMIT 1"
+	sql := "SELECT " + util.GetOutputFields(ScheduleRun{}, tableName) + " FROM " + tableName + additionalQuery
+	row := q.db.QueryRow(ctx, sql, runID, projectID)
+	return scanScheduleRunRow(row)
+}
+
+func (q *Queries) DBListScheduleRuns(ctx context.Context, projectID int64) ([]ScheduleRun, error) {
+	tableName := "schedule_runs"
+	_, err := q.DBGetProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	additionalQuery := " WHERE project_id = $1 ORDER BY created_at DESC"
+	sql := "SELECT " + util.GetOutputFields(ScheduleRun{}, tableName) + " FROM " + tableName + additionalQuery
+	rows, err := q.db.Query(ctx, sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanScheduleRunRows(rows)
+}
+
+const startScheduleRun = `
+	UPDATE schedule_runs SET status = 'running', started_at = current_timestamp
+	WHERE id = $1 AND status = 'queued' AND owner = $2`
+
+// Mark the run as running, started is false when the run is not queued on the server anymore, as it was marked as
+// failed or released to the other servers
+func (q *Queries) DBStartScheduleRun(ctx context.Context, runID int64, owner int64) (bool, error) {
+	tag, err := q.db.Exec(ctx, startScheduleRun, runID, owner)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+const finishScheduleRun = `
+	UPDATE schedule_runs SET status = $2, error = $3, finished_at = current_timestamp
+	WHERE id = $1 AND status IN ('queued', 'running')`
+
+// Mark the run as succeeded, or as failed with the error message when runErr is not nil
+func (q *Queries) DBFinishScheduleRun(ctx context.Context, runID int64, runErr error) error {
+	status := "succeeded"
+	var errMsg *string
+	if runErr != nil {
+		status = "failed"
+		msg := runErr.Error()
+		errMsg = &msg
+	}
+	_, err := q.db.Exec(ctx, finishScheduleRun, runID, status, errMsg)
+	return err
+}
+
+const heartbeatScheduleRuns = `
+	UPDATE schedule_runs SET heartbeat_at = current_timestamp
+	WHERE owner = $1 AND status IN ('queued', 'running')`
+
+// Record the heartbeat of the runs which are queued or running on the server
+func (q *Queries) DBHeartbeatScheduleRuns(ctx context.Context, owner int64) error {
+	_, err := q.db.Exec(ctx, heartbeatScheduleRuns, owner)
+	return err
+}
+
+const requeueScheduleRun = `
+	UPDATE schedule_runs SET status = 'queued', started_at = NULL, owner = NULL
+	WHERE id = $1 AND status = 'running'`
+
+// Mark the running run as queued again without owner, so that it is executed by another server
+func (q *Queries) DBRequeueScheduleRun(ctx context.Context, runID int64) error {
+	_, err := q.db.Exec(ctx, requeueScheduleRun, runID)
+	return err
+}
+
+const releaseScheduleRuns = `
+	UPDATE schedule_runs SET owner = NULL
+	WHERE owner = $1 AND status = 'queued'`
+
+// Release the queued runs of the server, so that they are executed by another server
+func (q *Queries) DBReleaseScheduleRuns(ctx context.Context, owner int64) error {
+	_, err := q.db.Exec(ctx, releaseScheduleRuns, owner)
+	return err
+}
+
+const claimScheduleRuns = `
+	UPDATE schedule_runs SET owner = $1, heartbeat_at = current_timestamp
+	WHERE id IN (
+		SELECT id FROM schedule_runs
+		WHERE status = 'queued' AND (owner IS NULL OR heartbeat_at < current_timestamp - make_interval(secs => $2))
+		ORDER BY created_at
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	)`
+
+// Claim at most limit queued runs which were released, or whose server has not recorded a heartbeat for the
+// staleAfter duration, so that they are executed by the server, oldest first
+func (q *Queries) DBClaimScheduleRuns(ctx context.Context, owner int64, staleAfter time.Duration, limit int) ([]ScheduleRun, error) {
+	tableName := "schedule_runs"
+	return_sql := " RETURNING " + util.GetOutputFields(ScheduleRun{}, tableName)
+	rows, err := q.db.Query(ctx, claimScheduleRuns+return_sql, owner, staleAfter.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanScheduleRunRows(rows)
+}
+
+const failStaleScheduleRuns = `
+	UPDATE schedule_runs
+	SET status = 'failed', error = 'The server was stopped before the run was finished', finished_at = current_timestamp
+	WHERE status = 'running' AND heartbeat_at < current_timestamp - make_interval(secs => $1)`
+
+// Mark the running runs as failed when their server has not recorded a heartbeat for the staleAfter duration, as
+// they were left unfinished by a stopped server
+func (q *Queries) DBFailStaleScheduleRuns(ctx context.Context, staleAfter time.Duration) error {
+	_, err := q.db.Exec(ctx, failStaleScheduleRuns, staleAfter.Seconds())
+	return err
+}
+
+func scanScheduleRunRow(row pgx.Row) (ScheduleRun, error) {
+	var i ScheduleRun
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Status,
+		&i.Fresh,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	err = util.HandleDBError(err)
+	return i, err
+}
+
+func scanScheduleRunRows(rows pgx.Rows) ([]ScheduleRun, error) {
+	items := []ScheduleRun{}
+	for rows.Next() {
+		var i ScheduleRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Status,
+			&i.Fresh,
+			&i.Error,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
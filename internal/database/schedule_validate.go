@@ -0,0 +1,65 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_validate.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+)
+
+// DBValidateSchedule simulates the proposed routes against the jobs, shipments, vehicles and breaks of a project,
+// using the cached matrix, and returns the computed timings along with the constraints which are not satisfied.
+// The schedule of the project is not modified, and the distances of the simulated routes are not computed.
+func (q *Queries) DBValidateSchedule(ctx context.Context, projectID int64, arg util.ScheduleValidateParams) (util.ScheduleValidation, error) {
+	snapshot, err := q.DBExportProject(ctx, projectID, false)
+	if err != nil {
+		return util.ScheduleValidation{}, err
+	}
+	problem, err := q.getScheduleProblem(ctx, snapshot)
+	if err != nil {
+		return util.ScheduleValidation{}, err
+	}
+
+	schedule, violations, err := util.SimulateSchedule(arg.Routes, problem)
+	if err != nil {
+		return util.ScheduleValidation{}, err
+	}
+	data := getScheduleData(schedule)
+	data.ProjectID = projectID
+	util.AddUnassignedReasons(data.Metadata.Unassigned, problem)
+	if err := q.addScheduleCosts(ctx, &data); err != nil {
+		return util.ScheduleValidation{}, err
+	}
+	return util.ScheduleValidation{
+		ScheduleData: data,
+		Feasible:     len(violations) == 0,
+		Violations:   violations,
+	}, nil
+}
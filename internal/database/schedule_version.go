@@ -0,0 +1,225 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_version.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/jackc/pgx/v4"
+)
+
+// scheduleStepFields are the columns of the schedules table, which are copied in the schedule_version_steps table
+const scheduleStepFields = `type, project_id, vehicle_id, task_id, location_id, arrival, departure, travel_time,
+	setup_time, service_time, waiting_time, distance, load, vehicle_data, task_data, created_at, updated_at`
+
+const createScheduleVersion = `
+	INSERT INTO schedule_versions (project_id, version, inputs_hash, fresh, settings, metadata, active)
+	VALUES (
+		$1, (SELECT COALESCE(MAX(version), 0) + 1 FROM schedule_versions WHERE project_id = $1),
+		get_project_inputs_hash($1), $2, $3, $4, TRUE
+	)
+	RETURNING version`
+
+// createScheduleVersion saves the current schedule of the project as a new version, and makes it the active version
+func (q *Queries) createScheduleVersion(ctx context.Context, projectID int64, fresh bool) error {
+	project, err := q.DBGetProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	schedule, err := q.DBGetSchedule(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	settings := ScheduleSettings{
+		DurationCalc:       project.DurationCalc,
+		ExplorationLevel:   project.ExplorationLevel,
+		Timeout:            project.Timeout,
+		MaxShift:           project.MaxShift,
+		Objective:          project.Objective,
+		VehicleFixedCost:   project.VehicleFixedCost,
+		VehicleCostPerHour: project.VehicleCostPerHour,
+	}
+
+	tx, err := q.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Lock the project, so that the versions are numbered sequentially
+	if _, err := tx.Exec(ctx, "SELECT id FROM projects WHERE id = $1 FOR UPDATE", projectID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE schedule_versions SET active = FALSE WHERE project_id = $1 AND active", projectID); err != nil {
+		return err
+	}
+	var version int32
+	if err := tx.QueryRow(ctx, createScheduleVersion, projectID, fresh, settings, schedule.Metadata).Scan(&version); err != nil {
+		return err
+	}
+	sql := fmt.Sprintf(
+		"INSERT INTO schedule_version_steps (version, %s) SELECT $2, %s FROM schedules WHERE project_id = $1",
+		scheduleStepFields, scheduleStepFields,
+	)
+	if _, err := tx.Exec(ctx, sql, projectID, version); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (q *Queries) DBListScheduleVersions(ctx context.Context, projectID int64) ([]ScheduleVersion, error) {
+	tableName := "schedule_versions"
+	_, err := q.DBGetProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	additionalQuery := " WHERE project_id = $1 ORDER BY version DESC"
+	sql := "SELECT " + util.GetOutputFields(ScheduleVersion{}, tableName) + " FROM " + tableName + additionalQuery
+	rows, err := q.db.Query(ctx, sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanScheduleVersionRows(rows)
+}
+
+func (q *Queries) DBGetScheduleVersion(ctx context.Context, projectID int64, version int32) (ScheduleVersion, error) {
+	tableName := "schedule_versions"
+	_, err := q.DBGetProject(ctx, projectID)
+	if err != nil {
+		return ScheduleVersion{}, err
+	}
+	additionalQuery := " WHERE project_id = $1 AND version = $2 LIMIT 1"
+	sql := "SELECT " + util.GetOutputFields(ScheduleVersion{}, tableName) + " FROM " + tableName + additionalQuery
+	row := q.db.QueryRow(ctx, sql, projectID, version)
+	return scanScheduleVersionRow(row)
+}
+
+// DBGetScheduleVersionData returns the schedule saved in a version, in the same format as the schedule of the project
+func (q *Queries) DBGetScheduleVersionData(ctx context.Context, projectID int64, version int32) (util.ScheduleData, error) {
+	if _, err := q.DBGetScheduleVersion(ctx, projectID, version); err != nil {
+		return util.ScheduleData{}, err
+	}
+	tableName := "schedule_version_steps"
+	filter := " WHERE project_id = $1 AND version = $2"
+	orderBy := " ORDER BY vehicle_id, arrival, type"
+	sql := "SELECT " + util.GetOutputFields(util.ScheduleDB{}, tableName) + " FROM " + tableName + filter + orderBy
+	rows, err := q.db.Query(ctx, sql, projectID, version)
+	if err != nil {
+		return util.ScheduleData{}, err
+	}
+	data, err := scanScheduleRows(rows)
+	rows.Close()
+	if err != nil {
+		return util.ScheduleData{}, err
+	}
+	return data, q.addScheduleCosts(ctx, &data)
+}
+
+// DBRestoreScheduleVersion replaces the schedule of the project with the schedule saved in a version, and makes it
+// the active version, in a single transaction. The status of the jobs and shipments is reset by the triggers of the
+// schedules table.
+func (q *Queries) DBRestoreScheduleVersion(ctx context.Context, projectID int64, version int32) (util.ScheduleData, error) {
+	var data util.ScheduleData
+	err := q.execTx(ctx, func(q *Queries) error {
+		if err := q.lockSchedule(ctx, projectID); err != nil {
+			return err
+		}
+		if _, err := q.DBGetScheduleVersion(ctx, projectID, version); err != nil {
+			return err
+		}
+		if _, err := q.db.Exec(ctx, deleteSchedule, projectID); err != nil {
+			return err
+		}
+		sql := fmt.Sprintf(
+			"INSERT INTO schedules (%s) SELECT %s FROM schedule_version_steps WHERE project_id = $1 AND version = $2",
+			scheduleStepFields, scheduleStepFields,
+		)
+		if _, err := q.db.Exec(ctx, sql, projectID, version); err != nil {
+			return err
+		}
+		if err := q.setUnassignedReasons(ctx, projectID); err != nil {
+			return err
+		}
+		sql = "UPDATE schedule_versions SET active = (version = $2) WHERE project_id = $1 AND active != (version = $2)"
+		if _, err := q.db.Exec(ctx, sql, projectID, version); err != nil {
+			return err
+		}
+		var err error
+		data, err = q.DBGetSchedule(ctx, projectID)
+		return err
+	})
+	return data, err
+}
+
+func scanScheduleVersionRow(row pgx.Row) (ScheduleVersion, error) {
+	var i ScheduleVersion
+	err := row.Scan(
+		&i.ProjectID,
+		&i.Version,
+		&i.InputsHash,
+		&i.Fresh,
+		&i.Settings,
+		&i.Metadata,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	err = util.HandleDBError(err)
+	return i, err
+}
+
+func scanScheduleVersionRows(rows pgx.Rows) ([]ScheduleVersion, error) {
+	items := []ScheduleVersion{}
+	for rows.Next() {
+		var i ScheduleVersion
+		if err := rows.Scan(
+			&i.ProjectID,
+			&i.Version,
+			&i.InputsHash,
+			&i.Fresh,
+			&i.Settings,
+			&i.Metadata,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -37,37 +37,41 @@ import (
 )
 
 type CreateShipmentParams struct {
-	PLocation    *util.LocationParams `json:"p_location" validate:"required"`
-	PSetup       *string              `json:"p_setup"    validate:"omitempty" example:"00:00:00"`
-	PService     *string              `json:"p_service"  validate:"omitempty" example:"00:02:00"`
-	DLocation    *util.LocationParams `json:"d_location" validate:"required"`
-	DSetup       *string              `json:"d_setup"    validate:"omitempty" example:"00:00:00"`
-	DService     *string              `json:"d_service"  validate:"omitempty" example:"00:02:00"`
-	Amount       *[]int64             `json:"amount"     validate:"omitempty,dive,min=0" example:"5,15"`
-	Skills       *[]int32             `json:"skills"     validate:"omitempty,dive,min=0" example:"1,5"`
-	PTimeWindows *[][]string          `json:"p_time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
-	DTimeWindows *[][]string          `json:"d_time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
-	Priority     *int32               `json:"priority"   validate:"omitempty,min=0,max=100" example:"10"`
-	ProjectID    *int64               `json:"project_id,string" validate:"required" swaggerignore:"true"`
-	PData        *interface{}         `json:"p_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
-	DData        *interface{}         `json:"d_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	PLocation       *util.LocationParams `json:"p_location" validate:"required"`
+	PSetup          *string              `json:"p_setup"    validate:"omitempty" example:"00:00:00"`
+	PService        *string              `json:"p_service"  validate:"omitempty" example:"00:02:00"`
+	DLocation       *util.LocationParams `json:"d_location" validate:"required"`
+	DSetup          *string              `json:"d_setup"    validate:"omitempty" example:"00:00:00"`
+	DService        *string              `json:"d_service"  validate:"omitempty" example:"00:02:00"`
+	Amount          *[]int64             `json:"amount"     validate:"omitempty,dive,min=0" example:"5,15"`
+	Skills          *[]int32             `json:"skills"     validate:"omitempty,dive,skill" example:"1,5"`
+	PTimeWindows    *[][]string          `json:"p_time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
+	DTimeWindows    *[][]string          `json:"d_time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
+	Priority        *int32               `json:"priority"   validate:"omitempty,min=0,max=100" example:"10"`
+	PinnedVehicleID *int64               `json:"pinned_vehicle_id,string" validate:"omitempty,min=0" example:"1234567812345678"`
+	Locked          *bool                `json:"locked" example:"false"`
+	ProjectID       *int64               `json:"project_id,string" validate:"required" swaggerignore:"true"`
+	PData           *interface{}         `json:"p_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	DData           *interface{}         `json:"d_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
 }
 
 type UpdateShipmentParams struct {
-	PLocation    *util.LocationParams `json:"p_location"`
-	PSetup       *string              `json:"p_setup"    validate:"omitempty" example:"00:00:00"`
-	PService     *string              `json:"p_service"  validate:"omitempty" example:"00:02:00"`
-	DLocation    *util.LocationParams `json:"d_location"`
-	DSetup       *string              `json:"d_setup"    validate:"omitempty" example:"00:00:00"`
-	DService     *string              `json:"d_service"  validate:"omitempty" example:"00:02:00"`
-	Amount       *[]int64             `json:"amount"     validate:"omitempty,dive,min=0" example:"5,15"`
-	Skills       *[]int32             `json:"skills"     validate:"omitempty,dive,min=0" example:"1,5"`
-	PTimeWindows *[][]string          `json:"p_time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
-	DTimeWindows *[][]string          `json:"d_time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
-	Priority     *int32               `json:"priority"   validate:"omitempty,min=0,max=100" example:"10"`
-	ProjectID    *int64               `json:"project_id,string" swaggerignore:"true"`
-	PData        *interface{}         `json:"p_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
-	DData        *interface{}         `json:"d_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	PLocation       *util.LocationParams `json:"p_location"`
+	PSetup          *string              `json:"p_setup"    validate:"omitempty" example:"00:00:00"`
+	PService        *string              `json:"p_service"  validate:"omitempty" example:"00:02:00"`
+	DLocation       *util.LocationParams `json:"d_location"`
+	DSetup          *string              `json:"d_setup"    validate:"omitempty" example:"00:00:00"`
+	DService        *string              `json:"d_service"  validate:"omitempty" example:"00:02:00"`
+	Amount          *[]int64             `json:"amount"     validate:"omitempty,dive,min=0" example:"5,15"`
+	Skills          *[]int32             `json:"skills"     validate:"omitempty,dive,skill" example:"1,5"`
+	PTimeWindows    *[][]string          `json:"p_time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
+	DTimeWindows    *[][]string          `json:"d_time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
+	Priority        *int32               `json:"priority"   validate:"omitempty,min=0,max=100" example:"10"`
+	PinnedVehicleID *int64               `json:"pinned_vehicle_id,string" validate:"omitempty,min=0" example:"1234567812345678"`
+	Locked          *bool                `json:"locked" example:"false"`
+	ProjectID       *int64               `json:"project_id,string" swaggerignore:"true"`
+	PData           *interface{}         `json:"p_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	DData           *interface{}         `json:"d_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
 }
 
 func (q *Queries) DBCreateShipment(ctx context.Context, arg CreateShipmentParams) (int64, error) {
@@ -135,6 +139,9 @@ func (q *Queries) DBCreateShipmentWithTw(ctx context.Context, arg CreateShipment
 		if err != nil {
 			return 0, err
 		}
+		if err := checkPinnedVehicles(ctx, q.db, "shipments", []int64{id}); err != nil {
+			return 0, err
+		}
 
 		// create time windows from arg and pass to DBCreateShipmentTimeWindows
 		timeWindows := []ShipmentTimeWindowParams{}
@@ -171,6 +178,9 @@ func (q *Queries) DBUpdateShipmentWithTw(ctx context.Context, arg UpdateShipment
 		if err := q.DBUpdateShipment(ctx, arg, shipment_id); err != nil {
 			return err
 		}
+		if err := checkPinnedVehicles(ctx, q.db, "shipments", []int64{shipment_id}); err != nil {
+			return err
+		}
 		// delete all time windows
 		if err := q.DBDeleteShipmentTimeWindows(ctx, shipment_id); err != nil {
 			return err
@@ -234,6 +244,8 @@ func scanShipmentRow(row pgx.Row) (Shipment, error) {
 		&i.Amount,
 		&i.Skills,
 		&i.Priority,
+		&i.PinnedVehicleID,
+		&i.Locked,
 		&i.ProjectID,
 		&i.PData,
 		&i.DData,
@@ -277,6 +289,8 @@ func scanShipmentRows(rows pgx.Rows) ([]Shipment, error) {
 			&i.Amount,
 			&i.Skills,
 			&i.Priority,
+			&i.PinnedVehicleID,
+			&i.Locked,
 			&i.ProjectID,
 			&i.PData,
 			&i.DData,
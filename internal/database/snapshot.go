@@ -0,0 +1,693 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: snapshot.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/jackc/pgx/v4"
+)
+
+// SnapshotVersion is the version of the format of the project snapshots
+const SnapshotVersion = 1
+
+// ProjectSnapshot is a portable copy of a project, along with all its tasks, vehicles, vehicle types and breaks,
+// and optionally its schedule. The ids in the snapshot are only used to refer to each other.
+type ProjectSnapshot struct {
+	Version      int               `json:"version" example:"1"`
+	Project      Project           `json:"project"`
+	Jobs         []Job             `json:"jobs"`
+	Shipments    []Shipment        `json:"shipments"`
+	VehicleTypes []VehicleType     `json:"vehicle_types,omitempty"`
+	Vehicles     []Vehicle         `json:"vehicles"`
+	Breaks       []Break           `json:"breaks"`
+	Shifts       []VehicleShift    `json:"shifts,omitempty"`
+	Schedule     []util.ScheduleDB `json:"schedule,omitempty"`
+}
+
+// DBExportProject returns the snapshot of a project, along with its schedule when withSchedule is true
+func (q *Queries) DBExportProject(ctx context.Context, projectID int64, withSchedule bool) (ProjectSnapshot, error) {
+	project, err := q.DBGetProject(ctx, projectID)
+	if err != nil {
+		return ProjectSnapshot{}, err
+	}
+	snapshot := ProjectSnapshot{Version: SnapshotVersion, Project: project, Breaks: []Break{}}
+
+	if snapshot.Jobs, err = q.DBListJobs(ctx, projectID); err != nil {
+		return ProjectSnapshot{}, err
+	}
+	if snapshot.Shipments, err = q.DBListShipments(ctx, projectID); err != nil {
+		return ProjectSnapshot{}, err
+	}
+	if snapshot.VehicleTypes, err = q.DBListVehicleTypes(ctx, projectID); err != nil {
+		return ProjectSnapshot{}, err
+	}
+	vehicleTypeIDs := map[int64]bool{}
+	for _, vehicleType := range snapshot.VehicleTypes {
+		vehicleTypeIDs[vehicleType.ID] = true
+	}
+	if snapshot.Vehicles, err = q.DBListVehicles(ctx, projectID); err != nil {
+		return ProjectSnapshot{}, err
+	}
+	for i, vehicle := range snapshot.Vehicles {
+		// the vehicles of a deleted vehicle type keep their fields, without the reference to the type
+		if vehicle.VehicleTypeID != nil && !vehicleTypeIDs[*vehicle.VehicleTypeID] {
+			snapshot.Vehicles[i].VehicleTypeID = nil
+		}
+
+		breaks, err := q.DBListBreaks(ctx, vehicle.ID, true)
+		if err != nil {
+			return ProjectSnapshot{}, err
+		}
+		snapshot.Breaks = append(snapshot.Breaks, breaks...)
+
+		// The recurring shifts are created again from the shift recurrence of the vehicle
+		shifts, err := q.DBListVehicleShifts(ctx, vehicle.ID)
+		if err != nil {
+			return ProjectSnapshot{}, err
+		}
+		for _, shift := range shifts {
+			if !shift.Recurring {
+				snapshot.Shifts = append(snapshot.Shifts, shift)
+			}
+		}
+	}
+	if withSchedule {
+		if snapshot.Schedule, err = q.listScheduleRows(ctx, projectID); err != nil {
+			return ProjectSnapshot{}, err
+		}
+	}
+	return snapshot, nil
+}
+
+func getDataParam(data interface{}) *interface{} {
+	if data == nil {
+		return nil
+	}
+	return &data
+}
+
+// GetCreateParams returns the params to create the project of the snapshot
+func (snapshot ProjectSnapshot) GetCreateParams() CreateProjectParams {
+	project := snapshot.Project
+	params := CreateProjectParams{
+		Name:             &project.Name,
+		DurationCalc:     &project.DurationCalc,
+		ExplorationLevel: &project.ExplorationLevel,
+		Timeout:          &project.Timeout,
+		MaxShift:         &project.MaxShift,
+		VehicleFixedCost: &project.VehicleFixedCost,
+		HorizonStart:     project.HorizonStart,
+		HorizonEnd:       project.HorizonEnd,
+		Timezone:         project.Timezone,
+		Data:             getDataParam(project.Data),
+	}
+	// the snapshots exported before the solver options keep the default objective and cost per hour
+	if project.Objective != "" {
+		params.Objective = &project.Objective
+		params.VehicleCostPerHour = &project.VehicleCostPerHour
+	}
+	return params
+}
+
+// GetVehicleTypeParams returns the params to create the vehicle types of the snapshot in a project
+func (snapshot ProjectSnapshot) GetVehicleTypeParams(projectID int64) []CreateVehicleTypeParams {
+	params := []CreateVehicleTypeParams{}
+	for i := range snapshot.VehicleTypes {
+		vehicleType := snapshot.VehicleTypes[i]
+		breaks := []VehicleTypeBreakParams{}
+		for j := range vehicleType.Breaks {
+			vBreak := vehicleType.Breaks[j]
+			breaks = append(breaks, VehicleTypeBreakParams{
+				Service:     &vBreak.Service,
+				TimeWindows: &vBreak.TimeWindows,
+				Data:        getDataParam(vBreak.Data),
+			})
+		}
+		// the capacity and the skills of a vehicle type are not set when they are null
+		var capacity *[]int64
+		if vehicleType.Capacity != nil {
+			capacity = &vehicleType.Capacity
+		}
+		var skills *[]int32
+		if vehicleType.Skills != nil {
+			skills = &vehicleType.Skills
+		}
+		params = append(params, CreateVehicleTypeParams{
+			Name:          &vehicleType.Name,
+			Capacity:      capacity,
+			Skills:        skills,
+			TwOpen:        vehicleType.TwOpen,
+			TwClose:       vehicleType.TwClose,
+			SpeedFactor:   vehicleType.SpeedFactor,
+			MaxTasks:      vehicleType.MaxTasks,
+			MaxTravelTime: vehicleType.MaxTravelTime,
+			MaxDistance:   vehicleType.MaxDistance,
+			FixedCost:     vehicleType.FixedCost,
+			CostPerHour:   vehicleType.CostPerHour,
+			CostPerKm:     vehicleType.CostPerKm,
+			Breaks:        getVehicleTypeBreaks(&breaks),
+			ProjectID:     &projectID,
+			Data:          getDataParam(vehicleType.Data),
+		})
+	}
+	return params
+}
+
+// GetImportParams returns the params to import the jobs, shipments, vehicles and breaks of the snapshot in a project.
+// The row of each param is its index in the snapshot, starting from 1.
+func (snapshot ProjectSnapshot) GetImportParams(projectID int64) (ImportParams, error) {
+	params := ImportParams{}
+	for i := range snapshot.Jobs {
+		job := snapshot.Jobs[i]
+		params.Jobs = append(params.Jobs, ImportJobParams{
+			Row: i + 1,
+			CreateJobParams: CreateJobParams{
+				Location:    &job.Location,
+				Setup:       &job.Setup,
+				Service:     &job.Service,
+				Delivery:    &job.Delivery,
+				Pickup:      &job.Pickup,
+				Skills:      &job.Skills,
+				Priority:    &job.Priority,
+				Locked:      &job.Locked,
+				Recurrence:  &job.Recurrence,
+				TimeWindows: &job.TimeWindows,
+				ProjectID:   &projectID,
+				Data:        getDataParam(job.Data),
+			},
+		})
+	}
+	for i := range snapshot.Shipments {
+		shipment := snapshot.Shipments[i]
+		params.Shipments = append(params.Shipments, ImportShipmentParams{
+			Row: i + 1,
+			CreateShipmentParams: CreateShipmentParams{
+				PLocation:    &shipment.PLocation,
+				PSetup:       &shipment.PSetup,
+				PService:     &shipment.PService,
+				DLocation:    &shipment.DLocation,
+				DSetup:       &shipment.DSetup,
+				DService:     &shipment.DService,
+				Amount:       &shipment.Amount,
+				Skills:       &shipment.Skills,
+				PTimeWindows: &shipment.PTimeWindows,
+				DTimeWindows: &shipment.DTimeWindows,
+				Priority:     &shipment.Priority,
+				Locked:       &shipment.Locked,
+				ProjectID:    &projectID,
+				PData:        getDataParam(shipment.PData),
+				DData:        getDataParam(shipment.DData),
+			},
+		})
+	}
+	vehicleIndex := map[int64]int{}
+	for i := range snapshot.Vehicles {
+		vehicle := snapshot.Vehicles[i]
+		vehicleIndex[vehicle.ID] = i
+		params.Vehicles = append(params.Vehicles, ImportVehicleParams{
+			Row: i + 1,
+			CreateVehicleParams: CreateVehicleParams{
+				StartLocation:   &vehicle.StartLocation,
+				EndLocation:     &vehicle.EndLocation,
+				Capacity:        &vehicle.Capacity,
+				Skills:          &vehicle.Skills,
+				TwOpen:          &vehicle.TwOpen,
+				TwClose:         &vehicle.TwClose,
+				SpeedFactor:     &vehicle.SpeedFactor,
+				MaxTasks:        &vehicle.MaxTasks,
+				MaxTravelTime:   vehicle.MaxTravelTime,
+				MaxDistance:     vehicle.MaxDistance,
+				FixedCost:       vehicle.FixedCost,
+				CostPerHour:     vehicle.CostPerHour,
+				CostPerKm:       &vehicle.CostPerKm,
+				ShiftRecurrence: &vehicle.ShiftRecurrence,
+				ProjectID:       &projectID,
+				Data:            getDataParam(vehicle.Data),
+			},
+		})
+	}
+	for i := range snapshot.Breaks {
+		vBreak := snapshot.Breaks[i]
+		index, found := vehicleIndex[vBreak.VehicleID]
+		if !found {
+			return ImportParams{}, fmt.Errorf("%s: Vehicle with the given 'vehicle_id' does not exist in the snapshot", getSnapshotRowName("breaks", i+1))
+		}
+		params.Breaks = append(params.Breaks, ImportBreakParams{
+			Row:          i + 1,
+			VehicleIndex: index,
+			CreateBreakParams: CreateBreakParams{
+				VehicleID:   new(int64),
+				Service:     &vBreak.Service,
+				Data:        getDataParam(vBreak.Data),
+				TimeWindows: &vBreak.TimeWindows,
+			},
+		})
+	}
+	return params, nil
+}
+
+// getSnapshotRowName returns the name of a row of the snapshot, used in the error messages
+func getSnapshotRowName(kind string, row int) string {
+	return fmt.Sprintf("%s[%d]", kind, row-1)
+}
+
+// IDMapping maps the ids of the original project, tasks, vehicles and breaks to the ids of the new ones
+type IDMapping struct {
+	Projects  map[string]string `json:"projects"`
+	Jobs      map[string]string `json:"jobs"`
+	Shipments map[string]string `json:"shipments"`
+	Vehicles  map[string]string `json:"vehicles"`
+	Breaks    map[string]string `json:"breaks"`
+}
+
+// ClonedProject is the project created by cloning, along with the mapping of the old ids to the new ids
+type ClonedProject struct {
+	Project   Project   `json:"project"`
+	IDMapping IDMapping `json:"id_mapping"`
+}
+
+// DBImportProject creates a new project from the snapshot in a single transaction, with new ids
+// for the project, tasks, vehicles and breaks, and the references between them updated accordingly
+func (q *Queries) DBImportProject(ctx context.Context, snapshot ProjectSnapshot) (Project, error) {
+	tx, err := q.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return Project{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	projectID, _, err := createSnapshotProject(ctx, tx, snapshot)
+	if err != nil {
+		return Project{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Project{}, err
+	}
+	return q.DBGetProject(ctx, projectID)
+}
+
+// DBCloneProject copies a project along with its tasks, vehicles and breaks, and its schedule when withSchedule
+// is true, into a new project in a single transaction. The project is read in this transaction with the repeatable
+// read isolation level, so that the copy is consistent even when the project is changed during the clone.
+func (q *Queries) DBCloneProject(ctx context.Context, projectID int64, withSchedule bool) (ClonedProject, error) {
+	tx, err := q.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead})
+	if err != nil {
+		return ClonedProject{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	snapshot, err := New(nestedTx{tx}).DBExportProject(ctx, projectID, withSchedule)
+	if err != nil {
+		return ClonedProject{}, err
+	}
+	newProjectID, ids, err := createSnapshotProject(ctx, tx, snapshot)
+	if err != nil {
+		return ClonedProject{}, err
+	}
+
+	// Keep the ids of the original rows, so that the schedules of the projects can be compared
+	oldIDs := snapshot.getIDs()
+	for _, table := range []struct {
+		name   string
+		oldIDs []int64
+		newIDs []int64
+	}{
+		{"jobs", oldIDs.jobs, ids.jobs},
+		{"shipments", oldIDs.shipments, ids.shipments},
+		{"vehicles", oldIDs.vehicles, ids.vehicles},
+		{"breaks", oldIDs.breaks, ids.breaks},
+	} {
+		if err := setSourceIDs(ctx, tx, table.name, table.oldIDs, table.newIDs); err != nil {
+			return ClonedProject{}, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return ClonedProject{}, err
+	}
+
+	project, err := q.DBGetProject(ctx, newProjectID)
+	if err != nil {
+		return ClonedProject{}, err
+	}
+	return ClonedProject{Project: project, IDMapping: getIDMapping(snapshot.Project.ID, newProjectID, oldIDs, ids)}, nil
+}
+
+// pinSnapshotTasks sets the pinned vehicle of the imported jobs or shipments, mapping the pinned vehicle ids
+// of the snapshot to the new ids of the vehicles
+func pinSnapshotTasks(ctx context.Context, tx pgx.Tx, tableName string, pinnedVehicleIDs []*int64, taskIDs []int64, vehicleIDs map[int64]int64) error {
+	pinnedTaskIDs, newVehicleIDs := []int64{}, []int64{}
+	for i, pinnedVehicleID := range pinnedVehicleIDs {
+		if pinnedVehicleID == nil {
+			continue
+		}
+		vehicleID, found := vehicleIDs[*pinnedVehicleID]
+		if !found {
+			return fmt.Errorf("%s: Vehicle with the given 'pinned_vehicle_id' does not exist in the snapshot", getSnapshotRowName(tableName, i+1))
+		}
+		pinnedTaskIDs = append(pinnedTaskIDs, taskIDs[i])
+		newVehicleIDs = append(newVehicleIDs, vehicleID)
+	}
+	if len(pinnedTaskIDs) == 0 {
+		return nil
+	}
+	sql := fmt.Sprintf(`
+		UPDATE %s T SET pinned_vehicle_id = P.vehicle_id
+		FROM unnest($1::BIGINT[], $2::BIGINT[]) AS P(id, vehicle_id)
+		WHERE T.id = P.id`, tableName)
+	_, err := tx.Exec(ctx, sql, pinnedTaskIDs, newVehicleIDs)
+	return err
+}
+
+// createSnapshotVehicleTypes creates the vehicle types of the snapshot in the project, and returns the mapping of
+// their ids in the snapshot to their new ids
+func createSnapshotVehicleTypes(ctx context.Context, tx pgx.Tx, snapshot ProjectSnapshot, projectID int64) (map[int64]int64, error) {
+	vehicleTypeIDs := map[int64]int64{}
+	for i, params := range snapshot.GetVehicleTypeParams(projectID) {
+		sql, args := createResource("vehicle_types", params)
+		id, err := scanID(tx.QueryRow(ctx, sql+" RETURNING id", args...))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", getSnapshotRowName("vehicle_types", i+1), err)
+		}
+		vehicleTypeIDs[snapshot.VehicleTypes[i].ID] = id
+	}
+	return vehicleTypeIDs, nil
+}
+
+// setSnapshotVehicleTypes sets the vehicle type of the imported vehicles, mapping the vehicle type ids of the
+// snapshot to the new ids of the vehicle types. The fields and the breaks of the vehicles are kept as they are.
+func setSnapshotVehicleTypes(ctx context.Context, tx pgx.Tx, vehicles []Vehicle, vehicleIDs []int64, vehicleTypeIDs map[int64]int64) error {
+	typedVehicleIDs, newVehicleTypeIDs := []int64{}, []int64{}
+	for i, vehicle := range vehicles {
+		if vehicle.VehicleTypeID == nil {
+			continue
+		}
+		vehicleTypeID, found := vehicleTypeIDs[*vehicle.VehicleTypeID]
+		if !found {
+			return fmt.Errorf("%s: Vehicle type with the given 'vehicle_type_id' does not exist in the snapshot", getSnapshotRowName("vehicles", i+1))
+		}
+		typedVehicleIDs = append(typedVehicleIDs, vehicleIDs[i])
+		newVehicleTypeIDs = append(newVehicleTypeIDs, vehicleTypeID)
+	}
+	if len(typedVehicleIDs) == 0 {
+		return nil
+	}
+	sql := `
+		UPDATE vehicles V SET vehicle_type_id = T.vehicle_type_id
+		FROM unnest($1::BIGINT[], $2::BIGINT[]) AS T(id, vehicle_type_id)
+		WHERE V.id = T.id`
+	_, err := tx.Exec(ctx, sql, typedVehicleIDs, newVehicleTypeIDs)
+	return err
+}
+
+// setSnapshotRecurrences links the imported occurrences to the new ids of their recurring jobs, keeping whether they
+// are overridden, and marks the imported off-shift breaks. The shifts of the vehicles are created again when the project is scheduled.
+func setSnapshotRecurrences(ctx context.Context, tx pgx.Tx, snapshot ProjectSnapshot, ids importIDs, jobIDs map[int64]int64) error {
+	occurrenceIDs, recurringJobIDs, occurrences, overridden := []int64{}, []int64{}, []string{}, []bool{}
+	for i, job := range snapshot.Jobs {
+		if job.RecurringJobID == nil || job.Occurrence == nil {
+			continue
+		}
+		recurringJobID, found := jobIDs[*job.RecurringJobID]
+		if !found {
+			return fmt.Errorf("%s: Job with the given 'recurring_job_id' does not exist in the snapshot", getSnapshotRowName("jobs", i+1))
+		}
+		occurrenceIDs = append(occurrenceIDs, ids.jobs[i])
+		recurringJobIDs = append(recurringJobIDs, recurringJobID)
+		occurrences = append(occurrences, *job.Occurrence)
+		overridden = append(overridden, job.Overridden)
+	}
+	offShiftIDs := []int64{}
+	for i, vBreak := range snapshot.Breaks {
+		if vBreak.OffShift {
+			offShiftIDs = append(offShiftIDs, ids.breaks[i])
+		}
+	}
+	sql := `
+		UPDATE jobs J SET recurring_job_id = O.recurring_job_id, occurrence = O.occurrence::DATE, overridden = O.overridden
+		FROM unnest($1::BIGINT[], $2::BIGINT[], $3::TEXT[], $4::BOOLEAN[]) AS O(id, recurring_job_id, occurrence, overridden)
+		WHERE J.id = O.id`
+	if _, err := tx.Exec(ctx, sql, occurrenceIDs, recurringJobIDs, occurrences, overridden); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, "UPDATE breaks SET off_shift = TRUE WHERE id = ANY($1)", offShiftIDs)
+	return err
+}
+
+// createSnapshotShifts creates the shifts and the days off of the snapshot for the new ids of their vehicles
+func createSnapshotShifts(ctx context.Context, tx pgx.Tx, shifts []VehicleShift, vehicleIDs map[int64]int64) error {
+	if len(shifts) == 0 {
+		return nil
+	}
+	newVehicleIDs, twOpens, twCloses, daysOff := []int64{}, []string{}, []string{}, []bool{}
+	for i, shift := range shifts {
+		vehicleID, found := vehicleIDs[shift.VehicleID]
+		if !found {
+			return fmt.Errorf("%s: Vehicle with the given 'vehicle_id' does not exist in the snapshot", getSnapshotRowName("shifts", i+1))
+		}
+		newVehicleIDs = append(newVehicleIDs, vehicleID)
+		twOpens = append(twOpens, shift.TwOpen)
+		twCloses = append(twCloses, shift.TwClose)
+		daysOff = append(daysOff, shift.DayOff)
+	}
+	sql := `
+		INSERT INTO vehicle_shifts (vehicle_id, tw_open, tw_close, day_off)
+		SELECT vehicle_id, tw_open::TIMESTAMP, tw_close::TIMESTAMP, day_off
+		FROM unnest($1::BIGINT[], $2::TEXT[], $3::TEXT[], $4::BOOLEAN[]) AS S(vehicle_id, tw_open, tw_close, day_off)`
+	_, err := tx.Exec(ctx, sql, newVehicleIDs, twOpens, twCloses, daysOff)
+	return util.HandleDBError(err)
+}
+
+// getPinnedVehicleIDs returns the pinned vehicle of each job or shipment of the snapshot
+func (snapshot ProjectSnapshot) getPinnedVehicleIDs(kind string) []*int64 {
+	vehicleIDs := []*int64{}
+	if kind == "jobs" {
+		for _, job := range snapshot.Jobs {
+			vehicleIDs = append(vehicleIDs, job.PinnedVehicleID)
+		}
+	} else {
+		for _, shipment := range snapshot.Shipments {
+			vehicleIDs = append(vehicleIDs, shipment.PinnedVehicleID)
+		}
+	}
+	return vehicleIDs
+}
+
+// getIDs returns the ids of the jobs, shipments, vehicles and breaks of the snapshot
+func (snapshot ProjectSnapshot) getIDs() importIDs {
+	ids := importIDs{}
+	for _, job := range snapshot.Jobs {
+		ids.jobs = append(ids.jobs, job.ID)
+	}
+	for _, shipment := range snapshot.Shipments {
+		ids.shipments = append(ids.shipments, shipment.ID)
+	}
+	for _, vehicle := range snapshot.Vehicles {
+		ids.vehicles = append(ids.vehicles, vehicle.ID)
+	}
+	for _, vBreak := range snapshot.Breaks {
+		ids.breaks = append(ids.breaks, vBreak.ID)
+	}
+	return ids
+}
+
+// getIDMapping returns the mapping of the old ids to the new ids
+func getIDMapping(oldProjectID int64, newProjectID int64, oldIDs importIDs, newIDs importIDs) IDMapping {
+	getMapping := func(oldIDs []int64, newIDs []int64) map[string]string {
+		mapping := map[string]string{}
+		for i := range oldIDs {
+			mapping[formatID(oldIDs[i])] = formatID(newIDs[i])
+		}
+		return mapping
+	}
+	return IDMapping{
+		Projects:  map[string]string{formatID(oldProjectID): formatID(newProjectID)},
+		Jobs:      getMapping(oldIDs.jobs, newIDs.jobs),
+		Shipments: getMapping(oldIDs.shipments, newIDs.shipments),
+		Vehicles:  getMapping(oldIDs.vehicles, newIDs.vehicles),
+		Breaks:    getMapping(oldIDs.breaks, newIDs.breaks),
+	}
+}
+
+// setSourceIDs sets the source_id of the new rows of the table to the source_id of the old rows,
+// or to the id of the old rows if they are not cloned themselves
+func setSourceIDs(ctx context.Context, tx pgx.Tx, table string, oldIDs []int64, newIDs []int64) error {
+	if len(newIDs) == 0 {
+		return nil
+	}
+	sql := fmt.Sprintf(`
+	UPDATE %s SET source_id = COALESCE(old.source_id, old.id)
+	FROM unnest($1::BIGINT[], $2::BIGINT[]) AS ids(new_id, old_id)
+	JOIN %s old ON old.id = ids.old_id
+	WHERE %s.id = ids.new_id`, table, table, table)
+	_, err := tx.Exec(ctx, sql, newIDs, oldIDs)
+	return err
+}
+
+// createSnapshotProject creates the project of the snapshot, with new ids for the project, tasks, vehicles and breaks,
+// and the references between them updated accordingly. It returns the id of the project and the ids of the rows.
+func createSnapshotProject(ctx context.Context, tx pgx.Tx, snapshot ProjectSnapshot) (int64, importIDs, error) {
+	sql, args := createResource("projects", snapshot.GetCreateParams())
+	projectID, err := scanID(tx.QueryRow(ctx, sql+" RETURNING id", args...))
+	if err != nil {
+		return 0, importIDs{}, err
+	}
+
+	vehicleTypeIDs, err := createSnapshotVehicleTypes(ctx, tx, snapshot, projectID)
+	if err != nil {
+		return 0, importIDs{}, err
+	}
+	params, err := snapshot.GetImportParams(projectID)
+	if err != nil {
+		return 0, importIDs{}, err
+	}
+	ids, err := importRows(ctx, tx, params, getSnapshotRowName)
+	if err != nil {
+		return 0, importIDs{}, err
+	}
+	if err := setSnapshotVehicleTypes(ctx, tx, snapshot.Vehicles, ids.vehicles, vehicleTypeIDs); err != nil {
+		return 0, importIDs{}, err
+	}
+
+	// Map the ids in the snapshot to the new ids
+	vehicleIDs := map[int64]int64{}
+	for i, vehicle := range snapshot.Vehicles {
+		vehicleIDs[vehicle.ID] = ids.vehicles[i]
+	}
+	taskIDs := map[string]map[int64]int64{"job": {}, "pickup": {}, "delivery": {}, "break": {}}
+	for i, job := range snapshot.Jobs {
+		taskIDs["job"][job.ID] = ids.jobs[i]
+	}
+	for i, shipment := range snapshot.Shipments {
+		taskIDs["pickup"][shipment.ID] = ids.shipments[i]
+		taskIDs["delivery"][shipment.ID] = ids.shipments[i]
+	}
+	for i, vBreak := range snapshot.Breaks {
+		taskIDs["break"][vBreak.ID] = ids.breaks[i]
+	}
+
+	// Pin the tasks to the new ids of their vehicles
+	if err := pinSnapshotTasks(ctx, tx, "jobs", snapshot.getPinnedVehicleIDs("jobs"), ids.jobs, vehicleIDs); err != nil {
+		return 0, importIDs{}, err
+	}
+	if err := pinSnapshotTasks(ctx, tx, "shipments", snapshot.getPinnedVehicleIDs("shipments"), ids.shipments, vehicleIDs); err != nil {
+		return 0, importIDs{}, err
+	}
+	if err := setSnapshotRecurrences(ctx, tx, snapshot, ids, taskIDs["job"]); err != nil {
+		return 0, importIDs{}, err
+	}
+	if err := createSnapshotShifts(ctx, tx, snapshot.Shifts, vehicleIDs); err != nil {
+		return 0, importIDs{}, err
+	}
+
+	if len(snapshot.Schedule) != 0 {
+		schedule := make([]util.ScheduleDB, 0, len(snapshot.Schedule))
+		for i, step := range snapshot.Schedule {
+			if step.Location.Latitude == nil || step.Location.Longitude == nil {
+				return 0, importIDs{}, fmt.Errorf("%s: Field 'location' is required", getSnapshotRowName("schedule", i+1))
+			}
+			// vehicle_id is 0 for the total summary and -1 for the unassigned tasks
+			if step.VehicleID > 0 {
+				vehicleID, found := vehicleIDs[step.VehicleID]
+				if !found {
+					return 0, importIDs{}, fmt.Errorf("%s: Vehicle with the given 'vehicle_id' does not exist in the snapshot", getSnapshotRowName("schedule", i+1))
+				}
+				step.VehicleID = vehicleID
+			}
+			// task_id is -1 for the start and end steps, and 0 for the summary
+			if ids, ok := taskIDs[step.Type]; ok {
+				taskID, found := ids[step.TaskID]
+				if !found {
+					return 0, importIDs{}, fmt.Errorf("%s: Task with the given 'task_id' does not exist in the snapshot", getSnapshotRowName("schedule", i+1))
+				}
+				step.TaskID = taskID
+			}
+			schedule = append(schedule, step)
+		}
+		if err := createScheduleRows(ctx, tx, projectID, schedule); err != nil {
+			return 0, importIDs{}, err
+		}
+	}
+	return projectID, ids, nil
+}
+
+func formatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// createScheduleRows inserts the rows of a schedule, with multi-row inserts of at most scheduleRowsPerInsert rows
+func createScheduleRows(ctx context.Context, tx pgx.Tx, projectID int64, schedule []util.ScheduleDB) error {
+	const scheduleRowsPerInsert = 1000
+	for start := 0; start < len(schedule); start += scheduleRowsPerInsert {
+		end := start + scheduleRowsPerInsert
+		if end > len(schedule) {
+			end = len(schedule)
+		}
+
+		// create an sql query to insert multiple rows
+		sql := `INSERT INTO schedules (type, project_id, vehicle_id, task_id, location_id, arrival, departure,
+		travel_time, setup_time, service_time, waiting_time, distance, load, vehicle_data, task_data) VALUES `
+		args := []interface{}{}
+		for i, step := range schedule[start:end] {
+			if i > 0 {
+				sql += ","
+			}
+			n := len(args)
+			sql += fmt.Sprintf(
+				"($%d::STEP_TYPE, $%d, $%d, $%d, $%d, $%d::TIMESTAMP, $%d::TIMESTAMP, $%d::INTERVAL, $%d::INTERVAL, $%d::INTERVAL, $%d::INTERVAL, $%d, $%d, $%d, $%d)",
+				n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+11, n+12, n+13, n+14, n+15,
+			)
+			vehicleData, taskData := step.VehicleData, step.TaskData
+			if vehicleData == nil {
+				vehicleData = map[string]interface{}{}
+			}
+			if taskData == nil {
+				taskData = map[string]interface{}{}
+			}
+			args = append(args,
+				step.Type, projectID, step.VehicleID, step.TaskID,
+				util.GetLocationId(*step.Location.Latitude, *step.Location.Longitude),
+				step.Arrival, step.Departure, step.TravelTime, step.SetupTime, step.ServiceTime, step.WaitingTime,
+				step.Distance, step.Load, vehicleData, taskData,
+			)
+		}
+
+		// execute the query
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return util.HandleDBError(err)
+		}
+	}
+	return nil
+}
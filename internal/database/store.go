@@ -29,6 +29,8 @@ along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
 package database
 
 import (
+	"context"
+
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -43,3 +45,15 @@ func NewStore(db *pgxpool.Pool) *Store {
 		Querier: New(db),
 	}
 }
+
+// Connect creates a pool of connections to the database. The TIMESTAMP columns store the times in UTC, such as the
+// current_timestamp of the created_at and updated_at columns, so the sessions use the UTC time zone whatever the
+// time zone of the database server.
+func Connect(ctx context.Context, connString string) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, err
+	}
+	config.ConnConfig.RuntimeParams["timezone"] = "UTC"
+	return pgxpool.ConnectConfig(ctx, config)
+}
@@ -36,32 +36,59 @@ import (
 )
 
 type CreateVehicleParams struct {
-	StartLocation *util.LocationParams `json:"start_location" validate:"required"`
-	EndLocation   *util.LocationParams `json:"end_location" validate:"required"`
-	Capacity      *[]int64             `json:"capacity" validate:"omitempty,dive,min=0" example:"50,25"`
-	Skills        *[]int32             `json:"skills" validate:"omitempty,dive,min=0" example:"1,5"`
-	TwOpen        *string              `json:"tw_open" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-31T23:00:00"`
-	TwClose       *string              `json:"tw_close" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-31T23:59:00"`
-	SpeedFactor   *float64             `json:"speed_factor" validate:"omitempty,gt=0" example:"1.0"`
-	MaxTasks      *int32               `json:"max_tasks" validate:"omitempty,gt=0" example:"20"`
-	ProjectID     *int64               `json:"project_id,string" validate:"required" swaggerignore:"true"`
-	Data          *interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	StartLocation   *util.LocationParams `json:"start_location" validate:"required"`
+	EndLocation     *util.LocationParams `json:"end_location" validate:"required"`
+	Capacity        *[]int64             `json:"capacity" validate:"omitempty,dive,min=0" example:"50,25"`
+	Skills          *[]int32             `json:"skills" validate:"omitempty,dive,skill" example:"1,5"`
+	TwOpen          *string              `json:"tw_open" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-31T23:00:00"`
+	TwClose         *string              `json:"tw_close" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-31T23:59:00"`
+	SpeedFactor     *float64             `json:"speed_factor" validate:"omitempty,gt=0" example:"1.0"`
+	MaxTasks        *int32               `json:"max_tasks" validate:"omitempty,gt=0" example:"20"`
+	MaxTravelTime   *string              `json:"max_travel_time" validate:"omitempty,duration" example:"08:00:00"`
+	MaxDistance     *int64               `json:"max_distance" validate:"omitempty,min=0" example:"200000"`
+	FixedCost       *int64               `json:"fixed_cost" validate:"omitempty,min=0" example:"5000"`
+	CostPerHour     *int64               `json:"cost_per_hour" validate:"omitempty,min=0" example:"3600"`
+	CostPerKm       *int64               `json:"cost_per_km" validate:"omitempty,min=0" example:"100"`
+	VehicleTypeID   *int64               `json:"vehicle_type_id,string" validate:"omitempty,min=0" example:"1234567812345678"`
+	ShiftRecurrence *string              `json:"shift_recurrence" validate:"omitempty,rrule" example:"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR"`
+	ProjectID       *int64               `json:"project_id,string" validate:"required" swaggerignore:"true"`
+	Data            *interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
 }
 
 type UpdateVehicleParams struct {
-	StartLocation *util.LocationParams `json:"start_location"`
-	EndLocation   *util.LocationParams `json:"end_location"`
-	Capacity      *[]int64             `json:"capacity" validate:"omitempty,dive,min=0" example:"50,25"`
-	Skills        *[]int32             `json:"skills" validate:"omitempty,dive,min=0" example:"1,5"`
-	TwOpen        *string              `json:"tw_open" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-31T23:00:00"`
-	TwClose       *string              `json:"tw_close" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-31T23:59:00"`
-	SpeedFactor   *float64             `json:"speed_factor" validate:"omitempty,gt=0" example:"1.0"`
-	MaxTasks      *int32               `json:"max_tasks" validate:"omitempty,gt=0" example:"20"`
-	ProjectID     *int64               `json:"project_id,string" swaggerignore:"true"`
-	Data          *interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	StartLocation   *util.LocationParams `json:"start_location"`
+	EndLocation     *util.LocationParams `json:"end_location"`
+	Capacity        *[]int64             `json:"capacity" validate:"omitempty,dive,min=0" example:"50,25"`
+	Skills          *[]int32             `json:"skills" validate:"omitempty,dive,skill" example:"1,5"`
+	TwOpen          *string              `json:"tw_open" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-31T23:00:00"`
+	TwClose         *string              `json:"tw_close" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-31T23:59:00"`
+	SpeedFactor     *float64             `json:"speed_factor" validate:"omitempty,gt=0" example:"1.0"`
+	MaxTasks        *int32               `json:"max_tasks" validate:"omitempty,gt=0" example:"20"`
+	MaxTravelTime   *string              `json:"max_travel_time" validate:"omitempty,duration" example:"08:00:00"`
+	MaxDistance     *int64               `json:"max_distance" validate:"omitempty,min=0" example:"200000"`
+	FixedCost       *int64               `json:"fixed_cost" validate:"omitempty,min=0" example:"5000"`
+	CostPerHour     *int64               `json:"cost_per_hour" validate:"omitempty,min=0" example:"3600"`
+	CostPerKm       *int64               `json:"cost_per_km" validate:"omitempty,min=0" example:"100"`
+	VehicleTypeID   *int64               `json:"vehicle_type_id,string" validate:"omitempty,min=0" example:"1234567812345678"`
+	ShiftRecurrence *string              `json:"shift_recurrence" validate:"omitempty,rrule" example:"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR"`
+	ProjectID       *int64               `json:"project_id,string" swaggerignore:"true"`
+	Data            *interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
 }
 
 func (q *Queries) DBCreateVehicle(ctx context.Context, arg CreateVehicleParams) (Vehicle, error) {
+	// the vehicle of a vehicle type is created along with the breaks of the type
+	if arg.VehicleTypeID != nil && *arg.VehicleTypeID != 0 {
+		vehicleType, err := q.getProjectVehicleType(ctx, *arg.ProjectID, *arg.VehicleTypeID)
+		if err != nil {
+			return Vehicle{}, err
+		}
+		vehicles, err := q.createVehiclesOfType(ctx, vehicleType, []CreateVehicleParams{arg})
+		if err != nil {
+			return Vehicle{}, err
+		}
+		return vehicles[0], nil
+	}
+
 	tableName := "vehicles"
 	sql, args := createResource(tableName, arg)
 	return_sql := " RETURNING " + util.GetOutputFields(Vehicle{}, tableName)
@@ -94,6 +121,17 @@ func (q *Queries) DBListVehicles(ctx context.Context, projectID int64) ([]Vehicl
 }
 
 func (q *Queries) DBUpdateVehicle(ctx context.Context, arg UpdateVehicleParams, vehicle_id int64) (Vehicle, error) {
+	// only the reference to the vehicle type is changed, the fields of the vehicle are kept
+	if arg.VehicleTypeID != nil && *arg.VehicleTypeID != 0 {
+		vehicle, err := q.DBGetVehicle(ctx, vehicle_id)
+		if err != nil {
+			return Vehicle{}, err
+		}
+		if _, err := q.getProjectVehicleType(ctx, vehicle.ProjectID, *arg.VehicleTypeID); err != nil {
+			return Vehicle{}, err
+		}
+	}
+
 	tableName := "vehicles"
 	sql, args := updateResource(tableName, arg, vehicle_id)
 	return_sql := " RETURNING " + util.GetOutputFields(Vehicle{}, tableName)
@@ -122,6 +160,13 @@ func scanVehicleRow(row pgx.Row) (Vehicle, error) {
 		&i.TwClose,
 		&i.SpeedFactor,
 		&i.MaxTasks,
+		&i.MaxTravelTime,
+		&i.MaxDistance,
+		&i.FixedCost,
+		&i.CostPerHour,
+		&i.CostPerKm,
+		&i.VehicleTypeID,
+		&i.ShiftRecurrence,
 		&i.ProjectID,
 		&i.Data,
 		&i.CreatedAt,
@@ -156,6 +201,13 @@ func scanVehicleRows(rows pgx.Rows) ([]Vehicle, error) {
 			&i.TwClose,
 			&i.SpeedFactor,
 			&i.MaxTasks,
+			&i.MaxTravelTime,
+			&i.MaxDistance,
+			&i.FixedCost,
+			&i.CostPerHour,
+			&i.CostPerKm,
+			&i.VehicleTypeID,
+			&i.ShiftRecurrence,
 			&i.ProjectID,
 			&i.Data,
 			&i.CreatedAt,
@@ -0,0 +1,195 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: vehicle_shift.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/jackc/pgx/v4"
+)
+
+type CreateVehicleShiftParams struct {
+	VehicleID *int64  `json:"vehicle_id,string" example:"1234567812345678" validate:"required" swaggerignore:"true"`
+	TwOpen    *string `json:"tw_open" validate:"required,datetime=2006-01-02T15:04:05" example:"2021-12-01T08:00:00"`
+	TwClose   *string `json:"tw_close" validate:"required,datetime=2006-01-02T15:04:05" example:"2021-12-01T17:00:00"`
+	DayOff    *bool   `json:"day_off" example:"false"`
+}
+
+type UpdateVehicleShiftParams struct {
+	TwOpen  *string `json:"tw_open" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-01T08:00:00"`
+	TwClose *string `json:"tw_close" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-01T17:00:00"`
+	DayOff  *bool   `json:"day_off" example:"false"`
+}
+
+func (q *Queries) DBCreateVehicleShift(ctx context.Context, arg CreateVehicleShiftParams) (VehicleShift, error) {
+	tableName := "vehicle_shifts"
+	sql, args := createResource(tableName, arg)
+	return_sql := " RETURNING id"
+	id, err := scanID(q.db.QueryRow(ctx, sql+return_sql, args...))
+	if err != nil {
+		return VehicleShift{}, err
+	}
+	return q.DBGetVehicleShift(ctx, id)
+}
+
+func (q *Queries) DBGetVehicleShift(ctx context.Context, id int64) (VehicleShift, error) {
+	tableName := "vehicle_shifts"
+	additionalQuery := " WHERE id = $1 LIMIT 1"
+	sql := "SELECT " + util.GetOutputFields(VehicleShift{}, tableName) + " FROM " + tableName + additionalQuery
+	row := q.db.QueryRow(ctx, sql, id)
+	return scanVehicleShiftRow(row)
+}
+
+func (q *Queries) DBListVehicleShifts(ctx context.Context, vehicleID int64) ([]VehicleShift, error) {
+	_, err := q.DBGetVehicle(ctx, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	tableName := "vehicle_shifts"
+	additionalQuery := " WHERE vehicle_id = $1 ORDER BY tw_open, tw_close"
+	sql := "SELECT " + util.GetOutputFields(VehicleShift{}, tableName) + " FROM " + tableName + additionalQuery
+	rows, err := q.db.Query(ctx, sql, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanVehicleShiftRows(rows)
+}
+
+func (q *Queries) DBUpdateVehicleShift(ctx context.Context, arg UpdateVehicleShiftParams, shiftID int64) (VehicleShift, error) {
+	if err := q.checkEditableShift(ctx, shiftID); err != nil {
+		return VehicleShift{}, err
+	}
+	tableName := "vehicle_shifts"
+	sql, args := updateResource(tableName, arg, shiftID)
+	if _, err := q.db.Exec(ctx, sql, args...); err != nil {
+		return VehicleShift{}, util.HandleDBError(err)
+	}
+	return q.DBGetVehicleShift(ctx, shiftID)
+}
+
+func (q *Queries) DBDeleteVehicleShift(ctx context.Context, shiftID int64) error {
+	if err := q.checkEditableShift(ctx, shiftID); err != nil {
+		return err
+	}
+	_, err := q.db.Exec(ctx, "DELETE FROM vehicle_shifts WHERE id = $1", shiftID)
+	return err
+}
+
+// DBImportVehicleShifts creates the shifts and the days off of a vehicle in a single transaction, and returns all the
+// shifts of the vehicle. When replace is true, the shifts which are not recurring are deleted first.
+func (q *Queries) DBImportVehicleShifts(ctx context.Context, vehicleID int64, shifts []util.CalendarShift, replace bool) ([]VehicleShift, error) {
+	if _, err := q.DBGetVehicle(ctx, vehicleID); err != nil {
+		return nil, err
+	}
+	tx, err := q.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	if replace {
+		if _, err := tx.Exec(ctx, "DELETE FROM vehicle_shifts WHERE vehicle_id = $1 AND NOT recurring", vehicleID); err != nil {
+			return nil, err
+		}
+	}
+	twOpens, twCloses, daysOff := []string{}, []string{}, []bool{}
+	for _, shift := range shifts {
+		twOpens = append(twOpens, shift.TwOpen)
+		twCloses = append(twCloses, shift.TwClose)
+		daysOff = append(daysOff, shift.DayOff)
+	}
+	sql := `
+	INSERT INTO vehicle_shifts (vehicle_id, tw_open, tw_close, day_off)
+	SELECT $1, tw_open::TIMESTAMP, tw_close::TIMESTAMP, day_off
+	FROM unnest($2::TEXT[], $3::TEXT[], $4::BOOLEAN[]) AS S(tw_open, tw_close, day_off)`
+	if _, err := tx.Exec(ctx, sql, vehicleID, twOpens, twCloses, daysOff); err != nil {
+		return nil, util.HandleDBError(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return q.DBListVehicleShifts(ctx, vehicleID)
+}
+
+// checkEditableShift returns an error when the shift is created from the shift recurrence of its vehicle,
+// as it would be created again when the project is scheduled
+func (q *Queries) checkEditableShift(ctx context.Context, shiftID int64) error {
+	shift, err := q.DBGetVehicleShift(ctx, shiftID)
+	if err != nil {
+		return err
+	}
+	if shift.Recurring {
+		return fmt.Errorf("Shifts created from the 'shift_recurrence' of the vehicle can not be modified")
+	}
+	return nil
+}
+
+func scanVehicleShiftRow(row pgx.Row) (VehicleShift, error) {
+	var i VehicleShift
+	err := row.Scan(
+		&i.ID,
+		&i.VehicleID,
+		&i.TwOpen,
+		&i.TwClose,
+		&i.DayOff,
+		&i.Recurring,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	err = util.HandleDBError(err)
+	return i, err
+}
+
+func scanVehicleShiftRows(rows pgx.Rows) ([]VehicleShift, error) {
+	items := []VehicleShift{}
+	for rows.Next() {
+		var i VehicleShift
+		if err := rows.Scan(
+			&i.ID,
+			&i.VehicleID,
+			&i.TwOpen,
+			&i.TwClose,
+			&i.DayOff,
+			&i.Recurring,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
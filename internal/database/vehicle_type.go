@@ -0,0 +1,347 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: vehicle_type.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/jackc/pgx/v4"
+)
+
+// VehicleTypeBreakParams is the template of a break created with each vehicle of a vehicle type
+type VehicleTypeBreakParams struct {
+	Service     *string      `json:"service" validate:"omitempty,duration" example:"00:30:00"`
+	TimeWindows *[][]string  `json:"time_windows" validate:"omitempty,dive,min=2,max=2,dive,datetime=2006-01-02T15:04:05"`
+	Data        *interface{} `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+}
+
+type CreateVehicleTypeParams struct {
+	Name          *string                   `json:"name" example:"Van"`
+	Capacity      *[]int64                  `json:"capacity" validate:"omitempty,dive,min=0" example:"50,25"`
+	Skills        *[]int32                  `json:"skills" validate:"omitempty,dive,skill" example:"1,5"`
+	TwOpen        *string                   `json:"tw_open" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-31T23:00:00"`
+	TwClose       *string                   `json:"tw_close" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-31T23:59:00"`
+	SpeedFactor   *float64                  `json:"speed_factor" validate:"omitempty,gt=0" example:"1.0"`
+	MaxTasks      *int32                    `json:"max_tasks" validate:"omitempty,gt=0" example:"20"`
+	MaxTravelTime *string                   `json:"max_travel_time" validate:"omitempty,duration" example:"08:00:00"`
+	MaxDistance   *int64                    `json:"max_distance" validate:"omitempty,min=0" example:"200000"`
+	FixedCost     *int64                    `json:"fixed_cost" validate:"omitempty,min=0" example:"5000"`
+	CostPerHour   *int64                    `json:"cost_per_hour" validate:"omitempty,min=0" example:"3600"`
+	CostPerKm     *int64                    `json:"cost_per_km" validate:"omitempty,min=0" example:"100"`
+	Breaks        *[]VehicleTypeBreakParams `json:"breaks" validate:"omitempty,dive"`
+	ProjectID     *int64                    `json:"project_id,string" validate:"required" swaggerignore:"true"`
+	Data          *interface{}              `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+}
+
+type UpdateVehicleTypeParams struct {
+	Name          *string                   `json:"name" example:"Van"`
+	Capacity      *[]int64                  `json:"capacity" validate:"omitempty,dive,min=0" example:"50,25"`
+	Skills        *[]int32                  `json:"skills" validate:"omitempty,dive,skill" example:"1,5"`
+	TwOpen        *string                   `json:"tw_open" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-31T23:00:00"`
+	TwClose       *string                   `json:"tw_close" validate:"omitempty,datetime=2006-01-02T15:04:05" example:"2021-12-31T23:59:00"`
+	SpeedFactor   *float64                  `json:"speed_factor" validate:"omitempty,gt=0" example:"1.0"`
+	MaxTasks      *int32                    `json:"max_tasks" validate:"omitempty,gt=0" example:"20"`
+	MaxTravelTime *string                   `json:"max_travel_time" validate:"omitempty,duration" example:"08:00:00"`
+	MaxDistance   *int64                    `json:"max_distance" validate:"omitempty,min=0" example:"200000"`
+	FixedCost     *int64                    `json:"fixed_cost" validate:"omitempty,min=0" example:"5000"`
+	CostPerHour   *int64                    `json:"cost_per_hour" validate:"omitempty,min=0" example:"3600"`
+	CostPerKm     *int64                    `json:"cost_per_km" validate:"omitempty,min=0" example:"100"`
+	Breaks        *[]VehicleTypeBreakParams `json:"breaks" validate:"omitempty,dive"`
+	ProjectID     *int64                    `json:"project_id,string" swaggerignore:"true"`
+	Data          *interface{}              `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+}
+
+// CreateVehiclesOfTypeParams are the vehicles of a vehicle type created at the same depot.
+// The end location is the start location when it is not given.
+type CreateVehiclesOfTypeParams struct {
+	Count         *int32               `json:"count" validate:"required,min=1,max=1000" example:"10"`
+	StartLocation *util.LocationParams `json:"start_location" validate:"required"`
+	EndLocation   *util.LocationParams `json:"end_location"`
+	Data          *interface{}         `json:"data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+}
+
+var errVehicleTypeNotFound = errors.New("Field 'vehicle_type_id' must be the ID of a vehicle type of the same project")
+
+func (q *Queries) DBCreateVehicleType(ctx context.Context, arg CreateVehicleTypeParams) (VehicleType, error) {
+	tableName := "vehicle_types"
+	arg.Breaks = getVehicleTypeBreaks(arg.Breaks)
+	sql, args := createResource(tableName, arg)
+	return_sql := " RETURNING " + util.GetOutputFields(VehicleType{}, tableName)
+	row := q.db.QueryRow(ctx, sql+return_sql, args...)
+	return scanVehicleTypeRow(row)
+}
+
+func (q *Queries) DBGetVehicleType(ctx context.Context, id int64) (VehicleType, error) {
+	tableName := "vehicle_types"
+	additionalQuery := " WHERE id = $1 AND deleted = FALSE LIMIT 1"
+	sql := "SELECT " + util.GetOutputFields(VehicleType{}, tableName) + " FROM " + tableName + additionalQuery
+	row := q.db.QueryRow(ctx, sql, id)
+	return scanVehicleTypeRow(row)
+}
+
+func (q *Queries) DBListVehicleTypes(ctx context.Context, projectID int64) ([]VehicleType, error) {
+	_, err := q.DBGetProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	tableName := "vehicle_types"
+	additionalQuery := " WHERE project_id = $1 AND deleted = FALSE ORDER BY created_at"
+	sql := "SELECT " + util.GetOutputFields(VehicleType{}, tableName) + " FROM " + tableName + additionalQuery
+	rows, err := q.db.Query(ctx, sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanVehicleTypeRows(rows)
+}
+
+func (q *Queries) DBUpdateVehicleType(ctx context.Context, arg UpdateVehicleTypeParams, vehicle_type_id int64) (VehicleType, error) {
+	tableName := "vehicle_types"
+	arg.Breaks = getVehicleTypeBreaks(arg.Breaks)
+	sql, args := updateResource(tableName, arg, vehicle_type_id)
+	return_sql := " AND deleted = FALSE RETURNING " + util.GetOutputFields(VehicleType{}, tableName)
+	row := q.db.QueryRow(ctx, sql+return_sql, args...)
+	return scanVehicleTypeRow(row)
+}
+
+// DBDeleteVehicleType deletes a vehicle type. The vehicles of the type are kept, along with their fields.
+func (q *Queries) DBDeleteVehicleType(ctx context.Context, id int64) (VehicleType, error) {
+	tableName := "vehicle_types"
+	sql := "UPDATE " + tableName + " SET deleted = TRUE WHERE id = $1 AND deleted = FALSE"
+	return_sql := " RETURNING " + util.GetOutputFields(VehicleType{}, tableName)
+	row := q.db.QueryRow(ctx, sql+return_sql, id)
+	return scanVehicleTypeRow(row)
+}
+
+// DBCreateVehiclesOfType creates the given number of vehicles of a vehicle type at the same depot,
+// along with the breaks of the type, in a single transaction
+func (q *Queries) DBCreateVehiclesOfType(ctx context.Context, vehicleTypeID int64, arg CreateVehiclesOfTypeParams) ([]Vehicle, error) {
+	vehicleType, err := q.DBGetVehicleType(ctx, vehicleTypeID)
+	if err != nil {
+		return nil, err
+	}
+	endLocation := arg.EndLocation
+	if endLocation == nil {
+		endLocation = arg.StartLocation
+	}
+	vehicles := make([]CreateVehicleParams, *arg.Count)
+	for i := range vehicles {
+		vehicles[i] = CreateVehicleParams{
+			StartLocation: arg.StartLocation,
+			EndLocation:   endLocation,
+			ProjectID:     &vehicleType.ProjectID,
+			Data:          arg.Data,
+		}
+	}
+	return q.createVehiclesOfType(ctx, vehicleType, vehicles)
+}
+
+// getProjectVehicleType returns a vehicle type of the project, which is not deleted
+func (q *Queries) getProjectVehicleType(ctx context.Context, projectID int64, vehicleTypeID int64) (VehicleType, error) {
+	vehicleType, err := q.DBGetVehicleType(ctx, vehicleTypeID)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && vehicleType.ProjectID != projectID) {
+		return VehicleType{}, errVehicleTypeNotFound
+	}
+	return vehicleType, err
+}
+
+// createVehiclesOfType creates the vehicles of a vehicle type in a single transaction, setting the fields of each
+// vehicle which are not given to the ones of the vehicle type, and creating the breaks of the type for each vehicle
+func (q *Queries) createVehiclesOfType(ctx context.Context, vehicleType VehicleType, vehicles []CreateVehicleParams) ([]Vehicle, error) {
+	params := ImportParams{}
+	for i := range vehicles {
+		vehicle := vehicles[i]
+		applyVehicleType(vehicleType, &vehicle)
+		params.Vehicles = append(params.Vehicles, ImportVehicleParams{Row: i + 1, CreateVehicleParams: vehicle})
+		for j := range vehicleType.Breaks {
+			vBreak := vehicleType.Breaks[j]
+			params.Breaks = append(params.Breaks, ImportBreakParams{
+				Row:          i + 1,
+				VehicleIndex: i,
+				CreateBreakParams: CreateBreakParams{
+					Service:     &vBreak.Service,
+					TimeWindows: &vBreak.TimeWindows,
+					Data:        getDataParam(vBreak.Data),
+				},
+			})
+		}
+	}
+
+	tx, err := q.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+	ids, err := importRows(ctx, tx, params, func(kind string, row int) string {
+		return fmt.Sprintf("Vehicle %d", row)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	tableName := "vehicles"
+	additionalQuery := " WHERE id = ANY($1) ORDER BY array_position($1, id)"
+	sql := "SELECT " + util.GetOutputFields(Vehicle{}, tableName) + " FROM " + tableName + additionalQuery
+	rows, err := q.db.Query(ctx, sql, ids.vehicles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanVehicleRows(rows)
+}
+
+// applyVehicleType sets the fields of the vehicle which are not given to the ones of its vehicle type
+func applyVehicleType(vehicleType VehicleType, vehicle *CreateVehicleParams) {
+	vehicle.VehicleTypeID = &vehicleType.ID
+	if vehicle.Capacity == nil && vehicleType.Capacity != nil {
+		vehicle.Capacity = &vehicleType.Capacity
+	}
+	if vehicle.Skills == nil && vehicleType.Skills != nil {
+		vehicle.Skills = &vehicleType.Skills
+	}
+	if vehicle.TwOpen == nil {
+		vehicle.TwOpen = vehicleType.TwOpen
+	}
+	if vehicle.TwClose == nil {
+		vehicle.TwClose = vehicleType.TwClose
+	}
+	if vehicle.SpeedFactor == nil {
+		vehicle.SpeedFactor = vehicleType.SpeedFactor
+	}
+	if vehicle.MaxTasks == nil {
+		vehicle.MaxTasks = vehicleType.MaxTasks
+	}
+	if vehicle.MaxTravelTime == nil {
+		vehicle.MaxTravelTime = vehicleType.MaxTravelTime
+	}
+	if vehicle.MaxDistance == nil {
+		vehicle.MaxDistance = vehicleType.MaxDistance
+	}
+	if vehicle.FixedCost == nil {
+		vehicle.FixedCost = vehicleType.FixedCost
+	}
+	if vehicle.CostPerHour == nil {
+		vehicle.CostPerHour = vehicleType.CostPerHour
+	}
+	if vehicle.CostPerKm == nil {
+		vehicle.CostPerKm = vehicleType.CostPerKm
+	}
+}
+
+// getVehicleTypeBreaks returns the breaks of a vehicle type with their default values,
+// so that the breaks of the vehicles of the type can be created from them
+func getVehicleTypeBreaks(breaks *[]VehicleTypeBreakParams) *[]VehicleTypeBreakParams {
+	if breaks == nil {
+		return nil
+	}
+	typeBreaks := make([]VehicleTypeBreakParams, len(*breaks))
+	for i, vBreak := range *breaks {
+		if vBreak.Service == nil {
+			service := "00:00:00"
+			vBreak.Service = &service
+		}
+		if vBreak.TimeWindows == nil {
+			vBreak.TimeWindows = &[][]string{}
+		}
+		if vBreak.Data == nil {
+			var data interface{} = map[string]interface{}{}
+			vBreak.Data = &data
+		}
+		typeBreaks[i] = vBreak
+	}
+	return &typeBreaks
+}
+
+func scanVehicleTypeRow(row pgx.Row) (VehicleType, error) {
+	var i VehicleType
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Capacity,
+		&i.Skills,
+		&i.TwOpen,
+		&i.TwClose,
+		&i.SpeedFactor,
+		&i.MaxTasks,
+		&i.MaxTravelTime,
+		&i.MaxDistance,
+		&i.FixedCost,
+		&i.CostPerHour,
+		&i.CostPerKm,
+		&i.Breaks,
+		&i.ProjectID,
+		&i.Data,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	err = util.HandleDBError(err)
+	return i, err
+}
+
+func scanVehicleTypeRows(rows pgx.Rows) ([]VehicleType, error) {
+	items := []VehicleType{}
+	for rows.Next() {
+		var i VehicleType
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Capacity,
+			&i.Skills,
+			&i.TwOpen,
+			&i.TwClose,
+			&i.SpeedFactor,
+			&i.MaxTasks,
+			&i.MaxTravelTime,
+			&i.MaxDistance,
+			&i.FixedCost,
+			&i.CostPerHour,
+			&i.CostPerKm,
+			&i.Breaks,
+			&i.ProjectID,
+			&i.Data,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
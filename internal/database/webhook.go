@@ -0,0 +1,284 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: webhook.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
+	"github.com/jackc/pgx/v4"
+)
+
+type CreateWebhookParams struct {
+	ProjectID *int64    `json:"project_id,string" example:"1234567812345678" validate:"required" swaggerignore:"true"`
+	URL       *string   `json:"url" validate:"required,webhook_url" example:"https://example.com/webhook"`
+	Secret    *string   `json:"secret" validate:"required" example:"3f1b6f9e0c2d4a8b"`
+	Events    *[]string `json:"events" validate:"omitempty,dive,webhook_event" example:"schedule.created,job.status_changed"`
+	Active    *bool     `json:"active" example:"true"`
+}
+
+type UpdateWebhookParams struct {
+	URL    *string   `json:"url" validate:"omitempty,webhook_url" example:"https://example.com/webhook"`
+	Secret *string   `json:"secret" validate:"omitempty,min=1" example:"3f1b6f9e0c2d4a8b"`
+	Events *[]string `json:"events" validate:"omitempty,dive,webhook_event" example:"schedule.created,job.status_changed"`
+	Active *bool     `json:"active" example:"true"`
+}
+
+// PendingWebhookDelivery is a delivery claimed by the dispatcher, along with the URL and the secret of its webhook
+type PendingWebhookDelivery struct {
+	ID       int64
+	Event    string
+	Payload  []byte
+	Attempts int32
+	URL      string
+	Secret   string
+}
+
+func (q *Queries) DBCreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	if _, err := q.DBGetProject(ctx, *arg.ProjectID); err != nil {
+		return Webhook{}, err
+	}
+	tableName := "webhooks"
+	sql, args := createResource(tableName, arg)
+	return_sql := " RETURNING id"
+	id, err := scanID(q.db.QueryRow(ctx, sql+return_sql, args...))
+	if err != nil {
+		return Webhook{}, err
+	}
+	return q.DBGetWebhook(ctx, id)
+}
+
+func (q *Queries) DBGetWebhook(ctx context.Context, id int64) (Webhook, error) {
+	tableName := "webhooks"
+	additionalQuery := " WHERE id = $1 LIMIT 1"
+	sql := "SELECT " + util.GetOutputFields(Webhook{}, tableName) + " FROM " + tableName + additionalQuery
+	row := q.db.QueryRow(ctx, sql, id)
+	return scanWebhookRow(row)
+}
+
+func (q *Queries) DBListWebhooks(ctx context.Context, projectID int64) ([]Webhook, error) {
+	if _, err := q.DBGetProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	tableName := "webhooks"
+	additionalQuery := " WHERE project_id = $1 ORDER BY created_at, id"
+	sql := "SELECT " + util.GetOutputFields(Webhook{}, tableName) + " FROM " + tableName + additionalQuery
+	rows, err := q.db.Query(ctx, sql, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanWebhookRows(rows)
+}
+
+func (q *Queries) DBUpdateWebhook(ctx context.Context, arg UpdateWebhookParams, webhookID int64) (Webhook, error) {
+	if _, err := q.DBGetWebhook(ctx, webhookID); err != nil {
+		return Webhook{}, err
+	}
+	tableName := "webhooks"
+	sql, args := updateResource(tableName, arg, webhookID)
+	if _, err := q.db.Exec(ctx, sql, args...); err != nil {
+		return Webhook{}, util.HandleDBError(err)
+	}
+	return q.DBGetWebhook(ctx, webhookID)
+}
+
+// DBDeleteWebhook deletes a webhook along with its deliveries
+func (q *Queries) DBDeleteWebhook(ctx context.Context, webhookID int64) error {
+	if _, err := q.DBGetWebhook(ctx, webhookID); err != nil {
+		return err
+	}
+	_, err := q.db.Exec(ctx, "DELETE FROM webhooks WHERE id = $1", webhookID)
+	return err
+}
+
+// DBListWebhookDeliveries returns the delivery log of a webhook, latest first, optionally filtered by status
+func (q *Queries) DBListWebhookDeliveries(ctx context.Context, webhookID int64, status string) ([]WebhookDelivery, error) {
+	if _, err := q.DBGetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	tableName := "webhook_deliveries"
+	additionalQuery := " WHERE webhook_id = $1 AND ($2::VARCHAR = '' OR status = $2) ORDER BY created_at DESC, id"
+	sql := "SELECT " + util.GetOutputFields(WebhookDelivery{}, tableName) + " FROM " + tableName + additionalQuery
+	rows, err := q.db.Query(ctx, sql, webhookID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanWebhookDeliveryRows(rows)
+}
+
+const claimWebhookDeliveries = `
+	UPDATE webhook_deliveries D
+	SET attempts = D.attempts + 1, next_attempt_at = current_timestamp + make_interval(secs => $2)
+	FROM webhooks W
+	WHERE W.id = D.webhook_id AND D.id IN (
+		SELECT P.id FROM webhook_deliveries P JOIN webhooks PW ON (PW.id = P.webhook_id)
+		WHERE P.status = 'pending' AND P.next_attempt_at <= current_timestamp AND PW.active
+		ORDER BY P.next_attempt_at, P.created_at
+		LIMIT $1
+		FOR UPDATE OF P SKIP LOCKED
+	)
+	RETURNING D.id, D.event, D.payload::TEXT, D.attempts, W.url, W.secret`
+
+// DBClaimWebhookDeliveries returns the pending deliveries of the active webhooks which are due, counting their
+// attempt. They are not claimed again during the lease, so that the deliveries of a crashed server are retried after
+// the lease and the servers sharing the database do not send the same delivery at the same time.
+func (q *Queries) DBClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]PendingWebhookDelivery, error) {
+	rows, err := q.db.Query(ctx, claimWebhookDeliveries, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PendingWebhookDelivery{}
+	for rows.Next() {
+		var i PendingWebhookDelivery
+		var payload string
+		if err := rows.Scan(&i.ID, &i.Event, &payload, &i.Attempts, &i.URL, &i.Secret); err != nil {
+			return nil, err
+		}
+		i.Payload = []byte(payload)
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const finishWebhookDelivery = `
+	UPDATE webhook_deliveries
+	SET status = $2, response_code = $3, error = $4,
+		next_attempt_at = CASE WHEN $2::VARCHAR = 'pending' THEN current_timestamp + make_interval(secs => $5) END
+	WHERE id = $1`
+
+// Mark the delivery as succeeded when deliveryErr is nil. Otherwise, the delivery is attempted again after the
+// retryDelay, or marked as failed when retryDelay is nil.
+func (q *Queries) DBFinishWebhookDelivery(ctx context.Context, deliveryID int64, responseCode *int32, deliveryErr error, retryDelay *time.Duration) error {
+	status := "succeeded"
+	var errMsg *string
+	var delay float64
+	if deliveryErr != nil {
+		status = "failed"
+		msg := deliveryErr.Error()
+		errMsg = &msg
+		if retryDelay != nil {
+			status = "pending"
+			delay = retryDelay.Seconds()
+		}
+	}
+	_, err := q.db.Exec(ctx, finishWebhookDelivery, deliveryID, status, responseCode, errMsg, delay)
+	return err
+}
+
+// DBPruneWebhookDeliveries deletes the succeeded and failed deliveries which were finished before the retention
+// period, and returns the number of deleted deliveries. The pending deliveries are kept.
+func (q *Queries) DBPruneWebhookDeliveries(ctx context.Context, retention time.Duration) (int64, error) {
+	sql := `
+	DELETE FROM webhook_deliveries
+	WHERE status != 'pending' AND updated_at < current_timestamp - make_interval(secs => $1)`
+	result, err := q.db.Exec(ctx, sql, retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// createEvent creates an event of a project, which is delivered to the webhooks of the project subscribed to it.
+// The events of the tasks and the schedule versions are created by the triggers of their tables.
+func (q *Queries) createEvent(ctx context.Context, projectID int64, event string, data map[string]interface{}) error {
+	_, err := q.db.Exec(ctx, "SELECT create_event($1, $2, $3)", projectID, event, data)
+	return err
+}
+
+func scanWebhookRow(row pgx.Row) (Webhook, error) {
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.URL,
+		&i.Events,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	err = util.HandleDBError(err)
+	return i, err
+}
+
+func scanWebhookRows(rows pgx.Rows) ([]Webhook, error) {
+	items := []Webhook{}
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.URL,
+			&i.Events,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func scanWebhookDeliveryRows(rows pgx.Rows) ([]WebhookDelivery, error) {
+	items := []WebhookDelivery{}
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.Event,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.ResponseCode,
+			&i.Error,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
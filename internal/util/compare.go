@@ -0,0 +1,315 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: compare.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+-------------------------
+Schedule Comparison
+-------------------------
+*/
+
+type ScheduleTotals struct {
+	TravelTime  string `json:"travel_time" example:"01:00:00"`
+	SetupTime   string `json:"setup_time" example:"00:05:00"`
+	ServiceTime string `json:"service_time" example:"00:10:00"`
+	WaitingTime string `json:"waiting_time" example:"00:30:00"`
+	Distance    int64  `json:"distance" example:"32400"`
+	Cost        int64  `json:"cost" example:"12600"`
+}
+
+type ScheduleDelta struct {
+	Base  ScheduleTotals `json:"base"`
+	Other ScheduleTotals `json:"other"`
+	Delta ScheduleTotals `json:"delta"`
+}
+
+type VehicleComparison struct {
+	VehicleID      *int64 `json:"vehicle_id,string" example:"1234567812345678"`
+	OtherVehicleID *int64 `json:"other_vehicle_id,string" example:"1234567812345678"`
+	ScheduleDelta
+}
+
+type TaskComparison struct {
+	Type           string   `json:"type" example:"job"`
+	TaskID         *int64   `json:"task_id,string" example:"1234567812345678"`
+	OtherTaskID    *int64   `json:"other_task_id,string" example:"1234567812345678"`
+	VehicleID      *int64   `json:"vehicle_id,string" example:"1234567812345678"`
+	OtherVehicleID *int64   `json:"other_vehicle_id,string" example:"1234567812345678"`
+	Sequence       *int     `json:"sequence" example:"1"`
+	OtherSequence  *int     `json:"other_sequence" example:"2"`
+	ArrivalShift   *string  `json:"arrival_shift" example:"-00:10:00"`
+	Changes        []string `json:"changes" example:"moved"`
+}
+
+type ScheduleComparison struct {
+	ProjectID      int64               `json:"project_id,string" example:"1234567812345678"`
+	OtherProjectID int64               `json:"other_project_id,string" example:"1234567812345678"`
+	Total          ScheduleDelta       `json:"total"`
+	Vehicles       []VehicleComparison `json:"vehicles"`
+	Tasks          []TaskComparison    `json:"tasks"`
+}
+
+// ParseDuration returns the number of seconds of a duration in the HH:MM:SS format, where the hours can exceed 24
+func ParseDuration(duration string) (int64, error) {
+	sign := int64(1)
+	if strings.HasPrefix(duration, "-") {
+		sign = -1
+		duration = duration[1:]
+	}
+	parts := strings.Split(duration, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("Invalid duration '%s'", duration)
+	}
+	var seconds int64
+	for _, part := range parts {
+		value, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid duration '%s'", duration)
+		}
+		seconds = seconds*60 + value
+	}
+	return sign * seconds, nil
+}
+
+// FormatDuration returns a number of seconds in the HH:MM:SS format, with a minus sign for negative durations
+func FormatDuration(seconds int64) string {
+	sign := ""
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d:%02d:%02d", sign, seconds/3600, seconds/60%60, seconds%60)
+}
+
+// getDurationDelta returns the difference of two durations in the HH:MM:SS format
+func getDurationDelta(base string, other string) string {
+	baseSeconds, _ := ParseDuration(base)
+	otherSeconds, _ := ParseDuration(other)
+	return FormatDuration(otherSeconds - baseSeconds)
+}
+
+func getScheduleDelta(base ScheduleTotals, other ScheduleTotals) ScheduleDelta {
+	return ScheduleDelta{
+		Base:  base,
+		Other: other,
+		Delta: ScheduleTotals{
+			TravelTime:  getDurationDelta(base.TravelTime, other.TravelTime),
+			SetupTime:   getDurationDelta(base.SetupTime, other.SetupTime),
+			ServiceTime: getDurationDelta(base.ServiceTime, other.ServiceTime),
+			WaitingTime: getDurationDelta(base.WaitingTime, other.WaitingTime),
+			Distance:    other.Distance - base.Distance,
+			Cost:        other.Cost - base.Cost,
+		},
+	}
+}
+
+var emptyScheduleTotals = ScheduleTotals{
+	TravelTime:  "00:00:00",
+	SetupTime:   "00:00:00",
+	ServiceTime: "00:00:00",
+	WaitingTime: "00:00:00",
+}
+
+// scheduleTask is the position of a task in a schedule
+type scheduleTask struct {
+	Type      string
+	TaskID    int64
+	VehicleID *int64
+	Sequence  *int
+	Arrival   string
+}
+
+// getScheduleTasks returns the tasks of the schedule in order, along with their key
+func getScheduleTasks(scheduleData ScheduleData, origins map[int64]int64) ([]string, map[string]scheduleTask) {
+	keys := []string{}
+	tasks := map[string]scheduleTask{}
+	addTask := func(task scheduleTask) {
+		key := fmt.Sprintf("%s:%d", task.Type, getOrigin(origins, task.TaskID))
+		keys = append(keys, key)
+		tasks[key] = task
+	}
+	for _, schedule := range scheduleData.Schedule {
+		vehicleID := schedule.VehicleID
+		for i, route := range schedule.Route {
+			// Skip the start and end of the route
+			if route.TaskID == -1 {
+				continue
+			}
+			sequence := i
+			addTask(scheduleTask{Type: route.Type, TaskID: route.TaskID, VehicleID: &vehicleID, Sequence: &sequence, Arrival: route.Arrival})
+		}
+	}
+	for _, unassigned := range scheduleData.Metadata.Unassigned {
+		addTask(scheduleTask{Type: unassigned.Type, TaskID: unassigned.TaskID})
+	}
+	return keys, tasks
+}
+
+// getOrigin returns the id of the original row of a cloned row, or the id itself
+func getOrigin(origins map[int64]int64, id int64) int64 {
+	if origin, found := origins[id]; found {
+		return origin
+	}
+	return id
+}
+
+func int64Pointer(value int64) *int64 {
+	return &value
+}
+
+// CompareSchedules compares the schedule of a project with the schedule of another project.
+// The vehicles and tasks of both schedules are matched using the origins maps, which map the id of
+// a cloned vehicle or task to the id of the original one.
+func CompareSchedules(base ScheduleData, other ScheduleData, baseOrigins map[int64]int64, otherOrigins map[int64]int64) ScheduleComparison {
+	comparison := ScheduleComparison{
+		ProjectID:      base.ProjectID,
+		OtherProjectID: other.ProjectID,
+		Total: getScheduleDelta(
+			ScheduleTotals{
+				TravelTime:  base.Metadata.TotalTravel,
+				SetupTime:   base.Metadata.TotalSetup,
+				ServiceTime: base.Metadata.TotalService,
+				WaitingTime: base.Metadata.TotalWaiting,
+				Distance:    base.Metadata.TotalDistance,
+				Cost:        base.Metadata.TotalCost,
+			},
+			ScheduleTotals{
+				TravelTime:  other.Metadata.TotalTravel,
+				SetupTime:   other.Metadata.TotalSetup,
+				ServiceTime: other.Metadata.TotalService,
+				WaitingTime: other.Metadata.TotalWaiting,
+				Distance:    other.Metadata.TotalDistance,
+				Cost:        other.Metadata.TotalCost,
+			},
+		),
+		Vehicles: []VehicleComparison{},
+		Tasks:    []TaskComparison{},
+	}
+
+	// Per-vehicle deltas
+	getTotals := func(summary ScheduleSummary) ScheduleTotals {
+		return ScheduleTotals{
+			TravelTime:  summary.TravelTime,
+			SetupTime:   summary.SetupTime,
+			ServiceTime: summary.ServiceTime,
+			WaitingTime: summary.WaitingTime,
+			Distance:    summary.TotalDistance,
+			Cost:        summary.Cost,
+		}
+	}
+	otherSummaries := map[int64]ScheduleSummary{}
+	for _, summary := range other.Metadata.Summary {
+		otherSummaries[getOrigin(otherOrigins, summary.VehicleID)] = summary
+	}
+	for _, summary := range base.Metadata.Summary {
+		origin := getOrigin(baseOrigins, summary.VehicleID)
+		vehicle := VehicleComparison{VehicleID: int64Pointer(summary.VehicleID)}
+		otherTotals := emptyScheduleTotals
+		if otherSummary, found := otherSummaries[origin]; found {
+			vehicle.OtherVehicleID = int64Pointer(otherSummary.VehicleID)
+			otherTotals = getTotals(otherSummary)
+			delete(otherSummaries, origin)
+		}
+		vehicle.ScheduleDelta = getScheduleDelta(getTotals(summary), otherTotals)
+		comparison.Vehicles = append(comparison.Vehicles, vehicle)
+	}
+	for _, summary := range other.Metadata.Summary {
+		if _, found := otherSummaries[getOrigin(otherOrigins, summary.VehicleID)]; !found {
+			continue
+		}
+		comparison.Vehicles = append(comparison.Vehicles, VehicleComparison{
+			OtherVehicleID: int64Pointer(summary.VehicleID),
+			ScheduleDelta:  getScheduleDelta(emptyScheduleTotals, getTotals(summary)),
+		})
+	}
+
+	// Changes of the tasks
+	baseKeys, baseTasks := getScheduleTasks(base, baseOrigins)
+	otherKeys, otherTasks := getScheduleTasks(other, otherOrigins)
+	for _, key := range baseKeys {
+		baseTask := baseTasks[key]
+		task := TaskComparison{
+			Type:      baseTask.Type,
+			TaskID:    int64Pointer(baseTask.TaskID),
+			VehicleID: baseTask.VehicleID,
+			Sequence:  baseTask.Sequence,
+			Changes:   []string{},
+		}
+		otherTask, found := otherTasks[key]
+		if !found {
+			task.Changes = append(task.Changes, "removed")
+			comparison.Tasks = append(comparison.Tasks, task)
+			continue
+		}
+		task.OtherTaskID = int64Pointer(otherTask.TaskID)
+		task.OtherVehicleID = otherTask.VehicleID
+		task.OtherSequence = otherTask.Sequence
+
+		switch {
+		case baseTask.VehicleID == nil && otherTask.VehicleID != nil:
+			task.Changes = append(task.Changes, "assigned")
+		case baseTask.VehicleID != nil && otherTask.VehicleID == nil:
+			task.Changes = append(task.Changes, "unassigned")
+		case baseTask.VehicleID != nil && otherTask.VehicleID != nil:
+			if getOrigin(baseOrigins, *baseTask.VehicleID) != getOrigin(otherOrigins, *otherTask.VehicleID) {
+				task.Changes = append(task.Changes, "moved")
+			} else if *baseTask.Sequence != *otherTask.Sequence {
+				task.Changes = append(task.Changes, "resequenced")
+			}
+			baseArrival, baseErr := time.Parse("2006-01-02T15:04:05", baseTask.Arrival)
+			otherArrival, otherErr := time.Parse("2006-01-02T15:04:05", otherTask.Arrival)
+			if baseErr == nil && otherErr == nil {
+				shift := FormatDuration(int64(otherArrival.Sub(baseArrival).Seconds()))
+				task.ArrivalShift = &shift
+			}
+		}
+		comparison.Tasks = append(comparison.Tasks, task)
+	}
+	for _, key := range otherKeys {
+		if _, found := baseTasks[key]; found {
+			continue
+		}
+		otherTask := otherTasks[key]
+		comparison.Tasks = append(comparison.Tasks, TaskComparison{
+			Type:           otherTask.Type,
+			OtherTaskID:    int64Pointer(otherTask.TaskID),
+			OtherVehicleID: otherTask.VehicleID,
+			OtherSequence:  otherTask.Sequence,
+			Changes:        []string{"added"},
+		})
+	}
+	return comparison
+}
@@ -0,0 +1,164 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: compare_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormatDuration(t *testing.T) {
+	seconds, err := ParseDuration("58:42:33")
+	require.NoError(t, err)
+	assert.Equal(t, int64(211353), seconds)
+	assert.Equal(t, "58:42:33", FormatDuration(seconds))
+
+	seconds, err = ParseDuration("-00:05:28")
+	require.NoError(t, err)
+	assert.Equal(t, int64(-328), seconds)
+	assert.Equal(t, "-00:05:28", FormatDuration(seconds))
+
+	_, err = ParseDuration("00:05")
+	assert.Error(t, err)
+}
+
+func TestCompareSchedules(t *testing.T) {
+	getRoute := func(taskType string, taskID int64, arrival string) ScheduleRoute {
+		return ScheduleRoute{Type: taskType, TaskID: taskID, Arrival: arrival}
+	}
+	base := ScheduleData{
+		Schedule: []ScheduleResponse{
+			{
+				VehicleID: 1,
+				Route: []ScheduleRoute{
+					getRoute("start", -1, "2021-12-01T13:00:00"),
+					getRoute("job", 10, "2021-12-01T13:10:00"),
+					getRoute("job", 11, "2021-12-01T13:20:00"),
+					getRoute("job", 12, "2021-12-01T13:30:00"),
+					getRoute("end", -1, "2021-12-01T13:40:00"),
+				},
+			},
+		},
+		Metadata: MetadataResponse{
+			Summary: []ScheduleSummary{
+				{VehicleID: 1, TravelTime: "00:40:00", SetupTime: "00:00:00", ServiceTime: "00:03:00", WaitingTime: "00:00:00", TotalDistance: 4000},
+			},
+			Unassigned:    []ScheduleUnassigned{{Type: "job", TaskID: 13}},
+			TotalTravel:   "00:40:00",
+			TotalSetup:    "00:00:00",
+			TotalService:  "00:03:00",
+			TotalWaiting:  "00:00:00",
+			TotalDistance: 4000,
+		},
+		ProjectID: 100,
+	}
+
+	// The other project is a clone, with the tasks 10-13 cloned as 20-23, and the vehicle 1 cloned as 2
+	other := ScheduleData{
+		Schedule: []ScheduleResponse{
+			{
+				VehicleID: 2,
+				Route: []ScheduleRoute{
+					getRoute("start", -1, "2021-12-01T13:00:00"),
+					getRoute("job", 21, "2021-12-01T13:05:00"),
+					getRoute("job", 20, "2021-12-01T13:15:00"),
+					getRoute("end", -1, "2021-12-01T13:25:00"),
+				},
+			},
+			{
+				VehicleID: 3,
+				Route: []ScheduleRoute{
+					getRoute("start", -1, "2021-12-01T13:00:00"),
+					getRoute("job", 22, "2021-12-01T13:30:00"),
+					getRoute("job", 23, "2021-12-01T13:45:00"),
+					getRoute("end", -1, "2021-12-01T14:00:00"),
+				},
+			},
+		},
+		Metadata: MetadataResponse{
+			Summary: []ScheduleSummary{
+				{VehicleID: 2, TravelTime: "00:25:00", SetupTime: "00:00:00", ServiceTime: "00:02:00", WaitingTime: "00:00:00", TotalDistance: 2500},
+				{VehicleID: 3, TravelTime: "01:00:00", SetupTime: "00:00:00", ServiceTime: "00:02:00", WaitingTime: "00:10:00", TotalDistance: 6000},
+			},
+			Unassigned:    []ScheduleUnassigned{},
+			TotalTravel:   "01:25:00",
+			TotalSetup:    "00:00:00",
+			TotalService:  "00:04:00",
+			TotalWaiting:  "00:10:00",
+			TotalDistance: 8500,
+		},
+		ProjectID: 200,
+	}
+	otherOrigins := map[int64]int64{2: 1, 20: 10, 21: 11, 22: 12, 23: 13}
+
+	comparison := CompareSchedules(base, other, map[int64]int64{}, otherOrigins)
+	assert.Equal(t, int64(100), comparison.ProjectID)
+	assert.Equal(t, int64(200), comparison.OtherProjectID)
+	assert.Equal(t, ScheduleTotals{TravelTime: "00:45:00", SetupTime: "00:00:00", ServiceTime: "00:01:00", WaitingTime: "00:10:00", Distance: 4500}, comparison.Total.Delta)
+
+	require.Len(t, comparison.Vehicles, 2)
+	assert.Equal(t, int64(1), *comparison.Vehicles[0].VehicleID)
+	assert.Equal(t, int64(2), *comparison.Vehicles[0].OtherVehicleID)
+	assert.Equal(t, ScheduleTotals{TravelTime: "-00:15:00", SetupTime: "00:00:00", ServiceTime: "-00:01:00", WaitingTime: "00:00:00", Distance: -1500}, comparison.Vehicles[0].Delta)
+	assert.Nil(t, comparison.Vehicles[1].VehicleID)
+	assert.Equal(t, int64(3), *comparison.Vehicles[1].OtherVehicleID)
+	assert.Equal(t, emptyScheduleTotals, comparison.Vehicles[1].Base)
+
+	require.Len(t, comparison.Tasks, 4)
+	changes := map[int64][]string{}
+	shifts := map[int64]*string{}
+	for _, task := range comparison.Tasks {
+		changes[*task.TaskID] = task.Changes
+		shifts[*task.TaskID] = task.ArrivalShift
+	}
+	assert.Equal(t, []string{"resequenced"}, changes[10])
+	assert.Equal(t, []string{"resequenced"}, changes[11])
+	assert.Equal(t, []string{"moved"}, changes[12])
+	assert.Equal(t, []string{"assigned"}, changes[13])
+	assert.Equal(t, "00:05:00", *shifts[10])
+	assert.Equal(t, "-00:15:00", *shifts[11])
+	assert.Equal(t, "00:00:00", *shifts[12])
+	assert.Nil(t, shifts[13])
+	assert.Equal(t, int64(20), *comparison.Tasks[0].OtherTaskID)
+}
+
+func TestCompareSchedulesAddedRemoved(t *testing.T) {
+	base := ScheduleData{Metadata: MetadataResponse{Unassigned: []ScheduleUnassigned{{Type: "job", TaskID: 1}}}}
+	other := ScheduleData{Metadata: MetadataResponse{Unassigned: []ScheduleUnassigned{{Type: "job", TaskID: 2}}}}
+
+	comparison := CompareSchedules(base, other, map[int64]int64{}, map[int64]int64{})
+	require.Len(t, comparison.Tasks, 2)
+	assert.Equal(t, []string{"removed"}, comparison.Tasks[0].Changes)
+	assert.Equal(t, int64(1), *comparison.Tasks[0].TaskID)
+	assert.Equal(t, []string{"added"}, comparison.Tasks[1].Changes)
+	assert.Equal(t, int64(2), *comparison.Tasks[1].OtherTaskID)
+	assert.Equal(t, []VehicleComparison{}, comparison.Vehicles)
+}
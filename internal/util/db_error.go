@@ -48,6 +48,8 @@ func HandleDBError(err error) error {
 				err = fmt.Errorf("Shipment with the given 'shipment_id' does not exist")
 			case "breaks_vehicle_id_fkey":
 				err = fmt.Errorf("Vehicle with the given 'vehicle_id' does not exist")
+			case "vehicle_shifts_vehicle_id_fkey":
+				err = fmt.Errorf("Vehicle with the given 'vehicle_id' does not exist")
 
 			case "jobs_check":
 				err = fmt.Errorf("Field 'pickup' and 'delivery' must have same length")
@@ -60,6 +62,12 @@ func HandleDBError(err error) error {
 				err = fmt.Errorf("Field 'tw_open' must be less than or equal to field 'tw_close'")
 			case "shipments_time_windows_check":
 				err = fmt.Errorf("Field 'tw_open' must be less than or equal to field 'tw_close'")
+			case "vehicle_types_check":
+				err = fmt.Errorf("Field 'tw_open' must be less than or equal to field 'tw_close'")
+			case "vehicle_shifts_check":
+				err = fmt.Errorf("Field 'tw_open' must be less than field 'tw_close'")
+			case "projects_horizon_check":
+				err = fmt.Errorf("Field 'horizon_start' must be less than or equal to field 'horizon_end', within 366 days")
 
 			case "jobs_time_windows_pkey":
 				err = fmt.Errorf("Jobs time window with given values already exist")
@@ -89,6 +97,12 @@ func HandleDBError(err error) error {
 				err = fmt.Errorf("Project with the given 'project_id' does not exist")
 			case "vehicles_project_id_fkey":
 				err = fmt.Errorf("Project with the given 'project_id' does not exist")
+			case "vehicle_types_project_id_fkey":
+				err = fmt.Errorf("Project with the given 'project_id' does not exist")
+			case "webhooks_project_id_fkey":
+				err = fmt.Errorf("Project with the given 'project_id' does not exist")
+			case "vehicles_vehicle_type_id_fkey":
+				err = fmt.Errorf("Vehicle type with the given 'vehicle_type_id' does not exist")
 			}
 		}
 	}
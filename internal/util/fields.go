@@ -29,27 +29,37 @@ along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
 package util
 
 var IntervalFields = map[string]bool{
-	"setup":        true,
-	"service":      true,
-	"p_setup":      true,
-	"p_service":    true,
-	"d_setup":      true,
-	"d_service":    true,
-	"travel_time":  true,
-	"setup_time":   true,
-	"service_time": true,
-	"waiting_time": true,
-	"max_shift":    true,
-	"timeout":      true,
+	"setup":           true,
+	"service":         true,
+	"p_setup":         true,
+	"p_service":       true,
+	"d_setup":         true,
+	"d_service":       true,
+	"travel_time":     true,
+	"setup_time":      true,
+	"service_time":    true,
+	"waiting_time":    true,
+	"max_shift":       true,
+	"timeout":         true,
+	"max_travel_time": true,
 }
 
 var TimestampFields = map[string]bool{
-	"tw_open":    true,
-	"tw_close":   true,
-	"arrival":    true,
-	"departure":  true,
-	"created_at": true,
-	"updated_at": true,
+	"tw_open":         true,
+	"tw_close":        true,
+	"arrival":         true,
+	"departure":       true,
+	"started_at":      true,
+	"finished_at":     true,
+	"created_at":      true,
+	"updated_at":      true,
+	"next_attempt_at": true,
+}
+
+var DateFields = map[string]bool{
+	"horizon_start": true,
+	"horizon_end":   true,
+	"occurrence":    true,
 }
 
 var AliasFields = map[string]string{
@@ -59,3 +69,11 @@ var AliasFields = map[string]string{
 	"start_location": "start_id",
 	"end_location":   "end_id",
 }
+
+// Fields which are set to NULL when a zero value is given, with their type
+var NullableFields = map[string]string{
+	"pinned_vehicle_id": "BIGINT",
+	"max_travel_time":   "INTERVAL",
+	"max_distance":      "BIGINT",
+	"vehicle_type_id":   "BIGINT",
+}
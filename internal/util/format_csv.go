@@ -0,0 +1,261 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: format_csv.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+-------------------------
+Table Format struct
+-------------------------
+*/
+
+// Table is a section of the CSV output, or a sheet of the XLSX output
+type Table struct {
+	Name   string
+	Header []string
+	Rows   [][]interface{}
+}
+
+// flattenData flattens the nested objects of the data to keys joined with a dot,
+// and converts the arrays to their JSON representation
+func flattenData(prefix string, data interface{}, flattened map[string]interface{}) {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenData(key, v, flattened)
+		}
+	case []interface{}:
+		b, err := json.Marshal(value)
+		if err != nil {
+			logrus.Error(err)
+		}
+		flattened[prefix] = string(b)
+	case nil:
+		if prefix != "" {
+			flattened[prefix] = ""
+		}
+	default:
+		if prefix == "" {
+			prefix = "value"
+		}
+		flattened[prefix] = value
+	}
+}
+
+// getDataColumns returns the flattened data of each row, along with the sorted union of its keys
+func getDataColumns(data []interface{}) ([]map[string]interface{}, []string) {
+	flattenedData := make([]map[string]interface{}, 0, len(data))
+	keys := map[string]bool{}
+	for _, d := range data {
+		flattened := map[string]interface{}{}
+		flattenData("", d, flattened)
+		for key := range flattened {
+			keys[key] = true
+		}
+		flattenedData = append(flattenedData, flattened)
+	}
+
+	columns := make([]string, 0, len(keys))
+	for key := range keys {
+		columns = append(columns, key)
+	}
+	sort.Strings(columns)
+	return flattenedData, columns
+}
+
+func appendDataColumns(header []string, rows [][]interface{}, data []interface{}) ([]string, [][]interface{}) {
+	flattenedData, columns := getDataColumns(data)
+	for _, column := range columns {
+		header = append(header, "task_data."+column)
+	}
+	for i := range rows {
+		for _, column := range columns {
+			value, ok := flattenedData[i][column]
+			if !ok {
+				value = ""
+			}
+			rows[i] = append(rows[i], value)
+		}
+	}
+	return header, rows
+}
+
+// escapeFormula prefixes the strings starting with a formula character with a quote, so that the data of the tasks
+// is not evaluated as a formula when the CSV output is opened in a spreadsheet application. The cells of the XLSX
+// output are typed, and their strings are never evaluated.
+func escapeFormula(value interface{}) interface{} {
+	if s, ok := value.(string); ok && s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return value
+}
+
+func getLoad(load []int64) string {
+	b, err := json.Marshal(load)
+	if err != nil {
+		logrus.Error(err)
+	}
+	return string(b)
+}
+
+// GetScheduleTables returns the schedule as three tables: the route steps of each vehicle without the off-shift
+// breaks, the unassigned tasks, and the summary of each vehicle along with the total summary
+func (r *Formatter) GetScheduleTables(scheduleData ScheduleData) []Table {
+	// Route steps, with the flattened task_data
+	header := []string{
+		"vehicle_id", "sequence", "type", "task_id", "latitude", "longitude", "arrival", "departure",
+		"travel_time", "setup_time", "service_time", "waiting_time", "distance", "load",
+	}
+	rows := [][]interface{}{}
+	taskData := []interface{}{}
+	for _, schedule := range scheduleData.Schedule {
+		sequence := 0
+		for _, route := range schedule.Route {
+			if route.OffShift {
+				continue
+			}
+			sequence++
+			rows = append(rows, []interface{}{
+				fmt.Sprintf("%d", schedule.VehicleID), sequence, route.Type, fmt.Sprintf("%d", route.TaskID),
+				*route.Location.Latitude, *route.Location.Longitude, route.Arrival, route.Departure,
+				route.TravelTime, route.SetupTime, route.ServiceTime, route.WaitingTime, route.Distance, getLoad(route.Load),
+			})
+			taskData = append(taskData, route.TaskData)
+		}
+	}
+	header, rows = appendDataColumns(header, rows, taskData)
+	scheduleTable := Table{Name: "Schedule", Header: header, Rows: rows}
+
+	// Unassigned tasks, with the flattened task_data
+	header = []string{"type", "task_id", "latitude", "longitude"}
+	rows = [][]interface{}{}
+	taskData = []interface{}{}
+	for _, task := range scheduleData.Metadata.Unassigned {
+		rows = append(rows, []interface{}{
+			task.Type, fmt.Sprintf("%d", task.TaskID), *task.Location.Latitude, *task.Location.Longitude,
+		})
+		taskData = append(taskData, task.TaskData)
+	}
+	header, rows = appendDataColumns(header, rows, taskData)
+	unassignedTable := Table{Name: "Unassigned", Header: header, Rows: rows}
+
+	// Summary of each vehicle, and the total summary
+	header = []string{"vehicle_id", "travel_time", "setup_time", "service_time", "waiting_time", "distance", "cost"}
+	rows = [][]interface{}{}
+	for _, summary := range scheduleData.Metadata.Summary {
+		rows = append(rows, []interface{}{
+			fmt.Sprintf("%d", summary.VehicleID), summary.TravelTime, summary.SetupTime,
+			summary.ServiceTime, summary.WaitingTime, summary.TotalDistance, summary.Cost,
+		})
+	}
+	if len(rows) != 0 {
+		metadata := scheduleData.Metadata
+		rows = append(rows, []interface{}{
+			"total", metadata.TotalTravel, metadata.TotalSetup, metadata.TotalService, metadata.TotalWaiting,
+			metadata.TotalDistance, metadata.TotalCost,
+		})
+	}
+	summaryTable := Table{Name: "Summary", Header: header, Rows: rows}
+
+	return []Table{scheduleTable, unassignedTable, summaryTable}
+}
+
+// SerializeCSV writes the tables one after the other, separating them with an empty line and the name of the table.
+// The formulas in the data of the tasks are escaped.
+func SerializeCSV(tables []Table) (string, error) {
+	b := &bytes.Buffer{}
+	writer := csv.NewWriter(b)
+	for i, table := range tables {
+		if i > 0 {
+			if err := writer.Write([]string{}); err != nil {
+				return "", err
+			}
+			if err := writer.Write([]string{table.Name}); err != nil {
+				return "", err
+			}
+		}
+		if err := writer.Write(table.Header); err != nil {
+			return "", err
+		}
+		for _, row := range table.Rows {
+			record := make([]string, len(row))
+			for j, value := range row {
+				if j < len(table.Header) && strings.HasPrefix(table.Header[j], "task_data.") {
+					value = escapeFormula(value)
+				}
+				record[j] = fmt.Sprint(value)
+			}
+			if err := writer.Write(record); err != nil {
+				return "", err
+			}
+		}
+	}
+	writer.Flush()
+	return b.String(), writer.Error()
+}
+
+func (r *Formatter) FormatCSV(w http.ResponseWriter, respCode int, tables []Table, filename string) {
+	data, err := SerializeCSV(localizeTables(tables, GetResponseLocation(w)))
+	if err != nil {
+		logrus.Error(err)
+		r.FormatJSON(w, http.StatusInternalServerError, nil)
+		return
+	}
+
+	// Set the content-type, content-disposition, and response code in the header
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.WriteHeader(respCode)
+
+	b := r.pool.Get().(*bytes.Buffer)
+	b.Reset()
+	defer r.pool.Put(b)
+
+	b.WriteString(data)
+
+	_, err = b.WriteTo(w)
+	if err != nil {
+		logrus.Error(err)
+	}
+}
@@ -0,0 +1,142 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: format_csv_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func getTestScheduleData() ScheduleData {
+	latitude, longitude := 48.6113, 2.0365
+	location := LocationParams{Latitude: &latitude, Longitude: &longitude}
+	return ScheduleData{
+		Schedule: []ScheduleResponse{
+			{
+				VehicleID: 1,
+				Route: []ScheduleRoute{
+					{Type: "start", TaskID: -1, Location: location, Arrival: "2021-12-01T13:00:00", Departure: "2021-12-01T13:00:00", TravelTime: "00:00:00", SetupTime: "00:00:00", ServiceTime: "00:00:00", WaitingTime: "00:00:00", Load: []int64{0}, TaskData: map[string]interface{}{}},
+					{Type: "break", TaskID: 5, Location: location, Arrival: "2021-12-01T00:00:00", Departure: "2021-12-01T13:00:00", TravelTime: "00:00:00", SetupTime: "00:00:00", ServiceTime: "13:00:00", WaitingTime: "00:00:00", Load: []int64{0}, TaskData: map[string]interface{}{"shift": "off"}, OffShift: true},
+					{Type: "job", TaskID: 2, Location: location, Arrival: "2021-12-01T13:00:00", Departure: "2021-12-01T13:02:00", TravelTime: "00:00:00", SetupTime: "00:00:00", ServiceTime: "00:02:00", WaitingTime: "00:00:00", Load: []int64{5}, TaskData: map[string]interface{}{"customer": map[string]interface{}{"name": "A, B", "floor": float64(2)}, "tags": []interface{}{"x", "y"}}},
+				},
+			},
+		},
+		Metadata: MetadataResponse{
+			Summary: []ScheduleSummary{
+				{VehicleID: 1, TravelTime: "00:00:00", SetupTime: "00:00:00", ServiceTime: "00:02:00", WaitingTime: "00:00:00", TotalDistance: 0, Cost: 120},
+			},
+			Unassigned: []ScheduleUnassigned{
+				{Type: "job", TaskID: 3, Location: location, TaskData: map[string]interface{}{"priority": "high"}},
+			},
+			TotalTravel:   "00:00:00",
+			TotalSetup:    "00:00:00",
+			TotalService:  "00:02:00",
+			TotalWaiting:  "00:00:00",
+			TotalDistance: 0,
+			TotalCost:     120,
+		},
+		ProjectID: 4,
+	}
+}
+
+func TestSerializeCSV(t *testing.T) {
+	tables := NewFormatter().GetScheduleTables(getTestScheduleData())
+	data, err := SerializeCSV(tables)
+	require.NoError(t, err)
+	assert.Equal(t, "vehicle_id,sequence,type,task_id,latitude,longitude,arrival,departure,travel_time,setup_time,service_time,waiting_time,distance,load,task_data.customer.floor,task_data.customer.name,task_data.tags\n"+
+		"1,1,start,-1,48.6113,2.0365,2021-12-01T13:00:00,2021-12-01T13:00:00,00:00:00,00:00:00,00:00:00,00:00:00,0,[0],,,\n"+
+		"1,2,job,2,48.6113,2.0365,2021-12-01T13:00:00,2021-12-01T13:02:00,00:00:00,00:00:00,00:02:00,00:00:00,0,[5],2,\"A, B\",\"[\"\"x\"\",\"\"y\"\"]\"\n"+
+		"\n"+
+		"Unassigned\n"+
+		"type,task_id,latitude,longitude,task_data.priority\n"+
+		"job,3,48.6113,2.0365,high\n"+
+		"\n"+
+		"Summary\n"+
+		"vehicle_id,travel_time,setup_time,service_time,waiting_time,distance,cost\n"+
+		"1,00:00:00,00:00:00,00:02:00,00:00:00,0,120\n"+
+		"total,00:00:00,00:00:00,00:02:00,00:00:00,0,120\n", data)
+}
+
+func TestEscapeFormula(t *testing.T) {
+	data := getTestScheduleData()
+	data.Metadata.Unassigned[0].TaskData = map[string]interface{}{
+		"formula": "=HYPERLINK(\"http://example.com\")", "plus": "+1", "minus": "-1", "at": "@SUM(A1)",
+		"number": float64(-1), "text": "a=b",
+	}
+	tables := NewFormatter().GetScheduleTables(data)
+	assert.Equal(t, []string{"type", "task_id", "latitude", "longitude", "task_data.at", "task_data.formula",
+		"task_data.minus", "task_data.number", "task_data.plus", "task_data.text"}, tables[1].Header)
+	assert.Equal(t, []interface{}{"@SUM(A1)", "=HYPERLINK(\"http://example.com\")", "-1", float64(-1), "+1", "a=b"}, tables[1].Rows[0][4:])
+
+	csvData, err := SerializeCSV(tables)
+	require.NoError(t, err)
+	assert.Contains(t, csvData, "job,3,48.6113,2.0365,'@SUM(A1),\"'=HYPERLINK(\"\"http://example.com\"\")\",'-1,-1,'+1,a=b\n")
+
+	b, err := SerializeXLSX(tables)
+	require.NoError(t, err)
+	f, err := excelize.OpenReader(b)
+	require.NoError(t, err)
+	defer f.Close()
+	value, err := f.GetCellValue("Unassigned", "F2")
+	require.NoError(t, err)
+	assert.Equal(t, "=HYPERLINK(\"http://example.com\")", value)
+}
+
+func TestSerializeXLSX(t *testing.T) {
+	tables := NewFormatter().GetScheduleTables(getTestScheduleData())
+	b, err := SerializeXLSX(tables)
+	require.NoError(t, err)
+
+	f, err := excelize.OpenReader(b)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{"Schedule", "Unassigned", "Summary"}, f.GetSheetList())
+
+	rows, err := f.GetRows("Unassigned")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"type", "task_id", "latitude", "longitude", "task_data.priority"},
+		{"job", "3", "48.6113", "2.0365", "high"},
+	}, rows)
+
+	rows, err = f.GetRows("Summary")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"vehicle_id", "travel_time", "setup_time", "service_time", "waiting_time", "distance", "cost"},
+		{"1", "00:00:00", "00:00:00", "00:02:00", "00:00:00", "0", "120"},
+		{"total", "00:00:00", "00:00:00", "00:02:00", "00:00:00", "0", "120"},
+	}, rows)
+
+	value, err := f.GetCellValue("Schedule", "Q3")
+	require.NoError(t, err)
+	assert.Equal(t, `["x","y"]`, value)
+}
@@ -0,0 +1,161 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: format_geojson.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+-------------------------
+GeoJSON Format struct
+-------------------------
+*/
+
+type Point struct {
+	Type        string    `json:"type" example:"Point"`
+	Coordinates []float64 `json:"coordinates" example:"2.0365,48.6113"`
+}
+
+type Feature struct {
+	Type       string                 `json:"type" example:"Feature"`
+	Geometry   interface{}            `json:"geometry" swaggertype:"object"`
+	Properties map[string]interface{} `json:"properties" swaggertype:"object,string" example:"type:job,vehicle_id:1234567812345678"`
+}
+
+type FeatureCollection struct {
+	Type     string    `json:"type" example:"FeatureCollection"`
+	Features []Feature `json:"features"`
+}
+
+func getPoint(location LocationParams) Point {
+	return Point{
+		Type:        "Point",
+		Coordinates: []float64{*location.Longitude, *location.Latitude},
+	}
+}
+
+func getRouteLineString(schedule ScheduleResponse) LineString {
+	// use the road path of the route, if present
+	if lineString, ok := schedule.Geometry.(LineString); ok {
+		return lineString
+	}
+
+	// otherwise, straight segments between the locations of the route
+	coordinates := make([][]float64, 0)
+	for _, route := range schedule.Route {
+		coordinate := []float64{*route.Location.Longitude, *route.Location.Latitude}
+		if len(coordinates) != 0 {
+			last := coordinates[len(coordinates)-1]
+			if last[0] == coordinate[0] && last[1] == coordinate[1] {
+				continue
+			}
+		}
+		coordinates = append(coordinates, coordinate)
+	}
+	return LineString{
+		Type:        "LineString",
+		Coordinates: coordinates,
+	}
+}
+
+func (r *Formatter) FormatGeoJSON(w http.ResponseWriter, respCode int, featureCollection FeatureCollection) {
+	// Set the content-type and response code in the header
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.WriteHeader(respCode)
+
+	b := r.pool.Get().(*bytes.Buffer)
+	b.Reset()
+	defer r.pool.Put(b)
+
+	featureCollection = localizeFeatures(featureCollection, GetResponseLocation(w))
+	if err := json.NewEncoder(b).Encode(featureCollection); err != nil {
+		logrus.Error(err)
+		return
+	}
+
+	_, err := b.WriteTo(w)
+	if err != nil {
+		logrus.Error(err)
+	}
+}
+
+// GetScheduleGeoJSON returns the schedule as a FeatureCollection, with a Point feature for each step of the
+// routes and for each unassigned task, and a LineString feature for each vehicle route when routes = true
+func (r *Formatter) GetScheduleGeoJSON(scheduleData ScheduleData, routes bool) FeatureCollection {
+	features := []Feature{}
+	for _, schedule := range scheduleData.Schedule {
+		vehicleID := fmt.Sprintf("%d", schedule.VehicleID)
+		if routes && len(schedule.Route) > 0 {
+			features = append(features, Feature{
+				Type:     "Feature",
+				Geometry: getRouteLineString(schedule),
+				Properties: map[string]interface{}{
+					"type":       "route",
+					"vehicle_id": vehicleID,
+				},
+			})
+		}
+		for _, route := range schedule.Route {
+			features = append(features, Feature{
+				Type:     "Feature",
+				Geometry: getPoint(route.Location),
+				Properties: map[string]interface{}{
+					"type":       route.Type,
+					"task_id":    fmt.Sprintf("%d", route.TaskID),
+					"vehicle_id": vehicleID,
+					"arrival":    route.Arrival,
+					"departure":  route.Departure,
+					"load":       route.Load,
+					"unassigned": false,
+				},
+			})
+		}
+	}
+	for _, task := range scheduleData.Metadata.Unassigned {
+		features = append(features, Feature{
+			Type:     "Feature",
+			Geometry: getPoint(task.Location),
+			Properties: map[string]interface{}{
+				"type":       task.Type,
+				"task_id":    fmt.Sprintf("%d", task.TaskID),
+				"unassigned": true,
+			},
+		})
+	}
+	return FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: features,
+	}
+}
@@ -51,6 +51,7 @@ type ScheduleDB struct {
 	SetupTime   string         `json:"setup_time" example:"00:00:00"`
 	ServiceTime string         `json:"service_time" example:"00:02:00"`
 	WaitingTime string         `json:"waiting_time" example:"00:00:00"`
+	Distance    int64          `json:"distance" example:"9000"`
 	Load        []int64        `json:"load" example:"0,0"`
 	VehicleData interface{}    `json:"vehicle_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
 	TaskData    interface{}    `json:"task_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
@@ -65,25 +66,31 @@ Schedule Response
 */
 
 type ScheduleRoute struct {
-	Type        string         `json:"type" example:"job"`
-	TaskID      int64          `json:"task_id,string" example:"1234567812345678"`
-	Location    LocationParams `json:"location"`
-	Arrival     string         `json:"arrival" example:"2021-12-01T13:00:00"`
-	Departure   string         `json:"departure" example:"2021-12-01T13:00:00"`
-	TravelTime  string         `json:"travel_time" example:"00:16:40"`
-	SetupTime   string         `json:"setup_time" example:"00:00:00"`
-	ServiceTime string         `json:"service_time" example:"00:02:00"`
-	WaitingTime string         `json:"waiting_time" example:"00:00:00"`
-	Load        []int64        `json:"load" example:"0,0"`
-	TaskData    interface{}    `json:"task_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
-	CreatedAt   string         `json:"created_at" example:"2021-12-01T13:00:00"`
-	UpdatedAt   string         `json:"updated_at" example:"2021-12-01T13:00:00"`
+	Type               string         `json:"type" example:"job"`
+	TaskID             int64          `json:"task_id,string" example:"1234567812345678"`
+	Location           LocationParams `json:"location"`
+	Arrival            string         `json:"arrival" example:"2021-12-01T13:00:00"`
+	Departure          string         `json:"departure" example:"2021-12-01T13:00:00"`
+	TravelTime         string         `json:"travel_time" example:"00:16:40"`
+	SetupTime          string         `json:"setup_time" example:"00:00:00"`
+	ServiceTime        string         `json:"service_time" example:"00:02:00"`
+	WaitingTime        string         `json:"waiting_time" example:"00:00:00"`
+	Distance           int64          `json:"distance" example:"9000"`
+	CumulativeDistance int64          `json:"cumulative_distance" example:"18000"`
+	Load               []int64        `json:"load" example:"0,0"`
+	TaskData           interface{}    `json:"task_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	CreatedAt          string         `json:"created_at" example:"2021-12-01T13:00:00"`
+	UpdatedAt          string         `json:"updated_at" example:"2021-12-01T13:00:00"`
+
+	// Whether the step is an off-shift break, generated from the shifts of the vehicle
+	OffShift bool `json:"-"`
 }
 
 type ScheduleResponse struct {
 	VehicleID   int64           `json:"vehicle_id,string"`
 	VehicleData interface{}     `json:"vehicle_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
 	Route       []ScheduleRoute `json:"route"`
+	Geometry    interface{}     `json:"geometry,omitempty" swaggertype:"object"`
 }
 
 /*
@@ -93,12 +100,14 @@ Metadata Response
 */
 
 type ScheduleSummary struct {
-	VehicleID   int64       `json:"vehicle_id,string" example:"1234567812345678"`
-	TravelTime  string      `json:"travel_time" example:"00:16:40"`
-	SetupTime   string      `json:"setup_time" example:"00:00:00"`
-	ServiceTime string      `json:"service_time" example:"00:02:00"`
-	WaitingTime string      `json:"waiting_time" example:"00:00:00"`
-	VehicleData interface{} `json:"vehicle_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	VehicleID     int64       `json:"vehicle_id,string" example:"1234567812345678"`
+	TravelTime    string      `json:"travel_time" example:"00:16:40"`
+	SetupTime     string      `json:"setup_time" example:"00:00:00"`
+	ServiceTime   string      `json:"service_time" example:"00:02:00"`
+	WaitingTime   string      `json:"waiting_time" example:"00:00:00"`
+	TotalDistance int64       `json:"total_distance" example:"18000"`
+	Cost          int64       `json:"cost" example:"6600"`
+	VehicleData   interface{} `json:"vehicle_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
 }
 
 type ScheduleUnassigned struct {
@@ -106,15 +115,18 @@ type ScheduleUnassigned struct {
 	TaskID   int64          `json:"task_id,string" example:"1234567812345678"`
 	Location LocationParams `json:"location"`
 	TaskData interface{}    `json:"task_data" swaggertype:"object,string" example:"key1:value1,key2:value2"`
+	Reasons  []string       `json:"reasons" example:"No vehicle has the skills [3]"`
 }
 
 type MetadataResponse struct {
-	Summary      []ScheduleSummary    `json:"summary"`
-	Unassigned   []ScheduleUnassigned `json:"unassigned"`
-	TotalTravel  string               `json:"total_travel" example:"01:00:00"`
-	TotalSetup   string               `json:"total_setup" example:"00:05:00"`
-	TotalService string               `json:"total_service" example:"00:10:00"`
-	TotalWaiting string               `json:"total_waiting" example:"00:30:00"`
+	Summary       []ScheduleSummary    `json:"summary"`
+	Unassigned    []ScheduleUnassigned `json:"unassigned"`
+	TotalTravel   string               `json:"total_travel" example:"01:00:00"`
+	TotalSetup    string               `json:"total_setup" example:"00:05:00"`
+	TotalService  string               `json:"total_service" example:"00:10:00"`
+	TotalWaiting  string               `json:"total_waiting" example:"00:30:00"`
+	TotalDistance int64                `json:"total_distance" example:"32400"`
+	TotalCost     int64                `json:"total_cost" example:"12600"`
 }
 
 /*
@@ -218,7 +230,7 @@ func (r *Formatter) FormatICAL(w http.ResponseWriter, respCode int, calendar []I
 	b.Reset()
 	defer r.pool.Put(b)
 
-	b = bytes.NewBufferString(SerializeICal(calendar))
+	b = bytes.NewBufferString(SerializeICal(localizeICal(calendar, GetResponseLocation(w))))
 
 	_, err := b.WriteTo(w)
 	if err != nil {
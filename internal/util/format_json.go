@@ -76,6 +76,15 @@ func getFinalData(respCode int, data interface{}) (int, interface{}) {
 	return respCode, data
 }
 
+// ErrorMessages returns the messages of the error, as returned in the error response
+func ErrorMessages(err error) []string {
+	_, data := getFinalData(http.StatusBadRequest, err)
+	if msgs, ok := data.([]string); ok {
+		return msgs
+	}
+	return []string{err.Error()}
+}
+
 func (r *Formatter) FormatJSON(w http.ResponseWriter, respCode int, data interface{}) {
 	respCode, data = getFinalData(respCode, data)
 
@@ -97,6 +106,9 @@ func (r *Formatter) FormatJSON(w http.ResponseWriter, respCode int, data interfa
 	defer r.pool.Put(b)
 
 	if respCode >= 200 && respCode < 300 {
+		if HasTimestampFields(data) {
+			data = LocalizeTimestamps(data, GetResponseLocation(w))
+		}
 		data = SuccessResponse{
 			Data:    data,
 			Message: http.StatusText(respCode),
@@ -0,0 +1,94 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: format_xlsx.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xuri/excelize/v2"
+)
+
+const XLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// SerializeXLSX writes each table in a separate sheet of the workbook
+func SerializeXLSX(tables []Table) (*bytes.Buffer, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	for i, table := range tables {
+		if i == 0 {
+			if err := f.SetSheetName(f.GetSheetName(0), table.Name); err != nil {
+				return nil, err
+			}
+		} else if _, err := f.NewSheet(table.Name); err != nil {
+			return nil, err
+		}
+
+		header := make([]interface{}, len(table.Header))
+		for j, column := range table.Header {
+			header[j] = column
+		}
+		if err := f.SetSheetRow(table.Name, "A1", &header); err != nil {
+			return nil, err
+		}
+		for j, row := range table.Rows {
+			cell, err := excelize.CoordinatesToCellName(1, j+2)
+			if err != nil {
+				return nil, err
+			}
+			row := row
+			if err := f.SetSheetRow(table.Name, cell, &row); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return f.WriteToBuffer()
+}
+
+func (r *Formatter) FormatXLSX(w http.ResponseWriter, respCode int, tables []Table, filename string) {
+	b, err := SerializeXLSX(localizeTables(tables, GetResponseLocation(w)))
+	if err != nil {
+		logrus.Error(err)
+		r.FormatJSON(w, http.StatusInternalServerError, nil)
+		return
+	}
+
+	// Set the content-type, content-disposition, and response code in the header
+	w.Header().Set("Content-Type", XLSXContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.WriteHeader(respCode)
+
+	_, err = b.WriteTo(w)
+	if err != nil {
+		logrus.Error(err)
+	}
+}
@@ -0,0 +1,105 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: import.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// importStringFields are the fields of the import which are kept as strings, instead of being parsed as JSON
+var importStringFields = map[string]bool{
+	"type":        true,
+	"ref":         true,
+	"vehicle_id":  true,
+	"vehicle_ref": true,
+}
+
+// ReadImportCSV reads the rows of a CSV file with a header row, to the same format as a JSON array of objects.
+// The columns with a dot in their name (e.g. "location.latitude") are converted to nested objects,
+// and the values are parsed as JSON when possible (e.g. numbers, arrays, and objects).
+func ReadImportCSV(r io.Reader) ([]map[string]interface{}, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid CSV file: %s", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	rows := []map[string]interface{}{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid CSV file: %s", err)
+		}
+
+		row := map[string]interface{}{}
+		for i, column := range header {
+			value := strings.TrimSpace(record[i])
+			if value == "" {
+				continue
+			}
+
+			// get the object containing the field, creating the nested objects if needed
+			keys := strings.Split(column, ".")
+			object := row
+			for _, key := range keys[:len(keys)-1] {
+				nested, ok := object[key].(map[string]interface{})
+				if !ok {
+					nested = map[string]interface{}{}
+					object[key] = nested
+				}
+				object = nested
+			}
+
+			field := keys[len(keys)-1]
+			var parsed interface{}
+			if importStringFields[field] || json.Unmarshal([]byte(value), &parsed) != nil {
+				object[field] = value
+			} else {
+				object[field] = parsed
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,92 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: import_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadImportCSV(t *testing.T) {
+	var cases = []struct {
+		name string
+		data string
+		rows []map[string]interface{}
+		err  string
+	}{
+		{
+			name: "empty",
+			data: "",
+			rows: []map[string]interface{}{},
+		},
+		{
+			name: "nested_and_json_fields",
+			data: "type,ref,location.latitude,location.longitude,service,delivery,time_windows,data\n" +
+				"job,,48.6113,2.0365,00:02:00,\"[10,20]\",\"[[\"\"2021-12-31T23:00:00\"\",\"\"2021-12-31T23:59:00\"\"]]\",\"{\"\"key\"\": \"\"value\"\"}\"\n",
+			rows: []map[string]interface{}{
+				{
+					"type":         "job",
+					"location":     map[string]interface{}{"latitude": 48.6113, "longitude": 2.0365},
+					"service":      "00:02:00",
+					"delivery":     []interface{}{float64(10), float64(20)},
+					"time_windows": []interface{}{[]interface{}{"2021-12-31T23:00:00", "2021-12-31T23:59:00"}},
+					"data":         map[string]interface{}{"key": "value"},
+				},
+			},
+		},
+		{
+			name: "string_fields",
+			data: "type, vehicle_id, vehicle_ref\nbreak, 1234567812345678, \nbreak, , 1\n",
+			rows: []map[string]interface{}{
+				{"type": "break", "vehicle_id": "1234567812345678"},
+				{"type": "break", "vehicle_ref": "1"},
+			},
+		},
+		{
+			name: "wrong_number_of_fields",
+			data: "type,ref\njob\n",
+			err:  "Invalid CSV file: record on line 2: wrong number of fields",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := ReadImportCSV(strings.NewReader(tc.data))
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.rows, rows)
+		})
+	}
+}
@@ -29,25 +29,33 @@ along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
 package util
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
-
-	"github.com/Georepublic/pg_scheduleserv/internal/config"
-	"github.com/sirupsen/logrus"
+	"time"
 )
 
+// Timeout of the requests to the routing engines, so that a routing engine which does not respond does not block
+// the schedule of a project
+const routingTimeout = 60 * time.Second
+
+var routingClient = &http.Client{Timeout: routingTimeout}
+
 // make get request to an url with content-type, and return the response body as json
-func Get(url string, contentType string, target interface{}) (int, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func Get(ctx context.Context, url string, contentType string, target interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return 0, err
 	}
 	req.Header.Set("Content-Type", contentType)
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := routingClient.Do(req)
 	if err != nil {
 		return 0, err
 	}
@@ -56,107 +64,370 @@ func Get(url string, contentType string, target interface{}) (int, error) {
 	return res.StatusCode, json.NewDecoder(res.Body).Decode(target)
 }
 
-func GetMatrix(locationIds []int64, durationCalc string) (startIds []int64, endIds []int64, durations []int64, err error) {
-	// iterate through locationIds, convert all the ids to latitude and longitude, and append [longitude, latitude] in an array
-	coordinates := make([][]float64, 0)
-	for _, id := range locationIds {
-		latitude, longitude := GetCoordinates(id)
-		coordinates = append(coordinates, []float64{longitude, latitude})
+// make post request to an url with the json body and headers, and return the response body as json
+func Post(ctx context.Context, url string, headers map[string]string, body interface{}, target interface{}) (int, error) {
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return 0, err
 	}
 
-	var matrix [][]int64
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 
-	config, err := config.LoadConfig(".")
+	res, err := routingClient.Do(req)
 	if err != nil {
-		logrus.Error("Cannot load config:", err)
+		return 0, err
 	}
+	defer res.Body.Close()
 
-	valhallaUrl := config.ValhallaUrl
-	valhallaUrl = strings.TrimSuffix(valhallaUrl, "/")
+	return res.StatusCode, json.NewDecoder(res.Body).Decode(target)
+}
 
-	osrmUrl := config.OsrmUrl
-	osrmUrl = strings.TrimSuffix(osrmUrl, "/")
+// UnreachableDuration is the duration (in seconds) between a pair of locations that can not be reached,
+// the max 16-bit unsigned integer value
+const UnreachableDuration = int64(1<<16 - 1)
 
-	// call the appropriate function to get the matrix
-	switch durationCalc {
-	case "euclidean":
-		matrix, err = GetEuclideanMatrix(coordinates)
-	case "valhalla":
-		matrix, err = GetMatrixFromValhalla(coordinates, valhallaUrl)
-	case "osrm":
-		matrix, err = GetMatrixFromOSRM(coordinates, osrmUrl)
-	default:
-		err = fmt.Errorf("Invalid duration calculation method")
-	}
+// UnreachableDistance is the distance (in meters) between a pair of locations that can not be reached, the max
+// 32-bit signed integer value, as the max 16-bit unsigned integer value is a reachable distance (65 km)
+const UnreachableDistance = int64(1<<31 - 1)
 
+// MatrixEntry is the duration (in seconds) and the distance (in meters) between a pair of locations
+type MatrixEntry struct {
+	StartID  int64
+	EndID    int64
+	Duration int64
+	Distance int64
+}
+
+// GetMatrix computes the durations and distances from each start location to its end locations.
+// The start locations having the same end locations are requested together,
+// so that only the required pairs are computed by the routing engine.
+func GetMatrix(ctx context.Context, pairs map[int64][]int64, durationCalc string) ([]MatrixEntry, error) {
+	provider, err := GetMatrixProvider(durationCalc)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, err
 	}
 
-	// iterate through the 2D matrix and append the start and end ids and durations. start id is locationIds[i], end id is locationIds[j], duration is matrix[i][j]
-	for i := 0; i < len(locationIds); i++ {
-		for j := 0; j < len(locationIds); j++ {
-			startIds = append(startIds, locationIds[i])
-			endIds = append(endIds, locationIds[j])
-			durations = append(durations, matrix[i][j])
+	// group the start ids having the same end ids
+	groups := make(map[string][]int64)
+	groupEndIds := make(map[string][]int64)
+	for startId, endIds := range pairs {
+		if len(endIds) == 0 {
+			continue
 		}
+		sortedEndIds := append([]int64{}, endIds...)
+		sort.Slice(sortedEndIds, func(i, j int) bool { return sortedEndIds[i] < sortedEndIds[j] })
+		key := fmt.Sprint(sortedEndIds)
+		groups[key] = append(groups[key], startId)
+		groupEndIds[key] = sortedEndIds
 	}
 
-	return startIds, endIds, durations, nil
+	entries := make([]MatrixEntry, 0)
+	for key, startIds := range groups {
+		endIds := groupEndIds[key]
+		durations, distances, err := provider.GetMatrix(ctx, startIds, endIds)
+		if err != nil {
+			return nil, err
+		}
+		if !validMatrixSize(durations, len(startIds), len(endIds)) || !validMatrixSize(distances, len(startIds), len(endIds)) {
+			return nil, fmt.Errorf("Invalid matrix size returned by the '%s' duration calculation method", durationCalc)
+		}
+
+		// iterate through the 2D matrices, start id is startIds[i], end id is endIds[j], duration is durations[i][j]
+		for i := 0; i < len(startIds); i++ {
+			for j := 0; j < len(endIds); j++ {
+				entries = append(entries, MatrixEntry{
+					StartID:  startIds[i],
+					EndID:    endIds[j],
+					Duration: durations[i][j],
+					Distance: distances[i][j],
+				})
+			}
+		}
+	}
+
+	return entries, nil
 }
 
-func GetMatrixFromOSRM(coordinates [][]float64, baseUrl string) ([][]int64, error) {
-	// convert the coordinates to a string
+// check whether the matrix has the rows x columns size
+func validMatrixSize(matrix [][]int64, rows int, columns int) bool {
+	if len(matrix) != rows {
+		return false
+	}
+	for _, row := range matrix {
+		if len(row) != columns {
+			return false
+		}
+	}
+	return true
+}
+
+// convert all the ids to latitude and longitude, and return [longitude, latitude] for each id
+func getLocationCoordinates(locationIds []int64) [][]float64 {
+	coordinates := make([][]float64, 0)
+	for _, id := range locationIds {
+		latitude, longitude := GetCoordinates(id)
+		coordinates = append(coordinates, []float64{longitude, latitude})
+	}
+	return coordinates
+}
+
+func GetMatrixFromOSRM(ctx context.Context, sources [][]float64, destinations [][]float64, baseUrl string) ([][]int64, [][]int64, error) {
+	// convert the coordinates to a string, sources followed by destinations
 	coordinatesString := make([]string, 0)
-	for _, coordinate := range coordinates {
+	sourceIndexes := make([]string, 0)
+	destinationIndexes := make([]string, 0)
+	for i, coordinate := range sources {
+		coordinatesString = append(coordinatesString, fmt.Sprintf("%.4f,%.4f", coordinate[0], coordinate[1]))
+		sourceIndexes = append(sourceIndexes, strconv.Itoa(i))
+	}
+	for i, coordinate := range destinations {
 		coordinatesString = append(coordinatesString, fmt.Sprintf("%.4f,%.4f", coordinate[0], coordinate[1]))
+		destinationIndexes = append(destinationIndexes, strconv.Itoa(len(sources)+i))
 	}
 
 	// call the osrm api function to get the matrix
-	url := fmt.Sprintf("%s/table/v1/driving/%s", baseUrl, strings.Join(coordinatesString, ";"))
+	url := fmt.Sprintf(
+		"%s/table/v1/driving/%s?sources=%s&destinations=%s&annotations=duration,distance",
+		baseUrl,
+		strings.Join(coordinatesString, ";"),
+		strings.Join(sourceIndexes, ";"),
+		strings.Join(destinationIndexes, ";"),
+	)
 
 	// decode the response body as json, pass json in Get() function
 	response := make(map[string]interface{})
-	statusCode, err := Get(url, "application/json", &response)
+	statusCode, err := Get(ctx, url, "application/json", &response)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("Error: %s", response["message"])
+	}
+
+	// get the matrices from the response, distances are in meters
+	durations, ok := response["durations"].([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("Error: Invalid response from OSRM")
+	}
+	distances, ok := response["distances"].([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("Error: Invalid response from OSRM")
+	}
+	return getInt64Matrices(durations, distances, 1)
+}
+
+func GetMatrixFromValhalla(ctx context.Context, sources [][]float64, destinations [][]float64, baseUrl string) ([][]int64, [][]int64, error) {
+	// call the osrm api function to get the matrix
+	url := fmt.Sprintf("%s/sources_to_targets", baseUrl)
+
+	// join coordinates as {"lon": longitude, "lat": latitude}
+	sourcesJson := make([]map[string]float64, 0)
+	for _, coordinate := range sources {
+		sourcesJson = append(sourcesJson, map[string]float64{"lon": coordinate[0], "lat": coordinate[1]})
+	}
+	targetsJson := make([]map[string]float64, 0)
+	for _, coordinate := range destinations {
+		targetsJson = append(targetsJson, map[string]float64{"lon": coordinate[0], "lat": coordinate[1]})
+	}
+
+	jsonBody := map[string]interface{}{"sources": sourcesJson, "targets": targetsJson, "costing": "auto", "units": "kilometers"}
+
+	// encode the json body
+	jsonBodyBytes, err := json.Marshal(jsonBody)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// change the url to url + "?json=" + jsonBodyBytes
+	url = fmt.Sprintf("%s?json=%s", url, string(jsonBodyBytes))
+
+	// decode the response body as json, pass json in Get() function
+	response := make(map[string]interface{})
+	statusCode, err := Get(ctx, url, "application/json", &response)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("Error: %s", response["message"])
+		return nil, nil, fmt.Errorf("Error: %s", response["message"])
 	}
 
 	// get the matrix from the response
-	matrix := response["durations"].([]interface{})
+	matrix, ok := response["sources_to_targets"].([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("Error: Invalid response from Valhalla")
+	}
 
-	// convert the matrix to int64
-	matrixInt64 := make([][]int64, 0)
+	// split the time and distance of each element of the matrix
+	durations := make([]interface{}, 0)
+	distances := make([]interface{}, 0)
 	for _, row := range matrix {
-		rowInt64 := make([]int64, 0)
-		for _, value := range row.([]interface{}) {
+		values, ok := row.([]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("Error: Invalid response from Valhalla")
+		}
+		durationsRow := make([]interface{}, 0)
+		distancesRow := make([]interface{}, 0)
+		for _, value := range values {
+			// the element of an unreachable pair of locations is null
 			if value == nil {
-				// append max 16 bytes integer value
-				rowInt64 = append(rowInt64, int64(1<<16-1))
-			} else {
-				rowInt64 = append(rowInt64, int64(value.(float64)))
+				durationsRow = append(durationsRow, nil)
+				distancesRow = append(distancesRow, nil)
+				continue
 			}
+			element, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, nil, fmt.Errorf("Error: Invalid response from Valhalla")
+			}
+			durationsRow = append(durationsRow, element["time"])
+			distancesRow = append(distancesRow, element["distance"])
 		}
-		matrixInt64 = append(matrixInt64, rowInt64)
+		durations = append(durations, durationsRow)
+		distances = append(distances, distancesRow)
 	}
-	return matrixInt64, nil
+
+	// distances are in kilometers
+	return getInt64Matrices(durations, distances, 1000)
 }
 
-func GetMatrixFromValhalla(coordinates [][]float64, baseUrl string) ([][]int64, error) {
-	// call the osrm api function to get the matrix
-	url := fmt.Sprintf("%s/sources_to_targets", baseUrl)
+func GetMatrixFromGraphHopper(ctx context.Context, sources [][]float64, destinations [][]float64, baseUrl string, apiKey string) ([][]int64, [][]int64, error) {
+	// call the graphhopper matrix api function to get the matrix
+	url := fmt.Sprintf("%s/matrix", baseUrl)
+	if apiKey != "" {
+		url = fmt.Sprintf("%s?key=%s", url, apiKey)
+	}
+
+	jsonBody := map[string]interface{}{
+		"from_points": sources,
+		"to_points":   destinations,
+		"out_arrays":  []string{"times", "distances"},
+		"profile":     "car",
+	}
+
+	// decode the response body as json, pass json in Post() function
+	response := make(map[string]interface{})
+	statusCode, err := Post(ctx, url, nil, jsonBody, &response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("Error: %s", response["message"])
+	}
+
+	// get the matrices from the response, distances are in meters
+	durations, ok := response["times"].([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("Error: Invalid response from GraphHopper")
+	}
+	distances, ok := response["distances"].([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("Error: Invalid response from GraphHopper")
+	}
+	return getInt64Matrices(durations, distances, 1)
+}
+
+func GetMatrixFromOpenRouteService(ctx context.Context, sources [][]float64, destinations [][]float64, baseUrl string, apiKey string) ([][]int64, [][]int64, error) {
+	// call the openrouteservice matrix api function to get the matrix
+	url := fmt.Sprintf("%s/v2/matrix/driving-car", baseUrl)
+
+	// the locations are the sources followed by the destinations
+	locations := append(append([][]float64{}, sources...), destinations...)
+	sourceIndexes := make([]int, 0)
+	destinationIndexes := make([]int, 0)
+	for i := range sources {
+		sourceIndexes = append(sourceIndexes, i)
+	}
+	for i := range destinations {
+		destinationIndexes = append(destinationIndexes, len(sources)+i)
+	}
+
+	jsonBody := map[string]interface{}{
+		"locations":    locations,
+		"sources":      sourceIndexes,
+		"destinations": destinationIndexes,
+		"metrics":      []string{"duration", "distance"},
+		"units":        "m",
+	}
+
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["Authorization"] = apiKey
+	}
+
+	// decode the response body as json, pass json in Post() function
+	response := make(map[string]interface{})
+	statusCode, err := Post(ctx, url, headers, jsonBody, &response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("Error: %v", response["error"])
+	}
+
+	// get the matrices from the response, distances are in meters
+	durations, ok := response["durations"].([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("Error: Invalid response from OpenRouteService")
+	}
+	distances, ok := response["distances"].([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("Error: Invalid response from OpenRouteService")
+	}
+	return getInt64Matrices(durations, distances, 1)
+}
+
+func GetRouteFromOSRM(ctx context.Context, coordinates [][]float64, baseUrl string) ([][]float64, error) {
+	// convert the coordinates to a string
+	coordinatesString := make([]string, 0)
+	for _, coordinate := range coordinates {
+		coordinatesString = append(coordinatesString, fmt.Sprintf("%.4f,%.4f", coordinate[0], coordinate[1]))
+	}
+
+	// call the osrm api function to get the route
+	url := fmt.Sprintf("%s/route/v1/driving/%s?overview=full&geometries=geojson", baseUrl, strings.Join(coordinatesString, ";"))
+
+	// decode the response body as json, pass json in Get() function
+	response := struct {
+		Message string `json:"message"`
+		Routes  []struct {
+			Geometry LineString `json:"geometry"`
+		} `json:"routes"`
+	}{}
+	statusCode, err := Get(ctx, url, "application/json", &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error: %s", response.Message)
+	}
+	if len(response.Routes) == 0 {
+		return nil, fmt.Errorf("Error: Invalid response from OSRM")
+	}
+	return response.Routes[0].Geometry.Coordinates, nil
+}
+
+func GetRouteFromValhalla(ctx context.Context, coordinates [][]float64, baseUrl string) ([][]float64, error) {
+	// call the valhalla api function to get the route
+	url := fmt.Sprintf("%s/route", baseUrl)
 
 	// join coordinates as {"lon": longitude, "lat": latitude}
-	coordinatesJson := make([]map[string]float64, 0)
+	locationsJson := make([]map[string]float64, 0)
 	for _, coordinate := range coordinates {
-		coordinatesJson = append(coordinatesJson, map[string]float64{"lon": coordinate[0], "lat": coordinate[1]})
+		locationsJson = append(locationsJson, map[string]float64{"lon": coordinate[0], "lat": coordinate[1]})
 	}
 
-	jsonBody := map[string]interface{}{"sources": coordinatesJson, "targets": coordinatesJson, "costing": "auto"}
+	jsonBody := map[string]interface{}{"locations": locationsJson, "costing": "auto"}
 
 	// encode the json body
 	jsonBodyBytes, err := json.Marshal(jsonBody)
@@ -168,25 +439,139 @@ func GetMatrixFromValhalla(coordinates [][]float64, baseUrl string) ([][]int64,
 	url = fmt.Sprintf("%s?json=%s", url, string(jsonBodyBytes))
 
 	// decode the response body as json, pass json in Get() function
-	response := make(map[string]interface{})
-	statusCode, err := Get(url, "application/json", &response)
+	response := struct {
+		Message string `json:"error"`
+		Trip    struct {
+			Legs []struct {
+				Shape string `json:"shape"`
+			} `json:"legs"`
+		} `json:"trip"`
+	}{}
+	statusCode, err := Get(ctx, url, "application/json", &response)
 	if err != nil {
 		return nil, err
 	}
 
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("Error: %s", response["message"])
+		return nil, fmt.Errorf("Error: %s", response.Message)
 	}
 
-	// get the matrix from the response
-	matrix := response["sources_to_targets"].([]interface{})
+	// decode the shape of each leg (polyline with precision 6), and join them
+	route := make([][]float64, 0)
+	for _, leg := range response.Trip.Legs {
+		shape, err := DecodePolyline(leg.Shape, 6)
+		if err != nil {
+			return nil, err
+		}
+		// the first point of a leg is the last point of the previous leg
+		if len(route) != 0 && len(shape) != 0 {
+			shape = shape[1:]
+		}
+		route = append(route, shape...)
+	}
+	return route, nil
+}
+
+func GetRouteFromGraphHopper(ctx context.Context, coordinates [][]float64, baseUrl string, apiKey string) ([][]float64, error) {
+	// call the graphhopper route api function to get the route
+	url := fmt.Sprintf("%s/route", baseUrl)
+	if apiKey != "" {
+		url = fmt.Sprintf("%s?key=%s", url, apiKey)
+	}
+
+	jsonBody := map[string]interface{}{
+		"points":         coordinates,
+		"profile":        "car",
+		"points_encoded": false,
+	}
+
+	// decode the response body as json, pass json in Post() function
+	response := struct {
+		Message string `json:"message"`
+		Paths   []struct {
+			Points LineString `json:"points"`
+		} `json:"paths"`
+	}{}
+	statusCode, err := Post(ctx, url, nil, jsonBody, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error: %s", response.Message)
+	}
+	if len(response.Paths) == 0 {
+		return nil, fmt.Errorf("Error: Invalid response from GraphHopper")
+	}
+	return response.Paths[0].Points.Coordinates, nil
+}
+
+func GetRouteFromOpenRouteService(ctx context.Context, coordinates [][]float64, baseUrl string, apiKey string) ([][]float64, error) {
+	// call the openrouteservice directions api function to get the route
+	url := fmt.Sprintf("%s/v2/directions/driving-car/geojson", baseUrl)
 
-	// convert the matrix to int64
+	jsonBody := map[string]interface{}{"coordinates": coordinates}
+
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["Authorization"] = apiKey
+	}
+
+	// decode the response body as json, pass json in Post() function
+	response := struct {
+		Error    interface{} `json:"error"`
+		Features []struct {
+			Geometry LineString `json:"geometry"`
+		} `json:"features"`
+	}{}
+	statusCode, err := Post(ctx, url, headers, jsonBody, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error: %v", response.Error)
+	}
+	if len(response.Features) == 0 {
+		return nil, fmt.Errorf("Error: Invalid response from OpenRouteService")
+	}
+	return response.Features[0].Geometry.Coordinates, nil
+}
+
+// convert the durations and distances in the json response to int64, with the distances multiplied by the
+// distanceMultiplier to get meters
+func getInt64Matrices(durations []interface{}, distances []interface{}, distanceMultiplier float64) ([][]int64, [][]int64, error) {
+	durationsInt64, err := getInt64Matrix(durations, 1, UnreachableDuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	distancesInt64, err := getInt64Matrix(distances, distanceMultiplier, UnreachableDistance)
+	if err != nil {
+		return nil, nil, err
+	}
+	return durationsInt64, distancesInt64, nil
+}
+
+// convert the matrix in the json response to int64 after multiplying the values with the multiplier,
+// with the unreachable (null) values as the unreachable value
+func getInt64Matrix(matrix []interface{}, multiplier float64, unreachable int64) ([][]int64, error) {
 	matrixInt64 := make([][]int64, 0)
 	for _, row := range matrix {
+		values, ok := row.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Error: Invalid row in the matrix: %v", row)
+		}
 		rowInt64 := make([]int64, 0)
-		for _, value := range row.([]interface{}) {
-			rowInt64 = append(rowInt64, int64(value.(map[string]interface{})["time"].(float64)))
+		for _, value := range values {
+			if value == nil {
+				rowInt64 = append(rowInt64, unreachable)
+				continue
+			}
+			number, ok := value.(float64)
+			if !ok {
+				return nil, fmt.Errorf("Error: Invalid value in the matrix: %v", value)
+			}
+			rowInt64 = append(rowInt64, int64(number*multiplier))
 		}
 		matrixInt64 = append(matrixInt64, rowInt64)
 	}
@@ -209,17 +594,22 @@ func haversine(point1 []float64, point2 []float64) float64 {
 	return c * R
 }
 
-func GetEuclideanMatrix(coordinates [][]float64) ([][]int64, error) {
+func GetEuclideanMatrix(sources [][]float64, destinations [][]float64) ([][]int64, [][]int64, error) {
 	speed := 9.0 // m/sec
 
 	// get distance between each pair of coordinates using haversine formula
-	matrix := make([][]int64, 0)
-	for i := 0; i < len(coordinates); i++ {
-		row := make([]int64, 0)
-		for j := 0; j < len(coordinates); j++ {
-			row = append(row, int64(haversine(coordinates[i], coordinates[j])/speed))
+	durations := make([][]int64, 0)
+	distances := make([][]int64, 0)
+	for i := 0; i < len(sources); i++ {
+		durationsRow := make([]int64, 0)
+		distancesRow := make([]int64, 0)
+		for j := 0; j < len(destinations); j++ {
+			distance := haversine(sources[i], destinations[j])
+			durationsRow = append(durationsRow, int64(distance/speed))
+			distancesRow = append(distancesRow, int64(distance))
 		}
-		matrix = append(matrix, row)
+		durations = append(durations, durationsRow)
+		distances = append(distances, distancesRow)
 	}
-	return matrix, nil
+	return durations, distances, nil
 }
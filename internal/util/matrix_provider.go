@@ -0,0 +1,251 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: matrix_provider.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Georepublic/pg_scheduleserv/internal/config"
+)
+
+// MatrixProvider computes the durations (in seconds) and the distances (in meters) from each of
+// the start locations to each of the end locations, returned as len(startIds) x len(endIds) matrices.
+// The context is the context of the request, which cancels the queries to the database or to the routing engine.
+type MatrixProvider interface {
+	GetMatrix(ctx context.Context, startIds []int64, endIds []int64) (durations [][]int64, distances [][]int64, err error)
+}
+
+var (
+	matrixProvidersMu sync.RWMutex
+	matrixProviders   = make(map[string]MatrixProvider)
+)
+
+// RegisterMatrixProvider makes a matrix provider available by the name, which can then be
+// used as the "duration_calc" of a project
+func RegisterMatrixProvider(name string, provider MatrixProvider) {
+	matrixProvidersMu.Lock()
+	defer matrixProvidersMu.Unlock()
+	matrixProviders[name] = provider
+}
+
+// GetMatrixProvider returns the matrix provider registered with the name
+func GetMatrixProvider(name string) (MatrixProvider, error) {
+	matrixProvidersMu.RLock()
+	defer matrixProvidersMu.RUnlock()
+	provider, ok := matrixProviders[name]
+	if !ok {
+		return nil, fmt.Errorf("Invalid duration calculation method")
+	}
+	return provider, nil
+}
+
+// MatrixProviderNames returns the sorted names of all the registered matrix providers
+func MatrixProviderNames() []string {
+	matrixProvidersMu.RLock()
+	defer matrixProvidersMu.RUnlock()
+	names := make([]string, 0, len(matrixProviders))
+	for name := range matrixProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterMatrixProviders registers the built-in matrix providers. The euclidean, osrm and valhalla
+// providers are always registered, the other providers only when they are configured.
+func RegisterMatrixProviders(config config.Config) error {
+	RegisterMatrixProvider("euclidean", EuclideanProvider{})
+	RegisterMatrixProvider("osrm", OSRMProvider{Url: strings.TrimSuffix(config.OsrmUrl, "/")})
+	RegisterMatrixProvider("valhalla", ValhallaProvider{Url: strings.TrimSuffix(config.ValhallaUrl, "/")})
+
+	if config.GraphHopperUrl != "" {
+		RegisterMatrixProvider("graphhopper", GraphHopperProvider{
+			Url:    strings.TrimSuffix(config.GraphHopperUrl, "/"),
+			ApiKey: config.GraphHopperApiKey,
+		})
+	}
+	if config.OpenRouteServiceUrl != "" {
+		RegisterMatrixProvider("openrouteservice", OpenRouteServiceProvider{
+			Url:    strings.TrimSuffix(config.OpenRouteServiceUrl, "/"),
+			ApiKey: config.OpenRouteServiceApiKey,
+		})
+	}
+	if config.StaticMatrixFile != "" {
+		provider, err := NewStaticMatrixProvider(config.StaticMatrixFile)
+		if err != nil {
+			return err
+		}
+		RegisterMatrixProvider("static", provider)
+	}
+	return nil
+}
+
+// EuclideanProvider computes the durations from the haversine distance with a constant speed
+type EuclideanProvider struct{}
+
+func (p EuclideanProvider) GetMatrix(ctx context.Context, startIds []int64, endIds []int64) ([][]int64, [][]int64, error) {
+	return GetEuclideanMatrix(getLocationCoordinates(startIds), getLocationCoordinates(endIds))
+}
+
+// OSRMProvider computes the durations using the table service of the OSRM API
+type OSRMProvider struct {
+	Url string
+}
+
+func (p OSRMProvider) GetMatrix(ctx context.Context, startIds []int64, endIds []int64) ([][]int64, [][]int64, error) {
+	return GetMatrixFromOSRM(ctx, getLocationCoordinates(startIds), getLocationCoordinates(endIds), p.Url)
+}
+
+func (p OSRMProvider) GetRoute(ctx context.Context, locationIds []int64) ([][]float64, error) {
+	return GetRouteFromOSRM(ctx, getLocationCoordinates(locationIds), p.Url)
+}
+
+// ValhallaProvider computes the durations using the sources_to_targets service of the Valhalla API
+type ValhallaProvider struct {
+	Url string
+}
+
+func (p ValhallaProvider) GetMatrix(ctx context.Context, startIds []int64, endIds []int64) ([][]int64, [][]int64, error) {
+	return GetMatrixFromValhalla(ctx, getLocationCoordinates(startIds), getLocationCoordinates(endIds), p.Url)
+}
+
+func (p ValhallaProvider) GetRoute(ctx context.Context, locationIds []int64) ([][]float64, error) {
+	return GetRouteFromValhalla(ctx, getLocationCoordinates(locationIds), p.Url)
+}
+
+// GraphHopperProvider computes the durations using the matrix service of the GraphHopper API
+type GraphHopperProvider struct {
+	Url    string
+	ApiKey string
+}
+
+func (p GraphHopperProvider) GetMatrix(ctx context.Context, startIds []int64, endIds []int64) ([][]int64, [][]int64, error) {
+	return GetMatrixFromGraphHopper(ctx, getLocationCoordinates(startIds), getLocationCoordinates(endIds), p.Url, p.ApiKey)
+}
+
+func (p GraphHopperProvider) GetRoute(ctx context.Context, locationIds []int64) ([][]float64, error) {
+	return GetRouteFromGraphHopper(ctx, getLocationCoordinates(locationIds), p.Url, p.ApiKey)
+}
+
+// OpenRouteServiceProvider computes the durations using the matrix service of the OpenRouteService API
+type OpenRouteServiceProvider struct {
+	Url    string
+	ApiKey string
+}
+
+func (p OpenRouteServiceProvider) GetMatrix(ctx context.Context, startIds []int64, endIds []int64) ([][]int64, [][]int64, error) {
+	return GetMatrixFromOpenRouteService(ctx, getLocationCoordinates(startIds), getLocationCoordinates(endIds), p.Url, p.ApiKey)
+}
+
+func (p OpenRouteServiceProvider) GetRoute(ctx context.Context, locationIds []int64) ([][]float64, error) {
+	return GetRouteFromOpenRouteService(ctx, getLocationCoordinates(locationIds), p.Url, p.ApiKey)
+}
+
+// StaticMatrixProvider returns the durations and distances read from a CSV file with the
+// "start_id,end_id,duration[,distance]" columns, having the location ids, the durations
+// in seconds and the optional distances in meters
+type StaticMatrixProvider struct {
+	durations map[[2]int64]int64
+	distances map[[2]int64]int64
+}
+
+func NewStaticMatrixProvider(filename string) (*StaticMatrixProvider, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadStaticMatrix(file)
+}
+
+// ReadStaticMatrix reads the durations and distances in CSV format, skipping the header row if present
+func ReadStaticMatrix(r io.Reader) (*StaticMatrixProvider, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	durations := make(map[[2]int64]int64)
+	distances := make(map[[2]int64]int64)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) != 3 && len(record) != 4 {
+			return nil, fmt.Errorf("Invalid number of fields in line %d of the matrix file", line)
+		}
+		if line == 1 && record[0] == "start_id" {
+			continue
+		}
+
+		values := make([]int64, len(record))
+		for i, field := range record {
+			values[i], err = strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid value '%s' in line %d of the matrix file", field, line)
+			}
+		}
+		durations[[2]int64{values[0], values[1]}] = values[2]
+		if len(values) == 4 {
+			distances[[2]int64{values[0], values[1]}] = values[3]
+		}
+	}
+	return &StaticMatrixProvider{durations: durations, distances: distances}, nil
+}
+
+func (p *StaticMatrixProvider) GetMatrix(ctx context.Context, startIds []int64, endIds []int64) ([][]int64, [][]int64, error) {
+	durations := make([][]int64, 0)
+	distances := make([][]int64, 0)
+	for _, startId := range startIds {
+		durationsRow := make([]int64, 0)
+		distancesRow := make([]int64, 0)
+		for _, endId := range endIds {
+			duration, ok := p.durations[[2]int64{startId, endId}]
+			if !ok && startId != endId {
+				return nil, nil, fmt.Errorf("Duration from location %d to location %d is not present in the matrix file", startId, endId)
+			}
+			durationsRow = append(durationsRow, duration)
+			distancesRow = append(distancesRow, p.distances[[2]int64{startId, endId}])
+		}
+		durations = append(durations, durationsRow)
+		distances = append(distances, distancesRow)
+	}
+	return durations, distances, nil
+}
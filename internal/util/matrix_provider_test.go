@@ -0,0 +1,197 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: matrix_provider_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadStaticMatrix(t *testing.T) {
+	var cases = []struct {
+		name     string
+		data     string
+		startIds []int64
+		endIds   []int64
+		matrix   [][]int64
+		distance [][]int64
+		err      string
+	}{
+		{
+			name:     "with_header",
+			data:     "start_id,end_id,duration\n1,2,10\n2,1,20\n",
+			startIds: []int64{1, 2},
+			endIds:   []int64{1, 2},
+			matrix:   [][]int64{{0, 10}, {20, 0}},
+			distance: [][]int64{{0, 0}, {0, 0}},
+		},
+		{
+			name:     "with_distance",
+			data:     "start_id,end_id,duration,distance\n1,2,10,100\n2,1,20,200\n",
+			startIds: []int64{1, 2},
+			endIds:   []int64{1, 2},
+			matrix:   [][]int64{{0, 10}, {20, 0}},
+			distance: [][]int64{{0, 100}, {200, 0}},
+		},
+		{
+			name:     "without_header_missing_pair",
+			data:     "1, 2, 10\n2, 3, 30\n",
+			startIds: []int64{1, 2},
+			endIds:   []int64{2, 3},
+			err:      "Duration from location 1 to location 3 is not present in the matrix file",
+		},
+		{
+			name:     "same_location",
+			data:     "1,2,10\n2,3,30\n",
+			startIds: []int64{1, 2},
+			endIds:   []int64{2},
+			matrix:   [][]int64{{10}, {0}},
+			distance: [][]int64{{0}, {0}},
+		},
+		{
+			name:     "missing_pair",
+			data:     "1,2,10\n",
+			startIds: []int64{2},
+			endIds:   []int64{1},
+			err:      "Duration from location 2 to location 1 is not present in the matrix file",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := ReadStaticMatrix(strings.NewReader(tc.data))
+			require.NoError(t, err)
+
+			matrix, distance, err := provider.GetMatrix(context.Background(), tc.startIds, tc.endIds)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.matrix, matrix)
+			assert.Equal(t, tc.distance, distance)
+		})
+	}
+}
+
+func TestReadStaticMatrixInvalid(t *testing.T) {
+	_, err := ReadStaticMatrix(strings.NewReader("1,2,abc\n"))
+	assert.EqualError(t, err, "Invalid value 'abc' in line 1 of the matrix file")
+
+	_, err = ReadStaticMatrix(strings.NewReader("1,2\n"))
+	assert.EqualError(t, err, "Invalid number of fields in line 1 of the matrix file")
+}
+
+func TestGetMatrix(t *testing.T) {
+	provider, err := ReadStaticMatrix(strings.NewReader("1,2,10,100\n1,3,15,150\n2,1,20,200\n2,3,25,250\n3,1,30,300\n3,2,35,350\n"))
+	require.NoError(t, err)
+	RegisterMatrixProvider("test_static", provider)
+	assert.Contains(t, MatrixProviderNames(), "test_static")
+
+	// Only the requested pairs are returned
+	entries, err := GetMatrix(context.Background(), map[int64][]int64{
+		1: {3},
+		2: {3},
+		3: {1, 2, 3},
+	}, "test_static")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []MatrixEntry{
+		{StartID: 1, EndID: 3, Duration: 15, Distance: 150},
+		{StartID: 2, EndID: 3, Duration: 25, Distance: 250},
+		{StartID: 3, EndID: 1, Duration: 30, Distance: 300},
+		{StartID: 3, EndID: 2, Duration: 35, Distance: 350},
+		{StartID: 3, EndID: 3, Duration: 0, Distance: 0},
+	}, entries)
+
+	_, err = GetMatrix(context.Background(), map[int64][]int64{1: {2}}, "invalid")
+	assert.EqualError(t, err, "Invalid duration calculation method")
+}
+
+func TestGetMatrixFromValhalla(t *testing.T) {
+	var cases = []struct {
+		name     string
+		response string
+		matrix   [][]int64
+		distance [][]int64
+		err      string
+	}{
+		{
+			name:     "reachable",
+			response: `{"sources_to_targets": [[{"time": 0, "distance": 0}, {"time": 10, "distance": 1.5}]]}`,
+			matrix:   [][]int64{{0, 10}},
+			distance: [][]int64{{0, 1500}},
+		},
+		{
+			name:     "unreachable",
+			response: `{"sources_to_targets": [[{"time": 0, "distance": 0}, null], [{"time": null, "distance": null}, {"time": 0, "distance": 0}]]}`,
+			matrix:   [][]int64{{0, 65535}, {65535, 0}},
+			distance: [][]int64{{0, UnreachableDistance}, {UnreachableDistance, 0}},
+		},
+		{
+			name:     "invalid_row",
+			response: `{"sources_to_targets": [{"time": 0, "distance": 0}]}`,
+			err:      "Error: Invalid response from Valhalla",
+		},
+		{
+			name:     "invalid_element",
+			response: `{"sources_to_targets": [[10]]}`,
+			err:      "Error: Invalid response from Valhalla",
+		},
+		{
+			name:     "invalid_value",
+			response: `{"sources_to_targets": [[{"time": "10", "distance": 0}]]}`,
+			err:      "Error: Invalid value in the matrix: 10",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer server.Close()
+
+			matrix, distance, err := GetMatrixFromValhalla(context.Background(), [][]float64{{1, 1}}, [][]float64{{1, 1}}, server.URL)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.matrix, matrix)
+			assert.Equal(t, tc.distance, distance)
+		})
+	}
+}
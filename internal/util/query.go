@@ -97,11 +97,7 @@ func GetPartialSQL(resource interface{}) PartialSQL {
 		case reflect.String:
 			partialSQL.Args = append(partialSQL.Args, val.String())
 		case reflect.Bool:
-			if val.Bool() {
-				partialSQL.Args = append(partialSQL.Args, 1)
-			} else {
-				partialSQL.Args = append(partialSQL.Args, 0)
-			}
+			partialSQL.Args = append(partialSQL.Args, val.Bool())
 		case reflect.Struct:
 			value := val.Interface()
 			if typ, ok := value.(LocationParams); ok {
@@ -126,6 +122,10 @@ func GetFormattedInterval(fieldName string) string {
 	return fmt.Sprintf("to_char(%s, 'HH24:MI:SS')", fieldName)
 }
 
+func GetFormattedDate(fieldName string) string {
+	return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD')", fieldName)
+}
+
 func GetFormattedTimestamp(fieldName string) string {
 	return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD') || 'T' || to_char(%s, 'HH24:MI:SS')", fieldName, fieldName)
 }
@@ -153,6 +153,9 @@ func GetOutputFields(resourceStruct interface{}, tableName string) (sql string)
 		if _, timestampFieldFound := TimestampFields[fieldName]; timestampFieldFound {
 			fullFieldName = GetFormattedTimestamp(fullFieldName)
 		}
+		if _, dateFieldFound := DateFields[fieldName]; dateFieldFound {
+			fullFieldName = GetFormattedDate(fullFieldName)
+		}
 		if i != 0 {
 			sql += ","
 		}
@@ -0,0 +1,245 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: recurrence.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+-------------------------
+Recurrence
+-------------------------
+*/
+
+// DateLayout is the layout of the dates of the planning horizon and of the occurrences
+const DateLayout = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Recurrence is a recurrence rule, using a subset of the RRULE of RFC 5545:
+// FREQ (DAILY, WEEKLY or MONTHLY), INTERVAL, BYDAY (without ordinals), BYMONTHDAY, COUNT and UNTIL (as a date)
+type Recurrence struct {
+	Freq       string
+	Interval   int
+	ByDay      map[time.Weekday]bool
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time
+}
+
+// ParseRecurrence parses a recurrence rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", with an optional "RRULE:" prefix
+func ParseRecurrence(rule string) (Recurrence, error) {
+	recurrence := Recurrence{Interval: 1, ByDay: map[time.Weekday]bool{}}
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	for _, part := range strings.Split(rule, ";") {
+		keyValue := strings.SplitN(part, "=", 2)
+		if len(keyValue) != 2 || keyValue[1] == "" {
+			return Recurrence{}, fmt.Errorf("Invalid recurrence rule part '%s'", part)
+		}
+		key, value := strings.ToUpper(keyValue[0]), strings.ToUpper(keyValue[1])
+		switch key {
+		case "FREQ":
+			if value != "DAILY" && value != "WEEKLY" && value != "MONTHLY" {
+				return Recurrence{}, fmt.Errorf("Invalid recurrence frequency '%s'", value)
+			}
+			recurrence.Freq = value
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return Recurrence{}, fmt.Errorf("Invalid recurrence interval '%s'", value)
+			}
+			recurrence.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, found := weekdays[day]
+				if !found {
+					return Recurrence{}, fmt.Errorf("Invalid recurrence day '%s'", day)
+				}
+				recurrence.ByDay[weekday] = true
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				monthDay, err := strconv.Atoi(day)
+				if err != nil || monthDay == 0 || monthDay < -31 || monthDay > 31 {
+					return Recurrence{}, fmt.Errorf("Invalid recurrence month day '%s'", day)
+				}
+				recurrence.ByMonthDay = append(recurrence.ByMonthDay, monthDay)
+			}
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return Recurrence{}, fmt.Errorf("Invalid recurrence count '%s'", value)
+			}
+			recurrence.Count = count
+		case "UNTIL":
+			if len(value) > 8 {
+				value = value[:8]
+			}
+			until, err := time.Parse("20060102", value)
+			if err != nil {
+				return Recurrence{}, fmt.Errorf("Invalid recurrence end '%s'", value)
+			}
+			recurrence.Until = &until
+		default:
+			return Recurrence{}, fmt.Errorf("Unsupported recurrence rule part '%s'", key)
+		}
+	}
+	if recurrence.Freq == "" {
+		return Recurrence{}, fmt.Errorf("The recurrence frequency FREQ is required")
+	}
+	if recurrence.Count > 0 && recurrence.Until != nil {
+		return Recurrence{}, fmt.Errorf("The recurrence COUNT and UNTIL can not be both given")
+	}
+	return recurrence, nil
+}
+
+// matches returns whether the recurrence occurs on a date, starting from the date dtstart
+func (recurrence Recurrence) matches(dtstart time.Time, date time.Time) bool {
+	switch recurrence.Freq {
+	case "DAILY":
+		days := int(date.Sub(dtstart).Hours() / 24)
+		return days%recurrence.Interval == 0 && (len(recurrence.ByDay) == 0 || recurrence.ByDay[date.Weekday()])
+	case "WEEKLY":
+		weeks := int(startOfWeek(date).Sub(startOfWeek(dtstart)).Hours() / (24 * 7))
+		if len(recurrence.ByDay) == 0 {
+			return weeks%recurrence.Interval == 0 && date.Weekday() == dtstart.Weekday()
+		}
+		return weeks%recurrence.Interval == 0 && recurrence.ByDay[date.Weekday()]
+	case "MONTHLY":
+		months := (date.Year()-dtstart.Year())*12 + int(date.Month()) - int(dtstart.Month())
+		if months%recurrence.Interval != 0 {
+			return false
+		}
+		if len(recurrence.ByMonthDay) == 0 {
+			return date.Day() == dtstart.Day() && (len(recurrence.ByDay) == 0 || recurrence.ByDay[date.Weekday()])
+		}
+		lastDay := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		for _, monthDay := range recurrence.ByMonthDay {
+			if monthDay == date.Day() || lastDay+monthDay+1 == date.Day() {
+				return len(recurrence.ByDay) == 0 || recurrence.ByDay[date.Weekday()]
+			}
+		}
+	}
+	return false
+}
+
+// Dates returns the dates of the occurrences of the recurrence between the dates from and to (both included),
+// the first occurrence being on or after the date dtstart. The time of the dates is ignored.
+func (recurrence Recurrence) Dates(dtstart time.Time, from time.Time, to time.Time) []time.Time {
+	dtstart, from, to = truncateDate(dtstart), truncateDate(from), truncateDate(to)
+	if recurrence.Until != nil && recurrence.Until.Before(to) {
+		to = *recurrence.Until
+	}
+	dates := []time.Time{}
+	count := 0
+	for date := dtstart; !date.After(to); date = date.AddDate(0, 0, 1) {
+		if !recurrence.matches(dtstart, date) {
+			continue
+		}
+		count++
+		if recurrence.Count > 0 && count > recurrence.Count {
+			break
+		}
+		if !date.Before(from) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// truncateDate returns the date of a time, at midnight in UTC
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// startOfWeek returns the monday of the week of a date
+func startOfWeek(date time.Time) time.Time {
+	return date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+}
+
+// ShiftWindow is a time window of a vehicle shift, in the "2006-01-02T15:04:05" format
+type ShiftWindow struct {
+	TwOpen  string
+	TwClose string
+}
+
+// GetRecurringShifts returns the shifts of a vehicle on the dates of a recurrence between the dates from and to.
+// The first shift is the time window of the vehicle, and each shift starts at the same time of the day and has the
+// same duration.
+func GetRecurringShifts(rule string, twOpen string, twClose string, from time.Time, to time.Time) ([]ShiftWindow, error) {
+	recurrence, err := ParseRecurrence(rule)
+	if err != nil {
+		return nil, err
+	}
+	openTime, err := time.Parse("2006-01-02T15:04:05", twOpen)
+	if err != nil {
+		return nil, err
+	}
+	closeTime, err := time.Parse("2006-01-02T15:04:05", twClose)
+	if err != nil {
+		return nil, err
+	}
+	shifts := []ShiftWindow{}
+	for _, date := range recurrence.Dates(openTime, from, to) {
+		shiftOpen := date.Add(openTime.Sub(truncateDate(openTime)))
+		shifts = append(shifts, ShiftWindow{
+			TwOpen:  shiftOpen.Format("2006-01-02T15:04:05"),
+			TwClose: shiftOpen.Add(closeTime.Sub(openTime)).Format("2006-01-02T15:04:05"),
+		})
+	}
+	return shifts, nil
+}
+
+// GetOffShiftBreaks returns the breaks of a vehicle between its shifts, ordered by their start, as the time window
+// of the break and its service time. The vehicle can not serve any task during an off-shift break.
+func GetOffShiftBreaks(shifts []ShiftWindow) ([]ShiftWindow, []string) {
+	windows, services := []ShiftWindow{}, []string{}
+	for i := 1; i < len(shifts); i++ {
+		start := parseTime(shifts[i-1].TwClose)
+		end := parseTime(shifts[i].TwOpen)
+		if !end.After(start) {
+			continue
+		}
+		windows = append(windows, ShiftWindow{TwOpen: shifts[i-1].TwClose, TwClose: shifts[i-1].TwClose})
+		services = append(services, FormatDuration(int64(end.Sub(start).Seconds())))
+	}
+	return windows, services
+}
@@ -0,0 +1,114 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: recurrence_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	for _, rule := range []string{
+		"", "FREQ=YEARLY", "FREQ=WEEKLY;BYDAY=XX", "FREQ=DAILY;INTERVAL=0", "INTERVAL=2",
+		"FREQ=MONTHLY;BYMONTHDAY=32", "FREQ=DAILY;COUNT=2;UNTIL=20211231", "FREQ=DAILY;BYHOUR=8",
+	} {
+		_, err := ParseRecurrence(rule)
+		assert.Error(t, err, rule)
+	}
+
+	recurrence, err := ParseRecurrence("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20211231T235959Z")
+	require.NoError(t, err)
+	assert.Equal(t, "WEEKLY", recurrence.Freq)
+	assert.Equal(t, 2, recurrence.Interval)
+	assert.Equal(t, map[time.Weekday]bool{time.Monday: true, time.Wednesday: true}, recurrence.ByDay)
+	assert.Equal(t, time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC), *recurrence.Until)
+}
+
+func TestRecurrenceDates(t *testing.T) {
+	date := func(value string) time.Time {
+		d, err := time.Parse(DateLayout, value)
+		require.NoError(t, err)
+		return d
+	}
+	formatDates := func(dates []time.Time) []string {
+		values := []string{}
+		for _, d := range dates {
+			values = append(values, d.Format(DateLayout))
+		}
+		return values
+	}
+
+	testCases := []struct {
+		rule    string
+		dtstart string
+		from    string
+		to      string
+		dates   []string
+	}{
+		{"FREQ=DAILY", "2021-12-01", "2021-12-03", "2021-12-05", []string{"2021-12-03", "2021-12-04", "2021-12-05"}},
+		{"FREQ=DAILY;INTERVAL=2", "2021-12-01", "2021-12-01", "2021-12-06", []string{"2021-12-01", "2021-12-03", "2021-12-05"}},
+		{"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", "2021-12-03", "2021-12-01", "2021-12-07", []string{"2021-12-03", "2021-12-06", "2021-12-07"}},
+		{"FREQ=WEEKLY", "2021-12-01", "2021-12-01", "2021-12-20", []string{"2021-12-01", "2021-12-08", "2021-12-15"}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", "2021-11-29", "2021-12-01", "2021-12-16", []string{"2021-12-02", "2021-12-13", "2021-12-16"}},
+		{"FREQ=MONTHLY", "2021-10-15", "2021-11-01", "2022-01-31", []string{"2021-11-15", "2021-12-15", "2022-01-15"}},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1", "2021-11-01", "2021-11-01", "2021-12-31", []string{"2021-11-01", "2021-11-30", "2021-12-01", "2021-12-31"}},
+		{"FREQ=DAILY;COUNT=3", "2021-12-01", "2021-12-02", "2021-12-10", []string{"2021-12-02", "2021-12-03"}},
+		{"FREQ=DAILY;UNTIL=20211203", "2021-12-01", "2021-12-01", "2021-12-10", []string{"2021-12-01", "2021-12-02", "2021-12-03"}},
+		{"FREQ=DAILY", "2021-12-20", "2021-12-01", "2021-12-10", []string{}},
+	}
+	for _, tc := range testCases {
+		recurrence, err := ParseRecurrence(tc.rule)
+		require.NoError(t, err)
+		assert.Equal(t, tc.dates, formatDates(recurrence.Dates(date(tc.dtstart), date(tc.from), date(tc.to))), tc.rule)
+	}
+}
+
+func TestRecurringShifts(t *testing.T) {
+	from, to := time.Date(2021, 12, 3, 0, 0, 0, 0, time.UTC), time.Date(2021, 12, 7, 0, 0, 0, 0, time.UTC)
+	shifts, err := GetRecurringShifts("FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", "2021-12-01T22:00:00", "2021-12-02T06:00:00", from, to)
+	require.NoError(t, err)
+	assert.Equal(t, []ShiftWindow{
+		{TwOpen: "2021-12-03T22:00:00", TwClose: "2021-12-04T06:00:00"},
+		{TwOpen: "2021-12-06T22:00:00", TwClose: "2021-12-07T06:00:00"},
+		{TwOpen: "2021-12-07T22:00:00", TwClose: "2021-12-08T06:00:00"},
+	}, shifts)
+
+	windows, services := GetOffShiftBreaks(shifts)
+	assert.Equal(t, []ShiftWindow{
+		{TwOpen: "2021-12-04T06:00:00", TwClose: "2021-12-04T06:00:00"},
+		{TwOpen: "2021-12-07T06:00:00", TwClose: "2021-12-07T06:00:00"},
+	}, windows)
+	assert.Equal(t, []string{"64:00:00", "16:00:00"}, services)
+
+	_, err = GetRecurringShifts("FREQ=HOURLY", "2021-12-01T22:00:00", "2021-12-02T06:00:00", from, to)
+	assert.Error(t, err)
+}
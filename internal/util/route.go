@@ -0,0 +1,170 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: route.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// RouteProvider is implemented by the matrix providers which can also compute the road path
+// through the locations, returned as the [longitude, latitude] coordinates of the path
+type RouteProvider interface {
+	GetRoute(ctx context.Context, locationIds []int64) ([][]float64, error)
+}
+
+// LineString is a GeoJSON LineString geometry with [longitude, latitude] coordinates
+type LineString struct {
+	Type        string      `json:"type" example:"LineString"`
+	Coordinates [][]float64 `json:"coordinates" swaggertype:"array,number" example:"48.6113,2.0365"`
+}
+
+// GetRouteGeometry returns the path through the locations using the provider of the duration calculation
+// method. Straight segments between the locations are returned if the provider can not compute the path.
+func GetRouteGeometry(ctx context.Context, locationIds []int64, durationCalc string) ([][]float64, error) {
+	provider, err := GetMatrixProvider(durationCalc)
+	if err != nil {
+		return nil, err
+	}
+
+	// a single location is returned as a line of zero length
+	if len(locationIds) == 1 {
+		locationIds = append(locationIds, locationIds[0])
+	}
+
+	if routeProvider, ok := provider.(RouteProvider); ok && len(locationIds) > 1 {
+		return routeProvider.GetRoute(ctx, locationIds)
+	}
+	return getLocationCoordinates(locationIds), nil
+}
+
+// AddScheduleGeometry adds the geometry of the route of each vehicle in the schedule,
+// either as a GeoJSON LineString (format = "geojson") or an encoded polyline (format = "polyline")
+func AddScheduleGeometry(ctx context.Context, schedule *ScheduleData, durationCalc string, format string) error {
+	if format == "" {
+		format = "geojson"
+	}
+	if format != "geojson" && format != "polyline" {
+		return fmt.Errorf("Invalid geometry format '%s', must be one out of geojson, polyline", format)
+	}
+
+	for i := range schedule.Schedule {
+		// get the distinct consecutive locations of the route
+		locationIds := make([]int64, 0)
+		for _, route := range schedule.Schedule[i].Route {
+			locationId := GetLocationId(*route.Location.Latitude, *route.Location.Longitude)
+			if len(locationIds) == 0 || locationIds[len(locationIds)-1] != locationId {
+				locationIds = append(locationIds, locationId)
+			}
+		}
+		if len(locationIds) == 0 {
+			continue
+		}
+
+		coordinates, err := GetRouteGeometry(ctx, locationIds, durationCalc)
+		if err != nil {
+			return err
+		}
+
+		if format == "polyline" {
+			schedule.Schedule[i].Geometry = EncodePolyline(coordinates, 5)
+		} else {
+			schedule.Schedule[i].Geometry = LineString{
+				Type:        "LineString",
+				Coordinates: coordinates,
+			}
+		}
+	}
+	return nil
+}
+
+// EncodePolyline encodes the [longitude, latitude] coordinates using the encoded polyline algorithm format,
+// with the given precision (5 for the Google polyline, 6 for the Valhalla polyline)
+func EncodePolyline(coordinates [][]float64, precision int) string {
+	factor := math.Pow10(precision)
+	var builder strings.Builder
+	var prevLat, prevLon int64
+	for _, coordinate := range coordinates {
+		lat := int64(math.Round(coordinate[1] * factor))
+		lon := int64(math.Round(coordinate[0] * factor))
+		encodePolylineValue(&builder, lat-prevLat)
+		encodePolylineValue(&builder, lon-prevLon)
+		prevLat, prevLon = lat, lon
+	}
+	return builder.String()
+}
+
+func encodePolylineValue(builder *strings.Builder, value int64) {
+	value <<= 1
+	if value < 0 {
+		value = ^value
+	}
+	for value >= 0x20 {
+		builder.WriteByte(byte((0x20 | (value & 0x1f)) + 63))
+		value >>= 5
+	}
+	builder.WriteByte(byte(value + 63))
+}
+
+// DecodePolyline decodes the encoded polyline with the given precision to [longitude, latitude] coordinates
+func DecodePolyline(encoded string, precision int) ([][]float64, error) {
+	factor := math.Pow10(precision)
+	coordinates := make([][]float64, 0)
+	var lat, lon int64
+	for index := 0; index < len(encoded); {
+		var values [2]int64
+		for i := range values {
+			var result int64
+			var shift uint
+			for {
+				if index >= len(encoded) {
+					return nil, fmt.Errorf("Invalid encoded polyline")
+				}
+				b := int64(encoded[index]) - 63
+				index++
+				result |= (b & 0x1f) << shift
+				shift += 5
+				if b < 0x20 {
+					break
+				}
+			}
+			if result&1 != 0 {
+				values[i] = ^(result >> 1)
+			} else {
+				values[i] = result >> 1
+			}
+		}
+		lat += values[0]
+		lon += values[1]
+		coordinates = append(coordinates, []float64{float64(lon) / factor, float64(lat) / factor})
+	}
+	return coordinates, nil
+}
@@ -0,0 +1,90 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: route_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolyline(t *testing.T) {
+	var cases = []struct {
+		name        string
+		encoded     string
+		precision   int
+		coordinates [][]float64
+	}{
+		{
+			name:        "google_example",
+			encoded:     "_p~iF~ps|U_ulLnnqC_mqNvxq`@",
+			precision:   5,
+			coordinates: [][]float64{{-120.2, 38.5}, {-120.95, 40.7}, {-126.453, 43.252}},
+		},
+		{
+			name:        "precision_6",
+			encoded:     "_izlhA~rlgdF_{geC~ywl@_kwzCn`{nI",
+			precision:   6,
+			coordinates: [][]float64{{-120.2, 38.5}, {-120.95, 40.7}, {-126.453, 43.252}},
+		},
+		{
+			name:        "empty",
+			encoded:     "",
+			precision:   5,
+			coordinates: [][]float64{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.encoded, EncodePolyline(tc.coordinates, tc.precision))
+
+			coordinates, err := DecodePolyline(tc.encoded, tc.precision)
+			require.NoError(t, err)
+			require.Equal(t, len(tc.coordinates), len(coordinates))
+			for i := range coordinates {
+				assert.InDelta(t, tc.coordinates[i][0], coordinates[i][0], 1e-9)
+				assert.InDelta(t, tc.coordinates[i][1], coordinates[i][1], 1e-9)
+			}
+		})
+	}
+}
+
+func TestDecodePolylineInvalid(t *testing.T) {
+	_, err := DecodePolyline("_p~iF~ps|U_", 5)
+	assert.EqualError(t, err, "Invalid encoded polyline")
+}
+
+func TestAddScheduleGeometryInvalidFormat(t *testing.T) {
+	schedule := ScheduleData{}
+	err := AddScheduleGeometry(context.Background(), &schedule, "euclidean", "wkt")
+	assert.EqualError(t, err, "Invalid geometry format 'wkt', must be one out of geojson, polyline")
+}
@@ -0,0 +1,64 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_cost.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import "math"
+
+/*
+-------------------------
+Schedule Cost
+-------------------------
+*/
+
+// VehicleCost is the cost model of a vehicle
+type VehicleCost struct {
+	FixedCost   int64
+	CostPerHour int64
+	CostPerKm   int64
+}
+
+// GetRouteCost returns the cost of a route of the vehicle, from its duration (in seconds) and its distance (in meters)
+func (cost VehicleCost) GetRouteCost(duration int64, distance int64) int64 {
+	return cost.FixedCost +
+		int64(math.Round(float64(cost.CostPerHour*duration)/3600)) +
+		int64(math.Round(float64(cost.CostPerKm*distance)/1000))
+}
+
+// AddScheduleCosts sets the cost of the route of each vehicle in the summary of a schedule, and the total cost.
+// The duration of a route is the sum of its travel, setup, service and waiting time.
+func AddScheduleCosts(data *ScheduleData, costs map[int64]VehicleCost) {
+	var totalCost int64
+	for i, summary := range data.Metadata.Summary {
+		duration := parseSeconds(summary.TravelTime) + parseSeconds(summary.SetupTime) +
+			parseSeconds(summary.ServiceTime) + parseSeconds(summary.WaitingTime)
+		data.Metadata.Summary[i].Cost = costs[summary.VehicleID].GetRouteCost(duration, summary.TotalDistance)
+		totalCost += data.Metadata.Summary[i].Cost
+	}
+	data.Metadata.TotalCost = totalCost
+}
@@ -0,0 +1,58 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_cost_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddScheduleCosts(t *testing.T) {
+	data := ScheduleData{
+		Metadata: MetadataResponse{
+			Summary: []ScheduleSummary{
+				{VehicleID: 1, TravelTime: "00:40:00", SetupTime: "00:05:00", ServiceTime: "00:10:00", WaitingTime: "00:05:00", TotalDistance: 12500},
+				{VehicleID: 2, TravelTime: "00:20:00", SetupTime: "00:00:00", ServiceTime: "00:10:00", WaitingTime: "00:00:00", TotalDistance: 4000},
+				{VehicleID: 3, TravelTime: "00:10:00", SetupTime: "00:00:00", ServiceTime: "00:00:00", WaitingTime: "00:00:00", TotalDistance: 1000},
+			},
+		},
+	}
+	AddScheduleCosts(&data, map[int64]VehicleCost{
+		1: {FixedCost: 5000, CostPerHour: 3000, CostPerKm: 100},
+		2: {CostPerHour: 3600},
+	})
+
+	// 5000 + 3000 * 1h + 100 * 12.5km
+	assert.Equal(t, int64(9250), data.Metadata.Summary[0].Cost)
+	assert.Equal(t, int64(1800), data.Metadata.Summary[1].Cost)
+	// Vehicle without any cost
+	assert.Equal(t, int64(0), data.Metadata.Summary[2].Cost)
+	assert.Equal(t, int64(11050), data.Metadata.TotalCost)
+}
@@ -0,0 +1,89 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_days.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import "sort"
+
+/*
+-------------------------
+Schedule Days
+-------------------------
+*/
+
+// ScheduleDay is the schedule of a day of the planning horizon, with the steps of each vehicle route on that day
+type ScheduleDay struct {
+	Date     string             `json:"date" example:"2021-12-01"`
+	Schedule []ScheduleResponse `json:"schedule"`
+}
+
+// ScheduleDataDays is a schedule whose vehicle routes are grouped by day
+type ScheduleDataDays struct {
+	Days      []ScheduleDay    `json:"days"`
+	Metadata  MetadataResponse `json:"metadata"`
+	ProjectID int64            `json:"project_id,string,omitempty" example:"1234567812345678"`
+}
+
+// GroupScheduleByDay groups the steps of the vehicle routes of a schedule by the day of their arrival.
+// The days are ordered by date, and the vehicles of each day are in the order of the schedule.
+func GroupScheduleByDay(data ScheduleData) ScheduleDataDays {
+	days := []ScheduleDay{}
+	dayIndex := map[string]int{}
+	for _, vehicle := range data.Schedule {
+		vehicleIndex := map[string]int{}
+		for _, step := range vehicle.Route {
+			date := step.Arrival
+			if len(date) > len(DateLayout) {
+				date = date[:len(DateLayout)]
+			}
+			i, found := dayIndex[date]
+			if !found {
+				i = len(days)
+				dayIndex[date] = i
+				days = append(days, ScheduleDay{Date: date, Schedule: []ScheduleResponse{}})
+			}
+			j, found := vehicleIndex[date]
+			if !found {
+				j = len(days[i].Schedule)
+				vehicleIndex[date] = j
+				days[i].Schedule = append(days[i].Schedule, ScheduleResponse{
+					VehicleID:   vehicle.VehicleID,
+					VehicleData: vehicle.VehicleData,
+					Route:       []ScheduleRoute{},
+				})
+			}
+			days[i].Schedule[j].Route = append(days[i].Schedule[j].Route, step)
+		}
+	}
+	sort.SliceStable(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	return ScheduleDataDays{
+		Days:      days,
+		Metadata:  data.Metadata,
+		ProjectID: data.ProjectID,
+	}
+}
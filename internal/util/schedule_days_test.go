@@ -0,0 +1,74 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_days_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupScheduleByDay(t *testing.T) {
+	step := func(stepType string, arrival string) ScheduleRoute {
+		return ScheduleRoute{Type: stepType, Arrival: arrival}
+	}
+	data := ScheduleData{
+		Schedule: []ScheduleResponse{
+			{VehicleID: 1, Route: []ScheduleRoute{
+				step("start", "2021-12-02T08:00:00"),
+				step("job", "2021-12-02T09:00:00"),
+				step("break", "2021-12-02T17:00:00"),
+				step("job", "2021-12-03T08:30:00"),
+				step("end", "2021-12-03T10:00:00"),
+			}},
+			{VehicleID: 2, Route: []ScheduleRoute{
+				step("start", "2021-12-01T08:00:00"),
+				step("job", "2021-12-01T09:00:00"),
+				step("end", "2021-12-02T10:00:00"),
+			}},
+		},
+		ProjectID: 100,
+	}
+	days := GroupScheduleByDay(data)
+	assert.Equal(t, int64(100), days.ProjectID)
+	assert.Equal(t, []ScheduleDay{
+		{Date: "2021-12-01", Schedule: []ScheduleResponse{
+			{VehicleID: 2, Route: []ScheduleRoute{step("start", "2021-12-01T08:00:00"), step("job", "2021-12-01T09:00:00")}},
+		}},
+		{Date: "2021-12-02", Schedule: []ScheduleResponse{
+			{VehicleID: 1, Route: []ScheduleRoute{step("start", "2021-12-02T08:00:00"), step("job", "2021-12-02T09:00:00"), step("break", "2021-12-02T17:00:00")}},
+			{VehicleID: 2, Route: []ScheduleRoute{step("end", "2021-12-02T10:00:00")}},
+		}},
+		{Date: "2021-12-03", Schedule: []ScheduleResponse{
+			{VehicleID: 1, Route: []ScheduleRoute{step("job", "2021-12-03T08:30:00"), step("end", "2021-12-03T10:00:00")}},
+		}},
+	}, days.Days)
+
+	assert.Equal(t, []ScheduleDay{}, GroupScheduleByDay(ScheduleData{}).Days)
+}
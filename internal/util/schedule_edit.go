@@ -0,0 +1,697 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_edit.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+-------------------------
+Schedule Edit
+-------------------------
+*/
+
+// ScheduleStop is a step of a route which can be edited, identified by its type and task id
+type ScheduleStop struct {
+	Type   string `json:"type" validate:"required,oneof=job pickup delivery break" example:"job"`
+	TaskID int64  `json:"task_id,string" validate:"required" example:"1234567812345678"`
+}
+
+// ScheduleOperation is a manual edit of the routes of a schedule
+type ScheduleOperation struct {
+	Op          string         `json:"op" validate:"required,oneof=move insert remove swap reorder" example:"move"`
+	Type        string         `json:"type" validate:"omitempty,oneof=job pickup delivery break" example:"job"`
+	TaskID      int64          `json:"task_id,string" example:"1234567812345678"`
+	VehicleID   int64          `json:"vehicle_id,string" example:"1234567812345678"`
+	Position    *int           `json:"position" validate:"omitempty,min=0" example:"0"`
+	OtherType   string         `json:"other_type" validate:"omitempty,oneof=job pickup delivery break" example:"job"`
+	OtherTaskID int64          `json:"other_task_id,string" example:"1234567812345678"`
+	Stops       []ScheduleStop `json:"stops" validate:"omitempty,dive"`
+}
+
+type ScheduleEditParams struct {
+	Operations []ScheduleOperation `json:"operations" validate:"required,min=1,dive"`
+	Force      bool                `json:"force" example:"false"`
+}
+
+type ScheduleEditData struct {
+	ScheduleData
+	Warnings []string `json:"warnings" example:"Job 1234567812345678 cannot be served within its time windows"`
+}
+
+// ScheduleViolation is a constraint which is not satisfied by a route
+type ScheduleViolation struct {
+	Type      string `json:"type" example:"time_window"`
+	VehicleID int64  `json:"vehicle_id,string" example:"1234567812345678"`
+	TaskType  string `json:"task_type,omitempty" example:"job"`
+	TaskID    int64  `json:"task_id,string,omitempty" example:"1234567812345678"`
+	Dimension *int   `json:"dimension,omitempty" example:"0"`
+	Message   string `json:"message" example:"Job 1234567812345678 cannot be served within its time windows by vehicle 1234567812345678"`
+}
+
+// ScheduleTask has the properties of a stop which are used to compute the route and check its constraints
+type ScheduleTask struct {
+	LocationID  int64
+	Setup       string
+	Service     string
+	Delivery    []int64 // amount removed from the load of the vehicle at the stop
+	Pickup      []int64 // amount added to the load of the vehicle at the stop
+	Skills      []int32
+	TimeWindows [][]string
+	VehicleID   int64 // vehicle of a break, or pinned vehicle of a job or shipment
+	Locked      bool
+	Data        interface{}
+}
+
+// ScheduleVehicle has the properties of a vehicle which are used to compute its route and check its constraints
+type ScheduleVehicle struct {
+	StartLocationID int64
+	EndLocationID   int64
+	Capacity        []int64
+	Skills          []int32
+	TwOpen          string
+	TwClose         string
+	SpeedFactor     float64
+	MaxTasks        int32
+	MaxTravelTime   int64 // in seconds, or 0 when the travel time of the route is not limited
+	MaxDistance     int64 // in meters, or 0 when the distance of the route is not limited
+	Data            interface{}
+}
+
+// ScheduleProblem has the vehicles, the tasks, and the durations (in seconds) and the distances (in meters)
+// between the locations of a project
+type ScheduleProblem struct {
+	Vehicles  map[int64]ScheduleVehicle
+	Tasks     map[ScheduleStop]ScheduleTask
+	Durations map[[2]int64]int64
+	Distances map[[2]int64]int64
+}
+
+const scheduleTimeLayout = "2006-01-02T15:04:05"
+
+// emptyScheduleTime is the time of the steps which are not part of a route, such as the summaries and the unassigned tasks
+const emptyScheduleTime = "1970-01-01T00:00:00"
+
+func (stop ScheduleStop) String() string {
+	return fmt.Sprintf("%s %d", strings.Title(stop.Type), stop.TaskID)
+}
+
+// scheduleRoutes are the stops of the route of each vehicle in a schedule being edited
+type scheduleRoutes struct {
+	problem  ScheduleProblem
+	stops    map[int64][]ScheduleStop
+	vehicles map[ScheduleStop]int64
+	edited   map[int64]bool
+	warnings []string
+}
+
+// index returns the vehicle of a stop and the position of the stop in its route, or false if the stop is unassigned
+func (routes *scheduleRoutes) index(stop ScheduleStop) (int64, int, bool) {
+	vehicleID, found := routes.vehicles[stop]
+	if !found {
+		return 0, 0, false
+	}
+	for i, routeStop := range routes.stops[vehicleID] {
+		if routeStop == stop {
+			return vehicleID, i, true
+		}
+	}
+	return 0, 0, false
+}
+
+func (routes *scheduleRoutes) remove(stop ScheduleStop) {
+	vehicleID, i, found := routes.index(stop)
+	if !found {
+		return
+	}
+	stops := routes.stops[vehicleID]
+	routes.stops[vehicleID] = append(stops[:i:i], stops[i+1:]...)
+	delete(routes.vehicles, stop)
+	routes.edited[vehicleID] = true
+}
+
+func (routes *scheduleRoutes) insert(stop ScheduleStop, vehicleID int64, position *int) error {
+	stops := routes.stops[vehicleID]
+	i := len(stops)
+	if position != nil {
+		if *position > len(stops) {
+			return fmt.Errorf("Field 'position' must be between 0 and %d", len(stops))
+		}
+		i = *position
+	}
+	newStops := append([]ScheduleStop{}, stops[:i]...)
+	newStops = append(newStops, stop)
+	routes.stops[vehicleID] = append(newStops, stops[i:]...)
+	routes.vehicles[stop] = vehicleID
+	routes.edited[vehicleID] = true
+	return nil
+}
+
+// getStop returns a stop of the project, checking that the vehicle of a break does not change
+func (routes *scheduleRoutes) getStop(stopType string, taskID int64) (ScheduleStop, error) {
+	stop := ScheduleStop{Type: stopType, TaskID: taskID}
+	if stopType == "" || taskID == 0 {
+		return stop, fmt.Errorf("Field 'type' and 'task_id' are required")
+	}
+	if _, found := routes.problem.Tasks[stop]; !found {
+		return stop, fmt.Errorf("Task with the given 'type' and 'task_id' does not exist in the project")
+	}
+	return stop, nil
+}
+
+// warnLocked adds a warning when a locked task is moved by an operation
+func (routes *scheduleRoutes) warnLocked(stop ScheduleStop) {
+	if routes.problem.Tasks[stop].Locked {
+		routes.warnings = append(routes.warnings, fmt.Sprintf("%s is locked", stop))
+	}
+}
+
+// shipmentStops returns both the stops of a shipment, given its pickup or its delivery
+func shipmentStops(stop ScheduleStop) []ScheduleStop {
+	if stop.Type != "pickup" && stop.Type != "delivery" {
+		return []ScheduleStop{stop}
+	}
+	return []ScheduleStop{{Type: "pickup", TaskID: stop.TaskID}, {Type: "delivery", TaskID: stop.TaskID}}
+}
+
+func (routes *scheduleRoutes) apply(operation ScheduleOperation) error {
+	if operation.Op == "reorder" {
+		return routes.reorder(operation)
+	}
+
+	stop, err := routes.getStop(operation.Type, operation.TaskID)
+	if err != nil {
+		return err
+	}
+	vehicleID, _, assigned := routes.index(stop)
+
+	switch operation.Op {
+	case "move", "insert":
+		if operation.Op == "move" && !assigned {
+			return fmt.Errorf("%s is not assigned to a vehicle, use the 'insert' operation", stop)
+		}
+		if operation.Op == "insert" && assigned {
+			return fmt.Errorf("%s is already assigned to a vehicle, use the 'move' operation", stop)
+		}
+		if _, found := routes.problem.Vehicles[operation.VehicleID]; !found {
+			return fmt.Errorf("Vehicle with the given 'vehicle_id' does not exist in the project")
+		}
+		if stop.Type == "break" && routes.problem.Tasks[stop].VehicleID != operation.VehicleID {
+			return fmt.Errorf("%s can only be in the route of its vehicle", stop)
+		}
+		routes.warnLocked(stop)
+		routes.remove(stop)
+		return routes.insert(stop, operation.VehicleID, operation.Position)
+	case "remove":
+		if !assigned {
+			return fmt.Errorf("%s is not assigned to a vehicle", stop)
+		}
+		if stop.Type == "break" {
+			return fmt.Errorf("%s cannot be removed from the route of its vehicle", stop)
+		}
+		routes.warnLocked(stop)
+		for _, shipmentStop := range shipmentStops(stop) {
+			routes.remove(shipmentStop)
+		}
+	case "swap":
+		other, err := routes.getStop(operation.OtherType, operation.OtherTaskID)
+		if err != nil {
+			return fmt.Errorf("Field 'other_type' and 'other_task_id' must be a task of the project")
+		}
+		otherVehicleID, j, otherAssigned := routes.index(other)
+		if !assigned || !otherAssigned {
+			return fmt.Errorf("Both the tasks to swap must be assigned to a vehicle")
+		}
+		if (stop.Type == "break" || other.Type == "break") && vehicleID != otherVehicleID {
+			return fmt.Errorf("A break can only be in the route of its vehicle")
+		}
+		_, i, _ := routes.index(stop)
+		routes.warnLocked(stop)
+		routes.warnLocked(other)
+		routes.stops[vehicleID][i], routes.stops[otherVehicleID][j] = other, stop
+		routes.vehicles[stop], routes.vehicles[other] = otherVehicleID, vehicleID
+		routes.edited[vehicleID] = true
+		routes.edited[otherVehicleID] = true
+	}
+	return nil
+}
+
+// reorder changes the order of the stops of a route, which must be given exactly once each
+func (routes *scheduleRoutes) reorder(operation ScheduleOperation) error {
+	if _, found := routes.problem.Vehicles[operation.VehicleID]; !found {
+		return fmt.Errorf("Vehicle with the given 'vehicle_id' does not exist in the project")
+	}
+	stops := routes.stops[operation.VehicleID]
+	errInvalid := fmt.Errorf("Field 'stops' must contain each stop of the route of the vehicle exactly once")
+	if len(operation.Stops) != len(stops) {
+		return errInvalid
+	}
+	positions := map[ScheduleStop]int{}
+	for i, stop := range stops {
+		positions[stop] = i
+	}
+	for i, stop := range operation.Stops {
+		position, found := positions[stop]
+		if !found {
+			return errInvalid
+		}
+		delete(positions, stop)
+		if position != i {
+			routes.warnLocked(stop)
+		}
+	}
+	routes.stops[operation.VehicleID] = append([]ScheduleStop{}, operation.Stops...)
+	routes.edited[operation.VehicleID] = true
+	return nil
+}
+
+// getShipmentViolations returns the shipments of the given routes (or of all the routes) whose pickup and
+// delivery are not in the same route, with the pickup before the delivery
+func (routes *scheduleRoutes) getShipmentViolations(vehicleIDs map[int64]bool) []ScheduleViolation {
+	violations := []ScheduleViolation{}
+	shipmentIDs := []int64{}
+	for stop := range routes.problem.Tasks {
+		if stop.Type == "pickup" {
+			shipmentIDs = append(shipmentIDs, stop.TaskID)
+		}
+	}
+	sort.Slice(shipmentIDs, func(i, j int) bool { return shipmentIDs[i] < shipmentIDs[j] })
+	for _, shipmentID := range shipmentIDs {
+		pickupVehicleID, i, pickupAssigned := routes.index(ScheduleStop{Type: "pickup", TaskID: shipmentID})
+		deliveryVehicleID, j, deliveryAssigned := routes.index(ScheduleStop{Type: "delivery", TaskID: shipmentID})
+		if vehicleIDs != nil && !vehicleIDs[pickupVehicleID] && !vehicleIDs[deliveryVehicleID] {
+			continue
+		}
+		if pickupAssigned != deliveryAssigned || pickupVehicleID != deliveryVehicleID || i > j {
+			vehicleID := pickupVehicleID
+			if !pickupAssigned {
+				vehicleID = deliveryVehicleID
+			}
+			violations = append(violations, ScheduleViolation{
+				Type:      "pickup_delivery",
+				VehicleID: vehicleID,
+				TaskType:  "shipment",
+				TaskID:    shipmentID,
+				Message:   fmt.Sprintf("Shipment %d: the pickup and the delivery must be in the same route, with the pickup before the delivery", shipmentID),
+			})
+		}
+	}
+	return violations
+}
+
+// EditSchedule applies the operations to the rows of a schedule, ordered by vehicle and arrival, and returns the rows
+// of the new schedule. The times and the load of the edited routes are computed again, using the durations of the problem.
+// The tasks which are removed from their route are unassigned.
+//
+// An error is returned when an operation is not valid. The constraints which are not satisfied by the edited routes
+// (time windows, capacity, skills, max tasks, max travel time and distance, pinned and locked tasks) are returned as warnings.
+func EditSchedule(schedule []ScheduleDB, problem ScheduleProblem, operations []ScheduleOperation) ([]ScheduleDB, []string, error) {
+	routes := scheduleRoutes{
+		problem:  problem,
+		stops:    map[int64][]ScheduleStop{},
+		vehicles: map[ScheduleStop]int64{},
+		edited:   map[int64]bool{},
+		warnings: []string{},
+	}
+	assigned := map[ScheduleStop]bool{}
+	for _, step := range schedule {
+		stop := ScheduleStop{Type: step.Type, TaskID: step.TaskID}
+		if _, found := problem.Tasks[stop]; found && step.VehicleID > 0 {
+			routes.stops[step.VehicleID] = append(routes.stops[step.VehicleID], stop)
+			routes.vehicles[stop] = step.VehicleID
+			assigned[stop] = true
+		}
+	}
+
+	for i, operation := range operations {
+		if err := routes.apply(operation); err != nil {
+			return nil, nil, fmt.Errorf("operations[%d]: %s", i, err)
+		}
+	}
+	if violations := routes.getShipmentViolations(routes.edited); len(violations) != 0 {
+		return nil, nil, fmt.Errorf("%s", violations[0].Message)
+	}
+
+	// Keep the rows of the routes which are not edited, and the unassigned tasks which are still unassigned
+	newSchedule := []ScheduleDB{}
+	unassigned := map[ScheduleStop]bool{}
+	for _, step := range schedule {
+		stop := ScheduleStop{Type: step.Type, TaskID: step.TaskID}
+		switch {
+		case step.VehicleID > 0 && !routes.edited[step.VehicleID]:
+			newSchedule = append(newSchedule, step)
+		case step.VehicleID == -1:
+			if _, found := routes.vehicles[stop]; !found {
+				newSchedule = append(newSchedule, step)
+				unassigned[stop] = true
+			}
+		}
+	}
+
+	// Compute the edited routes again, in the order of the vehicle ids
+	vehicleIDs := []int64{}
+	for vehicleID := range routes.edited {
+		vehicleIDs = append(vehicleIDs, vehicleID)
+	}
+	sort.Slice(vehicleIDs, func(i, j int) bool { return vehicleIDs[i] < vehicleIDs[j] })
+	for _, vehicleID := range vehicleIDs {
+		route, violations := computeRoute(vehicleID, routes.stops[vehicleID], problem)
+		newSchedule = append(newSchedule, route...)
+		for _, violation := range violations {
+			routes.warnings = append(routes.warnings, violation.Message)
+		}
+	}
+
+	// Unassign the tasks removed from the routes
+	removed := []ScheduleStop{}
+	for stop := range assigned {
+		if _, found := routes.vehicles[stop]; !found && !unassigned[stop] {
+			removed = append(removed, stop)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		if removed[i].TaskID != removed[j].TaskID {
+			return removed[i].TaskID < removed[j].TaskID
+		}
+		return removed[i].Type < removed[j].Type
+	})
+	for _, stop := range removed {
+		newSchedule = append(newSchedule, newScheduleStep(stop.Type, -1, stop.TaskID, problem.Tasks[stop].LocationID, nil, problem.Tasks[stop].Data))
+	}
+
+	newSchedule = append(newSchedule, getTotalSummary(newSchedule))
+	return newSchedule, routes.warnings, nil
+}
+
+func newScheduleStep(stepType string, vehicleID int64, taskID int64, locationID int64, vehicleData interface{}, taskData interface{}) ScheduleDB {
+	latitude, longitude := GetCoordinates(locationID)
+	if vehicleData == nil {
+		vehicleData = map[string]interface{}{}
+	}
+	if taskData == nil {
+		taskData = map[string]interface{}{}
+	}
+	return ScheduleDB{
+		Type:        stepType,
+		VehicleID:   vehicleID,
+		TaskID:      taskID,
+		Location:    LocationParams{Latitude: &latitude, Longitude: &longitude},
+		Arrival:     emptyScheduleTime,
+		Departure:   emptyScheduleTime,
+		TravelTime:  FormatDuration(0),
+		SetupTime:   FormatDuration(0),
+		ServiceTime: FormatDuration(0),
+		WaitingTime: FormatDuration(0),
+		Load:        []int64{},
+		VehicleData: vehicleData,
+		TaskData:    taskData,
+	}
+}
+
+// getTotalSummary returns the summary of all the vehicles, from the summary of each vehicle
+func getTotalSummary(schedule []ScheduleDB) ScheduleDB {
+	var travel, setup, service, waiting int64
+	for _, step := range schedule {
+		if step.Type != "summary" || step.VehicleID <= 0 {
+			continue
+		}
+		travel += parseSeconds(step.TravelTime)
+		setup += parseSeconds(step.SetupTime)
+		service += parseSeconds(step.ServiceTime)
+		waiting += parseSeconds(step.WaitingTime)
+	}
+	summary := newScheduleStep("summary", 0, 0, 0, nil, nil)
+	summary.TravelTime = FormatDuration(travel)
+	summary.SetupTime = FormatDuration(setup)
+	summary.ServiceTime = FormatDuration(service)
+	summary.WaitingTime = FormatDuration(waiting)
+	return summary
+}
+
+func parseSeconds(duration string) int64 {
+	seconds, _ := ParseDuration(duration)
+	return seconds
+}
+
+// addLoad adds (or subtracts, with a sign of -1) an amount to the load, extending the dimensions of the load if needed
+func addLoad(load []int64, amount []int64, sign int64) []int64 {
+	newLoad := append([]int64{}, load...)
+	for i, value := range amount {
+		if i >= len(newLoad) {
+			newLoad = append(newLoad, 0)
+		}
+		newLoad[i] += sign * value
+	}
+	return newLoad
+}
+
+// getCapacityViolations returns the dimensions of the load which exceed the capacity of the vehicle and are not
+// already exceeded, at the given step of the route
+func getCapacityViolations(vehicleID int64, load []int64, capacity []int64, exceeded map[int]bool, stop *ScheduleStop) []ScheduleViolation {
+	violations := []ScheduleViolation{}
+	for i, value := range load {
+		if exceeded[i] || value <= 0 || (i < len(capacity) && value <= capacity[i]) {
+			continue
+		}
+		exceeded[i] = true
+		var vehicleCapacity int64
+		if i < len(capacity) {
+			vehicleCapacity = capacity[i]
+		}
+		dimension := i
+		violation := ScheduleViolation{Type: "capacity", VehicleID: vehicleID, Dimension: &dimension}
+		if stop == nil {
+			violation.Message = fmt.Sprintf("Vehicle %d: the load %d of dimension %d exceeds the capacity %d at the start of the route", vehicleID, value, i, vehicleCapacity)
+		} else {
+			violation.TaskType, violation.TaskID = stop.Type, stop.TaskID
+			violation.Message = fmt.Sprintf("Vehicle %d: the load %d of dimension %d exceeds the capacity %d at %s", vehicleID, value, i, vehicleCapacity, stop)
+		}
+		violations = append(violations, violation)
+	}
+	return violations
+}
+
+func hasSkills(vehicleSkills []int32, skills []int32) bool {
+	available := map[int32]bool{}
+	for _, skill := range vehicleSkills {
+		available[skill] = true
+	}
+	for _, skill := range skills {
+		if !available[skill] {
+			return false
+		}
+	}
+	return true
+}
+
+// getServiceStart returns the earliest time after the arrival at which the service can start within the time windows
+func getServiceStart(arrival time.Time, timeWindows [][]string) (time.Time, bool) {
+	if len(timeWindows) == 0 {
+		return arrival, true
+	}
+	for _, tw := range timeWindows {
+		twOpen, twClose := parseTime(tw[0]), parseTime(tw[1])
+		if arrival.After(twClose) {
+			continue
+		}
+		if arrival.Before(twOpen) {
+			return twOpen, true
+		}
+		return arrival, true
+	}
+	return arrival, false
+}
+
+// getTravelTime returns the travel time (in seconds) of a vehicle between two locations, using its speed factor
+func (problem ScheduleProblem) getTravelTime(vehicle ScheduleVehicle, startID int64, endID int64) int64 {
+	if startID == endID {
+		return 0
+	}
+	speedFactor := vehicle.SpeedFactor
+	if speedFactor <= 0 {
+		speedFactor = 1
+	}
+	return int64(math.Round(float64(problem.Durations[[2]int64{startID, endID}]) / speedFactor))
+}
+
+// computeRoute returns the rows of the route of a vehicle visiting the stops in the given order, along with its summary,
+// and the constraints which are not satisfied by the route. The route starts at the start of the time window of the
+// vehicle, delayed to avoid waiting at the first stop.
+func computeRoute(vehicleID int64, stops []ScheduleStop, problem ScheduleProblem) ([]ScheduleDB, []ScheduleViolation) {
+	if len(stops) == 0 {
+		return []ScheduleDB{}, []ScheduleViolation{}
+	}
+	vehicle := problem.Vehicles[vehicleID]
+	start := parseTime(vehicle.TwOpen)
+	route, waiting, violations := computeRouteFrom(vehicleID, stops, problem, start)
+	if waiting > 0 {
+		route, _, violations = computeRouteFrom(vehicleID, stops, problem, start.Add(time.Duration(waiting)*time.Second))
+	}
+
+	if int(vehicle.MaxTasks) < len(stops) {
+		violations = append(violations, ScheduleViolation{
+			Type:      "max_tasks",
+			VehicleID: vehicleID,
+			Message:   fmt.Sprintf("Vehicle %d: the route has %d tasks, more than the max_tasks of the vehicle", vehicleID, len(stops)),
+		})
+	}
+	violations = append(violations, problem.getLimitViolations(vehicleID, stops)...)
+	for _, stop := range stops {
+		task := problem.Tasks[stop]
+		if !hasSkills(vehicle.Skills, task.Skills) {
+			violations = append(violations, ScheduleViolation{
+				Type:      "skills",
+				VehicleID: vehicleID,
+				TaskType:  stop.Type,
+				TaskID:    stop.TaskID,
+				Message:   fmt.Sprintf("%s: vehicle %d does not have the skills of the task", stop, vehicleID),
+			})
+		}
+		if stop.Type != "break" && task.VehicleID != 0 && task.VehicleID != vehicleID {
+			violations = append(violations, ScheduleViolation{
+				Type:      "pinned_vehicle",
+				VehicleID: vehicleID,
+				TaskType:  stop.Type,
+				TaskID:    stop.TaskID,
+				Message:   fmt.Sprintf("%s: the task is pinned to vehicle %d", stop, task.VehicleID),
+			})
+		}
+	}
+
+	// Summary of the vehicle
+	var travel, setup, service, waitingTime int64
+	for _, step := range route {
+		travel += parseSeconds(step.TravelTime)
+		setup += parseSeconds(step.SetupTime)
+		service += parseSeconds(step.ServiceTime)
+		waitingTime += parseSeconds(step.WaitingTime)
+	}
+	summary := newScheduleStep("summary", vehicleID, 0, 0, vehicle.Data, nil)
+	summary.TravelTime = FormatDuration(travel)
+	summary.SetupTime = FormatDuration(setup)
+	summary.ServiceTime = FormatDuration(service)
+	summary.WaitingTime = FormatDuration(waitingTime)
+	return append(route, summary), violations
+}
+
+// computeRouteFrom computes the route of a vehicle starting at the given time, and returns
+// the waiting time at the first stop of the route
+func computeRouteFrom(vehicleID int64, stops []ScheduleStop, problem ScheduleProblem, start time.Time) ([]ScheduleDB, int64, []ScheduleViolation) {
+	vehicle := problem.Vehicles[vehicleID]
+	violations := []ScheduleViolation{}
+	getTravelTime := func(startID int64, endID int64) int64 {
+		return problem.getTravelTime(vehicle, startID, endID)
+	}
+
+	// The amounts delivered by the jobs are loaded at the start of the route
+	load := []int64{}
+	for _, stop := range stops {
+		if stop.Type == "job" {
+			load = addLoad(load, problem.Tasks[stop].Delivery, 1)
+		}
+	}
+	exceeded := map[int]bool{}
+	violations = append(violations, getCapacityViolations(vehicleID, load, vehicle.Capacity, exceeded, nil)...)
+
+	route := []ScheduleDB{}
+	startStep := newScheduleStep("start", vehicleID, -1, vehicle.StartLocationID, vehicle.Data, nil)
+	startStep.Arrival = start.Format(scheduleTimeLayout)
+	startStep.Departure = startStep.Arrival
+	startStep.Load = load
+	route = append(route, startStep)
+
+	current, locationID := start, vehicle.StartLocationID
+	var firstWaiting int64
+	for i := range stops {
+		stop := stops[i]
+		task := problem.Tasks[stop]
+		stopLocationID := task.LocationID
+		if stop.Type == "break" {
+			stopLocationID = locationID
+		}
+		travel := getTravelTime(locationID, stopLocationID)
+		arrival := current.Add(time.Duration(travel) * time.Second)
+		serviceStart, ok := getServiceStart(arrival, task.TimeWindows)
+		if !ok {
+			violations = append(violations, ScheduleViolation{
+				Type:      "time_window",
+				VehicleID: vehicleID,
+				TaskType:  stop.Type,
+				TaskID:    stop.TaskID,
+				Message:   fmt.Sprintf("%s cannot be served within its time windows by vehicle %d, arriving at %s", stop, vehicleID, arrival.Format(scheduleTimeLayout)),
+			})
+		}
+		waiting := int64(serviceStart.Sub(arrival) / time.Second)
+		if i == 0 {
+			firstWaiting = waiting
+		}
+		var setup int64
+		if stopLocationID != locationID {
+			setup = parseSeconds(task.Setup)
+		}
+		service := parseSeconds(task.Service)
+		current = serviceStart.Add(time.Duration(setup+service) * time.Second)
+
+		load = addLoad(addLoad(load, task.Delivery, -1), task.Pickup, 1)
+		violations = append(violations, getCapacityViolations(vehicleID, load, vehicle.Capacity, exceeded, &stop)...)
+
+		step := newScheduleStep(stop.Type, vehicleID, stop.TaskID, stopLocationID, vehicle.Data, task.Data)
+		step.Arrival = arrival.Format(scheduleTimeLayout)
+		step.Departure = current.Format(scheduleTimeLayout)
+		step.TravelTime = FormatDuration(travel)
+		step.SetupTime = FormatDuration(setup)
+		step.ServiceTime = FormatDuration(service)
+		step.WaitingTime = FormatDuration(waiting)
+		step.Load = load
+		route = append(route, step)
+		locationID = stopLocationID
+	}
+
+	travel := getTravelTime(locationID, vehicle.EndLocationID)
+	end := current.Add(time.Duration(travel) * time.Second)
+	endStep := newScheduleStep("end", vehicleID, -1, vehicle.EndLocationID, vehicle.Data, nil)
+	endStep.Arrival = end.Format(scheduleTimeLayout)
+	endStep.Departure = endStep.Arrival
+	endStep.TravelTime = FormatDuration(travel)
+	endStep.Load = load
+	route = append(route, endStep)
+	if end.After(parseTime(vehicle.TwClose)) {
+		violations = append(violations, ScheduleViolation{
+			Type:      "vehicle_time_window",
+			VehicleID: vehicleID,
+			Message:   fmt.Sprintf("Vehicle %d: the route ends at %s, after the end of the time window of the vehicle", vehicleID, endStep.Arrival),
+		})
+	}
+	return route, firstWaiting, violations
+}
@@ -0,0 +1,170 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_edit_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditSchedule(t *testing.T) {
+	vehicle := ScheduleVehicle{
+		StartLocationID: 1,
+		EndLocationID:   1,
+		Capacity:        []int64{10},
+		Skills:          []int32{},
+		TwOpen:          "2021-12-01T08:00:00",
+		TwClose:         "2021-12-01T18:00:00",
+		SpeedFactor:     1.0,
+		MaxTasks:        10,
+	}
+	problem := ScheduleProblem{
+		Vehicles: map[int64]ScheduleVehicle{1: vehicle, 2: vehicle},
+		Tasks: map[ScheduleStop]ScheduleTask{
+			{Type: "job", TaskID: 10}: {
+				LocationID:  2,
+				Service:     "00:05:00",
+				Delivery:    []int64{4},
+				TimeWindows: [][]string{{"2021-12-01T09:00:00", "2021-12-01T10:00:00"}},
+			},
+			{Type: "job", TaskID: 11}: {LocationID: 3, Service: "00:02:00", Pickup: []int64{8}},
+			{Type: "job", TaskID: 12}: {LocationID: 3, Skills: []int32{5}},
+		},
+		Durations: map[[2]int64]int64{
+			{1, 2}: 600, {2, 1}: 600,
+			{2, 3}: 300, {3, 2}: 300,
+			{1, 3}: 900, {3, 1}: 900,
+		},
+	}
+	schedule := []ScheduleDB{
+		{Type: "start", VehicleID: 1, TaskID: -1},
+		{Type: "job", VehicleID: 1, TaskID: 10},
+		{Type: "job", VehicleID: 1, TaskID: 11},
+		{Type: "end", VehicleID: 1, TaskID: -1},
+		{Type: "summary", VehicleID: 1},
+		{Type: "job", VehicleID: -1, TaskID: 12},
+		{Type: "summary", VehicleID: 0},
+	}
+	getSteps := func(schedule []ScheduleDB, vehicleID int64) [][]string {
+		steps := [][]string{}
+		for _, step := range schedule {
+			if step.VehicleID == vehicleID && step.Type != "summary" {
+				steps = append(steps, []string{step.Type, step.Arrival, step.Departure, step.WaitingTime})
+			}
+		}
+		return steps
+	}
+
+	t.Run("Reorder a route", func(t *testing.T) {
+		newSchedule, warnings, err := EditSchedule(schedule, problem, []ScheduleOperation{
+			{Op: "reorder", VehicleID: 1, Stops: []ScheduleStop{{Type: "job", TaskID: 11}, {Type: "job", TaskID: 10}}},
+		})
+		require.NoError(t, err)
+		// The load of job 11 and the amount of job 10 exceed the capacity
+		assert.Equal(t, []string{"Vehicle 1: the load 12 of dimension 0 exceeds the capacity 10 at Job 11"}, warnings)
+		assert.Equal(t, [][]string{
+			{"start", "2021-12-01T08:00:00", "2021-12-01T08:00:00", "00:00:00"},
+			{"job", "2021-12-01T08:15:00", "2021-12-01T08:17:00", "00:00:00"},
+			{"job", "2021-12-01T08:22:00", "2021-12-01T09:05:00", "00:38:00"},
+			{"end", "2021-12-01T09:15:00", "2021-12-01T09:15:00", "00:00:00"},
+		}, getSteps(newSchedule, 1))
+		assert.Equal(t, [][]string{{"job", "", "", ""}}, getSteps(newSchedule, -1))
+
+		total := newSchedule[len(newSchedule)-1]
+		assert.Equal(t, "summary", total.Type)
+		assert.Equal(t, int64(0), total.VehicleID)
+		assert.Equal(t, "00:30:00", total.TravelTime)
+		assert.Equal(t, "00:07:00", total.ServiceTime)
+		assert.Equal(t, "00:38:00", total.WaitingTime)
+	})
+
+	t.Run("Move a job to another vehicle", func(t *testing.T) {
+		position := 0
+		newSchedule, warnings, err := EditSchedule(schedule, problem, []ScheduleOperation{
+			{Op: "move", Type: "job", TaskID: 10, VehicleID: 2, Position: &position},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{}, warnings)
+		assert.Equal(t, [][]string{
+			{"start", "2021-12-01T08:00:00", "2021-12-01T08:00:00", "00:00:00"},
+			{"job", "2021-12-01T08:15:00", "2021-12-01T08:17:00", "00:00:00"},
+			{"end", "2021-12-01T08:32:00", "2021-12-01T08:32:00", "00:00:00"},
+		}, getSteps(newSchedule, 1))
+		// The start of the route is delayed to avoid waiting at the first stop
+		assert.Equal(t, [][]string{
+			{"start", "2021-12-01T08:50:00", "2021-12-01T08:50:00", "00:00:00"},
+			{"job", "2021-12-01T09:00:00", "2021-12-01T09:05:00", "00:00:00"},
+			{"end", "2021-12-01T09:15:00", "2021-12-01T09:15:00", "00:00:00"},
+		}, getSteps(newSchedule, 2))
+		for _, step := range newSchedule {
+			if step.VehicleID == 2 && step.Type == "start" {
+				assert.Equal(t, []int64{4}, step.Load)
+			}
+		}
+	})
+
+	t.Run("Insert and remove jobs", func(t *testing.T) {
+		newSchedule, warnings, err := EditSchedule(schedule, problem, []ScheduleOperation{
+			{Op: "insert", Type: "job", TaskID: 12, VehicleID: 2},
+			{Op: "remove", Type: "job", TaskID: 11},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Job 12: vehicle 2 does not have the skills of the task"}, warnings)
+		assert.Len(t, getSteps(newSchedule, 1), 3)
+		assert.Len(t, getSteps(newSchedule, 2), 3)
+		unassigned := getSteps(newSchedule, -1)
+		require.Len(t, unassigned, 1)
+		for _, step := range newSchedule {
+			if step.VehicleID == -1 {
+				assert.Equal(t, int64(11), step.TaskID)
+			}
+		}
+	})
+
+	t.Run("Invalid operations", func(t *testing.T) {
+		position := 3
+		testCases := []struct {
+			operation ScheduleOperation
+			err       string
+		}{
+			{ScheduleOperation{Op: "move", Type: "job", TaskID: 12, VehicleID: 1}, "operations[0]: Job 12 is not assigned to a vehicle, use the 'insert' operation"},
+			{ScheduleOperation{Op: "insert", Type: "job", TaskID: 10, VehicleID: 1}, "operations[0]: Job 10 is already assigned to a vehicle, use the 'move' operation"},
+			{ScheduleOperation{Op: "move", Type: "job", TaskID: 10, VehicleID: 3}, "operations[0]: Vehicle with the given 'vehicle_id' does not exist in the project"},
+			{ScheduleOperation{Op: "move", Type: "job", TaskID: 10, VehicleID: 1, Position: &position}, "operations[0]: Field 'position' must be between 0 and 1"},
+			{ScheduleOperation{Op: "remove", Type: "job", TaskID: 13}, "operations[0]: Task with the given 'type' and 'task_id' does not exist in the project"},
+			{ScheduleOperation{Op: "reorder", VehicleID: 1, Stops: []ScheduleStop{{Type: "job", TaskID: 10}}}, "operations[0]: Field 'stops' must contain each stop of the route of the vehicle exactly once"},
+		}
+		for _, tc := range testCases {
+			_, _, err := EditSchedule(schedule, problem, []ScheduleOperation{tc.operation})
+			assert.EqualError(t, err, tc.err)
+		}
+	})
+}
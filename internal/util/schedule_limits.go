@@ -0,0 +1,149 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_limits.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+)
+
+/*
+-------------------------
+Vehicle Limits
+-------------------------
+*/
+
+// getDistance returns the distance (in meters) between two locations
+func (problem ScheduleProblem) getDistance(startID int64, endID int64) int64 {
+	if startID == endID {
+		return 0
+	}
+	return problem.Distances[[2]int64{startID, endID}]
+}
+
+// getRouteTotals returns the travel time (in seconds) and the distance (in meters) of the route of a vehicle
+// visiting the stops in the given order, from its start to its end. The breaks do not change the location.
+func (problem ScheduleProblem) getRouteTotals(vehicle ScheduleVehicle, stops []ScheduleStop) (int64, int64) {
+	var travel, distance int64
+	locationID := vehicle.StartLocationID
+	for _, stop := range stops {
+		if stop.Type == "break" {
+			continue
+		}
+		stopLocationID := problem.Tasks[stop].LocationID
+		travel += problem.getTravelTime(vehicle, locationID, stopLocationID)
+		distance += problem.getDistance(locationID, stopLocationID)
+		locationID = stopLocationID
+	}
+	travel += problem.getTravelTime(vehicle, locationID, vehicle.EndLocationID)
+	distance += problem.getDistance(locationID, vehicle.EndLocationID)
+	return travel, distance
+}
+
+// getLimitViolations returns the max travel time and the max distance of a vehicle which are exceeded by its route
+func (problem ScheduleProblem) getLimitViolations(vehicleID int64, stops []ScheduleStop) []ScheduleViolation {
+	violations := []ScheduleViolation{}
+	vehicle := problem.Vehicles[vehicleID]
+	if len(stops) == 0 || (vehicle.MaxTravelTime == 0 && vehicle.MaxDistance == 0) {
+		return violations
+	}
+	travel, distance := problem.getRouteTotals(vehicle, stops)
+	if vehicle.MaxTravelTime > 0 && travel > vehicle.MaxTravelTime {
+		violations = append(violations, ScheduleViolation{
+			Type:      "max_travel_time",
+			VehicleID: vehicleID,
+			Message: fmt.Sprintf("Vehicle %d: the travel time %s of the route exceeds the max_travel_time %s of the vehicle",
+				vehicleID, FormatDuration(travel), FormatDuration(vehicle.MaxTravelTime)),
+		})
+	}
+	if vehicle.MaxDistance > 0 && distance > vehicle.MaxDistance {
+		violations = append(violations, ScheduleViolation{
+			Type:      "max_distance",
+			VehicleID: vehicleID,
+			Message: fmt.Sprintf("Vehicle %d: the distance %d of the route exceeds the max_distance %d of the vehicle",
+				vehicleID, distance, vehicle.MaxDistance),
+		})
+	}
+	return violations
+}
+
+// ApplyVehicleLimits unassigns the tasks of the routes of a schedule which exceed the max travel time or the max
+// distance of their vehicle, starting from the end of the route, until the route is within the limits. The breaks
+// and the locked tasks are kept in their route, and a shipment is unassigned along with both its stops.
+//
+// The rows of the schedule are ordered by vehicle and arrival. The rows of the new schedule are returned, with the
+// routes which are changed computed again as in EditSchedule, along with the unassigned jobs and pickups.
+func ApplyVehicleLimits(schedule []ScheduleDB, problem ScheduleProblem) ([]ScheduleDB, []ScheduleStop, error) {
+	routes := map[int64][]ScheduleStop{}
+	for _, step := range schedule {
+		stop := ScheduleStop{Type: step.Type, TaskID: step.TaskID}
+		if _, found := problem.Tasks[stop]; found && step.VehicleID > 0 {
+			routes[step.VehicleID] = append(routes[step.VehicleID], stop)
+		}
+	}
+	vehicleIDs := []int64{}
+	for vehicleID := range routes {
+		vehicleIDs = append(vehicleIDs, vehicleID)
+	}
+	sort.Slice(vehicleIDs, func(i, j int) bool { return vehicleIDs[i] < vehicleIDs[j] })
+
+	operations := []ScheduleOperation{}
+	removed := []ScheduleStop{}
+	for _, vehicleID := range vehicleIDs {
+		stops := routes[vehicleID]
+		for len(problem.getLimitViolations(vehicleID, stops)) != 0 {
+			i := len(stops) - 1
+			for i >= 0 && (stops[i].Type == "break" || problem.Tasks[stops[i]].Locked) {
+				i--
+			}
+			if i < 0 {
+				break
+			}
+			removedStops := shipmentStops(stops[i])
+			kept := []ScheduleStop{}
+			for _, stop := range stops {
+				if stop != removedStops[0] && stop != removedStops[len(removedStops)-1] {
+					kept = append(kept, stop)
+				}
+			}
+			stops = kept
+			operations = append(operations, ScheduleOperation{Op: "remove", Type: removedStops[0].Type, TaskID: removedStops[0].TaskID})
+			removed = append(removed, removedStops[0])
+		}
+	}
+	if len(operations) == 0 {
+		return schedule, removed, nil
+	}
+
+	newSchedule, _, err := EditSchedule(schedule, problem, operations)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newSchedule, removed, nil
+}
@@ -0,0 +1,118 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_limits_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyVehicleLimits(t *testing.T) {
+	vehicle := ScheduleVehicle{
+		StartLocationID: 1,
+		EndLocationID:   1,
+		TwOpen:          "2021-12-01T08:00:00",
+		TwClose:         "2021-12-01T18:00:00",
+		SpeedFactor:     1.0,
+		MaxTasks:        10,
+	}
+	vehicle1, vehicle2 := vehicle, vehicle
+	vehicle1.MaxDistance = 6000
+	vehicle2.MaxTravelTime = 1500
+	problem := ScheduleProblem{
+		Vehicles: map[int64]ScheduleVehicle{1: vehicle1, 2: vehicle2},
+		Tasks: map[ScheduleStop]ScheduleTask{
+			{Type: "job", TaskID: 10}:      {LocationID: 2},
+			{Type: "job", TaskID: 11}:      {LocationID: 3},
+			{Type: "job", TaskID: 12}:      {LocationID: 4, Locked: true},
+			{Type: "pickup", TaskID: 20}:   {LocationID: 2, Pickup: []int64{5}},
+			{Type: "delivery", TaskID: 20}: {LocationID: 3, Delivery: []int64{5}},
+		},
+		Durations: map[[2]int64]int64{
+			{1, 2}: 300, {2, 1}: 300,
+			{1, 3}: 600, {3, 1}: 600,
+			{1, 4}: 900, {4, 1}: 900,
+			{2, 3}: 300, {3, 2}: 300,
+			{2, 4}: 600, {4, 2}: 600,
+			{3, 4}: 300, {4, 3}: 300,
+		},
+		Distances: map[[2]int64]int64{},
+	}
+	for locations, duration := range problem.Durations {
+		problem.Distances[locations] = duration * 10
+	}
+
+	route1 := []ScheduleStop{{Type: "job", TaskID: 10}, {Type: "job", TaskID: 11}}
+	route2 := []ScheduleStop{{Type: "pickup", TaskID: 20}, {Type: "job", TaskID: 12}, {Type: "delivery", TaskID: 20}}
+	schedule := []ScheduleDB{}
+	steps, _ := computeRoute(1, route1, problem)
+	schedule = append(schedule, steps...)
+	steps, _ = computeRoute(2, route2, problem)
+	schedule = append(schedule, steps...)
+	schedule = append(schedule, getTotalSummary(schedule))
+
+	violations := problem.getLimitViolations(1, route1)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "Vehicle 1: the distance 12000 of the route exceeds the max_distance 6000 of the vehicle", violations[0].Message)
+	violations = problem.getLimitViolations(2, route2)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "Vehicle 2: the travel time 00:30:00 of the route exceeds the max_travel_time 00:25:00 of the vehicle", violations[0].Message)
+
+	newSchedule, removed, err := ApplyVehicleLimits(schedule, problem)
+	require.NoError(t, err)
+	// The locked job is kept, even if the route still exceeds the max travel time
+	assert.Equal(t, []ScheduleStop{{Type: "job", TaskID: 11}, {Type: "pickup", TaskID: 20}}, removed)
+
+	routes := map[int64][]ScheduleStop{}
+	for _, step := range newSchedule {
+		if step.Type != "summary" && step.Type != "start" && step.Type != "end" {
+			routes[step.VehicleID] = append(routes[step.VehicleID], ScheduleStop{Type: step.Type, TaskID: step.TaskID})
+		}
+	}
+	assert.Equal(t, []ScheduleStop{{Type: "job", TaskID: 10}}, routes[1])
+	assert.Equal(t, []ScheduleStop{{Type: "job", TaskID: 12}}, routes[2])
+	assert.Equal(t, []ScheduleStop{{Type: "job", TaskID: 11}, {Type: "delivery", TaskID: 20}, {Type: "pickup", TaskID: 20}}, routes[-1])
+
+	// The schedule is not changed when the routes are within the limits
+	vehicle1.MaxDistance, vehicle2.MaxTravelTime = 0, 1800
+	problem.Vehicles = map[int64]ScheduleVehicle{1: vehicle1, 2: vehicle2}
+	newSchedule, removed, err = ApplyVehicleLimits(schedule, problem)
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	assert.Equal(t, schedule, newSchedule)
+
+	// The task cannot be assigned when a route serving only the task exceeds the limits of every vehicle
+	vehicle1.MaxDistance, vehicle2.MaxDistance = 5000, 5000
+	problem.Vehicles = map[int64]ScheduleVehicle{1: vehicle1, 2: vehicle2}
+	assert.Equal(t, []string{
+		"The travel time or the distance of a route serving the task exceeds the max_travel_time or the max_distance of every vehicle",
+	}, GetUnassignedReasons(ScheduleStop{Type: "job", TaskID: 10}, problem))
+}
@@ -0,0 +1,175 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_unassigned.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+-------------------------
+Unassigned Reasons
+-------------------------
+*/
+
+// AddUnassignedReasons sets the reasons of the unassigned tasks of a schedule, using the vehicles and the tasks of the problem
+func AddUnassignedReasons(unassigned []ScheduleUnassigned, problem ScheduleProblem) {
+	for i, task := range unassigned {
+		unassigned[i].Reasons = GetUnassignedReasons(ScheduleStop{Type: task.Type, TaskID: task.TaskID}, problem)
+	}
+}
+
+// GetUnassignedReasons analyses whether a job or a shipment (given its pickup or its delivery) can be served by the
+// vehicles of the problem, ignoring the other tasks, and returns the reasons for which it cannot be assigned.
+//
+// The skills, the amount, the time windows of the task, and the max travel time and distance of a route serving
+// only the task are checked against each vehicle, or only against the pinned vehicle of the task. When the task is
+// feasible for a vehicle on its own, the returned reason is that it does not fit in the routes along with the other
+// tasks.
+func GetUnassignedReasons(stop ScheduleStop, problem ScheduleProblem) []string {
+	reasons := []string{}
+	task, found := problem.Tasks[stop]
+	if !found || stop.Type == "break" {
+		return reasons
+	}
+	stops := shipmentStops(stop)
+
+	vehicleIDs := []int64{}
+	if task.VehicleID != 0 {
+		if _, found := problem.Vehicles[task.VehicleID]; !found {
+			return append(reasons, fmt.Sprintf("The pinned vehicle %d does not exist", task.VehicleID))
+		}
+		vehicleIDs = append(vehicleIDs, task.VehicleID)
+	} else {
+		for vehicleID := range problem.Vehicles {
+			vehicleIDs = append(vehicleIDs, vehicleID)
+		}
+		sort.Slice(vehicleIDs, func(i, j int) bool { return vehicleIDs[i] < vehicleIDs[j] })
+	}
+	if len(vehicleIDs) == 0 {
+		return append(reasons, "The project has no vehicles")
+	}
+
+	// The amount of a job is the largest of its delivery and its pickup in each dimension
+	amount := problem.Tasks[stops[0]].Pickup
+	if stop.Type == "job" {
+		amount = []int64{}
+		for i := 0; i < len(task.Delivery) || i < len(task.Pickup); i++ {
+			var value int64
+			if i < len(task.Delivery) {
+				value = task.Delivery[i]
+			}
+			if i < len(task.Pickup) && task.Pickup[i] > value {
+				value = task.Pickup[i]
+			}
+			amount = append(amount, value)
+		}
+	}
+
+	var skillsFound, capacityFound, arrivalFound, endFound, limitsFound, vehicleFound bool
+	for _, vehicleID := range vehicleIDs {
+		vehicle := problem.Vehicles[vehicleID]
+		skills := hasSkills(vehicle.Skills, task.Skills)
+		capacity := len(getCapacityViolations(vehicleID, amount, vehicle.Capacity, map[int]bool{}, nil)) == 0
+		arrival, end := problem.canServe(vehicle, stops)
+		limits := len(problem.getLimitViolations(vehicleID, stops)) == 0
+		skillsFound = skillsFound || skills
+		capacityFound = capacityFound || capacity
+		arrivalFound = arrivalFound || arrival
+		endFound = endFound || end
+		limitsFound = limitsFound || limits
+		vehicleFound = vehicleFound || (skills && capacity && arrival && end && limits)
+	}
+
+	noVehicle, anyVehicle, everyVehicle := "No vehicle", "any vehicle", "every vehicle"
+	if task.VehicleID != 0 {
+		noVehicle = fmt.Sprintf("The pinned vehicle %d", task.VehicleID)
+		anyVehicle = fmt.Sprintf("the pinned vehicle %d", task.VehicleID)
+		everyVehicle = anyVehicle
+	}
+	if !skillsFound {
+		if task.VehicleID != 0 {
+			reasons = append(reasons, fmt.Sprintf("%s does not have the skills %s", noVehicle, formatList(task.Skills)))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("%s has the skills %s", noVehicle, formatList(task.Skills)))
+		}
+	}
+	if !capacityFound {
+		reasons = append(reasons, fmt.Sprintf("The amount %s exceeds the capacity of %s", formatList(amount), everyVehicle))
+	}
+	if !arrivalFound {
+		reasons = append(reasons, fmt.Sprintf("The time windows close before %s can arrive, given the travel time from the start of the vehicle", anyVehicle))
+	} else if !endFound {
+		if task.VehicleID != 0 {
+			reasons = append(reasons, fmt.Sprintf("%s cannot serve the task and reach the end of its route before the end of its time window", noVehicle))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("%s can serve the task and reach the end of its route before the end of its time window", noVehicle))
+		}
+	}
+	if !limitsFound {
+		reasons = append(reasons, fmt.Sprintf("The travel time or the distance of a route serving the task exceeds the max_travel_time or the max_distance of %s", everyVehicle))
+	}
+	if len(reasons) != 0 {
+		return reasons
+	}
+	if !vehicleFound {
+		return append(reasons, "No vehicle satisfies the skills, the capacity and the time windows of the task together")
+	}
+	return append(reasons, "The task is feasible on its own, but does not fit in the routes along with the other tasks")
+}
+
+// canServe returns whether a vehicle starting at the start of its time window can serve the stops in the given
+// order within their time windows, and then reach the end of its route before the end of its time window
+func (problem ScheduleProblem) canServe(vehicle ScheduleVehicle, stops []ScheduleStop) (bool, bool) {
+	current, locationID := parseTime(vehicle.TwOpen), vehicle.StartLocationID
+	for _, stop := range stops {
+		task := problem.Tasks[stop]
+		arrival := current.Add(time.Duration(problem.getTravelTime(vehicle, locationID, task.LocationID)) * time.Second)
+		serviceStart, ok := getServiceStart(arrival, task.TimeWindows)
+		if !ok {
+			return false, false
+		}
+		var setup int64
+		if task.LocationID != locationID {
+			setup = parseSeconds(task.Setup)
+		}
+		current = serviceStart.Add(time.Duration(setup+parseSeconds(task.Service)) * time.Second)
+		locationID = task.LocationID
+	}
+	end := current.Add(time.Duration(problem.getTravelTime(vehicle, locationID, vehicle.EndLocationID)) * time.Second)
+	return true, !end.After(parseTime(vehicle.TwClose))
+}
+
+// formatList formats a list of values as [1, 2, 3]
+func formatList(values interface{}) string {
+	return strings.ReplaceAll(fmt.Sprint(values), " ", ", ")
+}
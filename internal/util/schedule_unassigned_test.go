@@ -0,0 +1,102 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_unassigned_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUnassignedReasons(t *testing.T) {
+	vehicle := ScheduleVehicle{
+		StartLocationID: 1,
+		EndLocationID:   1,
+		TwOpen:          "2021-12-01T08:00:00",
+		TwClose:         "2021-12-01T18:00:00",
+		SpeedFactor:     1.0,
+	}
+	vehicle1, vehicle2 := vehicle, vehicle
+	vehicle1.Capacity, vehicle1.Skills = []int64{10}, []int32{1, 3}
+	vehicle2.Capacity, vehicle2.Skills = []int64{30}, []int32{}
+	problem := ScheduleProblem{
+		Vehicles: map[int64]ScheduleVehicle{1: vehicle1, 2: vehicle2},
+		Tasks: map[ScheduleStop]ScheduleTask{
+			{Type: "job", TaskID: 10}: {LocationID: 2, Skills: []int32{5}},
+			{Type: "job", TaskID: 11}: {LocationID: 2, Delivery: []int64{40}, Pickup: []int64{5}},
+			{Type: "job", TaskID: 12}: {
+				LocationID:  2,
+				TimeWindows: [][]string{{"2021-12-01T07:00:00", "2021-12-01T08:05:00"}},
+			},
+			{Type: "job", TaskID: 13}:    {LocationID: 2, Service: "10:00:00"},
+			{Type: "job", TaskID: 14}:    {LocationID: 2, Skills: []int32{3}, VehicleID: 2},
+			{Type: "job", TaskID: 15}:    {LocationID: 2, Skills: []int32{3}, Delivery: []int64{20}},
+			{Type: "job", TaskID: 16}:    {LocationID: 2},
+			{Type: "job", TaskID: 17}:    {LocationID: 2, VehicleID: 3},
+			{Type: "pickup", TaskID: 20}: {LocationID: 2, Pickup: []int64{5}},
+			{Type: "delivery", TaskID: 20}: {
+				LocationID:  3,
+				Delivery:    []int64{5},
+				TimeWindows: [][]string{{"2021-12-01T08:00:00", "2021-12-01T08:10:00"}},
+			},
+		},
+		Durations: map[[2]int64]int64{
+			{1, 2}: 600, {2, 1}: 600,
+			{2, 3}: 300, {3, 2}: 300,
+			{1, 3}: 900, {3, 1}: 900,
+		},
+	}
+
+	testCases := []struct {
+		stop    ScheduleStop
+		reasons []string
+	}{
+		{ScheduleStop{Type: "job", TaskID: 10}, []string{"No vehicle has the skills [5]"}},
+		{ScheduleStop{Type: "job", TaskID: 11}, []string{"The amount [40] exceeds the capacity of every vehicle"}},
+		{ScheduleStop{Type: "job", TaskID: 12}, []string{"The time windows close before any vehicle can arrive, given the travel time from the start of the vehicle"}},
+		{ScheduleStop{Type: "job", TaskID: 13}, []string{"No vehicle can serve the task and reach the end of its route before the end of its time window"}},
+		{ScheduleStop{Type: "job", TaskID: 14}, []string{"The pinned vehicle 2 does not have the skills [3]"}},
+		{ScheduleStop{Type: "job", TaskID: 15}, []string{"No vehicle satisfies the skills, the capacity and the time windows of the task together"}},
+		{ScheduleStop{Type: "job", TaskID: 16}, []string{"The task is feasible on its own, but does not fit in the routes along with the other tasks"}},
+		{ScheduleStop{Type: "delivery", TaskID: 20}, []string{"The time windows close before any vehicle can arrive, given the travel time from the start of the vehicle"}},
+		{ScheduleStop{Type: "job", TaskID: 17}, []string{"The pinned vehicle 3 does not exist"}},
+		{ScheduleStop{Type: "job", TaskID: 18}, []string{}},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.reasons, GetUnassignedReasons(tc.stop, problem), tc.stop.String())
+	}
+
+	unassigned := []ScheduleUnassigned{{Type: "pickup", TaskID: 20}, {Type: "job", TaskID: 10}}
+	AddUnassignedReasons(unassigned, problem)
+	assert.Equal(t, []string{"The time windows close before any vehicle can arrive, given the travel time from the start of the vehicle"}, unassigned[0].Reasons)
+	assert.Equal(t, []string{"No vehicle has the skills [5]"}, unassigned[1].Reasons)
+
+	problem.Vehicles = map[int64]ScheduleVehicle{}
+	assert.Equal(t, []string{"The project has no vehicles"}, GetUnassignedReasons(ScheduleStop{Type: "job", TaskID: 16}, problem))
+}
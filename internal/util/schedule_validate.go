@@ -0,0 +1,128 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_validate.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+)
+
+/*
+-------------------------
+Schedule Validation
+-------------------------
+*/
+
+// ScheduleRouteParams is a proposed route of a vehicle, as the ordered stops visited by the vehicle
+type ScheduleRouteParams struct {
+	VehicleID int64          `json:"vehicle_id,string" validate:"required" example:"1234567812345678"`
+	Stops     []ScheduleStop `json:"stops" validate:"dive"`
+}
+
+type ScheduleValidateParams struct {
+	Routes []ScheduleRouteParams `json:"routes" validate:"required,dive"`
+}
+
+type ScheduleValidation struct {
+	ScheduleData
+	Feasible   bool                `json:"feasible" example:"false"`
+	Violations []ScheduleViolation `json:"violations"`
+}
+
+// SimulateSchedule computes the rows of the schedule of the proposed routes, ordered by vehicle, using the durations
+// of the problem. The tasks which are not in any route are unassigned.
+//
+// An error is returned when the routes are not valid. The constraints which are not satisfied by the routes
+// (time windows, capacity, skills, max tasks, max travel time and distance, time window of the vehicle, pickup
+// before delivery) are returned as violations.
+func SimulateSchedule(params []ScheduleRouteParams, problem ScheduleProblem) ([]ScheduleDB, []ScheduleViolation, error) {
+	routes := scheduleRoutes{
+		problem:  problem,
+		stops:    map[int64][]ScheduleStop{},
+		vehicles: map[ScheduleStop]int64{},
+	}
+	for i, route := range params {
+		if _, found := problem.Vehicles[route.VehicleID]; !found {
+			return nil, nil, fmt.Errorf("routes[%d]: Vehicle with the given 'vehicle_id' does not exist in the project", i)
+		}
+		if _, found := routes.stops[route.VehicleID]; found {
+			return nil, nil, fmt.Errorf("routes[%d]: Vehicle %d has more than one route", i, route.VehicleID)
+		}
+		routes.stops[route.VehicleID] = []ScheduleStop{}
+		for j, stop := range route.Stops {
+			task, found := problem.Tasks[stop]
+			if !found {
+				return nil, nil, fmt.Errorf("routes[%d].stops[%d]: Task with the given 'type' and 'task_id' does not exist in the project", i, j)
+			}
+			if _, found := routes.vehicles[stop]; found {
+				return nil, nil, fmt.Errorf("routes[%d].stops[%d]: %s is given more than once", i, j, stop)
+			}
+			if stop.Type == "break" && task.VehicleID != route.VehicleID {
+				return nil, nil, fmt.Errorf("routes[%d].stops[%d]: %s can only be in the route of its vehicle", i, j, stop)
+			}
+			routes.stops[route.VehicleID] = append(routes.stops[route.VehicleID], stop)
+			routes.vehicles[stop] = route.VehicleID
+		}
+	}
+
+	// Compute the routes in the order of the vehicle ids
+	schedule := []ScheduleDB{}
+	violations := []ScheduleViolation{}
+	vehicleIDs := []int64{}
+	for vehicleID := range routes.stops {
+		vehicleIDs = append(vehicleIDs, vehicleID)
+	}
+	sort.Slice(vehicleIDs, func(i, j int) bool { return vehicleIDs[i] < vehicleIDs[j] })
+	for _, vehicleID := range vehicleIDs {
+		route, routeViolations := computeRoute(vehicleID, routes.stops[vehicleID], problem)
+		schedule = append(schedule, route...)
+		violations = append(violations, routeViolations...)
+	}
+	violations = append(violations, routes.getShipmentViolations(nil)...)
+
+	// Unassign the jobs and shipments which are not in any route
+	unassigned := []ScheduleStop{}
+	for stop := range problem.Tasks {
+		if _, found := routes.vehicles[stop]; !found && stop.Type != "break" {
+			unassigned = append(unassigned, stop)
+		}
+	}
+	sort.Slice(unassigned, func(i, j int) bool {
+		if unassigned[i].TaskID != unassigned[j].TaskID {
+			return unassigned[i].TaskID < unassigned[j].TaskID
+		}
+		return unassigned[i].Type < unassigned[j].Type
+	})
+	for _, stop := range unassigned {
+		schedule = append(schedule, newScheduleStep(stop.Type, -1, stop.TaskID, problem.Tasks[stop].LocationID, nil, problem.Tasks[stop].Data))
+	}
+
+	schedule = append(schedule, getTotalSummary(schedule))
+	return schedule, violations, nil
+}
@@ -0,0 +1,204 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: schedule_validate_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateSchedule(t *testing.T) {
+	vehicle := ScheduleVehicle{
+		StartLocationID: 1,
+		EndLocationID:   1,
+		Capacity:        []int64{10, 1},
+		Skills:          []int32{},
+		TwOpen:          "2021-12-01T08:00:00",
+		TwClose:         "2021-12-01T09:30:00",
+		SpeedFactor:     1.0,
+		MaxTasks:        3,
+	}
+	problem := ScheduleProblem{
+		Vehicles: map[int64]ScheduleVehicle{1: vehicle, 2: vehicle},
+		Tasks: map[ScheduleStop]ScheduleTask{
+			{Type: "job", TaskID: 10}: {
+				LocationID:  2,
+				Service:     "00:05:00",
+				Delivery:    []int64{4},
+				TimeWindows: [][]string{{"2021-12-01T09:00:00", "2021-12-01T10:00:00"}},
+			},
+			{Type: "job", TaskID: 11}: {
+				LocationID:  3,
+				TimeWindows: [][]string{{"2021-12-01T08:00:00", "2021-12-01T08:05:00"}},
+			},
+			{Type: "job", TaskID: 12}:      {LocationID: 3, Skills: []int32{5}},
+			{Type: "pickup", TaskID: 20}:   {LocationID: 2, Pickup: []int64{2, 2}},
+			{Type: "delivery", TaskID: 20}: {LocationID: 3, Delivery: []int64{2, 2}},
+			{Type: "break", TaskID: 30}:    {Service: "00:10:00", VehicleID: 1},
+		},
+		Durations: map[[2]int64]int64{
+			{1, 2}: 600, {2, 1}: 600,
+			{2, 3}: 300, {3, 2}: 300,
+			{1, 3}: 900, {3, 1}: 900,
+		},
+	}
+	getSteps := func(schedule []ScheduleDB, vehicleID int64) [][]string {
+		steps := [][]string{}
+		for _, step := range schedule {
+			if step.VehicleID == vehicleID && step.Type != "summary" {
+				steps = append(steps, []string{step.Type, step.Arrival, step.Departure, step.WaitingTime})
+			}
+		}
+		return steps
+	}
+
+	t.Run("Simulate the routes", func(t *testing.T) {
+		schedule, violations, err := SimulateSchedule([]ScheduleRouteParams{
+			{VehicleID: 2, Stops: []ScheduleStop{{Type: "job", TaskID: 11}}},
+			{VehicleID: 1, Stops: []ScheduleStop{
+				{Type: "job", TaskID: 10},
+				{Type: "pickup", TaskID: 20},
+				{Type: "job", TaskID: 12},
+				{Type: "break", TaskID: 30},
+			}},
+		}, problem)
+		require.NoError(t, err)
+
+		// The start of the route is delayed to avoid waiting at the first stop, and the break is at the previous location
+		assert.Equal(t, [][]string{
+			{"start", "2021-12-01T08:50:00", "2021-12-01T08:50:00", "00:00:00"},
+			{"job", "2021-12-01T09:00:00", "2021-12-01T09:05:00", "00:00:00"},
+			{"pickup", "2021-12-01T09:05:00", "2021-12-01T09:05:00", "00:00:00"},
+			{"job", "2021-12-01T09:10:00", "2021-12-01T09:10:00", "00:00:00"},
+			{"break", "2021-12-01T09:10:00", "2021-12-01T09:20:00", "00:00:00"},
+			{"end", "2021-12-01T09:35:00", "2021-12-01T09:35:00", "00:00:00"},
+		}, getSteps(schedule, 1))
+		assert.Equal(t, [][]string{
+			{"start", "2021-12-01T08:00:00", "2021-12-01T08:00:00", "00:00:00"},
+			{"job", "2021-12-01T08:15:00", "2021-12-01T08:15:00", "00:00:00"},
+			{"end", "2021-12-01T08:30:00", "2021-12-01T08:30:00", "00:00:00"},
+		}, getSteps(schedule, 2))
+
+		// The delivery of the shipment is unassigned
+		unassigned := []ScheduleStop{}
+		for _, step := range schedule {
+			if step.VehicleID == -1 {
+				unassigned = append(unassigned, ScheduleStop{Type: step.Type, TaskID: step.TaskID})
+			}
+		}
+		assert.Equal(t, []ScheduleStop{{Type: "delivery", TaskID: 20}}, unassigned)
+
+		dimension := 1
+		assert.Equal(t, []ScheduleViolation{
+			{
+				Type:      "capacity",
+				VehicleID: 1,
+				TaskType:  "pickup",
+				TaskID:    20,
+				Dimension: &dimension,
+				Message:   "Vehicle 1: the load 2 of dimension 1 exceeds the capacity 1 at Pickup 20",
+			},
+			{
+				Type:      "vehicle_time_window",
+				VehicleID: 1,
+				Message:   "Vehicle 1: the route ends at 2021-12-01T09:35:00, after the end of the time window of the vehicle",
+			},
+			{
+				Type:      "max_tasks",
+				VehicleID: 1,
+				Message:   "Vehicle 1: the route has 4 tasks, more than the max_tasks of the vehicle",
+			},
+			{
+				Type:      "skills",
+				VehicleID: 1,
+				TaskType:  "job",
+				TaskID:    12,
+				Message:   "Job 12: vehicle 1 does not have the skills of the task",
+			},
+			{
+				Type:      "time_window",
+				VehicleID: 2,
+				TaskType:  "job",
+				TaskID:    11,
+				Message:   "Job 11 cannot be served within its time windows by vehicle 2, arriving at 2021-12-01T08:15:00",
+			},
+			{
+				Type:      "pickup_delivery",
+				VehicleID: 1,
+				TaskType:  "shipment",
+				TaskID:    20,
+				Message:   "Shipment 20: the pickup and the delivery must be in the same route, with the pickup before the delivery",
+			},
+		}, violations)
+
+		total := schedule[len(schedule)-1]
+		assert.Equal(t, "summary", total.Type)
+		assert.Equal(t, int64(0), total.VehicleID)
+		assert.Equal(t, "01:00:00", total.TravelTime)
+		assert.Equal(t, "00:15:00", total.ServiceTime)
+	})
+
+	t.Run("Feasible routes", func(t *testing.T) {
+		_, violations, err := SimulateSchedule([]ScheduleRouteParams{
+			{VehicleID: 1, Stops: []ScheduleStop{
+				{Type: "pickup", TaskID: 20},
+				{Type: "delivery", TaskID: 20},
+			}},
+		}, problem)
+		require.NoError(t, err)
+		assert.Len(t, violations, 1)
+		assert.Equal(t, "capacity", violations[0].Type)
+
+		_, violations, err = SimulateSchedule([]ScheduleRouteParams{
+			{VehicleID: 1, Stops: []ScheduleStop{{Type: "job", TaskID: 10}}},
+		}, problem)
+		require.NoError(t, err)
+		assert.Equal(t, []ScheduleViolation{}, violations)
+	})
+
+	t.Run("Invalid routes", func(t *testing.T) {
+		job := ScheduleStop{Type: "job", TaskID: 10}
+		testCases := []struct {
+			routes []ScheduleRouteParams
+			err    string
+		}{
+			{[]ScheduleRouteParams{{VehicleID: 3}}, "routes[0]: Vehicle with the given 'vehicle_id' does not exist in the project"},
+			{[]ScheduleRouteParams{{VehicleID: 1}, {VehicleID: 1}}, "routes[1]: Vehicle 1 has more than one route"},
+			{[]ScheduleRouteParams{{VehicleID: 1, Stops: []ScheduleStop{{Type: "job", TaskID: 13}}}}, "routes[0].stops[0]: Task with the given 'type' and 'task_id' does not exist in the project"},
+			{[]ScheduleRouteParams{{VehicleID: 1, Stops: []ScheduleStop{job}}, {VehicleID: 2, Stops: []ScheduleStop{job}}}, "routes[1].stops[0]: Job 10 is given more than once"},
+			{[]ScheduleRouteParams{{VehicleID: 2, Stops: []ScheduleStop{{Type: "break", TaskID: 30}}}}, "routes[0].stops[0]: Break 30 can only be in the route of its vehicle"},
+		}
+		for _, tc := range testCases {
+			_, _, err := SimulateSchedule(tc.routes, problem)
+			assert.EqualError(t, err, tc.err)
+		}
+	})
+}
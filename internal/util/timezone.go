@@ -0,0 +1,415 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: timezone.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	// Embed the time zone database, as it may be missing on the server
+	_ "time/tzdata"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+-------------------------
+Time Zones
+-------------------------
+*/
+
+// timestampLayout is the layout of the timestamps stored in the database, which are the local times of the time zone
+// of their project
+const timestampLayout = "2006-01-02T15:04:05"
+
+// InstantFields are the timestamp fields set by the database, which are stored in UTC instead of the local time
+var InstantFields = map[string]bool{
+	"started_at":      true,
+	"finished_at":     true,
+	"created_at":      true,
+	"updated_at":      true,
+	"next_attempt_at": true,
+}
+
+// TimeWindowFields are the fields with a list of [tw_open, tw_close] timestamps
+var TimeWindowFields = map[string]bool{
+	"time_windows":   true,
+	"p_time_windows": true,
+	"d_time_windows": true,
+}
+
+// LocationRespWr is a response writer carrying the location of the time zone of the project of the request, so that
+// the timestamps of the response are returned in the time zone. The location is only loaded when it is needed.
+type LocationRespWr struct {
+	http.ResponseWriter
+	loadLocation func() *time.Location
+	once         sync.Once
+	location     *time.Location
+}
+
+func NewLocationRespWr(w http.ResponseWriter, loadLocation func() *time.Location) *LocationRespWr {
+	return &LocationRespWr{ResponseWriter: w, loadLocation: loadLocation}
+}
+
+// Location returns the location of the time zone, which is loaded on the first call
+func (w *LocationRespWr) Location() *time.Location {
+	w.once.Do(func() {
+		w.location = w.loadLocation()
+	})
+	return w.location
+}
+
+// Flush sends the buffered data to the client, for the streamed responses
+func (w *LocationRespWr) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// GetResponseLocation returns the location carried by the response writer, or nil when the timestamps are returned
+// without an offset
+func GetResponseLocation(w http.ResponseWriter) *time.Location {
+	if lw, ok := w.(*LocationRespWr); ok {
+		return lw.Location()
+	}
+	return nil
+}
+
+// LoadLocation returns the location of an IANA time zone, or nil when the time zone is not set
+func LoadLocation(timezone *string) *time.Location {
+	if timezone == nil || *timezone == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(*timezone)
+	if err != nil {
+		logrus.Error(err)
+		return nil
+	}
+	return loc
+}
+
+// NormalizeTimestamp converts an RFC 3339 timestamp with an offset to the local time of the location, or UTC when the
+// location is nil, in the "2006-01-02T15:04:05" format. The other values are returned unchanged.
+func NormalizeTimestamp(value string, loc *time.Location) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(timestampLayout)
+}
+
+// NormalizeTimestamps normalizes the timestamps of the decoded JSON input in place, with NormalizeTimestamp, and
+// returns whether any timestamp is changed
+func NormalizeTimestamps(data interface{}, loc *time.Location) bool {
+	return NormalizeTimestampsWith(data, func() *time.Location { return loc })
+}
+
+// NormalizeTimestampsWith normalizes the timestamps like NormalizeTimestamps, with the location returned by
+// getLocation, which is only called when the input has a timestamp with an offset
+func NormalizeTimestampsWith(data interface{}, getLocation func() *time.Location) bool {
+	changed := false
+	normalize := func(value interface{}) interface{} {
+		s, ok := value.(string)
+		if !ok {
+			return value
+		}
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return value
+		}
+		normalized := NormalizeTimestamp(s, getLocation())
+		changed = changed || normalized != s
+		return normalized
+	}
+	switch value := data.(type) {
+	case map[string]interface{}:
+		for key, v := range value {
+			switch {
+			case TimestampFields[key]:
+				value[key] = normalize(v)
+			case TimeWindowFields[key]:
+				windows, _ := v.([]interface{})
+				for _, window := range windows {
+					window, _ := window.([]interface{})
+					for i := range window {
+						window[i] = normalize(window[i])
+					}
+				}
+			case key != "data" && key != "task_data" && key != "vehicle_data":
+				changed = NormalizeTimestampsWith(v, getLocation) || changed
+			}
+		}
+	case []interface{}:
+		for _, v := range value {
+			changed = NormalizeTimestampsWith(v, getLocation) || changed
+		}
+	}
+	return changed
+}
+
+// LocalizeTimestamp returns a timestamp of the field in the time zone of the location, in the RFC 3339 format with
+// its offset. The value is returned unchanged when the location is nil or the value is not a timestamp.
+func LocalizeTimestamp(field string, value string, loc *time.Location) string {
+	if loc == nil {
+		return value
+	}
+	var t time.Time
+	var err error
+	if InstantFields[field] {
+		t, err = time.Parse(timestampLayout, value)
+		t = t.In(loc)
+	} else {
+		t, err = time.ParseInLocation(timestampLayout, value, loc)
+	}
+	if err != nil {
+		return value
+	}
+	return t.Format(time.RFC3339)
+}
+
+// timestampTypes caches whether the values of a type can have timestamp fields
+var timestampTypes sync.Map
+
+// HasTimestampFields returns whether the data can have timestamp fields localized by LocalizeTimestamps, so that the
+// location is only loaded for such data
+func HasTimestampFields(data interface{}) bool {
+	if data == nil {
+		return false
+	}
+	t := reflect.TypeOf(data)
+	if cached, ok := timestampTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	result := hasTimestampFields(t, map[reflect.Type]bool{})
+	timestampTypes.Store(t, result)
+	return result
+}
+
+func hasTimestampFields(t reflect.Type, visited map[reflect.Type]bool) bool {
+	// the recursive types are checked once
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	result := false
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		result = hasTimestampFields(t.Elem(), visited)
+	case reflect.Interface:
+		// the dynamic value is only known at run time
+		result = true
+	case reflect.Struct:
+		for i := 0; i < t.NumField() && !result; i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			tag := jsonTag(field)
+			result = TimestampFields[tag] || TimeWindowFields[tag] || hasTimestampFields(field.Type, visited)
+		}
+	}
+	return result
+}
+
+// LocalizeTimestamps returns a copy of the data where the timestamp fields of the structs are in the time zone of the
+// location, with LocalizeTimestamp. The structs with a "timezone" field, such as the projects, use their own time
+// zone instead of the location.
+func LocalizeTimestamps(data interface{}, loc *time.Location) interface{} {
+	v := reflect.ValueOf(data)
+	if !v.IsValid() {
+		return data
+	}
+	return localizeValue(v, loc).Interface()
+}
+
+func localizeValue(v reflect.Value, loc *time.Location) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		elem := localizeValue(v.Elem(), loc)
+		if v.Kind() == reflect.Interface {
+			result := reflect.New(v.Type()).Elem()
+			result.Set(elem)
+			return result
+		}
+		result := reflect.New(v.Type().Elem())
+		result.Elem().Set(elem)
+		return result
+	case reflect.Slice:
+		switch v.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Ptr, reflect.Interface, reflect.Slice:
+		default:
+			return v
+		}
+		if v.IsNil() {
+			return v
+		}
+		result := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			result.Index(i).Set(localizeValue(v.Index(i), loc))
+		}
+		return result
+	case reflect.Struct:
+		return localizeStruct(v, loc)
+	}
+	// the maps are the data of the users, which are returned unchanged
+	return v
+}
+
+func localizeStruct(v reflect.Value, loc *time.Location) reflect.Value {
+	result := reflect.New(v.Type()).Elem()
+	result.Set(v)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if jsonTag(field) == "timezone" && field.Type == reflect.TypeOf((*string)(nil)) {
+			loc = LoadLocation(v.Field(i).Interface().(*string))
+		}
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		value := result.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := jsonTag(field)
+		switch {
+		case TimestampFields[tag] && value.Kind() == reflect.String:
+			value.SetString(LocalizeTimestamp(tag, value.String(), loc))
+		case TimestampFields[tag] && value.Type() == reflect.TypeOf((*string)(nil)):
+			if !value.IsNil() {
+				timestamp := LocalizeTimestamp(tag, value.Elem().String(), loc)
+				value.Set(reflect.ValueOf(&timestamp))
+			}
+		case TimeWindowFields[tag] && value.Type() == reflect.TypeOf([][]string{}):
+			value.Set(reflect.ValueOf(localizeTimeWindows(value.Interface().([][]string), loc)))
+		case TimeWindowFields[tag] && value.Type() == reflect.TypeOf((*[][]string)(nil)):
+			if !value.IsNil() {
+				windows := localizeTimeWindows(*value.Interface().(*[][]string), loc)
+				value.Set(reflect.ValueOf(&windows))
+			}
+		default:
+			value.Set(localizeValue(value, loc))
+		}
+	}
+	return result
+}
+
+func localizeTimeWindows(windows [][]string, loc *time.Location) [][]string {
+	if windows == nil {
+		return nil
+	}
+	result := make([][]string, len(windows))
+	for i, window := range windows {
+		result[i] = make([]string, len(window))
+		for j := range window {
+			result[i][j] = LocalizeTimestamp("time_windows", window[j], loc)
+		}
+	}
+	return result
+}
+
+// localizeTables returns the tables where the columns of the timestamp fields are in the time zone of the location
+func localizeTables(tables []Table, loc *time.Location) []Table {
+	if loc == nil {
+		return tables
+	}
+	result := make([]Table, len(tables))
+	for i, table := range tables {
+		result[i] = Table{Name: table.Name, Header: table.Header, Rows: make([][]interface{}, len(table.Rows))}
+		for j, row := range table.Rows {
+			result[i].Rows[j] = make([]interface{}, len(row))
+			for k, cell := range row {
+				if s, ok := cell.(string); ok && k < len(table.Header) && TimestampFields[table.Header[k]] {
+					cell = LocalizeTimestamp(table.Header[k], s, loc)
+				}
+				result[i].Rows[j][k] = cell
+			}
+		}
+	}
+	return result
+}
+
+// localTime returns the time in the location of the local time t, which is parsed as UTC
+func localTime(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// wallTime returns the local time of t in the location, as a UTC time, which is the inverse of localTime
+func wallTime(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// localizeFeatures returns the features where the timestamp properties are in the time zone of the location
+func localizeFeatures(featureCollection FeatureCollection, loc *time.Location) FeatureCollection {
+	if loc == nil {
+		return featureCollection
+	}
+	features := make([]Feature, len(featureCollection.Features))
+	for i, feature := range featureCollection.Features {
+		properties := make(map[string]interface{}, len(feature.Properties))
+		for key, value := range feature.Properties {
+			if s, ok := value.(string); ok && TimestampFields[key] {
+				value = LocalizeTimestamp(key, s, loc)
+			}
+			properties[key] = value
+		}
+		features[i] = Feature{Type: feature.Type, Geometry: feature.Geometry, Properties: properties}
+	}
+	return FeatureCollection{Type: featureCollection.Type, Features: features}
+}
+
+// localizeICal returns the events where the start and the end, which are local times parsed as UTC, are in the time
+// zone of the location, so that they are serialized in UTC
+func localizeICal(calendar []ICal, loc *time.Location) []ICal {
+	if loc == nil {
+		return calendar
+	}
+	result := make([]ICal, len(calendar))
+	for i, entry := range calendar {
+		entry.StartAt = localTime(entry.StartAt, loc)
+		entry.EndAt = localTime(entry.EndAt, loc)
+		result[i] = entry
+	}
+	return result
+}
@@ -0,0 +1,212 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: timezone_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTimestamps(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	assert.Equal(t, "2021-12-01T09:00:00", NormalizeTimestamp("2021-12-01T08:00:00Z", berlin))
+	assert.Equal(t, "2021-07-01T10:00:00", NormalizeTimestamp("2021-07-01T08:00:00Z", berlin))
+	assert.Equal(t, "2021-12-01T07:00:00", NormalizeTimestamp("2021-12-01T08:00:00+01:00", nil))
+	assert.Equal(t, "2021-12-01T08:00:00", NormalizeTimestamp("2021-12-01T08:00:00", berlin))
+	assert.Equal(t, "invalid", NormalizeTimestamp("invalid", berlin))
+
+	input := map[string]interface{}{
+		"tw_open":      "2021-12-01T08:00:00Z",
+		"tw_close":     "2021-12-01T17:00:00",
+		"time_windows": []interface{}{[]interface{}{"2021-12-01T08:00:00+01:00", "2021-12-01T12:00:00+01:00"}},
+		"data":         map[string]interface{}{"tw_open": "2021-12-01T08:00:00Z"},
+		"jobs":         []interface{}{map[string]interface{}{"tw_open": "2021-12-01T08:00:00Z"}},
+	}
+	assert.True(t, NormalizeTimestamps(input, berlin))
+	assert.Equal(t, map[string]interface{}{
+		"tw_open":      "2021-12-01T09:00:00",
+		"tw_close":     "2021-12-01T17:00:00",
+		"time_windows": []interface{}{[]interface{}{"2021-12-01T08:00:00", "2021-12-01T12:00:00"}},
+		"data":         map[string]interface{}{"tw_open": "2021-12-01T08:00:00Z"},
+		"jobs":         []interface{}{map[string]interface{}{"tw_open": "2021-12-01T09:00:00"}},
+	}, input)
+	assert.False(t, NormalizeTimestamps(input, berlin))
+}
+
+func TestNormalizeTimestampsWith(t *testing.T) {
+	calls := 0
+	getLocation := func() *time.Location {
+		calls++
+		return time.UTC
+	}
+
+	// The location is only loaded for the timestamps with an offset
+	input := map[string]interface{}{"tw_open": "2021-12-01T08:00:00", "name": "2021-12-01T08:00:00Z"}
+	assert.False(t, NormalizeTimestampsWith(input, getLocation))
+	assert.Equal(t, 0, calls)
+
+	input = map[string]interface{}{"tw_open": "2021-12-01T08:00:00+01:00"}
+	assert.True(t, NormalizeTimestampsWith(input, getLocation))
+	assert.Equal(t, map[string]interface{}{"tw_open": "2021-12-01T07:00:00"}, input)
+	assert.Equal(t, 1, calls)
+}
+
+func TestHasTimestampFields(t *testing.T) {
+	type node struct {
+		Name     string `json:"name"`
+		Children []node `json:"children"`
+	}
+	type task struct {
+		Arrival *string `json:"arrival"`
+	}
+	type route struct {
+		Tasks []*task `json:"tasks"`
+	}
+
+	assert.False(t, HasTimestampFields(nil))
+	assert.False(t, HasTimestampFields("message"))
+	assert.False(t, HasTimestampFields(map[string]interface{}{"tw_open": "2021-12-01T08:00:00"}))
+	assert.False(t, HasTimestampFields([]node{}))
+	assert.True(t, HasTimestampFields(task{}))
+	assert.True(t, HasTimestampFields(&route{}))
+	assert.True(t, HasTimestampFields(struct {
+		Data interface{} `json:"data"`
+	}{}))
+}
+
+func TestLocalizeTimestamps(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	type task struct {
+		TimeWindows [][]string  `json:"time_windows"`
+		Arrival     *string     `json:"arrival"`
+		Data        interface{} `json:"data"`
+		CreatedAt   string      `json:"created_at"`
+	}
+	type project struct {
+		Timezone  *string `json:"timezone"`
+		Tasks     []task  `json:"tasks"`
+		UpdatedAt string  `json:"updated_at"`
+	}
+	arrival := "2021-07-01T08:00:00"
+	original := []task{{
+		TimeWindows: [][]string{{"2021-12-01T08:00:00", "2021-12-01T12:00:00"}},
+		Arrival:     &arrival,
+		Data:        map[string]interface{}{"created_at": "2021-12-01T08:00:00"},
+		CreatedAt:   "2021-12-01T08:00:00",
+	}}
+	assert.Equal(t, []task{{
+		TimeWindows: [][]string{{"2021-12-01T08:00:00+01:00", "2021-12-01T12:00:00+01:00"}},
+		Arrival:     &[]string{"2021-07-01T08:00:00+02:00"}[0],
+		Data:        map[string]interface{}{"created_at": "2021-12-01T08:00:00"},
+		CreatedAt:   "2021-12-01T09:00:00+01:00",
+	}}, LocalizeTimestamps(original, berlin))
+
+	// The original data is unchanged, and returned as it is without a location
+	assert.Equal(t, "2021-07-01T08:00:00", arrival)
+	assert.Equal(t, "2021-12-01T08:00:00", original[0].TimeWindows[0][0])
+	assert.Equal(t, original, LocalizeTimestamps(original, nil))
+
+	// The structs with a time zone use their own time zone
+	timezone := "America/New_York"
+	localized := LocalizeTimestamps(project{Timezone: &timezone, Tasks: original, UpdatedAt: "2021-12-01T08:00:00"}, berlin).(project)
+	assert.Equal(t, "2021-12-01T03:00:00-05:00", localized.UpdatedAt)
+	assert.Equal(t, "2021-12-01T08:00:00-05:00", localized.Tasks[0].TimeWindows[0][0])
+	localized = LocalizeTimestamps(project{UpdatedAt: "2021-12-01T08:00:00"}, berlin).(project)
+	assert.Equal(t, "2021-12-01T08:00:00", localized.UpdatedAt)
+}
+
+func TestLocalizeTables(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	tables := []Table{{
+		Name:   "Schedule",
+		Header: []string{"task_id", "arrival", "task_data.arrival"},
+		Rows:   [][]interface{}{{int64(1), "2021-12-01T08:00:00", "2021-12-01T08:00:00"}},
+	}}
+	assert.Equal(t, []Table{{
+		Name:   "Schedule",
+		Header: []string{"task_id", "arrival", "task_data.arrival"},
+		Rows:   [][]interface{}{{int64(1), "2021-12-01T08:00:00+01:00", "2021-12-01T08:00:00"}},
+	}}, localizeTables(tables, berlin))
+	assert.Equal(t, tables, localizeTables(tables, nil))
+}
+
+func TestShiftsICalTimezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	calendar := SerializeShiftsICal([]CalendarShift{
+		{ID: 1, VehicleID: 10, TwOpen: "2021-12-01T08:00:00", TwClose: "2021-12-01T17:00:00",
+			CreatedAt: "2021-11-01T10:00:00", UpdatedAt: "2021-11-01T10:00:00"},
+	}, berlin)
+	assert.Contains(t, calendar, "DTSTART:20211201T070000Z")
+	assert.Contains(t, calendar, "DTEND:20211201T160000Z")
+
+	calendar = strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:utc",
+		"DTSTART:20211201T070000Z",
+		"DTEND:20211201T160000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:tzid",
+		"DTSTART;TZID=America/New_York:20211202T030000",
+		"DTEND;TZID=America/New_York:20211202T110000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:floating",
+		"DTSTART:20211203T080000",
+		"DTEND:20211203T170000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	shifts, err := ReadShiftsICal(strings.NewReader(calendar), time.Time{}, berlin)
+	require.NoError(t, err)
+	assert.Equal(t, []CalendarShift{
+		{TwOpen: "2021-12-01T08:00:00", TwClose: "2021-12-01T17:00:00"},
+		{TwOpen: "2021-12-02T09:00:00", TwClose: "2021-12-02T17:00:00"},
+		{TwOpen: "2021-12-03T08:00:00", TwClose: "2021-12-03T17:00:00"},
+	}, shifts)
+
+	calendar = strings.Replace(calendar, "TZID=America/New_York", "TZID=Unknown/Zone", 1)
+	_, err = ReadShiftsICal(strings.NewReader(calendar), time.Time{}, berlin)
+	assert.Error(t, err)
+}
@@ -62,6 +62,24 @@ func getErrorMsg(ve validator.ValidationErrors) []string {
 			err = fmt.Sprintf("Field '%s' must be less than or equal to %s", ve[i].Field(), ve[i].Param())
 		case "oneof":
 			err = fmt.Sprintf("Field '%s' must be one out of %s", ve[i].Field(), strings.Replace(ve[i].Param(), " ", ", ", -1))
+		case "skill":
+			if ve[i].ActualTag() == "min" {
+				err = fmt.Sprintf("Field '%s' must be non-negative", ve[i].Field())
+			} else {
+				err = fmt.Sprintf("Field '%s' must be less than or equal to %d, the greater skills are reserved", ve[i].Field(), MaxSkill)
+			}
+		case "duration":
+			err = fmt.Sprintf("Field '%s' must be of 'HH:MM:SS' format", ve[i].Field())
+		case "rrule":
+			err = fmt.Sprintf("Field '%s' must be a recurrence rule such as 'FREQ=WEEKLY;BYDAY=MO,WE'", ve[i].Field())
+		case "timezone":
+			err = fmt.Sprintf("Field '%s' must be an IANA time zone such as 'Europe/Berlin'", ve[i].Field())
+		case "webhook_url":
+			err = fmt.Sprintf("Field '%s' must be an http or https URL of a public host", ve[i].Field())
+		case "webhook_event":
+			err = fmt.Sprintf("Field '%s' must be one out of %s", ve[i].Field(), strings.Join(WebhookEvents, ", "))
+		case "duration_calc":
+			err = fmt.Sprintf("Field '%s' must be one out of %s", ve[i].Field(), strings.Join(MatrixProviderNames(), ", "))
 		default:
 			err = fmt.Sprintf("Validation of Field '%s' failed on '%s' tag", ve[i].Field(), field)
 		}
@@ -39,6 +39,10 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// MaxSkill is the greatest skill given by the users, the greater skills being reserved for the vehicles of the pinned
+// tasks (see get_pinned_skill)
+const MaxSkill = 1<<30 - 1
+
 var locationTags = map[string]bool{
 	"location":       true,
 	"p_location":     true,
@@ -57,6 +61,31 @@ func NewValidator() *validator.Validate {
 		}
 		return name
 	})
+	// Validate the skills given by the users, which are below the skills of the pinned tasks
+	validate.RegisterAlias("skill", fmt.Sprintf("min=0,max=%d", MaxSkill))
+	// Validate the duration_calc field against the registered matrix providers
+	validate.RegisterValidation("duration_calc", func(fl validator.FieldLevel) bool {
+		_, err := GetMatrixProvider(fl.Field().String())
+		return err == nil
+	})
+	// Validate the duration fields in the HH:MM:SS format, where the hours can exceed 24
+	validate.RegisterValidation("duration", func(fl validator.FieldLevel) bool {
+		seconds, err := ParseDuration(fl.Field().String())
+		return err == nil && seconds >= 0
+	})
+	// Validate the recurrence rules, using the supported subset of the RRULE format
+	validate.RegisterValidation("rrule", func(fl validator.FieldLevel) bool {
+		_, err := ParseRecurrence(fl.Field().String())
+		return err == nil
+	})
+	// Validate the URLs of the webhooks, to which the deliveries are sent
+	validate.RegisterValidation("webhook_url", func(fl validator.FieldLevel) bool {
+		return IsWebhookURL(fl.Field().String())
+	})
+	// Validate the events to which the webhooks subscribe
+	validate.RegisterValidation("webhook_event", func(fl validator.FieldLevel) bool {
+		return IsWebhookEvent(fl.Field().String())
+	})
 	return validate
 }
 
@@ -101,8 +130,23 @@ func ValidateInput(jsonStruct map[string]interface{}, originalStruct interface{}
 			}
 		}
 
-		// Need to validate []int64 fields separately
+		// Need to validate []struct fields separately, validating each element as the struct
 		typ2, ok := jsonStruct[tag].([]interface{})
+		if ok && requiredType.Kind() == reflect.Slice && requiredType.Elem().Kind() == reflect.Struct {
+			for i := 0; i < len(typ2); i++ {
+				element, ok := typ2[i].(map[string]interface{})
+				if !ok {
+					errors = multierror.Append(errors, fmt.Errorf(fmt.Sprintf("Field '%s' must be of '%s' type.", tag, requiredType)))
+					break
+				}
+				if err := ValidateInput(element, reflect.New(requiredType.Elem()).Elem().Interface()); err != nil {
+					errors = multierror.Append(errors, err)
+				}
+			}
+			continue
+		}
+
+		// Need to validate []int64 fields separately
 		if ok && requiredType.Kind() == reflect.Slice {
 			convertible := true
 			for i := 0; i < len(typ2); i++ {
@@ -0,0 +1,258 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: vehicle_shifts.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+-------------------------
+Vehicle Shifts
+-------------------------
+*/
+
+const (
+	shiftCategory     = "SHIFT"
+	dayOffCategory    = "DAY OFF"
+	recurringCategory = "RECURRING"
+)
+
+// CalendarShift is a shift or a day off of a vehicle in its availability calendar
+type CalendarShift struct {
+	ID        int64
+	VehicleID int64
+	TwOpen    string
+	TwClose   string
+	DayOff    bool
+	Recurring bool
+	CreatedAt string
+	UpdatedAt string
+}
+
+// GetAvailableShifts returns the shifts of a vehicle which do not overlap any of its days off, ordered by their
+// start, the overlapping shifts being merged together
+func GetAvailableShifts(shifts []ShiftWindow, daysOff []ShiftWindow) []ShiftWindow {
+	available := []ShiftWindow{}
+	for _, shift := range shifts {
+		overlaps := false
+		for _, dayOff := range daysOff {
+			if dayOff.TwOpen < shift.TwClose && shift.TwOpen < dayOff.TwClose {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			available = append(available, shift)
+		}
+	}
+	sort.SliceStable(available, func(i, j int) bool { return available[i].TwOpen < available[j].TwOpen })
+
+	merged := []ShiftWindow{}
+	for _, shift := range available {
+		last := len(merged) - 1
+		if last >= 0 && shift.TwOpen <= merged[last].TwClose {
+			if shift.TwClose > merged[last].TwClose {
+				merged[last].TwClose = shift.TwClose
+			}
+			continue
+		}
+		merged = append(merged, shift)
+	}
+	return merged
+}
+
+// SerializeShiftsICal returns the availability calendar of a vehicle in the iCalendar format, with an event for each
+// shift and day off. The days off starting and ending at midnight are all-day events, and the other events are in UTC,
+// their local times being in the location when it is not nil.
+func SerializeShiftsICal(shifts []CalendarShift, loc *time.Location) string {
+	cal := ics.NewCalendar()
+	cal.SetMethod(ics.MethodPublish)
+	for _, shift := range shifts {
+		event := cal.AddEvent(fmt.Sprintf("%d", shift.ID))
+		event.SetCreatedTime(parseTime(shift.CreatedAt))
+		event.SetDtStampTime(parseTime(shift.UpdatedAt))
+		event.SetModifiedAt(parseTime(shift.UpdatedAt))
+
+		twOpen, twClose := parseTime(shift.TwOpen), parseTime(shift.TwClose)
+		categories := []string{shiftCategory}
+		summary := "Shift"
+		if shift.DayOff {
+			categories = []string{dayOffCategory}
+			summary = "Day off"
+			event.SetTimeTransparency(ics.TransparencyTransparent)
+		}
+		if shift.DayOff && twOpen.Equal(truncateDate(twOpen)) && twClose.Equal(truncateDate(twClose)) {
+			event.SetAllDayStartAt(twOpen, ics.WithValue(string(ics.ValueDataTypeDate)))
+			event.SetAllDayEndAt(twClose, ics.WithValue(string(ics.ValueDataTypeDate)))
+		} else {
+			event.SetStartAt(localTime(twOpen, loc))
+			event.SetEndAt(localTime(twClose, loc))
+		}
+		if shift.Recurring {
+			categories = append(categories, recurringCategory)
+		}
+		event.SetProperty(ics.ComponentPropertyCategories, strings.Join(categories, ","))
+		event.SetSummary(fmt.Sprintf("%s - Vehicle %d", summary, shift.VehicleID))
+
+		desc := fmt.Sprintf("Vehicle ID: %d\n", shift.VehicleID)
+		desc += fmt.Sprintf("Shift ID: %d\n", shift.ID)
+		desc += fmt.Sprintf("Recurring: %t\n", shift.Recurring)
+		event.SetDescription(desc)
+	}
+	return cal.Serialize()
+}
+
+// ReadShiftsICal reads the shifts and the days off of a vehicle from an iCalendar file. The all-day events and the
+// events with the "DAY OFF" category are days off, and the other events are shifts. The recurring events are expanded
+// until the date to, or during a year when it is zero, and the events with the "RECURRING" category, which are created
+// from the shift recurrence of a vehicle, are skipped. The times in UTC or with a TZID are converted to the local times
+// of the location (UTC when nil), and the floating times are read as local times.
+func ReadShiftsICal(r io.Reader, to time.Time, loc *time.Location) ([]CalendarShift, error) {
+	cal, err := ics.ParseCalendar(r)
+	if err != nil {
+		return nil, fmt.Errorf("Request body must be an iCalendar file")
+	}
+	shifts := []CalendarShift{}
+	for i, event := range cal.Events() {
+		categories := getICalCategories(event)
+		if categories[recurringCategory] {
+			continue
+		}
+		twOpen, allDay, err := getICalTime(event, ics.ComponentPropertyDtStart, loc)
+		if err != nil {
+			return nil, fmt.Errorf("Event %d: %s", i+1, err)
+		}
+		twClose, _, err := getICalTime(event, ics.ComponentPropertyDtEnd, loc)
+		if err != nil && allDay {
+			twClose, err = twOpen.AddDate(0, 0, 1), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Event %d: %s", i+1, err)
+		}
+		if !twClose.After(twOpen) {
+			return nil, fmt.Errorf("Event %d: Field 'DTEND' must be after field 'DTSTART'", i+1)
+		}
+
+		dates := []time.Time{truncateDate(twOpen)}
+		if rule := event.GetProperty(ics.ComponentPropertyRrule); rule != nil {
+			recurrence, err := ParseRecurrence(rule.Value)
+			if err != nil {
+				return nil, fmt.Errorf("Event %d: %s", i+1, err)
+			}
+			until := to
+			if until.IsZero() {
+				until = twOpen.AddDate(1, 0, 0)
+			}
+			dates = recurrence.Dates(twOpen, twOpen, until)
+		}
+		for _, date := range dates {
+			offset := date.Sub(truncateDate(twOpen))
+			shifts = append(shifts, CalendarShift{
+				TwOpen:  twOpen.Add(offset).Format("2006-01-02T15:04:05"),
+				TwClose: twClose.Add(offset).Format("2006-01-02T15:04:05"),
+				DayOff:  allDay || categories[dayOffCategory],
+			})
+		}
+	}
+	return shifts, nil
+}
+
+// getICalCategories returns the categories of an event, in upper case
+func getICalCategories(event *ics.VEvent) map[string]bool {
+	categories := map[string]bool{}
+	property := event.GetProperty(ics.ComponentPropertyCategories)
+	if property == nil {
+		return categories
+	}
+	for _, category := range strings.Split(property.Value, ",") {
+		categories[strings.ToUpper(strings.TrimSpace(category))] = true
+	}
+	return categories
+}
+
+// getICalTime returns the local time in the location of a date or date-time property of an event, parsed as UTC, and
+// whether it is a date
+func getICalTime(event *ics.VEvent, componentProperty ics.ComponentProperty, loc *time.Location) (time.Time, bool, error) {
+	property := event.GetProperty(componentProperty)
+	if property == nil {
+		return time.Time{}, false, fmt.Errorf("Field '%s' is required", componentProperty)
+	}
+	value := property.Value
+	if len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("Field '%s' must be a date or a date-time", componentProperty)
+		}
+		return t, true, nil
+	}
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return wallTime(t, loc), false, nil
+	}
+	if tzid, ok := property.ICalParameters["TZID"]; ok && len(tzid) == 1 {
+		tzLoc, err := time.LoadLocation(tzid[0])
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("Field '%s' has an unknown TZID '%s'", componentProperty, tzid[0])
+		}
+		if t, err := time.ParseInLocation("20060102T150405", value, tzLoc); err == nil {
+			return wallTime(t, loc), false, nil
+		}
+	}
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t, false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("Field '%s' must be a date or a date-time", componentProperty)
+}
+
+func (r *Formatter) FormatShiftsICAL(w http.ResponseWriter, respCode int, shifts []CalendarShift, filename string) {
+	// Set the content-type, content-disposition, and response code in the header
+	w.Header().Set("Content-Type", "text/calendar")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.WriteHeader(respCode)
+
+	b := r.pool.Get().(*bytes.Buffer)
+	b.Reset()
+	defer r.pool.Put(b)
+
+	b.WriteString(SerializeShiftsICal(shifts, GetResponseLocation(w)))
+
+	_, err := b.WriteTo(w)
+	if err != nil {
+		logrus.Error(err)
+	}
+}
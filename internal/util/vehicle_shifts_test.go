@@ -0,0 +1,121 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: vehicle_shifts_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAvailableShifts(t *testing.T) {
+	shifts := []ShiftWindow{
+		{"2021-12-02T13:00:00", "2021-12-02T17:00:00"},
+		{"2021-12-01T08:00:00", "2021-12-01T12:00:00"},
+		{"2021-12-01T12:00:00", "2021-12-01T16:00:00"},
+		{"2021-12-02T08:00:00", "2021-12-02T12:00:00"},
+		{"2021-12-03T08:00:00", "2021-12-03T17:00:00"},
+	}
+	daysOff := []ShiftWindow{{"2021-12-03T00:00:00", "2021-12-04T00:00:00"}}
+	assert.Equal(t, []ShiftWindow{
+		{"2021-12-01T08:00:00", "2021-12-01T16:00:00"},
+		{"2021-12-02T08:00:00", "2021-12-02T12:00:00"},
+		{"2021-12-02T13:00:00", "2021-12-02T17:00:00"},
+	}, GetAvailableShifts(shifts, daysOff))
+
+	assert.Equal(t, []ShiftWindow{}, GetAvailableShifts(shifts[4:], daysOff))
+}
+
+func TestShiftsICal(t *testing.T) {
+	shifts := []CalendarShift{
+		{ID: 1, VehicleID: 10, TwOpen: "2021-12-01T08:00:00", TwClose: "2021-12-01T17:00:00",
+			CreatedAt: "2021-11-01T10:00:00", UpdatedAt: "2021-11-01T10:00:00"},
+		{ID: 2, VehicleID: 10, TwOpen: "2021-12-02T00:00:00", TwClose: "2021-12-03T00:00:00", DayOff: true,
+			CreatedAt: "2021-11-01T10:00:00", UpdatedAt: "2021-11-01T10:00:00"},
+		{ID: 3, VehicleID: 10, TwOpen: "2021-12-03T12:00:00", TwClose: "2021-12-03T14:00:00", DayOff: true,
+			CreatedAt: "2021-11-01T10:00:00", UpdatedAt: "2021-11-01T10:00:00"},
+		{ID: 4, VehicleID: 10, TwOpen: "2021-12-06T08:00:00", TwClose: "2021-12-06T17:00:00", Recurring: true,
+			CreatedAt: "2021-11-01T10:00:00", UpdatedAt: "2021-11-01T10:00:00"},
+	}
+	calendar := SerializeShiftsICal(shifts, nil)
+	assert.Contains(t, calendar, "DTSTART;VALUE=DATE:20211202")
+	assert.Contains(t, calendar, "DTSTART:20211201T080000Z")
+	assert.Contains(t, calendar, "SUMMARY:Day off - Vehicle 10")
+
+	// The recurring shifts are skipped when reading the calendar
+	read, err := ReadShiftsICal(strings.NewReader(calendar), time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []CalendarShift{
+		{TwOpen: "2021-12-01T08:00:00", TwClose: "2021-12-01T17:00:00"},
+		{TwOpen: "2021-12-02T00:00:00", TwClose: "2021-12-03T00:00:00", DayOff: true},
+		{TwOpen: "2021-12-03T12:00:00", TwClose: "2021-12-03T14:00:00", DayOff: true},
+	}, read)
+}
+
+func TestReadShiftsICal(t *testing.T) {
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:shift",
+		"DTSTART:20211201T080000",
+		"DTEND:20211201T120000",
+		"RRULE:FREQ=WEEKLY;BYDAY=WE,FR",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:holiday",
+		"DTSTART;VALUE=DATE:20211210",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	shifts, err := ReadShiftsICal(strings.NewReader(calendar), time.Date(2021, 12, 10, 0, 0, 0, 0, time.UTC), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []CalendarShift{
+		{TwOpen: "2021-12-01T08:00:00", TwClose: "2021-12-01T12:00:00"},
+		{TwOpen: "2021-12-03T08:00:00", TwClose: "2021-12-03T12:00:00"},
+		{TwOpen: "2021-12-08T08:00:00", TwClose: "2021-12-08T12:00:00"},
+		{TwOpen: "2021-12-10T08:00:00", TwClose: "2021-12-10T12:00:00"},
+		{TwOpen: "2021-12-10T00:00:00", TwClose: "2021-12-11T00:00:00", DayOff: true},
+	}, shifts)
+
+	for _, event := range []string{
+		"DTSTART:20211201T080000",
+		"DTSTART:20211201T080000\r\nDTEND:20211201T070000",
+		"DTSTART:2021-12-01\r\nDTEND:20211201T070000",
+		"DTSTART:20211201T080000\r\nDTEND:20211201T120000\r\nRRULE:FREQ=YEARLY",
+	} {
+		calendar := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VEVENT\r\n" + event + "\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+		_, err := ReadShiftsICal(strings.NewReader(calendar), time.Date(2021, 12, 10, 0, 0, 0, 0, time.UTC), nil)
+		assert.Error(t, err, event)
+	}
+}
@@ -0,0 +1,141 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: webhook.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+/*
+-------------------------
+Webhooks
+-------------------------
+*/
+
+// WebhookEvents are the events of a project to which the webhooks can subscribe
+var WebhookEvents = []string{
+	"schedule.created",
+	"schedule.deleted",
+	"job.status_changed",
+	"shipment.status_changed",
+	"task.created",
+	"task.updated",
+	"task.deleted",
+}
+
+// AllowPrivateWebhookURLs allows the webhooks to target the loopback, link-local and private addresses, which are
+// rejected by default so that the webhooks can not reach the internal services of the server (SSRF)
+var AllowPrivateWebhookURLs = false
+
+// maxWebhookRetryDelay is the longest delay between two attempts of a webhook delivery
+const maxWebhookRetryDelay = 24 * time.Hour
+
+// IsWebhookEvent returns whether the event is one of the WebhookEvents
+func IsWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWebhookURL returns whether the value is an absolute http or https URL, to which the deliveries can be sent.
+// The URLs of the local host, or of a loopback, link-local or private address, are rejected unless
+// AllowPrivateWebhookURLs is set. The host names are checked again against the address they resolve to when the
+// deliveries are sent, with WebhookDialControl.
+func IsWebhookURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return false
+	}
+	if AllowPrivateWebhookURLs {
+		return true
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return isPublicIP(ip)
+	}
+	return true
+}
+
+// WebhookDialControl rejects the connections of the webhook deliveries to a loopback, link-local or private address,
+// unless AllowPrivateWebhookURLs is set. It is the Control function of the dialer of the deliveries, so that it
+// checks the resolved address of the host names and of the redirects.
+func WebhookDialControl(network string, address string, c syscall.RawConn) error {
+	if AllowPrivateWebhookURLs {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("Error: The webhook address %s is not a public address", host)
+	}
+	return nil
+}
+
+// isPublicIP returns whether the IP address is not a loopback, link-local, private (RFC 1918 and RFC 4193),
+// unspecified or multicast address
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsPrivate() &&
+		!ip.IsUnspecified() && !ip.IsMulticast()
+}
+
+// SignWebhookPayload returns the signature of the payload of a webhook delivery sent in the X-Scheduleserv-Signature
+// header, which is the hex encoded HMAC-SHA256 of the payload with the secret of the webhook, prefixed with "sha256="
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookRetryDelay returns the delay before the next attempt of a webhook delivery which failed after the given
+// number of attempts. The delay is doubled after each attempt, up to a day.
+func WebhookRetryDelay(delay time.Duration, attempts int) time.Duration {
+	for i := 1; i < attempts && delay < maxWebhookRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxWebhookRetryDelay {
+		return maxWebhookRetryDelay
+	}
+	return delay
+}
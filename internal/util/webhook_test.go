@@ -0,0 +1,91 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: webhook_test.go
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	// Signature computed with: echo -n '{"event": "task.created"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t,
+		"sha256=0c9005d1fcfccd31984eba1aabb73d7721c907fdf5a6c8cd774fbe7a2cddabfe",
+		SignWebhookPayload("secret", []byte(`{"event": "task.created"}`)),
+	)
+	assert.NotEqual(t,
+		SignWebhookPayload("secret", []byte(`{"event": "task.created"}`)),
+		SignWebhookPayload("other", []byte(`{"event": "task.created"}`)),
+	)
+}
+
+func TestWebhookDialControl(t *testing.T) {
+	assert.NoError(t, WebhookDialControl("tcp", "93.184.216.34:443", nil))
+	assert.EqualError(t, WebhookDialControl("tcp", "127.0.0.1:8080", nil), "Error: The webhook address 127.0.0.1 is not a public address")
+	assert.EqualError(t, WebhookDialControl("tcp", "[fe80::1]:80", nil), "Error: The webhook address fe80::1 is not a public address")
+	assert.Error(t, WebhookDialControl("tcp", "10.1.2.3:80", nil))
+
+	AllowPrivateWebhookURLs = true
+	assert.NoError(t, WebhookDialControl("tcp", "127.0.0.1:8080", nil))
+	AllowPrivateWebhookURLs = false
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, WebhookRetryDelay(30*time.Second, 1))
+	assert.Equal(t, 60*time.Second, WebhookRetryDelay(30*time.Second, 2))
+	assert.Equal(t, 4*time.Minute, WebhookRetryDelay(30*time.Second, 4))
+	assert.Equal(t, 24*time.Hour, WebhookRetryDelay(30*time.Second, 20))
+	assert.Equal(t, 24*time.Hour, WebhookRetryDelay(30*time.Second, 1000))
+}
+
+func TestWebhookValidation(t *testing.T) {
+	assert.True(t, IsWebhookURL("https://example.com/webhook"))
+	assert.True(t, IsWebhookURL("http://93.184.216.34:8080"))
+	assert.False(t, IsWebhookURL("ftp://example.com"))
+	assert.False(t, IsWebhookURL("/webhook"))
+	assert.False(t, IsWebhookURL("invalid"))
+
+	// The local and private addresses are rejected, unless they are allowed
+	for _, value := range []string{
+		"http://localhost:8080", "http://api.localhost", "http://127.0.0.1:8080", "http://[::1]/webhook",
+		"http://169.254.169.254/latest/meta-data", "http://10.0.0.1", "http://172.16.0.1", "http://192.168.1.1",
+		"http://0.0.0.0", "http://[fd00::1]",
+	} {
+		assert.False(t, IsWebhookURL(value), value)
+	}
+	AllowPrivateWebhookURLs = true
+	assert.True(t, IsWebhookURL("http://127.0.0.1:8080"))
+	assert.True(t, IsWebhookURL("http://localhost:8080"))
+	AllowPrivateWebhookURLs = false
+
+	assert.True(t, IsWebhookEvent("job.status_changed"))
+	assert.False(t, IsWebhookEvent("job.created"))
+}
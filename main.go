@@ -32,16 +32,23 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"runtime"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/Georepublic/pg_scheduleserv/internal/api"
 	"github.com/Georepublic/pg_scheduleserv/internal/config"
-	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/Georepublic/pg_scheduleserv/internal/database"
+	"github.com/Georepublic/pg_scheduleserv/internal/util"
 	"github.com/sirupsen/logrus"
 )
 
+// Duration for which the requests being served are waited for when the server is shut down
+const shutdownTimeout = 30 * time.Second
+
 // @title pg_scheduleserv API
 // @version 0.2.0
 // @description This is an API for scheduling VRP tasks. Source code can be found on https://github.com/Georepublic/pg_scheduleserv
@@ -81,12 +88,45 @@ func main() {
 		config.DatabasePort,
 		config.DatabaseName,
 	)
-	conn, err := pgxpool.Connect(context.Background(), connectionURL)
+	conn, err := database.Connect(context.Background(), connectionURL)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Unable to connect to database: %v\n", err)
 		os.Exit(1)
 	}
 	defer conn.Close()
+
+	// Register the matrix providers available as the "duration_calc" of the projects
+	if err := util.RegisterMatrixProviders(config); err != nil {
+		logrus.Error("Cannot register the matrix providers:", err)
+	}
+	if config.PgRoutingEdgesTable != "" {
+		util.RegisterMatrixProvider("pgrouting", database.NewPgRoutingMatrixProvider(
+			conn, config.PgRoutingEdgesTable, config.PgRoutingVerticesTable,
+		))
+	}
+
+	// Allow the webhooks targeting the internal services only when configured
+	util.AllowPrivateWebhookURLs = config.WebhookAllowPrivate
+
 	server := api.NewServer(conn)
-	logrus.Error(server.Start(config.ServerPort))
+
+	// Serve the requests until the process is interrupted or terminated, and then shut down the server gracefully
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Start(config.ServerPort)
+	}()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-errs:
+		logrus.Error(err)
+		server.Close()
+	case sig := <-signals:
+		logrus.Info("Shutting down the server on ", sig)
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			logrus.Error(err)
+		}
+	}
 }
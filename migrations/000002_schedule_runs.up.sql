@@ -0,0 +1,59 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000002_schedule_runs.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+DO $$ BEGIN
+  CREATE TYPE schedule_run_status AS ENUM ('queued', 'running', 'succeeded', 'failed');
+EXCEPTION
+  WHEN duplicate_object THEN null;
+END $$;
+
+-- SCHEDULE RUNS TABLE start
+CREATE TABLE IF NOT EXISTS schedule_runs (
+  id            BIGINT              DEFAULT random_bigint() PRIMARY KEY,
+  project_id    BIGINT              NOT NULL REFERENCES projects(id),
+  status        SCHEDULE_RUN_STATUS NOT NULL DEFAULT 'queued',
+  fresh         BOOLEAN             NOT NULL DEFAULT FALSE,
+  error         TEXT,
+
+  started_at    TIMESTAMP,
+  finished_at   TIMESTAMP,
+  created_at    TIMESTAMP           NOT NULL DEFAULT current_timestamp,
+  updated_at    TIMESTAMP           NOT NULL DEFAULT current_timestamp,
+
+  CHECK(id >= 0),
+  CHECK(started_at IS NULL OR finished_at IS NULL OR started_at <= finished_at)
+);
+-- SCHEDULE RUNS TABLE end
+
+CREATE TRIGGER tgr_updated_at_field
+BEFORE UPDATE ON schedule_runs
+FOR EACH ROW EXECUTE PROCEDURE tgr_updated_at_field_func();
+
+END;
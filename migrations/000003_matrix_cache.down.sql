@@ -0,0 +1,38 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000003_matrix_cache.down.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+DROP TRIGGER IF EXISTS tgr_projects_duration_calc_update ON projects;
+DROP FUNCTION IF EXISTS tgr_projects_duration_calc_update_func;
+DROP FUNCTION IF EXISTS clear_matrix;
+
+DROP TRIGGER IF EXISTS tgr_updated_at_field ON matrix;
+DROP TABLE IF EXISTS matrix;
+
+END;
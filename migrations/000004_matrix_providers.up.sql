@@ -0,0 +1,49 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000004_matrix_providers.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- The duration_calc can be any of the matrix providers registered in the server,
+-- so the DURATION_CALC_TYPE enum is replaced by VARCHAR
+DROP TRIGGER IF EXISTS tgr_projects_duration_calc_update ON projects;
+
+ALTER TABLE projects ALTER COLUMN duration_calc DROP DEFAULT;
+ALTER TABLE projects ALTER COLUMN duration_calc TYPE VARCHAR USING duration_calc::VARCHAR;
+ALTER TABLE projects ALTER COLUMN duration_calc SET DEFAULT 'euclidean';
+
+ALTER TABLE matrix ALTER COLUMN duration_calc TYPE VARCHAR USING duration_calc::VARCHAR;
+
+DROP TYPE IF EXISTS duration_calc_type;
+
+CREATE TRIGGER tgr_projects_duration_calc_update
+AFTER UPDATE OF duration_calc ON projects
+FOR EACH ROW
+WHEN (OLD.duration_calc IS DISTINCT FROM NEW.duration_calc)
+EXECUTE PROCEDURE tgr_projects_duration_calc_update_func();
+
+END;
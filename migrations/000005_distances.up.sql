@@ -0,0 +1,76 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000005_distances.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- The cached durations do not have the distances, so they are computed again
+DELETE FROM matrix;
+ALTER TABLE matrix ADD COLUMN distance BIGINT NOT NULL;
+ALTER TABLE matrix ADD CHECK(distance >= 0);
+
+ALTER TABLE schedules ADD COLUMN distance BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE schedules ADD CHECK(distance >= 0);
+
+
+-- Update the distance (in meters) of the steps in the schedule of a project, using the cached matrix.
+-- The distance of each step is from the location of the previous step of the vehicle, ignoring the breaks.
+-- The distance of a summary is the sum of the distances of the steps of the vehicle, or of all the vehicles.
+CREATE OR REPLACE FUNCTION update_schedule_distances(
+  project_id_param BIGINT
+)
+RETURNS void
+AS $BODY$
+  WITH steps AS (
+    SELECT task_id, type, vehicle_id, location_id,
+      LAG(location_id) OVER (PARTITION BY vehicle_id ORDER BY arrival, type) AS prev_location_id
+    FROM schedules
+    WHERE project_id = project_id_param AND vehicle_id > 0 AND type NOT IN ('summary', 'break')
+  )
+  UPDATE schedules S SET distance = COALESCE(M.distance, 0)
+  FROM steps
+  LEFT JOIN matrix M ON (
+    M.start_id = steps.prev_location_id AND M.end_id = steps.location_id
+    AND M.duration_calc = (SELECT duration_calc FROM projects WHERE id = project_id_param)
+  )
+  WHERE S.project_id = project_id_param AND S.task_id = steps.task_id
+    AND S.type = steps.type AND S.vehicle_id = steps.vehicle_id;
+
+  UPDATE schedules S SET distance = (
+    SELECT COALESCE(SUM(distance), 0) FROM schedules
+    WHERE project_id = project_id_param AND vehicle_id = S.vehicle_id AND type != 'summary'
+  )
+  WHERE S.project_id = project_id_param AND S.vehicle_id > 0 AND S.type = 'summary';
+
+  UPDATE schedules S SET distance = (
+    SELECT COALESCE(SUM(distance), 0) FROM schedules
+    WHERE project_id = project_id_param AND vehicle_id > 0 AND type = 'summary'
+  )
+  WHERE S.project_id = project_id_param AND S.vehicle_id = 0 AND S.type = 'summary';
+$BODY$ LANGUAGE sql VOLATILE;
+
+END;
@@ -0,0 +1,36 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000006_source_ids.down.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+ALTER TABLE breaks DROP COLUMN IF EXISTS source_id;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS source_id;
+ALTER TABLE shipments DROP COLUMN IF EXISTS source_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS source_id;
+
+END;
@@ -0,0 +1,38 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000006_source_ids.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- The id of the original row, set when a project is cloned, so that the
+-- schedules of a project and its clones can be compared
+ALTER TABLE jobs ADD COLUMN source_id BIGINT;
+ALTER TABLE shipments ADD COLUMN source_id BIGINT;
+ALTER TABLE vehicles ADD COLUMN source_id BIGINT;
+ALTER TABLE breaks ADD COLUMN source_id BIGINT;
+
+END;
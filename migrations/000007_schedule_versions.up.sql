@@ -0,0 +1,102 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000007_schedule_versions.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- SCHEDULE VERSIONS TABLE start
+CREATE TABLE IF NOT EXISTS schedule_versions (
+  project_id    BIGINT    NOT NULL REFERENCES projects(id),
+  version       INTEGER   NOT NULL,
+  inputs_hash   TEXT      NOT NULL,
+  fresh         BOOLEAN   NOT NULL DEFAULT FALSE,
+  settings      JSONB     NOT NULL DEFAULT '{}'::JSONB,
+  metadata      JSONB     NOT NULL DEFAULT '{}'::JSONB,
+  active        BOOLEAN   NOT NULL DEFAULT FALSE,
+
+  created_at    TIMESTAMP NOT NULL DEFAULT current_timestamp,
+  updated_at    TIMESTAMP NOT NULL DEFAULT current_timestamp,
+
+  PRIMARY KEY (project_id, version),
+  CHECK(version > 0)
+);
+-- SCHEDULE VERSIONS TABLE end
+
+CREATE TRIGGER tgr_updated_at_field
+BEFORE UPDATE ON schedule_versions
+FOR EACH ROW EXECUTE PROCEDURE tgr_updated_at_field_func();
+
+
+-- SCHEDULE VERSION STEPS TABLE start
+CREATE TABLE IF NOT EXISTS schedule_version_steps (
+  version       INTEGER   NOT NULL,
+  LIKE schedules INCLUDING DEFAULTS INCLUDING CONSTRAINTS,
+
+  PRIMARY KEY (task_id, type, vehicle_id, project_id, version),
+  FOREIGN KEY (project_id, version) REFERENCES schedule_versions(project_id, version)
+);
+-- SCHEDULE VERSION STEPS TABLE end
+
+
+-- Hash of the inputs of the scheduling of a project: the non-deleted jobs, shipments, vehicles and breaks
+-- along with their time windows, ignoring the timestamps and the status of the rows.
+CREATE OR REPLACE FUNCTION get_project_inputs_hash(
+  project_id_param BIGINT
+)
+RETURNS TEXT
+AS $BODY$
+  SELECT md5(jsonb_build_array(
+    (
+      SELECT jsonb_agg((to_jsonb(J) - ARRAY['created_at', 'updated_at', 'status']) || jsonb_build_object('time_windows', (
+        SELECT jsonb_agg(jsonb_build_array(tw_open, tw_close) ORDER BY tw_open, tw_close)
+        FROM jobs_time_windows TW WHERE TW.id = J.id
+      )) ORDER BY J.id)
+      FROM jobs J WHERE project_id = project_id_param AND deleted = FALSE
+    ),
+    (
+      SELECT jsonb_agg((to_jsonb(S) - ARRAY['created_at', 'updated_at', 'status']) || jsonb_build_object('time_windows', (
+        SELECT jsonb_agg(jsonb_build_array(kind, tw_open, tw_close) ORDER BY kind, tw_open, tw_close)
+        FROM shipments_time_windows TW WHERE TW.id = S.id
+      )) ORDER BY S.id)
+      FROM shipments S WHERE project_id = project_id_param AND deleted = FALSE
+    ),
+    (
+      SELECT jsonb_agg((to_jsonb(V) - ARRAY['created_at', 'updated_at']) ORDER BY V.id)
+      FROM vehicles V WHERE project_id = project_id_param AND deleted = FALSE
+    ),
+    (
+      SELECT jsonb_agg((to_jsonb(B) - ARRAY['created_at', 'updated_at']) || jsonb_build_object('time_windows', (
+        SELECT jsonb_agg(jsonb_build_array(tw_open, tw_close) ORDER BY tw_open, tw_close)
+        FROM breaks_time_windows TW WHERE TW.id = B.id
+      )) ORDER BY B.id)
+      FROM breaks B JOIN vehicles V ON (B.vehicle_id = V.id)
+      WHERE V.project_id = project_id_param AND V.deleted = FALSE AND B.deleted = FALSE
+    )
+  )::TEXT);
+$BODY$ LANGUAGE sql STABLE;
+
+END;
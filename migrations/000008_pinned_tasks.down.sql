@@ -0,0 +1,180 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000008_pinned_tasks.down.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+DROP FUNCTION IF EXISTS get_pinned_tasks;
+DROP FUNCTION IF EXISTS get_pinned_skill;
+
+ALTER TABLE shipments DROP COLUMN IF EXISTS locked;
+ALTER TABLE shipments DROP COLUMN IF EXISTS pinned_vehicle_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS locked;
+ALTER TABLE jobs DROP COLUMN IF EXISTS pinned_vehicle_id;
+
+-- Create schedule for a project (such that any previous scheduled tasks are not likely to be unscheduled)
+CREATE OR REPLACE FUNCTION create_schedule(
+  project_id_param BIGINT,
+  start_ids BIGINT[],
+  end_ids BIGINT[],
+  durations BIGINT[]
+)
+RETURNS void
+AS $BODY$
+
+  CREATE TABLE schedules_copy AS TABLE schedules;
+
+  -- DELETE the schedules without changing the status field of jobs/shipments. Status field will be set by insert trigger later.
+  ALTER TABLE schedules DISABLE TRIGGER tgr_schedule_delete;
+  DELETE FROM schedules WHERE project_id = project_id_param;
+  ALTER TABLE schedules ENABLE TRIGGER tgr_schedule_delete;
+
+  WITH delta AS (SELECT max_shift FROM projects WHERE id = project_id_param)
+  INSERT INTO schedules
+    (type, project_id, vehicle_id, location_id, task_id, vehicle_data, task_data,
+    arrival, travel_time, setup_time, service_time, waiting_time, departure, load)
+  SELECT
+    CASE
+      WHEN step_type = 0 THEN 'summary'::STEP_TYPE
+      WHEN step_type = 1 THEN 'start'::STEP_TYPE
+      WHEN step_type = 2 THEN 'job'::STEP_TYPE
+      WHEN step_type = 3 THEN 'pickup'::STEP_TYPE
+      WHEN step_type = 4 THEN 'delivery'::STEP_TYPE
+      WHEN step_type = 5 THEN 'break'::STEP_TYPE
+      WHEN step_type = 6 THEN 'end'::STEP_TYPE
+    END,
+    project_id_param::BIGINT, vehicle_id, location_id, task_id, vehicle_data, task_data,
+    arrival, travel_time, setup_time, service_time, waiting_time, departure, load
+  FROM vrp_vroom(
+    -- jobs (Unscheduled jobs + Scheduled jobs with 100 priority)
+    'SELECT id, location_id, setup, service, delivery, pickup, skills, priority, data
+     FROM jobs WHERE project_id = ' || project_id_param || ' AND status = ''unscheduled'' AND deleted = FALSE
+     UNION
+     SELECT id, location_id, setup, service, delivery, pickup, skills, 100 AS priority, data
+     FROM jobs WHERE project_id = ' || project_id_param || ' AND status = ''scheduled'' AND deleted = FALSE',
+
+    -- jobs_time_windows (For unscheduled, select original time windows. For scheduled, alter the time window with a delta interval from the arrival time)
+    'SELECT J.id AS id, tw_open, tw_close
+     FROM jobs_time_windows TW LEFT JOIN jobs J ON(TW.id = J.id)
+     WHERE status = ''unscheduled'' AND project_id = ' || project_id_param || '
+    UNION
+     SELECT
+      J.id AS id,
+      GREATEST(tw_open, arrival - $$' || (SELECT * FROM delta) || '$$::INTERVAL) AS tw_open,
+      LEAST(tw_close, arrival + $$' || (SELECT * FROM delta) || '$$::INTERVAL) AS tw_close
+     FROM jobs_time_windows TW RIGHT JOIN jobs J ON(TW.id = J.id) JOIN schedules_copy S ON (J.id = S.task_id)
+     WHERE
+      GREATEST(tw_open, arrival - $$' || (SELECT * FROM delta) || '$$::INTERVAL) <= LEAST(tw_close, arrival + $$' || (SELECT * FROM delta) || '$$::INTERVAL)
+      AND status = ''scheduled'' AND type = ''job'' AND J.project_id = ' || project_id_param || ' ORDER BY id, tw_open',
+
+    -- shipments (Unscheduled shipments + Scheduled shipments with 100 priority)
+    'SELECT id, p_location_id, p_setup, p_service, d_location_id, d_setup, d_service, amount, skills, priority, p_data, d_data
+     FROM shipments WHERE project_id = ' || project_id_param || ' AND status = ''unscheduled'' AND deleted = FALSE
+     UNION
+     SELECT id, p_location_id, p_setup, p_service, d_location_id, d_setup, d_service, amount, skills, 100 AS priority, p_data, d_data
+     FROM shipments WHERE project_id = ' || project_id_param || ' AND status = ''scheduled'' AND deleted = FALSE',
+
+    -- shipments_time_windows
+    -- For unscheduled, select original time windows.
+    -- For scheduled, alter the time window with a delta interval from the arrival time
+    -- TODO: When time windows are "edited" such that the delta range falls outside new time windows, then the time window is ignored because the <= condition fails
+    'SELECT S.id AS id, kind, tw_open, tw_close
+     FROM shipments_time_windows TW LEFT JOIN shipments S ON(TW.id = S.id)
+     WHERE status = ''unscheduled'' AND project_id = ' || project_id_param || '
+    UNION
+     SELECT
+      S.id AS id,
+      kind,
+      GREATEST(tw_open, arrival - $$' || (SELECT * FROM delta) || '$$::INTERVAL) AS tw_open,
+      LEAST(tw_close, arrival + $$' || (SELECT * FROM delta) || '$$::INTERVAL) AS tw_close
+     FROM shipments_time_windows TW RIGHT JOIN shipments S ON(TW.id = S.id) JOIN schedules_copy S2 ON (S.id = S2.task_id)
+     WHERE
+      GREATEST(tw_open, arrival - $$' || (SELECT * FROM delta) || '$$::INTERVAL) <= LEAST(tw_close, arrival + $$' || (SELECT * FROM delta) || '$$::INTERVAL)
+      AND status = ''scheduled'' AND ((type = ''pickup'' AND kind = ''p'') OR (type = ''delivery'' AND kind = ''d''))
+      AND S.project_id = ' || project_id_param || ' ORDER BY id, tw_open',
+
+    -- vehicles
+    'SELECT * FROM vehicles WHERE deleted = FALSE AND project_id = ' || project_id_param || '',
+
+    -- breaks
+    'SELECT * FROM breaks WHERE deleted = FALSE',
+    'SELECT * FROM breaks_time_windows ORDER BY id, tw_open',
+
+    -- matrix
+    'SELECT unnest(ARRAY[' || array_to_string(start_ids, ',') || ']::BIGINT[]) AS start_id,
+     unnest(ARRAY[' || array_to_string(end_ids, ',') || ']::BIGINT[]) AS end_id,
+     make_interval(secs => unnest(ARRAY[' || array_to_string(durations, ',') || ']::BIGINT[])) AS duration',
+
+    exploration_level => (SELECT exploration_level FROM projects WHERE id = project_id_param),
+    timeout => (SELECT timeout FROM projects WHERE id = project_id_param)
+  );
+  DROP TABLE schedules_copy;
+$BODY$ LANGUAGE sql VOLATILE;
+
+
+-- Create schedule for a project (fresh scheduling, deleting any previous schedule)
+CREATE OR REPLACE FUNCTION create_fresh_schedule(
+  project_id_param BIGINT,
+  start_ids BIGINT[],
+  end_ids BIGINT[],
+  durations BIGINT[]
+)
+RETURNS void
+AS $BODY$
+  DELETE FROM schedules WHERE project_id = project_id_param;
+  INSERT INTO schedules
+    (type, project_id, vehicle_id, location_id, task_id, vehicle_data, task_data,
+    arrival, travel_time, setup_time, service_time, waiting_time, departure, load)
+  SELECT
+    CASE
+      WHEN step_type = 0 THEN 'summary'::STEP_TYPE
+      WHEN step_type = 1 THEN 'start'::STEP_TYPE
+      WHEN step_type = 2 THEN 'job'::STEP_TYPE
+      WHEN step_type = 3 THEN 'pickup'::STEP_TYPE
+      WHEN step_type = 4 THEN 'delivery'::STEP_TYPE
+      WHEN step_type = 5 THEN 'break'::STEP_TYPE
+      WHEN step_type = 6 THEN 'end'::STEP_TYPE
+    END,
+    project_id_param::BIGINT, vehicle_id, location_id, task_id, vehicle_data, task_data,
+    arrival, travel_time, setup_time, service_time, waiting_time, departure, load
+  FROM vrp_vroom(
+    'SELECT * FROM jobs WHERE deleted = FALSE AND project_id = ' || project_id_param,
+    'SELECT * FROM jobs_time_windows ORDER BY id, tw_open',
+    'SELECT * FROM shipments WHERE deleted = FALSE AND project_id = ' || project_id_param,
+    'SELECT * FROM shipments_time_windows ORDER BY id, tw_open',
+    'SELECT * FROM vehicles WHERE deleted = FALSE AND project_id = ' || project_id_param,
+    'SELECT * FROM breaks WHERE deleted = FALSE',
+    'SELECT * FROM breaks_time_windows ORDER BY id, tw_open',
+    'SELECT unnest(ARRAY[' || array_to_string(start_ids, ',') || ']::BIGINT[]) AS start_id,
+     unnest(ARRAY[' || array_to_string(end_ids, ',') || ']::BIGINT[]) AS end_id,
+     make_interval(secs => unnest(ARRAY[' || array_to_string(durations, ',') || ']::BIGINT[])) AS duration',
+    exploration_level => (SELECT exploration_level FROM projects WHERE id = project_id_param),
+    timeout => (SELECT timeout FROM projects WHERE id = project_id_param)
+  );
+$BODY$ LANGUAGE sql VOLATILE;
+
+END;
@@ -0,0 +1,265 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000009_solver_options.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- Options of the solver, in addition to the exploration_level and the timeout:
+-- - objective: minimise the total "duration" of the routes, the number of "vehicles", or the total "cost"
+-- - vehicle_fixed_cost, vehicle_cost_per_hour: cost of using a vehicle, and cost of an hour of its route
+-- - seed: seed of the order of the tasks and vehicles given to the solver, 0 to keep their order
+ALTER TABLE projects ADD COLUMN objective VARCHAR NOT NULL DEFAULT 'duration';
+ALTER TABLE projects ADD COLUMN vehicle_fixed_cost BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE projects ADD COLUMN vehicle_cost_per_hour BIGINT NOT NULL DEFAULT 3600;
+ALTER TABLE projects ADD COLUMN seed BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE projects ADD CONSTRAINT projects_objective_check CHECK(objective IN ('duration', 'vehicles', 'cost'));
+ALTER TABLE projects ADD CONSTRAINT projects_vehicle_fixed_cost_check CHECK(vehicle_fixed_cost >= 0);
+ALTER TABLE projects ADD CONSTRAINT projects_vehicle_cost_per_hour_check CHECK(vehicle_cost_per_hour >= 0);
+ALTER TABLE projects ADD CONSTRAINT projects_seed_check CHECK(seed >= 0);
+
+
+-- Costs of the vehicles of a project given to the solver, which minimises the total cost of the routes.
+-- With the "duration" objective, the cost of a route is its duration in seconds.
+-- With the "vehicles" objective, the fixed cost is larger than the cost of any route, so that the number
+-- of vehicles is minimised first.
+CREATE OR REPLACE FUNCTION get_vehicle_costs(
+  project_id_param BIGINT
+)
+RETURNS TABLE(fixed_cost BIGINT, cost_per_hour BIGINT)
+AS $BODY$
+  SELECT
+    CASE objective
+      WHEN 'cost' THEN vehicle_fixed_cost
+      WHEN 'vehicles' THEN GREATEST(vehicle_fixed_cost, 1000000000)
+      ELSE 0
+    END,
+    CASE objective WHEN 'cost' THEN vehicle_cost_per_hour ELSE 3600 END
+  FROM projects WHERE id = project_id_param;
+$BODY$ LANGUAGE sql STABLE;
+
+
+-- ORDER BY clause ordering the rows given to the solver by the given id column, shuffled with the seed of the project.
+-- The rows are not ordered when the seed is 0.
+CREATE OR REPLACE FUNCTION get_seed_order(
+  project_id_param BIGINT,
+  id_column TEXT
+)
+RETURNS TEXT
+AS $BODY$
+  SELECT CASE WHEN seed = 0 THEN ''
+    ELSE ' ORDER BY md5(' || id_column || '::TEXT || ' || quote_literal(':' || seed) || ')'
+  END
+  FROM projects WHERE id = project_id_param;
+$BODY$ LANGUAGE sql STABLE;
+
+
+-- Create schedule for a project (such that any previous scheduled tasks are not likely to be unscheduled)
+-- The pinned tasks are only assigned to their vehicle, and the locked tasks keep their arrival time.
+-- The solver options of the project give the costs of the vehicles and the order of the tasks.
+CREATE OR REPLACE FUNCTION create_schedule(
+  project_id_param BIGINT,
+  start_ids BIGINT[],
+  end_ids BIGINT[],
+  durations BIGINT[]
+)
+RETURNS void
+AS $BODY$
+
+  CREATE TABLE schedules_copy AS TABLE schedules;
+  CREATE TEMP TABLE pinned_tasks AS SELECT * FROM get_pinned_tasks(project_id_param);
+
+  -- DELETE the schedules without changing the status field of jobs/shipments. Status field will be set by insert trigger later.
+  ALTER TABLE schedules DISABLE TRIGGER tgr_schedule_delete;
+  DELETE FROM schedules WHERE project_id = project_id_param;
+  ALTER TABLE schedules ENABLE TRIGGER tgr_schedule_delete;
+
+  WITH delta AS (SELECT max_shift FROM projects WHERE id = project_id_param)
+  INSERT INTO schedules
+    (type, project_id, vehicle_id, location_id, task_id, vehicle_data, task_data,
+    arrival, travel_time, setup_time, service_time, waiting_time, departure, load)
+  SELECT
+    CASE
+      WHEN step_type = 0 THEN 'summary'::STEP_TYPE
+      WHEN step_type = 1 THEN 'start'::STEP_TYPE
+      WHEN step_type = 2 THEN 'job'::STEP_TYPE
+      WHEN step_type = 3 THEN 'pickup'::STEP_TYPE
+      WHEN step_type = 4 THEN 'delivery'::STEP_TYPE
+      WHEN step_type = 5 THEN 'break'::STEP_TYPE
+      WHEN step_type = 6 THEN 'end'::STEP_TYPE
+    END,
+    project_id_param::BIGINT, vehicle_id, location_id, task_id, vehicle_data, task_data,
+    arrival, travel_time, setup_time, service_time, waiting_time, departure, load
+  FROM vrp_vroom(
+    -- jobs (Unscheduled jobs + Scheduled and locked jobs with 100 priority, with the skill of the pinned vehicle)
+    'SELECT J.id, location_id, setup, service, delivery, pickup,
+      CASE WHEN P.vehicle_id IS NULL THEN skills ELSE skills || get_pinned_skill(P.vehicle_id) END AS skills,
+      CASE WHEN status = ''scheduled'' OR P.locked THEN 100 ELSE priority END AS priority, data
+     FROM jobs J LEFT JOIN pinned_tasks P ON (P.type = ''job'' AND P.id = J.id)
+     WHERE project_id = ' || project_id_param || ' AND deleted = FALSE' || get_seed_order(project_id_param, 'J.id'),
+
+    -- jobs_time_windows (For unscheduled, select original time windows. For scheduled, alter the time window with a delta interval from the arrival time)
+    -- For locked, the time window is the start of the service in the current schedule
+    'SELECT * FROM (
+     SELECT J.id AS id, tw_open, tw_close
+     FROM jobs_time_windows TW LEFT JOIN jobs J ON(TW.id = J.id)
+     WHERE status = ''unscheduled'' AND project_id = ' || project_id_param || '
+    UNION
+     SELECT
+      J.id AS id,
+      GREATEST(tw_open, arrival - $$' || (SELECT * FROM delta) || '$$::INTERVAL) AS tw_open,
+      LEAST(tw_close, arrival + $$' || (SELECT * FROM delta) || '$$::INTERVAL) AS tw_close
+     FROM jobs_time_windows TW RIGHT JOIN jobs J ON(TW.id = J.id) JOIN schedules_copy S ON (J.id = S.task_id)
+     WHERE
+      GREATEST(tw_open, arrival - $$' || (SELECT * FROM delta) || '$$::INTERVAL) <= LEAST(tw_close, arrival + $$' || (SELECT * FROM delta) || '$$::INTERVAL)
+      AND status = ''scheduled'' AND type = ''job'' AND J.project_id = ' || project_id_param || '
+     ) AS TW WHERE id NOT IN (SELECT id FROM pinned_tasks WHERE type = ''job'' AND locked)
+    UNION ALL
+     SELECT id, service_start, service_start FROM pinned_tasks WHERE type = ''job'' AND locked
+     ORDER BY id, tw_open',
+
+    -- shipments (Unscheduled shipments + Scheduled and locked shipments with 100 priority, with the skill of the pinned vehicle)
+    'SELECT S.id, p_location_id, p_setup, p_service, d_location_id, d_setup, d_service, amount,
+      CASE WHEN P.vehicle_id IS NULL THEN skills ELSE skills || get_pinned_skill(P.vehicle_id) END AS skills,
+      CASE WHEN status = ''scheduled'' OR P.locked THEN 100 ELSE priority END AS priority, p_data, d_data
+     FROM shipments S LEFT JOIN pinned_tasks P ON (P.type = ''pickup'' AND P.id = S.id)
+     WHERE project_id = ' || project_id_param || ' AND deleted = FALSE' || get_seed_order(project_id_param, 'S.id'),
+
+    -- shipments_time_windows
+    -- For unscheduled, select original time windows.
+    -- For scheduled, alter the time window with a delta interval from the arrival time
+    -- For locked, the time window is the start of the service in the current schedule
+    -- TODO: When time windows are "edited" such that the delta range falls outside new time windows, then the time window is ignored because the <= condition fails
+    'SELECT * FROM (
+     SELECT S.id AS id, kind, tw_open, tw_close
+     FROM shipments_time_windows TW LEFT JOIN shipments S ON(TW.id = S.id)
+     WHERE status = ''unscheduled'' AND project_id = ' || project_id_param || '
+    UNION
+     SELECT
+      S.id AS id,
+      kind,
+      GREATEST(tw_open, arrival - $$' || (SELECT * FROM delta) || '$$::INTERVAL) AS tw_open,
+      LEAST(tw_close, arrival + $$' || (SELECT * FROM delta) || '$$::INTERVAL) AS tw_close
+     FROM shipments_time_windows TW RIGHT JOIN shipments S ON(TW.id = S.id) JOIN schedules_copy S2 ON (S.id = S2.task_id)
+     WHERE
+      GREATEST(tw_open, arrival - $$' || (SELECT * FROM delta) || '$$::INTERVAL) <= LEAST(tw_close, arrival + $$' || (SELECT * FROM delta) || '$$::INTERVAL)
+      AND status = ''scheduled'' AND ((type = ''pickup'' AND kind = ''p'') OR (type = ''delivery'' AND kind = ''d''))
+      AND S.project_id = ' || project_id_param || '
+     ) AS TW WHERE id NOT IN (SELECT id FROM pinned_tasks WHERE type IN (''pickup'', ''delivery'') AND locked)
+    UNION ALL
+     SELECT id, CASE WHEN type = ''pickup'' THEN ''p''::CHAR(1) ELSE ''d''::CHAR(1) END, service_start, service_start
+     FROM pinned_tasks WHERE type IN (''pickup'', ''delivery'') AND locked
+     ORDER BY id, tw_open',
+
+    -- vehicles (with the skill of the vehicle for the pinned tasks, and the costs given by the objective of the project)
+    'SELECT V.id, start_id, end_id, capacity, skills || get_pinned_skill(V.id) AS skills,
+      tw_open, tw_close, speed_factor, max_tasks, data, C.fixed_cost, C.cost_per_hour
+     FROM vehicles V CROSS JOIN get_vehicle_costs(' || project_id_param || ') C
+     WHERE deleted = FALSE AND project_id = ' || project_id_param || get_seed_order(project_id_param, 'V.id'),
+
+    -- breaks
+    'SELECT * FROM breaks WHERE deleted = FALSE',
+    'SELECT * FROM breaks_time_windows ORDER BY id, tw_open',
+
+    -- matrix
+    'SELECT unnest(ARRAY[' || array_to_string(start_ids, ',') || ']::BIGINT[]) AS start_id,
+     unnest(ARRAY[' || array_to_string(end_ids, ',') || ']::BIGINT[]) AS end_id,
+     make_interval(secs => unnest(ARRAY[' || array_to_string(durations, ',') || ']::BIGINT[])) AS duration',
+
+    exploration_level => (SELECT exploration_level FROM projects WHERE id = project_id_param),
+    timeout => (SELECT timeout FROM projects WHERE id = project_id_param)
+  );
+  DROP TABLE pinned_tasks;
+  DROP TABLE schedules_copy;
+$BODY$ LANGUAGE sql VOLATILE;
+
+
+-- Create schedule for a project (fresh scheduling, deleting any previous schedule)
+-- The pinned tasks are only assigned to their vehicle, and the locked tasks keep their arrival time.
+-- The solver options of the project give the costs of the vehicles and the order of the tasks.
+CREATE OR REPLACE FUNCTION create_fresh_schedule(
+  project_id_param BIGINT,
+  start_ids BIGINT[],
+  end_ids BIGINT[],
+  durations BIGINT[]
+)
+RETURNS void
+AS $BODY$
+  CREATE TEMP TABLE pinned_tasks AS SELECT * FROM get_pinned_tasks(project_id_param);
+  DELETE FROM schedules WHERE project_id = project_id_param;
+  INSERT INTO schedules
+    (type, project_id, vehicle_id, location_id, task_id, vehicle_data, task_data,
+    arrival, travel_time, setup_time, service_time, waiting_time, departure, load)
+  SELECT
+    CASE
+      WHEN step_type = 0 THEN 'summary'::STEP_TYPE
+      WHEN step_type = 1 THEN 'start'::STEP_TYPE
+      WHEN step_type = 2 THEN 'job'::STEP_TYPE
+      WHEN step_type = 3 THEN 'pickup'::STEP_TYPE
+      WHEN step_type = 4 THEN 'delivery'::STEP_TYPE
+      WHEN step_type = 5 THEN 'break'::STEP_TYPE
+      WHEN step_type = 6 THEN 'end'::STEP_TYPE
+    END,
+    project_id_param::BIGINT, vehicle_id, location_id, task_id, vehicle_data, task_data,
+    arrival, travel_time, setup_time, service_time, waiting_time, departure, load
+  FROM vrp_vroom(
+    'SELECT J.id, location_id, setup, service, delivery, pickup,
+      CASE WHEN P.vehicle_id IS NULL THEN skills ELSE skills || get_pinned_skill(P.vehicle_id) END AS skills,
+      CASE WHEN P.locked THEN 100 ELSE priority END AS priority, data
+     FROM jobs J LEFT JOIN pinned_tasks P ON (P.type = ''job'' AND P.id = J.id)
+     WHERE deleted = FALSE AND project_id = ' || project_id_param || get_seed_order(project_id_param, 'J.id'),
+    'SELECT id, tw_open, tw_close FROM jobs_time_windows
+     WHERE id NOT IN (SELECT id FROM pinned_tasks WHERE type = ''job'' AND locked)
+     UNION ALL
+     SELECT id, service_start, service_start FROM pinned_tasks WHERE type = ''job'' AND locked
+     ORDER BY id, tw_open',
+    'SELECT S.id, p_location_id, p_setup, p_service, d_location_id, d_setup, d_service, amount,
+      CASE WHEN P.vehicle_id IS NULL THEN skills ELSE skills || get_pinned_skill(P.vehicle_id) END AS skills,
+      CASE WHEN P.locked THEN 100 ELSE priority END AS priority, p_data, d_data
+     FROM shipments S LEFT JOIN pinned_tasks P ON (P.type = ''pickup'' AND P.id = S.id)
+     WHERE deleted = FALSE AND project_id = ' || project_id_param || get_seed_order(project_id_param, 'S.id'),
+    'SELECT id, kind, tw_open, tw_close FROM shipments_time_windows
+     WHERE id NOT IN (SELECT id FROM pinned_tasks WHERE type IN (''pickup'', ''delivery'') AND locked)
+     UNION ALL
+     SELECT id, CASE WHEN type = ''pickup'' THEN ''p''::CHAR(1) ELSE ''d''::CHAR(1) END, service_start, service_start
+     FROM pinned_tasks WHERE type IN (''pickup'', ''delivery'') AND locked
+     ORDER BY id, tw_open',
+    'SELECT V.id, start_id, end_id, capacity, skills || get_pinned_skill(V.id) AS skills,
+      tw_open, tw_close, speed_factor, max_tasks, data, C.fixed_cost, C.cost_per_hour
+     FROM vehicles V CROSS JOIN get_vehicle_costs(' || project_id_param || ') C
+     WHERE deleted = FALSE AND project_id = ' || project_id_param || get_seed_order(project_id_param, 'V.id'),
+    'SELECT * FROM breaks WHERE deleted = FALSE',
+    'SELECT * FROM breaks_time_windows ORDER BY id, tw_open',
+    'SELECT unnest(ARRAY[' || array_to_string(start_ids, ',') || ']::BIGINT[]) AS start_id,
+     unnest(ARRAY[' || array_to_string(end_ids, ',') || ']::BIGINT[]) AS end_id,
+     make_interval(secs => unnest(ARRAY[' || array_to_string(durations, ',') || ']::BIGINT[])) AS duration',
+    exploration_level => (SELECT exploration_level FROM projects WHERE id = project_id_param),
+    timeout => (SELECT timeout FROM projects WHERE id = project_id_param)
+  );
+  DROP TABLE pinned_tasks;
+$BODY$ LANGUAGE sql VOLATILE;
+
+END;
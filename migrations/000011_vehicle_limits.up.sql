@@ -0,0 +1,38 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000011_vehicle_limits.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- Limits of the route of a vehicle: the max travel time and the max distance (in meters).
+-- The routes exceeding them are shortened after scheduling, unassigning their last tasks.
+ALTER TABLE vehicles ADD COLUMN max_travel_time INTERVAL;
+ALTER TABLE vehicles ADD COLUMN max_distance BIGINT;
+ALTER TABLE vehicles ADD CONSTRAINT vehicles_max_travel_time_check CHECK(max_travel_time > '00:00:00'::INTERVAL);
+ALTER TABLE vehicles ADD CONSTRAINT vehicles_max_distance_check CHECK(max_distance > 0);
+
+END;
@@ -0,0 +1,80 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000012_vehicle_types.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- VEHICLE TYPES TABLE start
+-- Template of the vehicles of a project. The fields which are set are the defaults of the vehicles of the type,
+-- and the breaks are a template of the breaks created with each vehicle of the type, as a JSON array of
+-- {"service", "time_windows", "data"} objects.
+CREATE TABLE IF NOT EXISTS vehicle_types (
+  id              BIGINT    DEFAULT random_bigint() PRIMARY KEY,
+  name            VARCHAR   NOT NULL DEFAULT '',
+  capacity        BIGINT[],
+  skills          INTEGER[],
+  tw_open         TIMESTAMP,
+  tw_close        TIMESTAMP,
+  speed_factor    FLOAT,
+  max_tasks       INTEGER,
+  max_travel_time INTERVAL,
+  max_distance    BIGINT,
+  fixed_cost      BIGINT,
+  cost_per_hour   BIGINT,
+  cost_per_km     BIGINT,
+  breaks          JSONB     NOT NULL DEFAULT '[]'::JSONB,
+
+  project_id      BIGINT    NOT NULL REFERENCES projects(id),
+
+  data            JSONB     NOT NULL DEFAULT '{}'::JSONB,
+  created_at      TIMESTAMP NOT NULL DEFAULT current_timestamp,
+  updated_at      TIMESTAMP NOT NULL DEFAULT current_timestamp,
+  deleted         BOOLEAN   NOT NULL DEFAULT FALSE,
+
+  CHECK(id >= 0),
+  CHECK(0 <= ALL(capacity)),
+  CHECK(0 <= ALL(skills)),
+  CHECK(tw_open <= tw_close),
+  CHECK(speed_factor > 0.0),
+  CHECK(max_tasks >= 0),
+  CHECK(max_travel_time > '00:00:00'::INTERVAL),
+  CHECK(max_distance > 0),
+  CHECK(fixed_cost >= 0),
+  CHECK(cost_per_hour >= 0),
+  CHECK(cost_per_km >= 0),
+  CHECK(jsonb_typeof(breaks) = 'array')
+);
+-- VEHICLE TYPES TABLE end
+
+CREATE TRIGGER tgr_updated_at_field
+BEFORE UPDATE ON vehicle_types
+FOR EACH ROW EXECUTE PROCEDURE tgr_updated_at_field_func();
+
+-- Vehicle type of a vehicle, whose fields are copied to the vehicle when they are not given
+ALTER TABLE vehicles ADD COLUMN vehicle_type_id BIGINT REFERENCES vehicle_types(id);
+
+END;
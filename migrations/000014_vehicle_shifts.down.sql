@@ -0,0 +1,48 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000014_vehicle_shifts.down.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+DROP FUNCTION IF EXISTS get_vehicle_shifts_span;
+CREATE OR REPLACE FUNCTION get_vehicle_shifts_span(
+  project_id_param BIGINT
+)
+RETURNS TABLE(vehicle_id BIGINT, tw_open TIMESTAMP, tw_close TIMESTAMP)
+AS $BODY$
+  SELECT S.vehicle_id, MIN(S.tw_open), MAX(S.tw_close)
+  FROM vehicle_shifts S JOIN vehicles V ON (V.id = S.vehicle_id)
+  WHERE V.project_id = project_id_param
+  GROUP BY S.vehicle_id;
+$BODY$ LANGUAGE sql STABLE;
+
+
+DELETE FROM vehicle_shifts WHERE day_off OR NOT recurring;
+ALTER TABLE vehicle_shifts DROP COLUMN recurring;
+ALTER TABLE vehicle_shifts DROP COLUMN day_off;
+
+END;
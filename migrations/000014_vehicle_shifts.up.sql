@@ -0,0 +1,60 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000014_vehicle_shifts.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- Days off of a vehicle, removing the shifts which they overlap, and the shifts created from the shift recurrence
+ALTER TABLE vehicle_shifts ADD COLUMN day_off BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE vehicle_shifts ADD COLUMN recurring BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE vehicle_shifts SET recurring = TRUE;
+
+
+-- Time window of each vehicle of a project with shifts, from the start of its first shift to the end of its last shift.
+-- The shifts overlapping a day off are not available, and a vehicle without any available shift has an empty time window.
+DROP FUNCTION IF EXISTS get_vehicle_shifts_span;
+CREATE OR REPLACE FUNCTION get_vehicle_shifts_span(
+  project_id_param BIGINT
+)
+RETURNS TABLE(vehicle_id BIGINT, tw_open TIMESTAMP, tw_close TIMESTAMP)
+AS $BODY$
+  WITH shifts AS (
+    SELECT S.vehicle_id, S.tw_open, S.tw_close, NOT EXISTS (
+      SELECT 1 FROM vehicle_shifts D
+      WHERE D.vehicle_id = S.vehicle_id AND D.day_off AND D.tw_open < S.tw_close AND S.tw_open < D.tw_close
+    ) AS available
+    FROM vehicle_shifts S JOIN vehicles V ON (V.id = S.vehicle_id)
+    WHERE V.project_id = project_id_param AND NOT S.day_off
+  )
+  SELECT vehicle_id,
+    COALESCE(MIN(tw_open) FILTER (WHERE available), MIN(tw_open)),
+    COALESCE(MAX(tw_close) FILTER (WHERE available), MIN(tw_open))
+  FROM shifts
+  GROUP BY vehicle_id;
+$BODY$ LANGUAGE sql STABLE;
+
+END;
@@ -0,0 +1,41 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000016_webhooks.down.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+DROP TRIGGER IF EXISTS tgr_schedule_version_event ON schedule_versions;
+DROP FUNCTION IF EXISTS tgr_schedule_version_event_func;
+DROP TRIGGER IF EXISTS tgr_task_event ON shipments;
+DROP TRIGGER IF EXISTS tgr_task_event ON jobs;
+DROP FUNCTION IF EXISTS tgr_task_event_func;
+DROP FUNCTION IF EXISTS create_event;
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+
+END;
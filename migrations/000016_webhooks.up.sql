@@ -0,0 +1,168 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000016_webhooks.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- WEBHOOKS TABLE start
+CREATE TABLE IF NOT EXISTS webhooks (
+  id          BIGINT    DEFAULT random_bigint() PRIMARY KEY,
+  project_id  BIGINT    NOT NULL REFERENCES projects(id),
+  url         VARCHAR   NOT NULL,
+  secret      VARCHAR   NOT NULL,
+  events      TEXT[]    NOT NULL DEFAULT ARRAY[]::TEXT[],
+  active      BOOLEAN   NOT NULL DEFAULT TRUE,
+
+  created_at  TIMESTAMP NOT NULL DEFAULT current_timestamp,
+  updated_at  TIMESTAMP NOT NULL DEFAULT current_timestamp,
+
+  CHECK(id >= 0)
+);
+-- WEBHOOKS TABLE end
+
+CREATE INDEX IF NOT EXISTS webhooks_project_id_idx ON webhooks(project_id);
+
+CREATE TRIGGER tgr_updated_at_field
+BEFORE UPDATE ON webhooks
+FOR EACH ROW EXECUTE PROCEDURE tgr_updated_at_field_func();
+
+
+-- WEBHOOK DELIVERIES TABLE start
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id               BIGINT    DEFAULT random_bigint() PRIMARY KEY,
+  webhook_id       BIGINT    NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  event            VARCHAR   NOT NULL,
+  payload          JSONB     NOT NULL,
+  status           VARCHAR   NOT NULL DEFAULT 'pending',
+  attempts         INTEGER   NOT NULL DEFAULT 0,
+  next_attempt_at  TIMESTAMP DEFAULT current_timestamp,
+  response_code    INTEGER,
+  error            VARCHAR,
+
+  created_at       TIMESTAMP NOT NULL DEFAULT current_timestamp,
+  updated_at       TIMESTAMP NOT NULL DEFAULT current_timestamp,
+
+  CHECK(id >= 0),
+  CHECK(status IN ('pending', 'succeeded', 'failed'))
+);
+-- WEBHOOK DELIVERIES TABLE end
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx ON webhook_deliveries(webhook_id, created_at);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+CREATE TRIGGER tgr_updated_at_field
+BEFORE UPDATE ON webhook_deliveries
+FOR EACH ROW EXECUTE PROCEDURE tgr_updated_at_field_func();
+
+
+-- Create an event of a project, with a pending delivery for each active webhook of the project subscribed to the event.
+-- The webhooks without events are subscribed to all the events.
+CREATE OR REPLACE FUNCTION create_event(
+  project_id_param BIGINT,
+  event_param VARCHAR,
+  data_param JSONB
+)
+RETURNS void
+AS $BODY$
+DECLARE
+  event_payload JSONB;
+BEGIN
+  event_payload := jsonb_build_object(
+    'id', random_bigint()::TEXT,
+    'event', event_param,
+    'project_id', project_id_param::TEXT,
+    'created_at', to_char(current_timestamp AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+    'data', data_param
+  );
+
+  INSERT INTO webhook_deliveries (webhook_id, event, payload)
+  SELECT id, event_param, event_payload
+  FROM webhooks
+  WHERE project_id = project_id_param AND active AND (cardinality(events) = 0 OR event_param = ANY(events));
+END;
+$BODY$ LANGUAGE plpgsql VOLATILE;
+
+
+-- AFTER INSERT or UPDATE Trigger for jobs and shipments, creating the task.created, task.deleted and task.updated
+-- events, and the job.status_changed or shipment.status_changed events when the status is updated by the triggers
+-- of the schedules
+CREATE OR REPLACE FUNCTION tgr_task_event_func()
+RETURNS TRIGGER
+AS $trig$
+DECLARE
+  task_type TEXT;
+  data JSONB;
+BEGIN
+  task_type := CASE WHEN TG_TABLE_NAME = 'jobs' THEN 'job' ELSE 'shipment' END;
+  data := jsonb_build_object('task_id', NEW.id::TEXT, 'type', task_type);
+
+  IF TG_OP = 'INSERT' THEN
+    PERFORM create_event(NEW.project_id, 'task.created', data);
+  ELSIF NEW.deleted AND NOT OLD.deleted THEN
+    PERFORM create_event(NEW.project_id, 'task.deleted', data);
+  ELSIF NEW.deleted THEN
+    RETURN NULL;
+  ELSIF NEW.status IS DISTINCT FROM OLD.status THEN
+    PERFORM create_event(
+      NEW.project_id, task_type || '.status_changed',
+      data || jsonb_build_object('status', NEW.status, 'previous_status', OLD.status)
+    );
+  ELSE
+    PERFORM create_event(NEW.project_id, 'task.updated', data);
+  END IF;
+
+  RETURN NULL;
+END;
+$trig$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tgr_task_event
+AFTER INSERT OR UPDATE ON jobs
+FOR EACH ROW EXECUTE FUNCTION tgr_task_event_func();
+
+CREATE TRIGGER tgr_task_event
+AFTER INSERT OR UPDATE ON shipments
+FOR EACH ROW EXECUTE FUNCTION tgr_task_event_func();
+
+
+-- AFTER INSERT or UPDATE Trigger for schedule versions, creating the schedule.created event when a version becomes
+-- the active version, i.e. when the project is scheduled, its schedule is edited or a version is restored
+CREATE OR REPLACE FUNCTION tgr_schedule_version_event_func()
+RETURNS TRIGGER
+AS $trig$
+BEGIN
+  IF TG_OP = 'INSERT' OR NOT OLD.active THEN
+    PERFORM create_event(NEW.project_id, 'schedule.created', jsonb_build_object('version', NEW.version, 'fresh', NEW.fresh));
+  END IF;
+  RETURN NULL;
+END;
+$trig$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tgr_schedule_version_event
+AFTER INSERT OR UPDATE OF active ON schedule_versions
+FOR EACH ROW WHEN (NEW.active) EXECUTE FUNCTION tgr_schedule_version_event_func();
+
+END;
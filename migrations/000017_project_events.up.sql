@@ -0,0 +1,61 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000017_project_events.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- Create an event of a project, with a pending delivery for each active webhook of the project subscribed to the event.
+-- The webhooks without events are subscribed to all the events.
+-- The event is also sent on the project_events channel, which is listened to by the event streams of the servers.
+CREATE OR REPLACE FUNCTION create_event(
+  project_id_param BIGINT,
+  event_param VARCHAR,
+  data_param JSONB
+)
+RETURNS void
+AS $BODY$
+DECLARE
+  event_payload JSONB;
+BEGIN
+  event_payload := jsonb_build_object(
+    'id', random_bigint()::TEXT,
+    'event', event_param,
+    'project_id', project_id_param::TEXT,
+    'created_at', to_char(current_timestamp AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"'),
+    'data', data_param
+  );
+
+  INSERT INTO webhook_deliveries (webhook_id, event, payload)
+  SELECT id, event_param, event_payload
+  FROM webhooks
+  WHERE project_id = project_id_param AND active AND (cardinality(events) = 0 OR event_param = ANY(events));
+
+  PERFORM pg_notify('project_events', event_payload::TEXT);
+END;
+$BODY$ LANGUAGE plpgsql VOLATILE;
+
+END;
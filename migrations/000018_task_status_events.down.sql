@@ -0,0 +1,74 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000018_task_status_events.down.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+DROP TRIGGER IF EXISTS tgr_schedule_version_event ON schedule_versions;
+CREATE TRIGGER tgr_schedule_version_event
+AFTER INSERT OR UPDATE OF active ON schedule_versions
+FOR EACH ROW WHEN (NEW.active) EXECUTE FUNCTION tgr_schedule_version_event_func();
+
+DROP TRIGGER IF EXISTS tgr_task_status_event ON shipments;
+DROP TRIGGER IF EXISTS tgr_task_status_event ON jobs;
+DROP FUNCTION IF EXISTS tgr_task_status_event_func;
+
+-- AFTER INSERT or UPDATE Trigger for jobs and shipments, creating the task.created, task.deleted and task.updated
+-- events, and the job.status_changed or shipment.status_changed events when the status is updated by the triggers
+-- of the schedules
+CREATE OR REPLACE FUNCTION tgr_task_event_func()
+RETURNS TRIGGER
+AS $trig$
+DECLARE
+  task_type TEXT;
+  data JSONB;
+BEGIN
+  task_type := CASE WHEN TG_TABLE_NAME = 'jobs' THEN 'job' ELSE 'shipment' END;
+  data := jsonb_build_object('task_id', NEW.id::TEXT, 'type', task_type);
+
+  IF TG_OP = 'INSERT' THEN
+    PERFORM create_event(NEW.project_id, 'task.created', data);
+  ELSIF NEW.deleted AND NOT OLD.deleted THEN
+    PERFORM create_event(NEW.project_id, 'task.deleted', data);
+  ELSIF NEW.deleted THEN
+    RETURN NULL;
+  ELSIF NEW.status IS DISTINCT FROM OLD.status THEN
+    PERFORM create_event(
+      NEW.project_id, task_type || '.status_changed',
+      data || jsonb_build_object('status', NEW.status, 'previous_status', OLD.status)
+    );
+  ELSE
+    PERFORM create_event(NEW.project_id, 'task.updated', data);
+  END IF;
+
+  RETURN NULL;
+END;
+$trig$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS task_status_changes;
+
+END;
@@ -0,0 +1,135 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000018_task_status_events.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- Status of the tasks before their first status change in the current transaction, so that the status_changed events
+-- are created when the transaction is committed, and only for the tasks whose status is different at the end. The
+-- rows are deleted when the events are created.
+CREATE UNLOGGED TABLE IF NOT EXISTS task_status_changes (
+  transaction_id  BIGINT NOT NULL,
+  table_name      TEXT NOT NULL,
+  task_id         BIGINT NOT NULL,
+  previous_status TEXT,
+  PRIMARY KEY (transaction_id, table_name, task_id)
+);
+
+
+-- AFTER INSERT or UPDATE Trigger for jobs and shipments, creating the task.created, task.deleted and task.updated
+-- events. The task.updated event is only created when a column other than the status or updated_at is changed, and
+-- the status changes are recorded for tgr_task_status_event_func.
+CREATE OR REPLACE FUNCTION tgr_task_event_func()
+RETURNS TRIGGER
+AS $trig$
+DECLARE
+  task_type TEXT;
+  data JSONB;
+BEGIN
+  task_type := CASE WHEN TG_TABLE_NAME = 'jobs' THEN 'job' ELSE 'shipment' END;
+  data := jsonb_build_object('task_id', NEW.id::TEXT, 'type', task_type);
+
+  IF TG_OP = 'INSERT' THEN
+    PERFORM create_event(NEW.project_id, 'task.created', data);
+    RETURN NULL;
+  ELSIF NEW.deleted AND NOT OLD.deleted THEN
+    PERFORM create_event(NEW.project_id, 'task.deleted', data);
+    RETURN NULL;
+  ELSIF NEW.deleted THEN
+    RETURN NULL;
+  END IF;
+
+  IF NEW.status IS DISTINCT FROM OLD.status THEN
+    INSERT INTO task_status_changes (transaction_id, table_name, task_id, previous_status)
+    VALUES (txid_current(), TG_TABLE_NAME, NEW.id, OLD.status)
+    ON CONFLICT DO NOTHING;
+  END IF;
+  IF (to_jsonb(NEW) - 'status' - 'updated_at') IS DISTINCT FROM (to_jsonb(OLD) - 'status' - 'updated_at') THEN
+    PERFORM create_event(NEW.project_id, 'task.updated', data);
+  END IF;
+
+  RETURN NULL;
+END;
+$trig$ LANGUAGE plpgsql;
+
+
+-- Deferred AFTER UPDATE Trigger for jobs and shipments, creating the job.status_changed or shipment.status_changed
+-- event when the transaction is committed, if the status of the task is different from its status before the
+-- transaction. Creating a schedule unschedules and schedules again the tasks, which only changes the status of the
+-- tasks with a different assignment.
+CREATE OR REPLACE FUNCTION tgr_task_status_event_func()
+RETURNS TRIGGER
+AS $trig$
+DECLARE
+  task_type TEXT;
+  previous_status_value TEXT;
+  task RECORD;
+BEGIN
+  DELETE FROM task_status_changes
+  WHERE transaction_id = txid_current() AND table_name = TG_TABLE_NAME AND task_id = NEW.id
+  RETURNING previous_status INTO previous_status_value;
+  -- the event of the task is already created by a previous status change of the transaction
+  IF NOT FOUND THEN
+    RETURN NULL;
+  END IF;
+
+  EXECUTE format('SELECT project_id, status, deleted FROM %I WHERE id = $1', TG_TABLE_NAME) INTO task USING NEW.id;
+  IF task.deleted OR task.status IS NOT DISTINCT FROM previous_status_value THEN
+    RETURN NULL;
+  END IF;
+
+  task_type := CASE WHEN TG_TABLE_NAME = 'jobs' THEN 'job' ELSE 'shipment' END;
+  PERFORM create_event(
+    task.project_id, task_type || '.status_changed',
+    jsonb_build_object(
+      'task_id', NEW.id::TEXT, 'type', task_type, 'status', task.status, 'previous_status', previous_status_value
+    )
+  );
+  RETURN NULL;
+END;
+$trig$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER tgr_task_status_event
+AFTER UPDATE OF status ON jobs
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status) EXECUTE FUNCTION tgr_task_status_event_func();
+
+CREATE CONSTRAINT TRIGGER tgr_task_status_event
+AFTER UPDATE OF status ON shipments
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status) EXECUTE FUNCTION tgr_task_status_event_func();
+
+
+-- The schedule.created event is created when the transaction is committed as well, after the status_changed events
+-- of the schedule
+DROP TRIGGER IF EXISTS tgr_schedule_version_event ON schedule_versions;
+CREATE CONSTRAINT TRIGGER tgr_schedule_version_event
+AFTER INSERT OR UPDATE OF active ON schedule_versions
+DEFERRABLE INITIALLY DEFERRED
+FOR EACH ROW WHEN (NEW.active) EXECUTE FUNCTION tgr_schedule_version_event_func();
+
+END;
@@ -0,0 +1,60 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000023_scope_clear_matrix.down.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+DROP FUNCTION IF EXISTS clear_matrix(BIGINT, VARCHAR);
+
+-- Clear the cached durations between the locations of a project
+CREATE OR REPLACE FUNCTION clear_matrix(
+  project_id_param BIGINT
+)
+RETURNS void
+AS $BODY$
+  WITH project_locations AS (
+    SELECT location_id FROM jobs WHERE project_id = project_id_param UNION
+    SELECT unnest(ARRAY[p_location_id, d_location_id]) FROM shipments WHERE project_id = project_id_param UNION
+    SELECT unnest(ARRAY[start_id, end_id]) FROM vehicles WHERE project_id = project_id_param
+  )
+  DELETE FROM matrix
+    WHERE start_id IN (SELECT location_id FROM project_locations)
+    AND end_id IN (SELECT location_id FROM project_locations);
+$BODY$ LANGUAGE sql VOLATILE;
+
+
+-- AFTER UPDATE Trigger for projects, clear the cached durations when the duration_calc changes
+CREATE OR REPLACE FUNCTION tgr_projects_duration_calc_update_func()
+RETURNS TRIGGER
+AS $trig$
+BEGIN
+  PERFORM clear_matrix(NEW.id);
+  RETURN NULL;
+END;
+$trig$ LANGUAGE plpgsql;
+
+END;
@@ -0,0 +1,83 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000023_scope_clear_matrix.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- Clear the cached durations between the locations of a project for a duration_calc, which defaults to the
+-- duration_calc of the project. The location ids are derived from the coordinates, so the pairs of locations
+-- which are still used by another project with the same duration_calc are kept.
+DROP FUNCTION IF EXISTS clear_matrix(BIGINT);
+
+CREATE OR REPLACE FUNCTION clear_matrix(
+  project_id_param BIGINT,
+  duration_calc_param VARCHAR DEFAULT NULL
+)
+RETURNS void
+AS $BODY$
+  WITH project_locations AS (
+    SELECT location_id FROM jobs WHERE project_id = project_id_param UNION
+    SELECT unnest(ARRAY[p_location_id, d_location_id]) FROM shipments WHERE project_id = project_id_param UNION
+    SELECT unnest(ARRAY[start_id, end_id]) FROM vehicles WHERE project_id = project_id_param
+  ),
+  calc AS (
+    SELECT COALESCE(duration_calc_param, (SELECT duration_calc FROM projects WHERE id = project_id_param)) AS duration_calc
+  ),
+  other_projects AS (
+    SELECT P.id FROM projects P, calc
+    WHERE P.id != project_id_param AND P.deleted = FALSE AND P.duration_calc = calc.duration_calc
+  ),
+  other_locations AS (
+    SELECT project_id, location_id FROM jobs
+      WHERE deleted = FALSE AND project_id IN (SELECT id FROM other_projects) UNION
+    SELECT project_id, unnest(ARRAY[p_location_id, d_location_id]) FROM shipments
+      WHERE deleted = FALSE AND project_id IN (SELECT id FROM other_projects) UNION
+    SELECT project_id, unnest(ARRAY[start_id, end_id]) FROM vehicles
+      WHERE deleted = FALSE AND project_id IN (SELECT id FROM other_projects)
+  )
+  DELETE FROM matrix M
+    WHERE M.duration_calc = (SELECT duration_calc FROM calc)
+    AND M.start_id IN (SELECT location_id FROM project_locations)
+    AND M.end_id IN (SELECT location_id FROM project_locations)
+    AND NOT EXISTS (
+      SELECT 1 FROM other_locations S JOIN other_locations E ON (E.project_id = S.project_id)
+      WHERE S.location_id = M.start_id AND E.location_id = M.end_id
+    );
+$BODY$ LANGUAGE sql VOLATILE;
+
+
+-- AFTER UPDATE Trigger for projects, clear the durations cached under the old duration_calc when it changes
+CREATE OR REPLACE FUNCTION tgr_projects_duration_calc_update_func()
+RETURNS TRIGGER
+AS $trig$
+BEGIN
+  PERFORM clear_matrix(NEW.id, OLD.duration_calc);
+  RETURN NULL;
+END;
+$trig$ LANGUAGE plpgsql;
+
+END;
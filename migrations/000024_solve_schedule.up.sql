@@ -0,0 +1,245 @@
+/*GRP-GNU-AGPL******************************************************************
+
+File: 000024_solve_schedule.up.sql
+
+Copyright (C) 2021  Team Georepublic <info@georepublic.de>
+
+Developer(s):
+Copyright (C) 2021  Ashish Kumar <ashishkr23438@gmail.com>
+
+-----
+
+This file is part of pg_scheduleserv.
+
+pg_scheduleserv is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+pg_scheduleserv is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with pg_scheduleserv.  If not, see <https://www.gnu.org/licenses/>.
+
+******************************************************************GRP-GNU-AGPL*/
+
+BEGIN;
+
+-- Hash of the state of a project used to solve its schedule: the inputs of the scheduling, the solver settings of the project,
+-- the shifts of its vehicles and its current schedule, ignoring the timestamps of the rows. The solved schedule is only
+-- saved when this state is unchanged.
+CREATE OR REPLACE FUNCTION get_schedule_inputs_hash(
+  project_id_param BIGINT
+)
+RETURNS TEXT
+AS $BODY$
+  SELECT md5(jsonb_build_array(
+    get_project_inputs_hash(project_id_param),
+    (
+      SELECT to_jsonb(P) - ARRAY['name', 'data', 'timezone', 'created_at', 'updated_at']
+      FROM projects P WHERE id = project_id_param
+    ),
+    (
+      SELECT jsonb_agg((to_jsonb(S) - ARRAY['created_at', 'updated_at']) ORDER BY S.vehicle_id, S.tw_open, S.tw_close)
+      FROM vehicle_shifts S JOIN vehicles V ON (V.id = S.vehicle_id)
+      WHERE V.project_id = project_id_param AND V.deleted = FALSE
+    ),
+    (
+      SELECT jsonb_agg((to_jsonb(S) - ARRAY['created_at', 'updated_at']) ORDER BY S.vehicle_id, S.type, S.task_id)
+      FROM schedules S WHERE project_id = project_id_param
+    )
+  )::TEXT);
+$BODY$ LANGUAGE sql STABLE;
+
+
+-- Solve the schedule of a project (such that any previous scheduled tasks are not likely to be unscheduled)
+-- The rows of the solved schedule are returned without changing the schedule of the project, so that the solver
+-- runs outside of the transaction saving the schedule.
+-- The pinned tasks are only assigned to their vehicle, and the locked tasks keep their arrival time.
+-- The objective of the project gives the costs of the matrix, and the tasks and vehicles are ordered by their id.
+-- The recurring job templates are not scheduled, and the vehicles with shifts are available during their shifts.
+CREATE OR REPLACE FUNCTION solve_schedule(
+  project_id_param BIGINT,
+  start_ids BIGINT[],
+  end_ids BIGINT[],
+  durations BIGINT[]
+)
+RETURNS TABLE(type STEP_TYPE, project_id BIGINT, vehicle_id BIGINT, task_id BIGINT, location_id BIGINT,
+  arrival TIMESTAMP, departure TIMESTAMP, travel_time INTERVAL, setup_time INTERVAL, service_time INTERVAL,
+  waiting_time INTERVAL, distance BIGINT, load BIGINT[], vehicle_data JSONB, task_data JSONB,
+  created_at TIMESTAMP, updated_at TIMESTAMP)
+AS $BODY$
+  WITH delta AS (SELECT max_shift FROM projects WHERE id = project_id_param)
+  SELECT
+    CASE
+      WHEN step_type = 0 THEN 'summary'::STEP_TYPE
+      WHEN step_type = 1 THEN 'start'::STEP_TYPE
+      WHEN step_type = 2 THEN 'job'::STEP_TYPE
+      WHEN step_type = 3 THEN 'pickup'::STEP_TYPE
+      WHEN step_type = 4 THEN 'delivery'::STEP_TYPE
+      WHEN step_type = 5 THEN 'break'::STEP_TYPE
+      WHEN step_type = 6 THEN 'end'::STEP_TYPE
+    END,
+    project_id_param::BIGINT, vehicle_id, task_id, location_id, arrival, departure,
+    travel_time, setup_time, service_time, waiting_time, 0::BIGINT, load, vehicle_data, task_data,
+    current_timestamp::TIMESTAMP, current_timestamp::TIMESTAMP
+  FROM vrp_vroom(
+    -- jobs (Unscheduled jobs + Scheduled and locked jobs with 100 priority, with the skill of the pinned vehicle)
+    'SELECT J.id, location_id, setup, service, delivery, pickup,
+      CASE WHEN P.vehicle_id IS NULL THEN skills ELSE skills || get_pinned_skill(P.vehicle_id) END AS skills,
+      CASE WHEN status = ''scheduled'' OR P.locked THEN 100 ELSE priority END AS priority, data
+     FROM jobs J LEFT JOIN get_pinned_tasks(' || project_id_param || ') P ON (P.type = ''job'' AND P.id = J.id)
+     WHERE project_id = ' || project_id_param || ' AND deleted = FALSE AND recurrence = '''' ORDER BY J.id',
+
+    -- jobs_time_windows (For unscheduled, select original time windows. For scheduled, alter the time window with a delta interval from the arrival time)
+    -- For locked, the time window is the start of the service in the current schedule
+    'SELECT * FROM (
+     SELECT J.id AS id, tw_open, tw_close
+     FROM jobs_time_windows TW LEFT JOIN jobs J ON(TW.id = J.id)
+     WHERE status = ''unscheduled'' AND project_id = ' || project_id_param || '
+    UNION
+     SELECT
+      J.id AS id,
+      GREATEST(tw_open, arrival - $$' || (SELECT * FROM delta) || '$$::INTERVAL) AS tw_open,
+      LEAST(tw_close, arrival + $$' || (SELECT * FROM delta) || '$$::INTERVAL) AS tw_close
+     FROM jobs_time_windows TW RIGHT JOIN jobs J ON(TW.id = J.id) JOIN schedules S ON (S.project_id = J.project_id AND J.id = S.task_id)
+     WHERE
+      GREATEST(tw_open, arrival - $$' || (SELECT * FROM delta) || '$$::INTERVAL) <= LEAST(tw_close, arrival + $$' || (SELECT * FROM delta) || '$$::INTERVAL)
+      AND status = ''scheduled'' AND type = ''job'' AND J.project_id = ' || project_id_param || '
+     ) AS TW WHERE id NOT IN (SELECT id FROM get_pinned_tasks(' || project_id_param || ') WHERE type = ''job'' AND locked)
+    UNION ALL
+     SELECT id, service_start, service_start FROM get_pinned_tasks(' || project_id_param || ') WHERE type = ''job'' AND locked
+     ORDER BY id, tw_open',
+
+    -- shipments (Unscheduled shipments + Scheduled and locked shipments with 100 priority, with the skill of the pinned vehicle)
+    'SELECT S.id, p_location_id, p_setup, p_service, d_location_id, d_setup, d_service, amount,
+      CASE WHEN P.vehicle_id IS NULL THEN skills ELSE skills || get_pinned_skill(P.vehicle_id) END AS skills,
+      CASE WHEN status = ''scheduled'' OR P.locked THEN 100 ELSE priority END AS priority, p_data, d_data
+     FROM shipments S LEFT JOIN get_pinned_tasks(' || project_id_param || ') P ON (P.type = ''pickup'' AND P.id = S.id)
+     WHERE project_id = ' || project_id_param || ' AND deleted = FALSE ORDER BY S.id',
+
+    -- shipments_time_windows
+    -- For unscheduled, select original time windows.
+    -- For scheduled, alter the time window with a delta interval from the arrival time
+    -- For locked, the time window is the start of the service in the current schedule
+    -- TODO: When time windows are "edited" such that the delta range falls outside new time windows, then the time window is ignored because the <= condition fails
+    'SELECT * FROM (
+     SELECT S.id AS id, kind, tw_open, tw_close
+     FROM shipments_time_windows TW LEFT JOIN shipments S ON(TW.id = S.id)
+     WHERE status = ''unscheduled'' AND project_id = ' || project_id_param || '
+    UNION
+     SELECT
+      S.id AS id,
+      kind,
+      GREATEST(tw_open, arrival - $$' || (SELECT * FROM delta) || '$$::INTERVAL) AS tw_open,
+      LEAST(tw_close, arrival + $$' || (SELECT * FROM delta) || '$$::INTERVAL) AS tw_close
+     FROM shipments_time_windows TW RIGHT JOIN shipments S ON(TW.id = S.id) JOIN schedules S2 ON (S2.project_id = S.project_id AND S.id = S2.task_id)
+     WHERE
+      GREATEST(tw_open, arrival - $$' || (SELECT * FROM delta) || '$$::INTERVAL) <= LEAST(tw_close, arrival + $$' || (SELECT * FROM delta) || '$$::INTERVAL)
+      AND status = ''scheduled'' AND ((type = ''pickup'' AND kind = ''p'') OR (type = ''delivery'' AND kind = ''d''))
+      AND S.project_id = ' || project_id_param || '
+     ) AS TW WHERE id NOT IN (SELECT id FROM get_pinned_tasks(' || project_id_param || ') WHERE type IN (''pickup'', ''delivery'') AND locked)
+    UNION ALL
+     SELECT id, CASE WHEN type = ''pickup'' THEN ''p''::CHAR(1) ELSE ''d''::CHAR(1) END, service_start, service_start
+     FROM get_pinned_tasks(' || project_id_param || ') WHERE type IN (''pickup'', ''delivery'') AND locked
+     ORDER BY id, tw_open',
+
+    -- vehicles (with the skill of the vehicle for the pinned tasks)
+    'SELECT V.id, start_id, end_id, capacity, skills || get_pinned_skill(V.id) AS skills,
+      COALESCE(S.tw_open, V.tw_open) AS tw_open, COALESCE(S.tw_close, V.tw_close) AS tw_close,
+      speed_factor, max_tasks, data
+     FROM vehicles V
+     LEFT JOIN get_vehicle_shifts_span(' || project_id_param || ') S ON (S.vehicle_id = V.id)
+     WHERE deleted = FALSE AND project_id = ' || project_id_param || ' ORDER BY V.id',
+
+    -- breaks
+    'SELECT * FROM breaks WHERE deleted = FALSE',
+    'SELECT * FROM breaks_time_windows ORDER BY id, tw_open',
+
+    -- matrix
+    'SELECT unnest(ARRAY[' || array_to_string(start_ids, ',') || ']::BIGINT[]) AS start_id,
+     unnest(ARRAY[' || array_to_string(end_ids, ',') || ']::BIGINT[]) AS end_id,
+     make_interval(secs => unnest(ARRAY[' || array_to_string(durations, ',') || ']::BIGINT[])) AS duration,
+     unnest(ARRAY[' || array_to_string(get_matrix_costs(project_id_param, start_ids, end_ids, durations), ',') || ']::BIGINT[]) AS cost',
+
+    exploration_level => (SELECT exploration_level FROM projects WHERE id = project_id_param),
+    timeout => (SELECT timeout FROM projects WHERE id = project_id_param)
+  );
+$BODY$ LANGUAGE sql VOLATILE;
+
+
+-- Solve the schedule of a project (fresh scheduling, ignoring any previous schedule)
+-- The rows of the solved schedule are returned without changing the schedule of the project.
+-- The pinned tasks are only assigned to their vehicle, and the locked tasks keep their arrival time.
+-- The objective of the project gives the costs of the matrix, and the tasks and vehicles are ordered by their id.
+-- The recurring job templates are not scheduled, and the vehicles with shifts are available during their shifts.
+CREATE OR REPLACE FUNCTION solve_fresh_schedule(
+  project_id_param BIGINT,
+  start_ids BIGINT[],
+  end_ids BIGINT[],
+  durations BIGINT[]
+)
+RETURNS TABLE(type STEP_TYPE, project_id BIGINT, vehicle_id BIGINT, task_id BIGINT, location_id BIGINT,
+  arrival TIMESTAMP, departure TIMESTAMP, travel_time INTERVAL, setup_time INTERVAL, service_time INTERVAL,
+  waiting_time INTERVAL, distance BIGINT, load BIGINT[], vehicle_data JSONB, task_data JSONB,
+  created_at TIMESTAMP, updated_at TIMESTAMP)
+AS $BODY$
+  SELECT
+    CASE
+      WHEN step_type = 0 THEN 'summary'::STEP_TYPE
+      WHEN step_type = 1 THEN 'start'::STEP_TYPE
+      WHEN step_type = 2 THEN 'job'::STEP_TYPE
+      WHEN step_type = 3 THEN 'pickup'::STEP_TYPE
+      WHEN step_type = 4 THEN 'delivery'::STEP_TYPE
+      WHEN step_type = 5 THEN 'break'::STEP_TYPE
+      WHEN step_type = 6 THEN 'end'::STEP_TYPE
+    END,
+    project_id_param::BIGINT, vehicle_id, task_id, location_id, arrival, departure,
+    travel_time, setup_time, service_time, waiting_time, 0::BIGINT, load, vehicle_data, task_data,
+    current_timestamp::TIMESTAMP, current_timestamp::TIMESTAMP
+  FROM vrp_vroom(
+    'SELECT J.id, location_id, setup, service, delivery, pickup,
+      CASE WHEN P.vehicle_id IS NULL THEN skills ELSE skills || get_pinned_skill(P.vehicle_id) END AS skills,
+      CASE WHEN P.locked THEN 100 ELSE priority END AS priority, data
+     FROM jobs J LEFT JOIN get_pinned_tasks(' || project_id_param || ') P ON (P.type = ''job'' AND P.id = J.id)
+     WHERE deleted = FALSE AND recurrence = '''' AND project_id = ' || project_id_param || ' ORDER BY J.id',
+    'SELECT id, tw_open, tw_close FROM jobs_time_windows
+     WHERE id NOT IN (SELECT id FROM get_pinned_tasks(' || project_id_param || ') WHERE type = ''job'' AND locked)
+     UNION ALL
+     SELECT id, service_start, service_start FROM get_pinned_tasks(' || project_id_param || ') WHERE type = ''job'' AND locked
+     ORDER BY id, tw_open',
+    'SELECT S.id, p_location_id, p_setup, p_service, d_location_id, d_setup, d_service, amount,
+      CASE WHEN P.vehicle_id IS NULL THEN skills ELSE skills || get_pinned_skill(P.vehicle_id) END AS skills,
+      CASE WHEN P.locked THEN 100 ELSE priority END AS priority, p_data, d_data
+     FROM shipments S LEFT JOIN get_pinned_tasks(' || project_id_param || ') P ON (P.type = ''pickup'' AND P.id = S.id)
+     WHERE deleted = FALSE AND project_id = ' || project_id_param || ' ORDER BY S.id',
+    'SELECT id, kind, tw_open, tw_close FROM shipments_time_windows
+     WHERE id NOT IN (SELECT id FROM get_pinned_tasks(' || project_id_param || ') WHERE type IN (''pickup'', ''delivery'') AND locked)
+     UNION ALL
+     SELECT id, CASE WHEN type = ''pickup'' THEN ''p''::CHAR(1) ELSE ''d''::CHAR(1) END, service_start, service_start
+     FROM get_pinned_tasks(' || project_id_param || ') WHERE type IN (''pickup'', ''delivery'') AND locked
+     ORDER BY id, tw_open',
+    'SELECT V.id, start_id, end_id, capacity, skills || get_pinned_skill(V.id) AS skills,
+      COALESCE(S.tw_open, V.tw_open) AS tw_open, COALESCE(S.tw_close, V.tw_close) AS tw_close,
+      speed_factor, max_tasks, data
+     FROM vehicles V
+     LEFT JOIN get_vehicle_shifts_span(' || project_id_param || ') S ON (S.vehicle_id = V.id)
+     WHERE deleted = FALSE AND project_id = ' || project_id_param || ' ORDER BY V.id',
+    'SELECT * FROM breaks WHERE deleted = FALSE',
+    'SELECT * FROM breaks_time_windows ORDER BY id, tw_open',
+    'SELECT unnest(ARRAY[' || array_to_string(start_ids, ',') || ']::BIGINT[]) AS start_id,
+     unnest(ARRAY[' || array_to_string(end_ids, ',') || ']::BIGINT[]) AS end_id,
+     make_interval(secs => unnest(ARRAY[' || array_to_string(durations, ',') || ']::BIGINT[])) AS duration,
+     unnest(ARRAY[' || array_to_string(get_matrix_costs(project_id_param, start_ids, end_ids, durations), ',') || ']::BIGINT[]) AS cost',
+    exploration_level => (SELECT exploration_level FROM projects WHERE id = project_id_param),
+    timeout => (SELECT timeout FROM projects WHERE id = project_id_param)
+  );
+$BODY$ LANGUAGE sql VOLATILE;
+
+DROP FUNCTION IF EXISTS create_schedule;
+DROP FUNCTION IF EXISTS create_fresh_schedule;
+
+END;